@@ -3,23 +3,43 @@ package app
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/SneedusSnake/Reservations/internal/adapters/driven/clock/cache"
 	"github.com/SneedusSnake/Reservations/internal/adapters/driven/clock/system"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/mail"
 	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
 	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/mysql"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/postgres"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/webhooks"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/discord"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/matrix"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/slack"
 	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/web"
 	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/logging"
 	"github.com/SneedusSnake/Reservations/internal/ports"
 	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
 	"github.com/SneedusSnake/Reservations/internal/ports/users"
+	webhooksPort "github.com/SneedusSnake/Reservations/internal/ports/webhooks"
+	workspacesPort "github.com/SneedusSnake/Reservations/internal/ports/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/transfer"
 	mysqlDriver "github.com/go-sql-driver/mysql"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/pressly/goose/v3"
@@ -31,16 +51,44 @@ const (
 
 	STORE_SUBJECTS     = "subjects_store"
 	STORE_USERS        = "users_store"
+	STORE_TOKENS = "tokens_store"
+	STORE_ACCESS_TOKENS = "access_tokens_store"
+	USER_MERGER = "user_merger"
 	STORE_TG_USERS     = "tg_users_store"
+	STORE_SLACK_USERS = "slack_users_store"
+	STORE_DISCORD_USERS = "discord_users_store"
+	STORE_MATRIX_USERS = "matrix_users_store"
 	STORE_RESERVATIONS = "reservations_store"
 	STORE_READ_RESERVATIONS = "reservations_read_store"
+	STORE_WEBHOOKS = "webhooks_store"
+	STORE_DEAD_LETTERS = "dead_letters_store"
+	STORE_WORKSPACES = "workspaces_store"
+	STORE_TOPICS = "topics_store"
+	STORE_CHATS = "chats_store"
+	UNIT_OF_WORK = "unit_of_work"
+	SNAPSHOTTER = "snapshotter"
+	WEBHOOK_DISPATCHER = "webhook_dispatcher"
+	TOPIC_NOTIFIER = "topic_notifier"
 
 	SERVICE_SUBJECT = "subject_service"
 	SERVICE_USER = "user_service"
+	SERVICE_ACCOUNT = "account_service"
+	SERVICE_ACCESS_TOKEN = "access_token_service"
 	SERVICE_TELEGRAM_USER = "telegram_user_service"
+	SERVICE_SLACK_USER = "slack_user_service"
+	SERVICE_DISCORD_USER = "discord_user_service"
+	SERVICE_MATRIX_USER = "matrix_user_service"
 	SERVICE_RESERVATION = "reservation_service"
+	SERVICE_CALENDAR = "calendar_service"
+	SERVICE_CALENDAR_IMPORT = "calendar_import_service"
+	SERVICE_WEBHOOK = "webhook_service"
+	SERVICE_WORKSPACE = "workspace_service"
+	SERVICE_TOPIC = "topic_service"
+	SERVICE_LANGUAGE = "language_service"
 
 	TELERAM_BOT = "telegram_bot"
+	TELEGRAM_COMMANDS = "telegram_commands"
+	MATRIX_BOT = "matrix_bot"
 )
 
 type App struct{
@@ -65,7 +113,43 @@ type Config struct {
 		User string `envconfig:"MYSQL_USER"`
 		Password string `envconfig:"MYSQL_PASSWORD"`
 	}
+	PostgresConnection struct {
+		ConnectionString string `envconfig:"POSTGRES_CONNECTION"`
+		Host string `envconfig:"POSTGRES_HOST"`
+		Port string `envconfig:"POSTGRES_PORT"`
+		Database string `envconfig:"POSTGRES_DATABASE"`
+		User string `envconfig:"POSTGRES_USER"`
+		Password string `envconfig:"POSTGRES_PASSWORD"`
+	}
+	SqlitePath string `envconfig:"SQLITE_PATH" default:"reservations.db"`
+	SnapshotPath string `envconfig:"SNAPSHOT_PATH"`
+	SnapshotInterval time.Duration `envconfig:"SNAPSHOT_INTERVAL" default:"5m"`
 	TimeZone string `envconfig:"TZ"`
+	RequestTimeout time.Duration `envconfig:"REQUEST_TIMEOUT" default:"10s"`
+	HttpAddr string `envconfig:"HTTP_ADDR"`
+	PublicUrl string `envconfig:"PUBLIC_URL"`
+	CalendarSecret string `envconfig:"CALENDAR_SECRET"`
+	SlackSigningSecret string `envconfig:"SLACK_SIGNING_SECRET"`
+	Discord struct {
+		PublicKey string `envconfig:"DISCORD_PUBLIC_KEY"`
+		ApplicationId string `envconfig:"DISCORD_APPLICATION_ID"`
+		BotToken string `envconfig:"DISCORD_BOT_TOKEN"`
+		ApiUrl string `envconfig:"DISCORD_API_URL" default:"https://discord.com/api/v10"`
+	}
+	Matrix struct {
+		Homeserver string `envconfig:"MATRIX_HOMESERVER_URL"`
+		AccessToken string `envconfig:"MATRIX_ACCESS_TOKEN"`
+		UserId string `envconfig:"MATRIX_USER_ID"`
+	}
+	Smtp struct {
+		Addr string `envconfig:"SMTP_ADDR"`
+		From string `envconfig:"SMTP_FROM"`
+		User string `envconfig:"SMTP_USER"`
+		Password string `envconfig:"SMTP_PASSWORD"`
+	}
+	AdminTelegramIds []int64 `envconfig:"ADMIN_TELEGRAM_IDS"`
+	WebhookMaxAttempts int `envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"5"`
+	WebhookBackoff time.Duration `envconfig:"WEBHOOK_BACKOFF" default:"1s"`
 }
 
 func (app *App) Resolve(dependency string) any {
@@ -88,6 +172,52 @@ func Bootstrap() *App {
 	return app
 }
 
+// BootstrapStorage wires the stores only, for tools working with the data without running the bot
+func BootstrapStorage() *App {
+	app := &App{
+		Log: log.Default(),
+		container: make(map[string]any),
+	}
+	app.loadConfig()
+	app.registerClock()
+	app.registerStores()
+
+	return app
+}
+
+// BootstrapServices wires the stores and the application services, for tools acting on behalf of users
+func BootstrapServices() *App {
+	app := BootstrapStorage()
+	app.registerServices()
+
+	return app
+}
+
+func (app *App) TransferStores() transfer.Stores {
+	return transfer.Stores{
+		Users: app.usersStore(),
+		TelegramUsers: app.tgUsersStore(),
+		Workspaces: app.Resolve(STORE_WORKSPACES).(workspacesPort.WorkspacesRepository),
+		Subjects: app.subjectsStore(),
+		Reservations: app.reservationsStore(),
+	}
+}
+
+// DeliverWebhooks delivers the events queued by tools which do not Run the app
+func (app *App) DeliverWebhooks(ctx context.Context) {
+	app.Resolve(WEBHOOK_DISPATCHER).(*webhooks.Dispatcher).Drain(logging.WithLogger(ctx, app.Log))
+}
+
+// SaveSnapshot saves the in-memory stores, when snapshots are configured
+func (app *App) SaveSnapshot() error {
+	snapshotter, ok := app.container[SNAPSHOTTER].(*inmemory.Snapshotter)
+	if !ok {
+		return nil
+	}
+
+	return snapshotter.Save()
+}
+
 func (app *App) usersStore() users.UsersRepository {
 	return app.Resolve(STORE_USERS).(users.UsersRepository)
 }
@@ -108,6 +238,10 @@ func (app *App) reservationsReadStore() reservations.ReservationsReadRepository
 	return app.Resolve(STORE_READ_RESERVATIONS).(reservations.ReservationsReadRepository)
 }
 
+func (app *App) unitOfWork() reservations.UnitOfWork {
+	return app.Resolve(UNIT_OF_WORK).(reservations.UnitOfWork)
+}
+
 func (app *App) loadConfig() {
 	cfg := Config{}
 	err := envconfig.Process("", &cfg)
@@ -119,56 +253,298 @@ func (app *App) loadConfig() {
 }
 
 func (app *App) registerDependencies() {
-	var clock ports.Clock
-
-	clock = system.SystemClock{}
-	if app.Config.Clock == "cache" {
-		clock = cache.NewClock(app.Config.CacheClockPath)
-	} 
-	app.container[CLOCK] = clock
-
+	app.registerClock()
 	app.registerStores()
 	app.registerServices()
 
+	if app.Config.Matrix.Homeserver != "" {
+		app.container[MATRIX_BOT] = app.matrixBot()
+	}
+
+	// the bot is optional for deployments serving Slack, Discord or Matrix only
+	if app.Config.TelegramApi.Token == "" {
+		return
+	}
 	tgBot := app.telegramBot()
 
 	app.container[TELERAM_BOT] = tgBot
+	app.Resolve(TOPIC_NOTIFIER).(*telegram.TopicNotifier).Use(tgBot)
 	app.registerTelegramBotHandlers()
 }
 
+func (app *App) registerClock() {
+	var clock ports.Clock
+
+	clock = system.SystemClock{}
+	if app.Config.Clock == "cache" {
+		clock = cache.NewClock(app.Config.CacheClockPath)
+	} 
+	app.container[CLOCK] = clock
+}
+
 func (app *App) registerStores() {
 	var subjectsStore reservations.SubjectsRepository
 	var reservationsStore reservations.ReservationsRepository
 	var reservationsReadStore reservations.ReservationsReadRepository
 	var usersStore users.UsersRepository
+	var tokensStore users.TokensRepository
+	var accessTokensStore users.AccessTokensRepository
+	var userMerger users.UserMerger
 	var tgUsersStore telegram.TelegramUsersRepository
+	var slackUsersStore users.ChatUsersRepository
+	var discordUsersStore users.ChatUsersRepository
+	var matrixUsersStore users.ChatUsersRepository
+	var unitOfWork reservations.UnitOfWork
+	var webhooksStore webhooksPort.WebhooksRepository
+	var deadLettersStore webhooksPort.DeadLettersRepository
+	var workspacesStore workspacesPort.WorkspacesRepository
+	var topicsStore telegram.TopicsRepository
+	var chatsStore telegram.ChatsRepository
 
 	subjectsStore = inmemory.NewSubjectsStore()
 	usersStore = inmemory.NewUsersStore()
+	tokensStore = inmemory.NewTokensStore()
+	accessTokensStore = inmemory.NewAccessTokensStore()
 	tgUsersStore = inmemory.NewTelegramUsersStore(usersStore)
+	slackUsersStore = inmemory.NewChatUsersStore(slack.Platform, usersStore)
+	discordUsersStore = inmemory.NewChatUsersStore(discord.Platform, usersStore)
+	matrixUsersStore = inmemory.NewChatUsersStore(matrix.Platform, usersStore)
 	reservationsStore = inmemory.NewReservationStore()
 	reservationsReadStore = inmemory.NewReservationReadStore(
 		reservationsStore.(*inmemory.ReservationsStore),
 		usersStore.(*inmemory.UsersStore), 
 		subjectsStore.(*inmemory.SubjectsStore),
 	)
-
-	if app.Config.PersistenceDriver == "mysql" {
+	unitOfWork = inmemory.NewUnitOfWork(reservationsStore.(*inmemory.ReservationsStore))
+	webhooksStore = inmemory.NewWebhooksStore()
+	deadLettersStore = inmemory.NewDeadLettersStore()
+	workspacesStore = inmemory.NewWorkspacesStore()
+	topicsStore = inmemory.NewTopicsStore()
+	chatsStore = inmemory.NewChatsStore()
+
+	switch app.Config.PersistenceDriver {
+	case "mysql":
 		db := app.ConnectDB()
-		app.Migrate(db)
+		app.Migrate(db, "mysql", "/migrations")
 
 		subjectsStore = mysql.NewSubjectsRepository(db)
 		usersStore = mysql.NewUsersRepository(db)
+		tokensStore = mysql.NewTokensRepository(db)
+		accessTokensStore = mysql.NewAccessTokensRepository(db)
 		tgUsersStore = mysql.NewTelegramUsersRepository(db)
+		slackUsersStore = mysql.NewChatUsersRepository(db, slack.Platform)
+		discordUsersStore = mysql.NewChatUsersRepository(db, discord.Platform)
+		matrixUsersStore = mysql.NewChatUsersRepository(db, matrix.Platform)
 		reservationsStore = mysql.NewReservationsRepository(db)
 		reservationsReadStore = mysql.NewReservationsReadRepository(db)
+		unitOfWork = mysql.NewUnitOfWork(db)
+		webhooksStore = mysql.NewWebhooksRepository(db)
+		deadLettersStore = mysql.NewDeadLettersRepository(db)
+		workspacesStore = mysql.NewWorkspacesRepository(db)
+		topicsStore = mysql.NewTopicsRepository(db)
+		chatsStore = mysql.NewChatsRepository(db)
+		userMerger = mysql.NewUserMerger(db)
+	case "postgres":
+		db := app.ConnectPostgres()
+		app.Migrate(db, "postgres", "/migrations/postgres")
+
+		subjectsStore = postgres.NewSubjectsRepository(db)
+		usersStore = postgres.NewUsersRepository(db)
+		tokensStore = postgres.NewTokensRepository(db)
+		accessTokensStore = postgres.NewAccessTokensRepository(db)
+		tgUsersStore = postgres.NewTelegramUsersRepository(db)
+		slackUsersStore = postgres.NewChatUsersRepository(db, slack.Platform)
+		discordUsersStore = postgres.NewChatUsersRepository(db, discord.Platform)
+		matrixUsersStore = postgres.NewChatUsersRepository(db, matrix.Platform)
+		reservationsStore = postgres.NewReservationsRepository(db)
+		reservationsReadStore = postgres.NewReservationsReadRepository(db)
+		unitOfWork = postgres.NewUnitOfWork(db)
+		webhooksStore = postgres.NewWebhooksRepository(db)
+		deadLettersStore = postgres.NewDeadLettersRepository(db)
+		workspacesStore = postgres.NewWorkspacesRepository(db)
+		topicsStore = postgres.NewTopicsRepository(db)
+		chatsStore = postgres.NewChatsRepository(db)
+		userMerger = postgres.NewUserMerger(db)
+	case "sqlite":
+		db, err := sqlite.Open(app.Config.SqlitePath)
+		if err != nil {
+			app.Error(err)
+		}
+		app.Migrate(db, "sqlite3", "/migrations/sqlite")
+
+		subjectsStore = sqlite.NewSubjectsRepository(db)
+		usersStore = sqlite.NewUsersRepository(db)
+		tokensStore = sqlite.NewTokensRepository(db)
+		accessTokensStore = sqlite.NewAccessTokensRepository(db)
+		tgUsersStore = sqlite.NewTelegramUsersRepository(db)
+		slackUsersStore = sqlite.NewChatUsersRepository(db, slack.Platform)
+		discordUsersStore = sqlite.NewChatUsersRepository(db, discord.Platform)
+		matrixUsersStore = sqlite.NewChatUsersRepository(db, matrix.Platform)
+		reservationsStore = sqlite.NewReservationsRepository(db)
+		reservationsReadStore = sqlite.NewReservationsReadRepository(db)
+		unitOfWork = sqlite.NewUnitOfWork(db)
+		webhooksStore = sqlite.NewWebhooksRepository(db)
+		deadLettersStore = sqlite.NewDeadLettersRepository(db)
+		workspacesStore = sqlite.NewWorkspacesRepository(db)
+		topicsStore = sqlite.NewTopicsRepository(db)
+		chatsStore = sqlite.NewChatsRepository(db)
+		userMerger = sqlite.NewUserMerger(db)
+	default:
+		userMerger = inmemory.NewUserMerger(
+			usersStore.(*inmemory.UsersStore),
+			reservationsStore.(*inmemory.ReservationsStore),
+			tgUsersStore.(*inmemory.TelegramUsersStore),
+			slackUsersStore.(*inmemory.ChatUsersStore),
+			discordUsersStore.(*inmemory.ChatUsersStore),
+			matrixUsersStore.(*inmemory.ChatUsersStore),
+			accessTokensStore.(*inmemory.AccessTokensStore),
+			tokensStore.(*inmemory.TokensStore),
+			workspacesStore.(*inmemory.WorkspacesStore),
+		)
+		if app.Config.SnapshotPath != "" {
+			snapshotter := inmemory.NewSnapshotter(
+				app.Config.SnapshotPath,
+				subjectsStore.(*inmemory.SubjectsStore),
+				usersStore.(*inmemory.UsersStore),
+				tgUsersStore.(*inmemory.TelegramUsersStore),
+				reservationsStore.(*inmemory.ReservationsStore),
+			)
+			snapshotter.Include("tokens", tokensStore.(*inmemory.TokensStore))
+			snapshotter.Include("access_tokens", accessTokensStore.(*inmemory.AccessTokensStore))
+			snapshotter.Include("slack_users", slackUsersStore.(*inmemory.ChatUsersStore))
+			snapshotter.Include("discord_users", discordUsersStore.(*inmemory.ChatUsersStore))
+			snapshotter.Include("matrix_users", matrixUsersStore.(*inmemory.ChatUsersStore))
+			snapshotter.Include("webhooks", webhooksStore.(*inmemory.WebhooksStore))
+			snapshotter.Include("dead_letters", deadLettersStore.(*inmemory.DeadLettersStore))
+			snapshotter.Include("workspaces", workspacesStore.(*inmemory.WorkspacesStore))
+			snapshotter.Include("topics", topicsStore.(*inmemory.TopicsStore))
+			snapshotter.Include("chats", chatsStore.(*inmemory.ChatsStore))
+			if err := snapshotter.Restore(); err != nil {
+				app.Error(err)
+			}
+			app.container[SNAPSHOTTER] = snapshotter
+		}
 	}
 
 	app.container[STORE_SUBJECTS] = subjectsStore
 	app.container[STORE_USERS] = usersStore
+	app.container[STORE_TOKENS] = tokensStore
+	app.container[STORE_ACCESS_TOKENS] = accessTokensStore
+	app.container[USER_MERGER] = userMerger
 	app.container[STORE_TG_USERS] = tgUsersStore
+	app.container[STORE_SLACK_USERS] = slackUsersStore
+	app.container[STORE_DISCORD_USERS] = discordUsersStore
+	app.container[STORE_MATRIX_USERS] = matrixUsersStore
 	app.container[STORE_RESERVATIONS] = reservationsStore
 	app.container[STORE_READ_RESERVATIONS] = reservationsReadStore
+	app.container[UNIT_OF_WORK] = unitOfWork
+	app.container[STORE_WEBHOOKS] = webhooksStore
+	app.container[STORE_DEAD_LETTERS] = deadLettersStore
+	app.container[STORE_WORKSPACES] = workspacesStore
+	app.container[STORE_TOPICS] = topicsStore
+	app.container[STORE_CHATS] = chatsStore
+}
+
+// Run serves the bot until ctx is done, along with the background jobs
+func (app *App) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	if snapshotter, ok := app.container[SNAPSHOTTER].(*inmemory.Snapshotter); ok {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := snapshotter.Run(logging.WithLogger(ctx, app.Log), app.Config.SnapshotInterval); err != nil {
+				app.Log.Print(err)
+			}
+		}()
+	}
+
+	dispatcher := app.Resolve(WEBHOOK_DISPATCHER).(*webhooks.Dispatcher)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		dispatcher.Run(logging.WithLogger(ctx, app.Log))
+	}()
+
+	topicNotifier := app.Resolve(TOPIC_NOTIFIER).(*telegram.TopicNotifier)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		topicNotifier.Run(logging.WithLogger(ctx, app.Log))
+	}()
+
+	if app.Config.Discord.ApplicationId != "" && app.Config.Discord.BotToken != "" {
+		app.registerDiscordCommands(ctx)
+	}
+
+	if matrixBot, ok := app.container[MATRIX_BOT].(*matrix.Bot); ok {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			matrixBot.Start(logging.WithLogger(ctx, app.Log))
+		}()
+	}
+
+	if app.Config.HttpAddr != "" {
+		server := &http.Server{
+			Addr: app.Config.HttpAddr,
+			Handler: app.httpHandler(),
+			BaseContext: func(net.Listener) context.Context { return logging.WithLogger(context.Background(), app.Log) },
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), app.Config.RequestTimeout)
+			defer cancel()
+			server.Shutdown(shutdownCtx)
+		}()
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				app.Error(err)
+			}
+		}()
+	}
+
+	if b, ok := app.container[TELERAM_BOT].(*bot.Bot); ok {
+		app.registerTelegramCommands(ctx, b)
+		b.Start(ctx)
+	} else {
+		<-ctx.Done()
+	}
+	wg.Wait()
+}
+
+func (app *App) httpHandler() http.Handler {
+	mux := http.NewServeMux()
+	web.RegisterAccountHandlers(mux, app.Resolve(SERVICE_ACCOUNT).(*application.AccountService))
+	web.RegisterApiHandlers(
+		mux,
+		app.Resolve(SERVICE_ACCESS_TOKEN).(*application.AccessTokenService),
+		app.Resolve(SERVICE_SUBJECT).(*application.SubjectService),
+		app.Resolve(SERVICE_RESERVATION).(*application.ReservationService),
+		app.Resolve(CLOCK).(ports.Clock),
+	)
+	if app.Config.CalendarSecret != "" {
+		web.RegisterCalendarHandlers(mux, app.Resolve(SERVICE_CALENDAR).(*application.CalendarService), app.Resolve(CLOCK).(ports.Clock))
+	}
+	if app.Config.SlackSigningSecret != "" {
+		app.slackHandler().Register(mux)
+	}
+	if app.Config.Discord.PublicKey != "" {
+		app.discordHandler().Register(mux)
+	}
+
+	return mux
+}
+
+// feedLinks is nil unless calendar feeds are served publicly
+func (app *App) feedLinks() telegram.FeedLinks {
+	if app.Config.PublicUrl == "" || app.Config.CalendarSecret == "" {
+		return nil
+	}
+
+	return web.FeedLinks{BaseUrl: app.Config.PublicUrl, Calendars: app.Resolve(SERVICE_CALENDAR).(*application.CalendarService)}
 }
 
 func (app *App) registerServices() {
@@ -177,22 +553,76 @@ func (app *App) registerServices() {
 	reservationsReadStore := app.reservationsReadStore()
 	usersStore := app.usersStore()
 	tgUsersStore := app.tgUsersStore()
+	webhooksStore := app.Resolve(STORE_WEBHOOKS).(webhooksPort.WebhooksRepository)
+	deadLettersStore := app.Resolve(STORE_DEAD_LETTERS).(webhooksPort.DeadLettersRepository)
+
+	options := webhooks.DefaultOptions()
+	options.MaxAttempts = app.Config.WebhookMaxAttempts
+	options.Backoff = app.Config.WebhookBackoff
+	dispatcher := webhooks.NewDispatcher(webhooksStore, deadLettersStore, http.DefaultClient, app.Resolve(CLOCK).(ports.Clock), options)
 
+	subjectService := application.NewSubjectService(subjectsStore, dispatcher)
+	userService := application.NewUserService(usersStore)
+	tgUserService := telegram.NewTelegramUserService(tgUsersStore, *userService)
+	languageService := telegram.NewLanguageService(app.Resolve(STORE_CHATS).(telegram.ChatsRepository), tgUserService)
+	workspaceService := application.NewWorkspaceService(app.Resolve(STORE_WORKSPACES).(workspacesPort.WorkspacesRepository))
+	topicService := telegram.NewTopicService(app.Resolve(STORE_TOPICS).(telegram.TopicsRepository), subjectService, workspaceService)
+	// the notifier starts posting once the telegram bot is registered
+	topicNotifier := telegram.NewTopicNotifier(topicService, userService, languageService, app.Resolve(CLOCK).(ports.Clock), time.Local)
 	reservationService := application.NewReservationService(
 		subjectsStore,
 		reservationsStore,
 		reservationsReadStore,
 		usersStore,
+		app.unitOfWork(),
 		app.Resolve(CLOCK).(ports.Clock),
+		ports.Publishers{dispatcher, topicNotifier},
 	)
-	subjectService := application.NewSubjectService(subjectsStore)
-	userService := application.NewUserService(usersStore)
-	tgUserService := telegram.NewTelegramUserService(tgUsersStore, *userService)
+	slackUserService := application.NewChatUserService(app.Resolve(STORE_SLACK_USERS).(users.ChatUsersRepository), userService)
+	discordUserService := application.NewChatUserService(app.Resolve(STORE_DISCORD_USERS).(users.ChatUsersRepository), userService)
+	matrixUserService := application.NewChatUserService(app.Resolve(STORE_MATRIX_USERS).(users.ChatUsersRepository), userService)
 
 	app.container[SERVICE_RESERVATION] = reservationService
 	app.container[SERVICE_SUBJECT] = subjectService
 	app.container[SERVICE_USER] = userService
+	app.container[SERVICE_ACCESS_TOKEN] = application.NewAccessTokenService(
+		usersStore,
+		app.Resolve(STORE_WORKSPACES).(workspacesPort.WorkspacesRepository),
+		app.Resolve(STORE_ACCESS_TOKENS).(users.AccessTokensRepository),
+		app.Resolve(CLOCK).(ports.Clock),
+	)
+	app.container[SERVICE_ACCOUNT] = application.NewAccountService(
+		usersStore,
+		app.Resolve(STORE_TOKENS).(users.TokensRepository),
+		app.Resolve(USER_MERGER).(users.UserMerger),
+		app.mailer(),
+		app.Resolve(CLOCK).(ports.Clock),
+	)
 	app.container[SERVICE_TELEGRAM_USER] = tgUserService
+	app.container[SERVICE_SLACK_USER] = slackUserService
+	app.container[SERVICE_DISCORD_USER] = discordUserService
+	app.container[SERVICE_MATRIX_USER] = matrixUserService
+	calendarService, err := application.NewCalendarService(reservationsReadStore, subjectsStore, usersStore, app.Config.CalendarSecret)
+	if err != nil {
+		app.Log.Fatal(err)
+	}
+	app.container[SERVICE_CALENDAR] = calendarService
+	app.container[SERVICE_CALENDAR_IMPORT] = application.NewCalendarImportService(subjectsStore, reservationService)
+	app.container[SERVICE_WEBHOOK] = application.NewWebhookService(webhooksStore, deadLettersStore)
+	app.container[SERVICE_WORKSPACE] = workspaceService
+	app.container[SERVICE_TOPIC] = topicService
+	app.container[SERVICE_LANGUAGE] = languageService
+	app.container[WEBHOOK_DISPATCHER] = dispatcher
+	app.container[TOPIC_NOTIFIER] = topicNotifier
+}
+
+// mailer logs the mails unless an SMTP server is configured
+func (app *App) mailer() ports.Mailer {
+	if app.Config.Smtp.Addr == "" {
+		return mail.LogMailer{}
+	}
+
+	return mail.NewSmtpMailer(app.Config.Smtp.Addr, app.Config.Smtp.From, app.Config.Smtp.User, app.Config.Smtp.Password)
 }
 
 func (app *App) telegramBot() *bot.Bot {
@@ -211,35 +641,243 @@ func (app *App) telegramBot() *bot.Bot {
 	return b
 }
 
+// slackHandler serves the slash commands and the interactive messages of the Slack app
+func (app *App) slackHandler() *slack.Handler {
+	handler := slack.NewHandler(app.Config.SlackSigningSecret, http.DefaultClient, app.Config.RequestTimeout)
+	slack.NewAdapter(
+		app.Resolve(SERVICE_SUBJECT).(*application.SubjectService),
+		app.Resolve(SERVICE_RESERVATION).(*application.ReservationService),
+		app.Resolve(SERVICE_USER).(*application.UserService),
+		app.Resolve(SERVICE_SLACK_USER).(*application.ChatUserService),
+		app.Resolve(SERVICE_WORKSPACE).(*application.WorkspaceService),
+		app.Resolve(CLOCK).(ports.Clock),
+	).Register(handler)
+
+	return handler
+}
+
+// discordHandler serves the slash commands and the buttons of the Discord application
+func (app *App) discordHandler() *discord.Handler {
+	publicKey, err := discord.ParsePublicKey(app.Config.Discord.PublicKey)
+	if err != nil {
+		app.Error(err)
+	}
+	handler := discord.NewHandler(publicKey, app.Config.RequestTimeout)
+	discord.NewAdapter(
+		app.Resolve(SERVICE_SUBJECT).(*application.SubjectService),
+		app.Resolve(SERVICE_RESERVATION).(*application.ReservationService),
+		app.Resolve(SERVICE_USER).(*application.UserService),
+		app.Resolve(SERVICE_DISCORD_USER).(*application.ChatUserService),
+		app.Resolve(SERVICE_WORKSPACE).(*application.WorkspaceService),
+		app.Resolve(CLOCK).(ports.Clock),
+	).Register(handler)
+
+	return handler
+}
+
+// registerDiscordCommands keeps the slash commands shown by Discord in line with the ones the app handles
+func (app *App) registerDiscordCommands(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, app.Config.RequestTimeout)
+	defer cancel()
+
+	cfg := app.Config.Discord
+	if err := discord.RegisterCommands(ctx, http.DefaultClient, cfg.ApiUrl, cfg.ApplicationId, cfg.BotToken, discord.Commands()); err != nil {
+		app.Log.Printf("Could not register discord commands: %s", err)
+	}
+}
+
+// matrixBot answers the commands posted in the rooms the bot account is invited to
+func (app *App) matrixBot() *matrix.Bot {
+	cfg := app.Config.Matrix
+	b := matrix.NewBot(matrix.NewClient(http.DefaultClient, cfg.Homeserver, cfg.AccessToken), cfg.UserId, app.Config.RequestTimeout)
+	matrix.NewAdapter(
+		app.Resolve(SERVICE_SUBJECT).(*application.SubjectService),
+		app.Resolve(SERVICE_RESERVATION).(*application.ReservationService),
+		app.Resolve(SERVICE_USER).(*application.UserService),
+		app.Resolve(SERVICE_MATRIX_USER).(*application.ChatUserService),
+		app.Resolve(SERVICE_WORKSPACE).(*application.WorkspaceService),
+		app.Resolve(CLOCK).(ports.Clock),
+	).Register(b)
+
+	return b
+}
+
 func (app *App) registerTelegramBotHandlers() {
 	b := app.Resolve(TELERAM_BOT).(*bot.Bot)
+	subjectChooser := telegram.NewSubjectChooser()
 	adapter := telegram.NewAdapter(
 		app.Resolve(SERVICE_SUBJECT).(*application.SubjectService),
+		subjectChooser,
+		app.Resolve(SERVICE_WORKSPACE).(*application.WorkspaceService),
+		app.Resolve(SERVICE_TOPIC).(*telegram.TopicService),
 		app.Resolve(SERVICE_RESERVATION).(*application.ReservationService),
 		app.Resolve(SERVICE_USER).(*application.UserService),
 		app.Resolve(SERVICE_TELEGRAM_USER).(*telegram.TelegramUserService),
+		app.Resolve(SERVICE_CALENDAR).(*application.CalendarService),
+		app.Resolve(SERVICE_CALENDAR_IMPORT).(*application.CalendarImportService),
+		app.feedLinks(),
 		app.Resolve(CLOCK).(ports.Clock),
+		// time.Local follows the TZ setting
+		time.Local,
 		app.Log,
 	)
 
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/add_subject", bot.MatchTypePrefix, botHandlerFunc(adapter.AddSubjectHandler))
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/add_tags", bot.MatchTypePrefix, botHandlerFunc(adapter.AddSubjectTagsHandler))
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/list", bot.MatchTypeExact, botHandlerFunc(adapter.ListSubjectsHandler))
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/tags", bot.MatchTypePrefix, botHandlerFunc(adapter.ListSubjectTagsHandler))
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/reserved", bot.MatchTypePrefix, botHandlerFunc(adapter.ActiveReservationsHandler))
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/reserve", bot.MatchTypePrefix, botHandlerFunc(adapter.CreateReservationHandler))
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/remove", bot.MatchTypePrefix, botHandlerFunc(adapter.RemoveReservationHandler))
+	accountsAdapter := telegram.NewAccountsAdapter(app.Resolve(SERVICE_ACCOUNT).(*application.AccountService), app.Resolve(SERVICE_TELEGRAM_USER).(*telegram.TelegramUserService))
+	tokensAdapter := telegram.NewTokensAdapter(
+		app.Resolve(SERVICE_ACCESS_TOKEN).(*application.AccessTokenService),
+		app.Resolve(SERVICE_WORKSPACE).(*application.WorkspaceService),
+	)
+	webhooksAdapter := telegram.NewWebhooksAdapter(app.Resolve(SERVICE_WEBHOOK).(*application.WebhookService))
+	workspacesAdapter := telegram.NewWorkspacesAdapter(
+		app.Resolve(SERVICE_WORKSPACE).(*application.WorkspaceService),
+		app.Resolve(SERVICE_SUBJECT).(*application.SubjectService),
+		app.Resolve(SERVICE_TELEGRAM_USER).(*telegram.TelegramUserService),
+		subjectChooser,
+		app.telegramAccess,
+	)
+	topicsAdapter := telegram.NewTopicsAdapter(
+		app.Resolve(SERVICE_TOPIC).(*telegram.TopicService),
+		app.Resolve(SERVICE_WORKSPACE).(*application.WorkspaceService),
+		app.Resolve(SERVICE_SUBJECT).(*application.SubjectService),
+		subjectChooser,
+	)
+	languagesAdapter := telegram.NewLanguagesAdapter(
+		app.Resolve(SERVICE_LANGUAGE).(*telegram.LanguageService),
+		app.Resolve(SERVICE_TELEGRAM_USER).(*telegram.TelegramUserService),
+		app.Resolve(SERVICE_USER).(*application.UserService),
+	)
+
+	// handlers are matched in this order, /reserved has to come before /reserve, /merge_code before /merge
+	// and /workspace_admin_remove before /workspace_admin
+	registry := telegram.NewRegistry(app.telegramAccess)
+	registry.Add(
+		telegram.Command{Name: "start", Match: bot.MatchTypePrefix, Handler: registry.StartHandler},
+		telegram.Command{Name: "help", Match: bot.MatchTypeExact, Handler: registry.HelpHandler},
+		telegram.Command{Name: "add_subject", Match: bot.MatchTypePrefix, Handler: adapter.AddSubjectHandler},
+		telegram.Command{Name: "add_tags", Match: bot.MatchTypePrefix, Handler: adapter.AddSubjectTagsHandler},
+		telegram.Command{Name: "list", Match: bot.MatchTypeExact, Handler: adapter.ListSubjectsHandler},
+		telegram.Command{Name: "tags", Match: bot.MatchTypePrefix, Handler: adapter.ListSubjectTagsHandler},
+		telegram.Command{Name: "add_alias", Match: bot.MatchTypePrefix, Handler: adapter.AddAliasHandler},
+		telegram.Command{Name: "aliases", Match: bot.MatchTypePrefix, Handler: adapter.ListAliasesHandler},
+		telegram.Command{Name: "add_component", Match: bot.MatchTypePrefix, Handler: adapter.AddComponentHandler},
+		telegram.Command{Name: "components", Match: bot.MatchTypePrefix, Handler: adapter.ListComponentsHandler},
+		telegram.Command{Name: "calendar", Match: bot.MatchTypePrefix, Handler: adapter.SubjectCalendarHandler},
+		telegram.Command{Name: "my_calendar", Match: bot.MatchTypeExact, Handler: adapter.UserCalendarHandler},
+		telegram.Command{Name: "import_confirm", Match: bot.MatchTypeExact, Handler: adapter.ConfirmImportHandler},
+		telegram.Command{Name: "link_account", Match: bot.MatchTypeExact, Handler: accountsAdapter.LinkAccountHandler},
+		telegram.Command{Name: "merge_code", Match: bot.MatchTypeExact, Handler: accountsAdapter.MergeCodeHandler},
+		telegram.Command{Name: "merge", Match: bot.MatchTypePrefix, Handler: accountsAdapter.MergeHandler},
+		telegram.Command{Name: "service_account", Match: bot.MatchTypePrefix, Access: telegram.AccessAdmins, Handler: tokensAdapter.CreateServiceAccountHandler},
+		telegram.Command{Name: "tokens", Match: bot.MatchTypeExact, Access: telegram.AccessAdmins, Handler: tokensAdapter.ListTokensHandler},
+		telegram.Command{Name: "token_revoke", Match: bot.MatchTypePrefix, Access: telegram.AccessAdmins, Handler: tokensAdapter.RevokeTokenHandler},
+		telegram.Command{Name: "webhook_add", Match: bot.MatchTypePrefix, Access: telegram.AccessAdmins, Handler: webhooksAdapter.AddWebhookHandler},
+		telegram.Command{Name: "webhook_remove", Match: bot.MatchTypePrefix, Access: telegram.AccessAdmins, Handler: webhooksAdapter.RemoveWebhookHandler},
+		telegram.Command{Name: "webhooks", Match: bot.MatchTypeExact, Access: telegram.AccessAdmins, Handler: webhooksAdapter.ListWebhooksHandler},
+		telegram.Command{Name: "dead_letters", Match: bot.MatchTypeExact, Access: telegram.AccessAdmins, Handler: webhooksAdapter.DeadLettersHandler},
+		telegram.Command{Name: "workspace", Match: bot.MatchTypeExact, Handler: workspacesAdapter.CurrentWorkspaceHandler},
+		telegram.Command{Name: "workspaces", Match: bot.MatchTypeExact, Access: telegram.AccessAdmins, Handler: workspacesAdapter.ListWorkspacesHandler},
+		telegram.Command{Name: "workspace_create", Match: bot.MatchTypePrefix, Access: telegram.AccessAdmins, Handler: workspacesAdapter.CreateWorkspaceHandler},
+		telegram.Command{Name: "workspace_bind", Match: bot.MatchTypePrefix, Access: telegram.AccessChatAdmins, Handler: workspacesAdapter.BindWorkspaceHandler},
+		telegram.Command{Name: "workspace_admin_remove", Match: bot.MatchTypePrefix, Access: telegram.AccessWorkspaceAdmins, Handler: workspacesAdapter.RemoveWorkspaceAdminHandler},
+		telegram.Command{Name: "workspace_admin", Match: bot.MatchTypePrefix, Access: telegram.AccessWorkspaceAdmins, Handler: workspacesAdapter.AddWorkspaceAdminHandler},
+		telegram.Command{Name: "share", Match: bot.MatchTypePrefix, Access: telegram.AccessWorkspaceAdmins, Handler: workspacesAdapter.ShareSubjectHandler},
+		telegram.Command{Name: "unshare", Match: bot.MatchTypePrefix, Access: telegram.AccessWorkspaceAdmins, Handler: workspacesAdapter.UnshareSubjectHandler},
+		telegram.Command{Name: "topic", Match: bot.MatchTypeExact, Handler: topicsAdapter.TopicHandler},
+		telegram.Command{Name: "topic_tag", Match: bot.MatchTypePrefix, Access: telegram.AccessChatAdmins, Handler: topicsAdapter.BindTopicTagHandler},
+		telegram.Command{Name: "topic_subjects", Match: bot.MatchTypePrefix, Access: telegram.AccessChatAdmins, Handler: topicsAdapter.BindTopicSubjectsHandler},
+		telegram.Command{Name: "topic_unbind", Match: bot.MatchTypeExact, Access: telegram.AccessChatAdmins, Handler: topicsAdapter.UnbindTopicHandler},
+		telegram.Command{Name: "timezone", Match: bot.MatchTypePrefix, Handler: adapter.TimeZoneHandler},
+		telegram.Command{Name: "language", Match: bot.MatchTypePrefix, Handler: languagesAdapter.LanguageHandler},
+		telegram.Command{Name: "chat_language", Match: bot.MatchTypePrefix, Access: telegram.AccessChatAdmins, Handler: languagesAdapter.ChatLanguageHandler},
+		telegram.Command{Name: "reserved", Match: bot.MatchTypePrefix, Handler: adapter.ActiveReservationsHandler},
+		telegram.Command{Name: "reserve", Match: bot.MatchTypePrefix, Handler: adapter.CreateReservationHandler},
+		telegram.Command{Name: "remove", Match: bot.MatchTypePrefix, Handler: adapter.RemoveReservationHandler},
+	)
+	app.container[TELEGRAM_COMMANDS] = registry
+
+	for _, command := range registry.Commands() {
+		b.RegisterHandler(bot.HandlerTypeMessageText, "/" + command.Name, command.Match, app.botHandlerFunc(registry.Restrict(command)))
+	}
+	b.RegisterHandlerMatchFunc(telegram.IsCalendarUpload, app.botHandlerFunc(adapter.ImportCalendarHandler))
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, telegram.SubjectChoiceData, bot.MatchTypePrefix, subjectChooser.ChoiceHandler)
 }
 
-type UpdateHandler func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error)
+// telegramAccess tells the admins of the bot listed in ADMIN_TELEGRAM_IDS, the admins of the workspace of the chat
+// and the administrators of group chats from everyone else
+func (app *App) telegramAccess(ctx context.Context, b *bot.Bot, update *models.Update) (telegram.Access, error) {
+	if update.Message.From == nil {
+		return telegram.AccessEveryone, nil
+	}
+	if slices.Contains(app.Config.AdminTelegramIds, update.Message.From.ID) {
+		return telegram.AccessAdmins, nil
+	}
+	workspaceAdmin, err := app.telegramWorkspaceAdmin(ctx, update)
+	if err != nil {
+		return telegram.AccessEveryone, err
+	}
+	if workspaceAdmin {
+		return telegram.AccessWorkspaceAdmins, nil
+	}
+	if update.Message.Chat.Type != models.ChatTypeGroup && update.Message.Chat.Type != models.ChatTypeSupergroup {
+		return telegram.AccessEveryone, nil
+	}
 
-func botHandlerFunc(h UpdateHandler) bot.HandlerFunc {
-	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
-		text, err := h(ctx, b, update)
+	member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{ChatID: update.Message.Chat.ID, UserID: update.Message.From.ID})
+	if err != nil {
+		return telegram.AccessEveryone, err
+	}
+	if member.Type == models.ChatMemberTypeOwner || member.Type == models.ChatMemberTypeAdministrator {
+		return telegram.AccessChatAdmins, nil
+	}
 
-		if err != nil {
-			log.Print(err)
-			text = "An error occured"
+	return telegram.AccessEveryone, nil
+}
+
+// telegramWorkspaceAdmin tells whether the sender is an admin of the workspace of the chat, senders who never used the bot are not
+func (app *App) telegramWorkspaceAdmin(ctx context.Context, update *models.Update) (bool, error) {
+	user, err := app.Resolve(SERVICE_TELEGRAM_USER).(*telegram.TelegramUserService).Get(ctx, update.Message.From.ID)
+	if err != nil {
+		return false, nil
+	}
+	workspaceService := app.Resolve(SERVICE_WORKSPACE).(*application.WorkspaceService)
+	workspaceId, err := workspaceService.ForChat(ctx, telegram.ChatKey(update.Message.Chat.ID))
+	if err != nil {
+		return false, err
+	}
+
+	return workspaceService.IsAdmin(ctx, workspaceId, user.Id)
+}
+
+// registerTelegramCommands keeps the command menus of telegram clients in line with the registry
+func (app *App) registerTelegramCommands(ctx context.Context, b *bot.Bot) {
+	ctx, cancel := context.WithTimeout(ctx, app.Config.RequestTimeout)
+	defer cancel()
+
+	for _, menu := range app.Resolve(TELEGRAM_COMMANDS).(*telegram.Registry).Menus(app.Config.AdminTelegramIds) {
+		if _, err := b.SetMyCommands(ctx, &menu); err != nil {
+			app.Log.Printf("Could not register telegram commands: %s", err)
+			return
+		}
+	}
+}
+
+func (app *App) botHandlerFunc(h telegram.UpdateHandler) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		logger := log.New(app.Log.Writer(), fmt.Sprintf("%s[update %d] ", app.Log.Prefix(), update.ID), app.Log.Flags())
+		ctx = logging.WithLogger(ctx, logger)
+		requestCtx, cancel := context.WithTimeout(ctx, app.Config.RequestTimeout)
+		defer cancel()
+		requestCtx = telegram.FromTopic(requestCtx, update.Message.Chat.ID, update.Message.MessageThreadID)
+		requestCtx = i18n.WithPrinter(requestCtx, app.Resolve(SERVICE_LANGUAGE).(*telegram.LanguageService).Printer(requestCtx, update))
+
+		text, err := h(requestCtx, b, update)
+
+		var translatable *i18n.Error
+		if errors.As(err, &translatable) {
+			text = i18n.FromContext(requestCtx).Error(err)
+		} else if err != nil {
+			logger.Print(err)
+			text = i18n.FromContext(requestCtx).Sprintf("error.internal")
 		}
 
 		if text != "" {
@@ -284,14 +922,44 @@ func (app *App) ConnectDB() *sql.DB{
 	return db
 }
 
-func (app *App) Migrate(db *sql.DB) {
-	goose.SetDialect("mysql")
+func (app *App) ConnectPostgres() *sql.DB {
+	connectionString := app.Config.PostgresConnection.ConnectionString
+
+	if connectionString == "" {
+		dsn := url.URL{
+			Scheme: "postgres",
+			User: url.UserPassword(app.Config.PostgresConnection.User, app.Config.PostgresConnection.Password),
+			Host: fmt.Sprintf("%s:%s", app.Config.PostgresConnection.Host, app.Config.PostgresConnection.Port),
+			Path: app.Config.PostgresConnection.Database,
+			RawQuery: "sslmode=disable",
+		}
+
+		connectionString = dsn.String()
+	}
+
+	db, err := sql.Open("pgx", connectionString)
+	if err != nil {
+		app.Error(err)
+	}
+	err = db.Ping()
+	if err != nil {
+		app.Error(err)
+	}
+
+	return db
+}
+
+func (app *App) Migrate(db *sql.DB, dialect string, directory string) {
+	err := goose.SetDialect(dialect)
+	if err != nil {
+		app.Error(err)
+	}
 	wd, err := os.Getwd()
 	if err != nil {
 		app.Error(err)
 	}
 
-	err = goose.Up(db, wd + "/migrations")
+	err = goose.Up(db, wd + directory)
 	if err != nil {
 		app.Error(err)
 	}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SneedusSnake/Reservations"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/ical"
+)
+
+const usage = `Usage:
+  ical_import -in calendar.ics (-user id | -token token) [-workspace name] [-dry-run]
+
+Reserves a subject for every event of the calendar on behalf of the user. Subjects are matched
+by the event location, then by the summary, among the subjects of the workspace. Use -dry-run to preview the reservations first.
+With -token the reservations are made on behalf of the user the access token was issued to,
+it must grant reservations:write. The token is read from RESERVATIONS_TOKEN when the flag is omitted.
+Tokens only import into their own workspace, which -workspace defaults to.
+`
+
+func main() {
+	flags := flag.NewFlagSet("ical_import", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	in := flags.String("in", "", "the .ics file to import")
+	userId := flags.Int("user", 0, "id of the user making the reservations")
+	token := flags.String("token", os.Getenv("RESERVATIONS_TOKEN"), "access token of the user making the reservations")
+	workspace := flags.String("workspace", "", "name of the workspace the subjects are looked up in, the default one for -user")
+	dryRun := flags.Bool("dry-run", false, "report what would be reserved without reserving anything")
+	flags.Parse(os.Args[1:])
+
+	if err := importCalendar(*in, *userId, *token, *workspace, *dryRun); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func importCalendar(path string, userId int, token string, workspaceName string, dryRun bool) error {
+	if path == "" || (userId == 0 && token == "") {
+		return errors.New("Import requires -in path and -user id or -token")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	calendar, err := ical.Parse(file, time.Local)
+	if err != nil {
+		return err
+	}
+
+	services := app.BootstrapServices()
+	workspaceService := services.Resolve(app.SERVICE_WORKSPACE).(*application.WorkspaceService)
+	workspaceId := workspaces.Default
+	if workspaceName != "" {
+		workspace, err := workspaceService.GetByName(context.Background(), workspaceName)
+		if err != nil {
+			return err
+		}
+		workspaceId = workspace.Id
+	}
+	if userId == 0 {
+		accessToken, user, err := services.Resolve(app.SERVICE_ACCESS_TOKEN).(*application.AccessTokenService).Authenticate(context.Background(), token, users.ScopeReservationsWrite)
+		if err != nil {
+			return err
+		}
+		if workspaceName != "" && workspaceId != accessToken.WorkspaceId {
+			return fmt.Errorf("The token does not grant access to workspace %s", workspaceName)
+		}
+		userId = user.Id
+		workspaceId = accessToken.WorkspaceId
+	}
+	importer := services.Resolve(app.SERVICE_CALENDAR_IMPORT).(*application.CalendarImportService)
+	report, err := importer.Import(context.Background(), application.ImportCalendar{
+		UserId: userId,
+		WorkspaceId: workspaceId,
+		Calendar: calendar,
+		DryRun: dryRun,
+	})
+	fmt.Print(report)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	services.DeliverWebhooks(ctx)
+
+	return services.SaveSnapshot()
+}
@@ -5,19 +5,17 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/SneedusSnake/Reservations"
-	"github.com/go-telegram/bot"
 )
 
 func main() {
 	log.Print("Starting main")
 	application := app.Bootstrap()
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	b := application.Resolve(app.TELERAM_BOT).(*bot.Bot)
-
-	b.Start(ctx)
+	application.Run(ctx)
 }
 
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+)
+
+const usage = `Usage:
+  tokens create -name name -scopes scope,... [-ttl duration] [-user id] [-workspace name]
+  tokens list
+  tokens revoke -id id
+
+create adds a service account with a token, or issues a token to the existing -user.
+Scopes are reservations:read, reservations:write and subjects:admin. Tokens without -ttl do not expire.
+Tokens work with the subjects of the -workspace, the default one when omitted.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "create":
+		err = create(os.Args[2:])
+	case "list":
+		err = list()
+	case "revoke":
+		err = revoke(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func create(args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	name := flags.String("name", "", "name of the service account or of the token")
+	scopes := flags.String("scopes", "", "comma separated scopes")
+	ttl := flags.Duration("ttl", 0, "lifetime of the token, like 720h")
+	userId := flags.Int("user", 0, "id of an existing user to issue the token to")
+	workspaceName := flags.String("workspace", "", "name of the workspace the token works with")
+	flags.Parse(args)
+
+	if *name == "" || *scopes == "" {
+		return errors.New("Create requires -name and -scopes")
+	}
+
+	services := app.BootstrapServices()
+	tokens := services.Resolve(app.SERVICE_ACCESS_TOKEN).(*application.AccessTokenService)
+	workspaceId := workspaces.Default
+	if *workspaceName != "" {
+		workspace, err := services.Resolve(app.SERVICE_WORKSPACE).(*application.WorkspaceService).GetByName(context.Background(), *workspaceName)
+		if err != nil {
+			return err
+		}
+		workspaceId = workspace.Id
+	}
+	var token users.AccessToken
+	var value string
+	var err error
+	if *userId == 0 {
+		token, value, err = tokens.CreateServiceAccount(context.Background(), application.CreateServiceAccount{
+			Name: *name,
+			WorkspaceId: workspaceId,
+			Scopes: strings.Split(*scopes, ","),
+			TTL: *ttl,
+		})
+	} else {
+		token, value, err = tokens.Issue(context.Background(), application.IssueAccessToken{
+			UserId: *userId,
+			WorkspaceId: workspaceId,
+			Name: *name,
+			Scopes: strings.Split(*scopes, ","),
+			TTL: *ttl,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Token #%d issued to user %d\n", token.Id, token.UserId)
+	fmt.Println(value)
+
+	return services.SaveSnapshot()
+}
+
+func list() error {
+	services := app.BootstrapServices()
+	tokens, err := services.Resolve(app.SERVICE_ACCESS_TOKEN).(*application.AccessTokenService).List(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		expires := "never"
+		if !token.ExpiresAt.IsZero() {
+			expires = token.ExpiresAt.Format(time.DateTime)
+		}
+		fmt.Printf("%d\t%s\tuser %d\tworkspace %d\t%s\texpires %s\n", token.Id, token.Name, token.UserId, token.WorkspaceId, strings.Join(token.Scopes, ","), expires)
+	}
+
+	return nil
+}
+
+func revoke(args []string) error {
+	flags := flag.NewFlagSet("revoke", flag.ExitOnError)
+	id := flags.Int("id", 0, "id of the token")
+	flags.Parse(args)
+
+	if *id == 0 {
+		return errors.New("Revoke requires -id")
+	}
+
+	services := app.BootstrapServices()
+	if err := services.Resolve(app.SERVICE_ACCESS_TOKEN).(*application.AccessTokenService).Revoke(context.Background(), *id); err != nil {
+		return err
+	}
+
+	return services.SaveSnapshot()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SneedusSnake/Reservations"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	"github.com/SneedusSnake/Reservations/internal/transfer"
+)
+
+const usage = `Usage:
+  transfer export [-format json|csv] [-out path]
+  transfer import [-format json|csv] -in path [-dry-run] [-on-conflict fail|skip]
+
+The JSON bundle is written to stdout when -out is omitted, CSV files are written into the -out directory.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = export(os.Args[2:])
+	case "import":
+		err = importBundle(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "json", "json or csv")
+	out := flags.String("out", "", "output file for json, output directory for csv")
+	flags.Parse(args)
+
+	application := app.BootstrapStorage()
+	clock := application.Resolve(app.CLOCK).(ports.Clock)
+	bundle, err := transfer.Export(context.Background(), application.TransferStores(), clock.Current())
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		var w io.Writer = os.Stdout
+		if *out != "" {
+			file, err := os.Create(*out)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+		return bundle.WriteJSON(w)
+	case "csv":
+		if *out == "" {
+			return errors.New("CSV export requires -out directory")
+		}
+		return bundle.WriteCSV(*out)
+	}
+
+	return fmt.Errorf("Unknown format %s", *format)
+}
+
+func importBundle(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "json", "json or csv")
+	in := flags.String("in", "", "input file for json, input directory for csv")
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without writing anything")
+	onConflict := flags.String("on-conflict", string(transfer.ConflictFail), "fail or skip records conflicting with existing ones")
+	flags.Parse(args)
+
+	if *in == "" {
+		return errors.New("Import requires -in path")
+	}
+	mode, err := transfer.ParseConflictMode(*onConflict)
+	if err != nil {
+		return err
+	}
+
+	var bundle transfer.Bundle
+	switch *format {
+	case "json":
+		file, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		bundle, err = transfer.ReadJSON(file)
+		if err != nil {
+			return err
+		}
+	case "csv":
+		bundle, err = transfer.ReadCSV(*in)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Unknown format %s", *format)
+	}
+
+	application := app.BootstrapStorage()
+	report, err := transfer.Import(context.Background(), application.TransferStores(), bundle, transfer.ImportOptions{
+		DryRun: *dryRun,
+		OnConflict: mode,
+	})
+	fmt.Print(report)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		return nil
+	}
+
+	return application.SaveSnapshot()
+}
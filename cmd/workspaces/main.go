@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/SneedusSnake/Reservations"
+	"github.com/SneedusSnake/Reservations/internal/application"
+)
+
+const usage = `Usage:
+  workspaces list
+  workspaces bind -workspace name -chat platform:id
+  workspaces admin -workspace name -user id [-remove]
+
+bind makes a chat work with the subjects of the workspace. Chats are keyed by their platform,
+like slack:C0123, discord:987654321 or matrix:!room:example.org. Telegram chats are bound with /workspace_bind.
+admin lets the user bind telegram chats to the workspace, share its subjects and name its other admins.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "list":
+		err = list()
+	case "bind":
+		err = bind(os.Args[2:])
+	case "admin":
+		err = admin(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func list() error {
+	services := app.BootstrapServices()
+	list, err := services.Resolve(app.SERVICE_WORKSPACE).(*application.WorkspaceService).List(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, workspace := range list {
+		fmt.Printf("%d\t%s\n", workspace.Id, workspace.Name)
+	}
+
+	return nil
+}
+
+func bind(args []string) error {
+	flags := flag.NewFlagSet("bind", flag.ExitOnError)
+	name := flags.String("workspace", "", "name of the workspace")
+	chat := flags.String("chat", "", "platform and id of the chat")
+	flags.Parse(args)
+
+	if *name == "" || *chat == "" {
+		return errors.New("Bind requires -workspace and -chat")
+	}
+
+	services := app.BootstrapServices()
+	workspaceService := services.Resolve(app.SERVICE_WORKSPACE).(*application.WorkspaceService)
+	workspace, err := workspaceService.GetByName(context.Background(), *name)
+	if err != nil {
+		return err
+	}
+	if err = workspaceService.Bind(context.Background(), *chat, workspace.Id); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s bound to workspace %s\n", *chat, workspace.Name)
+
+	return services.SaveSnapshot()
+}
+
+func admin(args []string) error {
+	flags := flag.NewFlagSet("admin", flag.ExitOnError)
+	name := flags.String("workspace", "", "name of the workspace")
+	userId := flags.Int("user", 0, "id of the user")
+	remove := flags.Bool("remove", false, "stop the user from managing the workspace")
+	flags.Parse(args)
+
+	if *name == "" || *userId == 0 {
+		return errors.New("Admin requires -workspace and -user")
+	}
+
+	services := app.BootstrapServices()
+	workspaceService := services.Resolve(app.SERVICE_WORKSPACE).(*application.WorkspaceService)
+	workspace, err := workspaceService.GetByName(context.Background(), *name)
+	if err != nil {
+		return err
+	}
+	user, err := services.Resolve(app.SERVICE_USER).(*application.UserService).Get(context.Background(), *userId)
+	if err != nil {
+		return err
+	}
+	if *remove {
+		err = workspaceService.RemoveAdmin(context.Background(), workspace.Id, user.Id)
+	} else {
+		err = workspaceService.AddAdmin(context.Background(), workspace.Id, user.Id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *remove {
+		fmt.Fprintf(os.Stderr, "%s no longer manages workspace %s\n", user.Name, workspace.Name)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s now manages workspace %s\n", user.Name, workspace.Name)
+	}
+
+	return services.SaveSnapshot()
+}
@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/logging"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+)
+
+// LogMailer writes the mails to the log, for deployments without an SMTP server
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, mail ports.Mail) error {
+	logging.FromContext(ctx).Printf("Mail to %s: %s\n%s", mail.To, mail.Subject, mail.Body)
+
+	return nil
+}
+
+// SmtpMailer sends plain text mails through an SMTP server, authenticating when a user is given
+type SmtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSmtpMailer(addr string, from string, user string, password string) *SmtpMailer {
+	m := &SmtpMailer{addr: addr, from: from}
+	if user != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		m.auth = smtp.PlainAuth("", user, password, host)
+	}
+
+	return m
+}
+
+func (m *SmtpMailer) Send(ctx context.Context, mail ports.Mail) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(mail.To + mail.Subject, "\r\n") {
+		return fmt.Errorf("Mail headers must not contain line breaks")
+	}
+
+	message := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		m.from, mail.To, mail.Subject, strings.ReplaceAll(mail.Body, "\n", "\r\n"),
+	)
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{mail.To}, []byte(message))
+}
@@ -0,0 +1,173 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+)
+
+const (
+	opAddAccessToken = "access_tokens.add"
+	opRemoveAccessToken = "access_tokens.remove"
+	opReassignAccessTokens = "access_tokens.reassign_user"
+)
+
+type AccessTokensStore struct {
+	counter int
+	tokens []users.AccessToken
+	mu sync.Mutex
+	journal *Journal
+}
+
+type accessTokensState struct {
+	Counter int `json:"counter"`
+	Tokens []users.AccessToken `json:"tokens"`
+}
+
+type accessTokenEntry struct {
+	Id int `json:"id"`
+}
+
+func NewAccessTokensStore() *AccessTokensStore {
+	return &AccessTokensStore{}
+}
+
+func (s *AccessTokensStore) NextIdentity(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter++
+
+	return s.counter, nil
+}
+
+func (s *AccessTokensStore) Add(ctx context.Context, t users.AccessToken) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if slices.ContainsFunc(s.tokens, func(existing users.AccessToken) bool { return existing.Id == t.Id || existing.Hash == t.Hash }) {
+		return fmt.Errorf("Access token with id %d already exists", t.Id)
+	}
+	s.tokens = append(s.tokens, t)
+
+	return s.journal.append(opAddAccessToken, t)
+}
+
+func (s *AccessTokensStore) Get(ctx context.Context, id int) (users.AccessToken, error) {
+	return s.find(ctx, func(t users.AccessToken) bool { return t.Id == id }, fmt.Sprintf("Access token with id %d was not found", id))
+}
+
+func (s *AccessTokensStore) GetByHash(ctx context.Context, hash string) (users.AccessToken, error) {
+	return s.find(ctx, func(t users.AccessToken) bool { return t.Hash == hash }, "Access token was not found")
+}
+
+func (s *AccessTokensStore) find(ctx context.Context, match func(t users.AccessToken) bool, notFound string) (users.AccessToken, error) {
+	if err := ctx.Err(); err != nil {
+		return users.AccessToken{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.tokens, match)
+	if i == -1 {
+		return users.AccessToken{}, fmt.Errorf("%s", notFound)
+	}
+
+	return s.tokens[i], nil
+}
+
+func (s *AccessTokensStore) List(ctx context.Context) ([]users.AccessToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.tokens), nil
+}
+
+func (s *AccessTokensStore) Remove(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens = slices.DeleteFunc(s.tokens, func(t users.AccessToken) bool { return t.Id == id })
+
+	return s.journal.append(opRemoveAccessToken, accessTokenEntry{Id: id})
+}
+
+func (s *AccessTokensStore) ReassignUser(ctx context.Context, sourceId int, targetId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for index, t := range s.tokens {
+		if t.UserId == sourceId {
+			s.tokens[index].UserId = targetId
+		}
+	}
+
+	return s.journal.append(opReassignAccessTokens, reassignEntry{SourceId: sourceId, TargetId: targetId})
+}
+
+func (s *AccessTokensStore) lock() {
+	s.mu.Lock()
+}
+
+func (s *AccessTokensStore) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *AccessTokensStore) partState() any {
+	return accessTokensState{Counter: s.counter, Tokens: s.tokens}
+}
+
+func (s *AccessTokensStore) restorePart(data json.RawMessage) error {
+	var state accessTokensState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter = state.Counter
+	s.tokens = state.Tokens
+
+	return nil
+}
+
+func (s *AccessTokensStore) applyEntry(entry journalEntry) (bool, error) {
+	ctx := context.Background()
+
+	switch entry.Op {
+	case opAddAccessToken:
+		var t users.AccessToken
+		return true, decode(entry, &t, func() error {
+			s.counter = max(s.counter, t.Id)
+			return s.Add(ctx, t)
+		})
+	case opRemoveAccessToken:
+		var data accessTokenEntry
+		return true, decode(entry, &data, func() error { return s.Remove(ctx, data.Id) })
+	case opReassignAccessTokens:
+		var data reassignEntry
+		return true, decode(entry, &data, func() error { return s.ReassignUser(ctx, data.SourceId, data.TargetId) })
+	}
+
+	return false, nil
+}
+
+func (s *AccessTokensStore) setJournal(journal *Journal) {
+	s.journal = journal
+}
@@ -0,0 +1,17 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+)
+
+func TestInMemoryAccessTokensStore(t *testing.T) {
+	contract := users.AccessTokensRepositoryContract{
+		NewStore: func() users.AccessTokensRepository {
+			return inmemory.NewAccessTokensStore()
+		},
+	}
+	contract.Test(t)
+}
@@ -0,0 +1,151 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	usersPort "github.com/SneedusSnake/Reservations/internal/ports/users"
+)
+
+// ChatUsersStore links the accounts of one chat platform to users. Its journal operations and
+// entries are named after the platform, such as slack_users.add with a slack_id
+type ChatUsersStore struct {
+	platform string
+	users usersPort.UsersRepository
+	links map[string]int
+	mu sync.Mutex
+	journal *Journal
+}
+
+func NewChatUsersStore(platform string, s usersPort.UsersRepository) *ChatUsersStore {
+	return &ChatUsersStore{platform: platform, users: s, links: make(map[string]int)}
+}
+
+func (s *ChatUsersStore) Add(ctx context.Context, u users.ChatUser) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.links[u.ExternalId]; ok {
+		return fmt.Errorf("User with %s id %s already exists", s.platform, u.ExternalId)
+	}
+	s.links[u.ExternalId] = u.Id
+
+	return s.journal.append(s.op("add"), map[string]any{s.platform + "_id": u.ExternalId, "user_id": u.Id})
+}
+
+func (s *ChatUsersStore) Get(ctx context.Context, externalId string) (users.ChatUser, error) {
+	if err := ctx.Err(); err != nil {
+		return users.ChatUser{}, err
+	}
+	s.mu.Lock()
+	userId, ok := s.links[externalId]
+	s.mu.Unlock()
+
+	if !ok {
+		return users.ChatUser{}, fmt.Errorf("User with %s id %s was not found", s.platform, externalId)
+	}
+
+	u, err := s.users.Get(ctx, userId)
+	if err != nil {
+		return users.ChatUser{}, err
+	}
+
+	return users.ChatUser{ExternalId: externalId, User: u}, nil
+}
+
+func (s *ChatUsersStore) List(ctx context.Context) ([]users.ChatUser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	externalIds := slices.Sorted(maps.Keys(s.links))
+	s.mu.Unlock()
+
+	var result []users.ChatUser
+	for _, externalId := range externalIds {
+		u, err := s.Get(ctx, externalId)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, u)
+	}
+
+	return result, nil
+}
+
+func (s *ChatUsersStore) ReassignUser(ctx context.Context, sourceId int, targetId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reassignLinks(s.links, sourceId, targetId)
+
+	return s.journal.append(s.op("reassign_user"), reassignEntry{SourceId: sourceId, TargetId: targetId})
+}
+
+func (s *ChatUsersStore) op(name string) string {
+	return s.platform + "_users." + name
+}
+
+func (s *ChatUsersStore) lock() {
+	s.mu.Lock()
+}
+
+func (s *ChatUsersStore) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *ChatUsersStore) partState() any {
+	return maps.Clone(s.links)
+}
+
+func (s *ChatUsersStore) restorePart(data json.RawMessage) error {
+	links := make(map[string]int)
+	if err := json.Unmarshal(data, &links); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links = links
+
+	return nil
+}
+
+func (s *ChatUsersStore) applyEntry(entry journalEntry) (bool, error) {
+	switch entry.Op {
+	case s.op("add"):
+		var data map[string]json.RawMessage
+		return true, decode(entry, &data, func() error {
+			var externalId string
+			var userId int
+			if err := json.Unmarshal(data[s.platform + "_id"], &externalId); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(data["user_id"], &userId); err != nil {
+				return err
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.links[externalId] = userId
+
+			return nil
+		})
+	case s.op("reassign_user"):
+		var data reassignEntry
+		return true, decode(entry, &data, func() error { return s.ReassignUser(context.Background(), data.SourceId, data.TargetId) })
+	}
+
+	return false, nil
+}
+
+func (s *ChatUsersStore) setJournal(journal *Journal) {
+	s.journal = journal
+}
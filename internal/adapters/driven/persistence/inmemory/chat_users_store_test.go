@@ -0,0 +1,20 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+)
+
+func TestInMemoryChatUsersStore(t *testing.T) {
+	contract := users.ChatUsersRepositoryContract{
+		NewUsersStore: func() users.UsersRepository {
+			return inmemory.NewUsersStore()
+		},
+		NewStore: func(platform string, usersStore users.UsersRepository) users.ChatUsersRepository {
+			return inmemory.NewChatUsersStore(platform, usersStore)
+		},
+	}
+	contract.Test(t)
+}
@@ -0,0 +1,96 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+)
+
+const opSaveChat = "chats.save"
+
+type ChatsStore struct {
+	chats map[int64]telegram.Chat
+	mu sync.Mutex
+	journal *Journal
+}
+
+type chatsState struct {
+	Chats []telegram.Chat `json:"chats"`
+}
+
+func NewChatsStore() *ChatsStore {
+	return &ChatsStore{chats: make(map[int64]telegram.Chat)}
+}
+
+func (s *ChatsStore) Save(ctx context.Context, chat telegram.Chat) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats[chat.Id] = chat
+
+	return s.journal.append(opSaveChat, chat)
+}
+
+func (s *ChatsStore) Get(ctx context.Context, id int64) (telegram.Chat, error) {
+	if err := ctx.Err(); err != nil {
+		return telegram.Chat{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chat, ok := s.chats[id]
+	if !ok {
+		return telegram.Chat{}, telegram.ErrChatNotFound
+	}
+
+	return chat, nil
+}
+
+func (s *ChatsStore) lock() {
+	s.mu.Lock()
+}
+
+func (s *ChatsStore) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *ChatsStore) partState() any {
+	state := chatsState{Chats: []telegram.Chat{}}
+	for _, chat := range s.chats {
+		state.Chats = append(state.Chats, chat)
+	}
+
+	return state
+}
+
+func (s *ChatsStore) restorePart(data json.RawMessage) error {
+	var state chatsState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[int64]telegram.Chat)
+	for _, chat := range state.Chats {
+		s.chats[chat.Id] = chat
+	}
+
+	return nil
+}
+
+func (s *ChatsStore) applyEntry(entry journalEntry) (bool, error) {
+	if entry.Op != opSaveChat {
+		return false, nil
+	}
+	var chat telegram.Chat
+
+	return true, decode(entry, &chat, func() error { return s.Save(context.Background(), chat) })
+}
+
+func (s *ChatsStore) setJournal(journal *Journal) {
+	s.journal = journal
+}
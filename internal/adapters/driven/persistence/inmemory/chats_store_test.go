@@ -0,0 +1,17 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+)
+
+func TestInMemoryChatsStore(t *testing.T) {
+	contract := telegram.ChatsRepositoryContract{
+		NewStore: func() telegram.ChatsRepository {
+			return inmemory.NewChatsStore()
+		},
+	}
+	contract.Test(t)
+}
@@ -0,0 +1,124 @@
+package inmemory
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Journal is an append-only log of the store mutations made since the last snapshot, one JSON entry per line
+type Journal struct {
+	path string
+	file *os.File
+	// generation tags the entries, it goes up with each snapshot
+	generation int
+	mu sync.Mutex
+}
+
+type journalEntry struct {
+	Op string `json:"op"`
+	Data json.RawMessage `json:"data"`
+	// Generation is the generation of the journal the entry was appended to, entries of older ones are in the snapshot
+	Generation int `json:"generation,omitempty"`
+	Checksum uint32 `json:"checksum"`
+}
+
+func (e journalEntry) checksum() uint32 {
+	data := append([]byte(e.Op), e.Data...)
+	// entries written before generations existed are checked as they were
+	if e.Generation != 0 {
+		data = strconv.AppendInt(data, int64(e.Generation), 10)
+	}
+
+	return crc32.ChecksumIEEE(data)
+}
+
+func openJournal(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Journal{path: path, file: file}, nil
+}
+
+// append records a mutation, a nil journal records nothing
+func (j *Journal) append(op string, data any) error {
+	if j == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entry := journalEntry{Op: op, Data: raw, Generation: j.generation}
+	entry.Checksum = entry.checksum()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err = j.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("Could not write to journal %s: %w", j.path, err)
+	}
+
+	return j.file.Sync()
+}
+
+// entries reads the journal from the beginning, failing on the first incomplete or corrupted entry
+func (j *Journal) entries() ([]journalEntry, error) {
+	var entries []journalEntry
+
+	if _, err := j.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(j.file)
+
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(data) > 0 {
+				return nil, fmt.Errorf("Journal %s is incomplete: line %d is truncated", j.path, line)
+			}
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var entry journalEntry
+		if err = json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("Journal %s is corrupted at line %d: %w", j.path, line, err)
+		}
+		if entry.Checksum != entry.checksum() {
+			return nil, fmt.Errorf("Journal %s is corrupted at line %d: checksum mismatch", j.path, line)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// truncate empties the journal and starts the generation of the snapshot just written
+func (j *Journal) truncate(generation int) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.generation = generation
+	if err := j.file.Truncate(0); err != nil {
+		return err
+	}
+
+	return j.file.Sync()
+}
+
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
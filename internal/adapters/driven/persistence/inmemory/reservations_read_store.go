@@ -1,6 +1,8 @@
 package inmemory
 
 import (
+	"cmp"
+	"context"
 	"slices"
 	"time"
 
@@ -11,7 +13,7 @@ import (
 )
 
 type ReservationsReadStore struct {
-	reservationsStore reservationPorts.ReservationsRepository
+	reservationsStore *ReservationsStore
 	users        userPorts.UsersRepository
 	subjects 	reservationPorts.SubjectsRepository
 }
@@ -24,13 +26,13 @@ func NewReservationReadStore(reservationsStore *ReservationsStore, users *UsersS
 	}
 }
 
-func (r *ReservationsReadStore) Get(id int) (readmodel.Reservation, error) {
-	reservation, err := r.reservationsStore.Get(id)
+func (r *ReservationsReadStore) Get(ctx context.Context, id int) (readmodel.Reservation, error) {
+	reservation, err := r.reservationsStore.Get(ctx, id)
 
 	if err != nil {
 		return readmodel.Reservation{}, err
 	}
-	result, err := r.make(reservation)
+	result, err := r.make(ctx, reservation)
 	if err != nil {
 		return readmodel.Reservation{}, err
 	}
@@ -38,41 +40,87 @@ func (r *ReservationsReadStore) Get(id int) (readmodel.Reservation, error) {
 	return result, nil
 }
 
-func (r *ReservationsReadStore) Active(t time.Time, tags ...string) ([]readmodel.Reservation, error) {
+func (r *ReservationsReadStore) Active(ctx context.Context, workspaceId int, t time.Time, tags ...string) ([]readmodel.Reservation, error) {
 	var result []readmodel.Reservation
-	list, err := r.reservationsStore.List()
+	list, err := r.reservationsStore.List(ctx)
 	if err != nil {
 		return result, err
 	}
 
+	var filterSubjects reservations.Subjects
 	if len(tags) > 0 {
-		filterSubjects, err := r.subjects.GetByTags(tags)
-		if err != nil {
-			return result, err
-		}
-		list = filterBySubjects(list, filterSubjects)
+		filterSubjects, err = r.subjects.GetByTags(ctx, workspaceId, tags)
+	} else {
+		filterSubjects, err = r.subjects.InWorkspace(ctx, workspaceId)
 	}
+	if err != nil {
+		return result, err
+	}
+	list = filterBySubjects(list, filterSubjects)
 
 	for _, reservation := range list {
-		model, err := r.make(reservation)
+		model, err := r.make(ctx, reservation)
 		if err != nil {
 			return result, err
 		}
 
-		if (!reservation.End.Before(t)) {
+		if (reservation.Interval().Contains(t)) {
 			result = append(result, model)
 		}
 	}
 	return result, nil
 }
 
-func (r *ReservationsReadStore) make(reservation reservations.Reservation) (readmodel.Reservation, error) {
-	user, err := r.users.Get(reservation.UserId)
+func (r *ReservationsReadStore) ForSubject(ctx context.Context, subjectId int) ([]readmodel.Reservation, error) {
+	return r.listAll(ctx, func(rs reservations.Reservations) reservations.Reservations { return rs.ForSubject(subjectId) })
+}
+
+func (r *ReservationsReadStore) ForUser(ctx context.Context, userId int) ([]readmodel.Reservation, error) {
+	return r.listAll(ctx, func(rs reservations.Reservations) reservations.Reservations { return rs.ForUser(userId) })
+}
+
+// listAll returns the filtered reservations and removed reservations, marked cancelled, ordered by start
+func (r *ReservationsReadStore) listAll(ctx context.Context, filter func(rs reservations.Reservations) reservations.Reservations) ([]readmodel.Reservation, error) {
+	list, err := r.reservationsStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cancelled, err := r.reservationsStore.cancelledList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []readmodel.Reservation
+	for _, reservation := range filter(list) {
+		model, err := r.make(ctx, reservation)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, model)
+	}
+	for _, reservation := range filter(cancelled) {
+		model, err := r.make(ctx, reservation)
+		if err != nil {
+			// the subject or the user of a cancelled reservation may be gone by now
+			continue
+		}
+		model.Cancelled = true
+		result = append(result, model)
+	}
+	slices.SortStableFunc(result, func(a readmodel.Reservation, b readmodel.Reservation) int {
+		return cmp.Or(a.Start.Compare(b.Start), a.Id - b.Id)
+	})
+
+	return result, nil
+}
+
+func (r *ReservationsReadStore) make(ctx context.Context, reservation reservations.Reservation) (readmodel.Reservation, error) {
+	user, err := r.users.Get(ctx, reservation.UserId)
 	if err != nil {
 		return readmodel.Reservation{}, err
 	}
 
-	subject, err := r.subjects.Get(reservation.SubjectId)
+	subject, err := r.subjects.Get(ctx, reservation.SubjectId)
 	if err != nil {
 		return readmodel.Reservation{}, err
 	}
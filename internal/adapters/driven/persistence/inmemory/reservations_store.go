@@ -1,6 +1,7 @@
 package inmemory
 
 import (
+	"context"
 	"fmt"
 	"slices"
 	"sync"
@@ -9,18 +10,40 @@ import (
 	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
 )
 
+const (
+	opAddReservations = "reservations.add"
+	opRemoveReservation = "reservations.remove"
+	opReassignReservations = "reservations.reassign_user"
+)
+
 type ReservationsStore struct
 {
 	counter int
 	reservations reservations.Reservations
+	// cancelled are the removed reservations
+	cancelled reservations.Reservations
 	mu sync.Mutex
+	journal *Journal
+}
+
+type reservationsState struct {
+	Counter int `json:"counter"`
+	Reservations reservations.Reservations `json:"reservations"`
+	Cancelled reservations.Reservations `json:"cancelled,omitempty"`
+}
+
+type reservationEntry struct {
+	Id int `json:"id"`
 }
 
 func NewReservationStore() *ReservationsStore {
 	return &ReservationsStore{}
 }
 
-func (r *ReservationsStore) NextIdentity() (int, error) {
+func (r *ReservationsStore) NextIdentity(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.counter++
@@ -28,19 +51,33 @@ func (r *ReservationsStore) NextIdentity() (int, error) {
 	return r.counter, nil
 }
 
-func (r *ReservationsStore) List() (reservations.Reservations, error) {
-	return slices.Clone(r.reservations), nil
+func (r *ReservationsStore) AdvanceIdentity(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counter = max(r.counter, id)
+
+	return nil
 }
 
-func (r *ReservationsStore) ForPeriod(from time.Time, to time.Time) (reservations.Reservations, error) {
-	var result []reservations.Reservation
+func (r *ReservationsStore) List(ctx context.Context) (reservations.Reservations, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return slices.Clone(r.reservations), nil
+}
 
-	if (from.After(to)) {
-		return result, nil
+func (r *ReservationsStore) ForPeriod(ctx context.Context, from time.Time, to time.Time) (reservations.Reservations, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	var result []reservations.Reservation
+	period := reservations.NewInterval(from, to)
 
 	for _, reservation := range(r.reservations) {
-		if inInterval(reservation.Start, from, to) || inInterval(reservation.End, from, to) {
+		if reservation.Interval().Overlaps(period) {
 			result = append(result, reservation)
 		}
 	}
@@ -48,15 +85,31 @@ func (r *ReservationsStore) ForPeriod(from time.Time, to time.Time) (reservation
 	return result, nil
 }
 
-func (r *ReservationsStore) Add(reservation reservations.Reservation) error {
+func (r *ReservationsStore) Add(ctx context.Context, reservation reservations.Reservation) error {
+	return r.AddMany(ctx, reservations.Reservations{reservation})
+}
+
+func (r *ReservationsStore) AddMany(ctx context.Context, rs reservations.Reservations) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.reservations = append(r.reservations, reservation)
+	for index, reservation := range rs {
+		_, err := r.Get(ctx, reservation.Id)
+		if err == nil || slices.ContainsFunc(rs[:index], func(other reservations.Reservation) bool { return other.Id == reservation.Id }) {
+			return fmt.Errorf("Reservation with id %d already exists", reservation.Id)
+		}
+	}
+	r.reservations = append(r.reservations, rs...)
 
-	return nil
+	return r.journal.append(opAddReservations, rs)
 }
 
-func (r *ReservationsStore) Get(id int) (reservations.Reservation, error) {
+func (r *ReservationsStore) Get(ctx context.Context, id int) (reservations.Reservation, error) {
+	if err := ctx.Err(); err != nil {
+		return reservations.Reservation{}, err
+	}
 	for _, reservation := range r.reservations {
 		if (reservation.Id == id) {
 			return reservation, nil
@@ -66,19 +119,63 @@ func (r *ReservationsStore) Get(id int) (reservations.Reservation, error) {
 	return reservations.Reservation{}, fmt.Errorf("Reservation with id %d was not found", id)
 }
 
-func (r *ReservationsStore) Remove(id int) error {
+func (r *ReservationsStore) Remove(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	for index, reservation := range r.reservations {
 		if (reservation.Id == id) {
 			r.reservations = append(r.reservations[:index], r.reservations[index+1:]...)
-			return nil
+			r.cancelled = append(slices.DeleteFunc(r.cancelled, func(c reservations.Reservation) bool { return c.Id == id }), reservation)
+			return r.journal.append(opRemoveReservation, reservationEntry{Id: id})
 		}
 	}
 
 	return fmt.Errorf("Reservation with id %d was not found", id);
 }
 
-func inInterval(t time.Time, from time.Time, to time.Time) bool {
-	return t.Add(time.Second).After(from) && t.Add(-time.Second).Before(to)
+func (r *ReservationsStore) ReassignUser(ctx context.Context, sourceId int, targetId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for index, reservation := range r.reservations {
+		if reservation.UserId == sourceId {
+			r.reservations[index].UserId = targetId
+		}
+	}
+	for index, reservation := range r.cancelled {
+		if reservation.UserId == sourceId {
+			r.cancelled[index].UserId = targetId
+		}
+	}
+
+	return r.journal.append(opReassignReservations, reassignEntry{SourceId: sourceId, TargetId: targetId})
+}
+
+// state must be called with the store locked
+func (r *ReservationsStore) state() reservationsState {
+	return reservationsState{Counter: r.counter, Reservations: r.reservations, Cancelled: r.cancelled}
+}
+
+func (r *ReservationsStore) restore(state reservationsState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counter = state.Counter
+	r.reservations = state.Reservations
+	r.cancelled = state.Cancelled
+}
+
+// cancelledList returns the removed reservations
+func (r *ReservationsStore) cancelledList(ctx context.Context) (reservations.Reservations, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.cancelled), nil
 }
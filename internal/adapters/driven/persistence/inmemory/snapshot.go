@@ -0,0 +1,348 @@
+package inmemory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/logging"
+)
+
+const snapshotVersion = 1
+
+// Snapshotter keeps the in-memory stores in a JSON snapshot file,
+// with the mutations made after the latest snapshot recorded in a journal next to it
+type Snapshotter struct {
+	path string
+	// generation is the generation of the latest snapshot, journal entries of older generations are part of it
+	generation int
+	journal *Journal
+	subjects *SubjectsStore
+	users *UsersStore
+	telegramUsers *TelegramUsersStore
+	reservations *ReservationsStore
+	parts []namedPart
+}
+
+// SnapshotPart is a store kept in the snapshot besides the reservation stores
+type SnapshotPart interface {
+	lock()
+	unlock()
+	// partState must be called with the store locked
+	partState() any
+	restorePart(data json.RawMessage) error
+	// applyEntry replays the journal entry, when it is an operation of the store
+	applyEntry(entry journalEntry) (bool, error)
+	setJournal(journal *Journal)
+}
+
+type namedPart struct {
+	name string
+	part SnapshotPart
+}
+
+type snapshotFile struct {
+	Version int `json:"version"`
+	Checksum string `json:"checksum"`
+	State json.RawMessage `json:"state"`
+}
+
+type snapshotState struct {
+	Generation int `json:"generation,omitempty"`
+	Subjects subjectsState `json:"subjects"`
+	Users usersState `json:"users"`
+	TelegramUsers map[int64]int `json:"telegram_users"`
+	Reservations reservationsState `json:"reservations"`
+	Parts map[string]json.RawMessage `json:"parts,omitempty"`
+}
+
+func NewSnapshotter(path string, subjects *SubjectsStore, users *UsersStore, telegramUsers *TelegramUsersStore, reservations *ReservationsStore) *Snapshotter {
+	return &Snapshotter{
+		path: path,
+		subjects: subjects,
+		users: users,
+		telegramUsers: telegramUsers,
+		reservations: reservations,
+	}
+}
+
+// Include adds the store to the snapshot under the given name, it must be called before Restore
+func (s *Snapshotter) Include(name string, part SnapshotPart) {
+	s.parts = append(s.parts, namedPart{name: name, part: part})
+}
+
+// Restore loads the latest snapshot, replays the journal on top of it and starts journaling store mutations
+func (s *Snapshotter) Restore() error {
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	journal, err := openJournal(s.path + ".journal")
+	if err != nil {
+		return err
+	}
+	entries, err := journal.entries()
+	if err != nil {
+		journal.Close()
+		return err
+	}
+	// identities handed out but never persisted are not journaled, counters follow the added records instead
+	for i, entry := range entries {
+		// the journal is left behind when saving stops between writing the snapshot and truncating it
+		if entry.Generation < s.generation {
+			continue
+		}
+		if err = s.apply(entry); err != nil {
+			journal.Close()
+			return fmt.Errorf("Could not replay entry %d of journal %s: %w", i+1, journal.path, err)
+		}
+	}
+
+	journal.generation = s.generation
+	s.journal = journal
+	s.subjects.journal = journal
+	s.users.journal = journal
+	s.telegramUsers.journal = journal
+	s.reservations.journal = journal
+	for _, p := range s.parts {
+		p.part.setJournal(journal)
+	}
+
+	return nil
+}
+
+// Save writes a snapshot of all the stores and starts a new journal
+func (s *Snapshotter) Save() error {
+	s.subjects.mu.Lock()
+	defer s.subjects.mu.Unlock()
+	s.users.mu.Lock()
+	defer s.users.mu.Unlock()
+	s.telegramUsers.mu.Lock()
+	defer s.telegramUsers.mu.Unlock()
+	s.reservations.mu.Lock()
+	defer s.reservations.mu.Unlock()
+
+	parts := make(map[string]json.RawMessage)
+	for _, p := range s.parts {
+		p.part.lock()
+		defer p.part.unlock()
+		data, err := json.Marshal(p.part.partState())
+		if err != nil {
+			return err
+		}
+		parts[p.name] = data
+	}
+
+	generation := s.generation + 1
+	state, err := json.Marshal(snapshotState{
+		Generation: generation,
+		Subjects: s.subjects.state(),
+		Users: s.users.state(),
+		TelegramUsers: s.telegramUsers.state(),
+		Reservations: s.reservations.state(),
+		Parts: parts,
+	})
+	if err != nil {
+		return err
+	}
+	checksum := sha256.Sum256(state)
+	data, err := json.Marshal(snapshotFile{
+		Version: snapshotVersion,
+		Checksum: hex.EncodeToString(checksum[:]),
+		State: state,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = writeFileAtomically(s.path, data); err != nil {
+		return fmt.Errorf("Could not write snapshot %s: %w", s.path, err)
+	}
+	s.generation = generation
+	if s.journal == nil {
+		return nil
+	}
+
+	return s.journal.truncate(generation)
+}
+
+// Run saves a snapshot every interval and once more when ctx is done
+func (s *Snapshotter) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Save(); err != nil {
+				logging.FromContext(ctx).Print(err)
+			}
+		case <-ctx.Done():
+			return s.Save()
+		}
+	}
+}
+
+func (s *Snapshotter) Close() error {
+	if s.journal == nil {
+		return nil
+	}
+
+	return s.journal.Close()
+}
+
+func (s *Snapshotter) load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var file snapshotFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("Snapshot %s is corrupted: %w", s.path, err)
+	}
+	if file.Version != snapshotVersion {
+		return fmt.Errorf("Snapshot %s has unsupported version %d", s.path, file.Version)
+	}
+	checksum := sha256.Sum256(file.State)
+	if hex.EncodeToString(checksum[:]) != file.Checksum {
+		return fmt.Errorf("Snapshot %s is corrupted: checksum mismatch", s.path)
+	}
+
+	var state snapshotState
+	if err = json.Unmarshal(file.State, &state); err != nil {
+		return fmt.Errorf("Snapshot %s is corrupted: %w", s.path, err)
+	}
+	s.generation = state.Generation
+
+	s.subjects.restore(state.Subjects)
+	s.users.restore(state.Users)
+	s.telegramUsers.restore(state.TelegramUsers)
+	s.reservations.restore(state.Reservations)
+	for _, p := range s.parts {
+		data, ok := state.Parts[p.name]
+		if !ok {
+			continue
+		}
+		if err = p.part.restorePart(data); err != nil {
+			return fmt.Errorf("Snapshot %s is corrupted: %w", s.path, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Snapshotter) apply(entry journalEntry) error {
+	ctx := context.Background()
+
+	switch entry.Op {
+	case opAddSubject:
+		var subject reservations.Subject
+		return decode(entry, &subject, func() error {
+			s.subjects.counter = max(s.subjects.counter, subject.Id)
+			if subject.WorkspaceId == 0 {
+				subject.WorkspaceId = workspaces.Default
+			}
+			return s.subjects.Add(ctx, subject)
+		})
+	case opRemoveSubject:
+		var data subjectEntry
+		return decode(entry, &data, func() error { return s.subjects.Remove(ctx, data.Id) })
+	case opAddSubjectTag:
+		var data subjectEntry
+		return decode(entry, &data, func() error { return s.subjects.AddTag(ctx, data.Id, data.Tag) })
+	case opAddSubjectAlias:
+		var data subjectEntry
+		return decode(entry, &data, func() error { return s.subjects.AddAlias(ctx, data.Id, data.Alias) })
+	case opSetSubjectParent:
+		var data subjectEntry
+		return decode(entry, &data, func() error { return s.subjects.SetParent(ctx, data.Id, data.ParentId) })
+	case opShareSubject:
+		var data subjectEntry
+		return decode(entry, &data, func() error { return s.subjects.Share(ctx, data.Id, data.WorkspaceId) })
+	case opUnshareSubject:
+		var data subjectEntry
+		return decode(entry, &data, func() error { return s.subjects.Unshare(ctx, data.Id, data.WorkspaceId) })
+	case opAddUser:
+		var u users.User
+		return decode(entry, &u, func() error {
+			s.users.counter = max(s.users.counter, u.Id)
+			return s.users.Add(ctx, u)
+		})
+	case opUpdateUser:
+		var u users.User
+		return decode(entry, &u, func() error { return s.users.Update(ctx, u) })
+	case opRemoveUser:
+		var data userEntry
+		return decode(entry, &data, func() error { return s.users.Remove(ctx, data.Id) })
+	case opAddTelegramUser:
+		var data telegramUserEntry
+		return decode(entry, &data, func() error { return s.telegramUsers.link(data.TelegramId, data.UserId) })
+	case opReassignTelegramUser:
+		var data reassignEntry
+		return decode(entry, &data, func() error { return s.telegramUsers.ReassignUser(ctx, data.SourceId, data.TargetId) })
+	case opReassignReservations:
+		var data reassignEntry
+		return decode(entry, &data, func() error { return s.reservations.ReassignUser(ctx, data.SourceId, data.TargetId) })
+	case opAddReservations:
+		var rs reservations.Reservations
+		return decode(entry, &rs, func() error {
+			for _, r := range rs {
+				s.reservations.counter = max(s.reservations.counter, r.Id)
+			}
+			return s.reservations.AddMany(ctx, rs)
+		})
+	case opRemoveReservation:
+		var data reservationEntry
+		return decode(entry, &data, func() error { return s.reservations.Remove(ctx, data.Id) })
+	}
+
+	for _, p := range s.parts {
+		if applied, err := p.part.applyEntry(entry); applied || err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("Unknown operation %s", entry.Op)
+}
+
+func decode(entry journalEntry, v any, apply func() error) error {
+	if err := json.Unmarshal(entry.Data, v); err != nil {
+		return err
+	}
+
+	return apply()
+}
+
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path) + ".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,253 @@
+package inmemory_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/webhooks"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/alecthomas/assert/v2"
+)
+
+type stores struct {
+	subjects *inmemory.SubjectsStore
+	users *inmemory.UsersStore
+	telegramUsers *inmemory.TelegramUsersStore
+	slackUsers *inmemory.ChatUsersStore
+	reservations *inmemory.ReservationsStore
+	webhooks *inmemory.WebhooksStore
+	deadLetters *inmemory.DeadLettersStore
+	tokens *inmemory.TokensStore
+	snapshotter *inmemory.Snapshotter
+}
+
+func restore(t *testing.T, path string) (stores, error) {
+	s := stores{
+		subjects: inmemory.NewSubjectsStore(),
+		users: inmemory.NewUsersStore(),
+		reservations: inmemory.NewReservationStore(),
+		webhooks: inmemory.NewWebhooksStore(),
+		deadLetters: inmemory.NewDeadLettersStore(),
+		tokens: inmemory.NewTokensStore(),
+	}
+	s.telegramUsers = inmemory.NewTelegramUsersStore(s.users)
+	s.slackUsers = inmemory.NewChatUsersStore("slack", s.users)
+	s.snapshotter = inmemory.NewSnapshotter(path, s.subjects, s.users, s.telegramUsers, s.reservations)
+	s.snapshotter.Include("webhooks", s.webhooks)
+	s.snapshotter.Include("dead_letters", s.deadLetters)
+	s.snapshotter.Include("tokens", s.tokens)
+	s.snapshotter.Include("slack_users", s.slackUsers)
+	err := s.snapshotter.Restore()
+	t.Cleanup(func() {
+		s.snapshotter.Close()
+	})
+
+	return s, err
+}
+
+func TestSnapshotter(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("it restores the stores from snapshot and journal", func(t *testing.T) {
+		path := t.TempDir() + "/snapshot.json"
+		s, err := restore(t, path)
+		assert.NoError(t, err)
+
+		assert.NoError(t, s.subjects.Add(ctx, reservations.Subject{Id: 1, Name: "Bench", WorkspaceId: workspaces.Default}))
+		assert.NoError(t, s.subjects.Add(ctx, reservations.Subject{Id: 2, Name: "Phone", WorkspaceId: workspaces.Default}))
+		assert.NoError(t, s.subjects.AddTag(ctx, 1, "lab"))
+		assert.NoError(t, s.users.Add(ctx, users.User{Id: 1, Name: "Alice"}))
+		assert.NoError(t, s.telegramUsers.Add(ctx, telegram.TelegramUser{TelegramId: 100, User: users.User{Id: 1}}))
+		assert.NoError(t, s.snapshotter.Save())
+
+		assert.NoError(t, s.subjects.SetParent(ctx, 2, 1))
+		assert.NoError(t, s.subjects.AddAlias(ctx, 2, "mobile"))
+		assert.NoError(t, s.reservations.Add(ctx, reservations.Reservation{Id: 1, UserId: 1, SubjectId: 1, Start: start, End: start.Add(time.Hour)}))
+		assert.NoError(t, s.reservations.Add(ctx, reservations.Reservation{Id: 2, UserId: 1, SubjectId: 2, Start: start, End: start.Add(time.Hour)}))
+		assert.NoError(t, s.reservations.Remove(ctx, 2))
+		assert.NoError(t, s.users.Update(ctx, users.User{Id: 1, Name: "Alice", Email: "alice@example.com", Password: "hash"}))
+
+		restored, err := restore(t, path)
+		assert.NoError(t, err)
+
+		subjects, err := restored.subjects.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Subjects{{Id: 1, Name: "Bench", WorkspaceId: workspaces.Default}, {Id: 2, Name: "Phone", ParentId: 1, WorkspaceId: workspaces.Default}}, subjects)
+		tags, err := restored.subjects.GetTags(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"lab"}, tags)
+		aliases, err := restored.subjects.GetAliases(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mobile"}, aliases)
+		tgUser, err := restored.telegramUsers.Get(ctx, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", tgUser.Name)
+		user, err := restored.users.GetByEmail(ctx, "alice@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, user.Id)
+		list, err := restored.reservations.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Reservations{{Id: 1, UserId: 1, SubjectId: 1, Start: start, End: start.Add(time.Hour)}}, list)
+
+		id, err := restored.reservations.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+	})
+
+	t.Run("it restores included stores", func(t *testing.T) {
+		path := t.TempDir() + "/snapshot.json"
+		s, err := restore(t, path)
+		assert.NoError(t, err)
+
+		for range 2 {
+			_, err = s.webhooks.NextIdentity(ctx)
+			assert.NoError(t, err)
+		}
+		hook := webhooks.Webhook{Id: 1, Url: "https://example.com", Secret: "secret", Events: []string{"reservation.*"}}
+		assert.NoError(t, s.webhooks.Add(ctx, hook))
+		assert.NoError(t, s.webhooks.Add(ctx, webhooks.Webhook{Id: 2, Url: "https://example.org"}))
+		assert.NoError(t, s.snapshotter.Save())
+		assert.NoError(t, s.webhooks.Remove(ctx, 2))
+		letter := webhooks.DeadLetter{Id: 1, WebhookId: 1, Event: "reservation.created", Attempts: 3, FailedAt: start}
+		assert.NoError(t, s.deadLetters.Add(ctx, letter))
+		session := users.Token{Hash: "h1", Kind: users.TokenSession, UserId: 1, ExpiresAt: start}
+		assert.NoError(t, s.tokens.Add(ctx, session))
+		assert.NoError(t, s.tokens.Add(ctx, users.Token{Hash: "h2", Kind: users.TokenPasswordReset, UserId: 1, ExpiresAt: start}))
+		assert.NoError(t, s.tokens.RemoveByUser(ctx, 1, users.TokenPasswordReset))
+
+		restored, err := restore(t, path)
+		assert.NoError(t, err)
+
+		list, err := restored.webhooks.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []webhooks.Webhook{hook}, list)
+		letters, err := restored.deadLetters.List(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []webhooks.DeadLetter{letter}, letters)
+		token, err := restored.tokens.Get(ctx, "h1")
+		assert.NoError(t, err)
+		assert.Equal(t, session, token)
+		_, err = restored.tokens.Get(ctx, "h2")
+		assert.Error(t, err)
+		id, err := restored.webhooks.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+	})
+
+	t.Run("it restores chat users in the journal format of their platform", func(t *testing.T) {
+		path := t.TempDir() + "/snapshot.json"
+		s, err := restore(t, path)
+		assert.NoError(t, err)
+		assert.NoError(t, s.users.Add(ctx, users.User{Id: 1, Name: "Alice"}))
+		assert.NoError(t, s.users.Add(ctx, users.User{Id: 2, Name: "Bob"}))
+		assert.NoError(t, s.slackUsers.Add(ctx, users.ChatUser{ExternalId: "U1111111", User: users.User{Id: 1}}))
+		assert.NoError(t, s.snapshotter.Save())
+		assert.NoError(t, s.slackUsers.Add(ctx, users.ChatUser{ExternalId: "U2222222", User: users.User{Id: 2}}))
+
+		journal, err := os.ReadFile(path + ".journal")
+		assert.NoError(t, err)
+		assert.Contains(t, string(journal), `"op":"slack_users.add"`)
+		assert.Contains(t, string(journal), `"slack_id":"U2222222"`)
+		restored, err := restore(t, path)
+		assert.NoError(t, err)
+		list, err := restored.slackUsers.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []users.ChatUser{{ExternalId: "U1111111", User: users.User{Id: 1, Name: "Alice"}}, {ExternalId: "U2222222", User: users.User{Id: 2, Name: "Bob"}}}, list)
+	})
+
+	t.Run("it replays merged users", func(t *testing.T) {
+		path := t.TempDir() + "/snapshot.json"
+		s, err := restore(t, path)
+		assert.NoError(t, err)
+
+		assert.NoError(t, s.users.Add(ctx, users.User{Id: 1, Name: "Alice"}))
+		assert.NoError(t, s.users.Add(ctx, users.User{Id: 2, Name: "alice_phone", Email: "alice@example.com"}))
+		assert.NoError(t, s.telegramUsers.Add(ctx, telegram.TelegramUser{TelegramId: 200, User: users.User{Id: 2}}))
+		assert.NoError(t, s.reservations.Add(ctx, reservations.Reservation{Id: 1, UserId: 2, SubjectId: 1, Start: start, End: start.Add(time.Hour)}))
+		assert.NoError(t, s.tokens.Add(ctx, users.Token{Hash: "h1", Kind: users.TokenSession, UserId: 2, ExpiresAt: start}))
+		merger := inmemory.NewUserMerger(s.users, s.reservations, s.telegramUsers, s.tokens)
+		assert.NoError(t, merger.Merge(ctx, 2, users.User{Id: 1, Name: "Alice", Email: "alice@example.com"}))
+
+		restored, err := restore(t, path)
+		assert.NoError(t, err)
+
+		_, err = restored.users.Get(ctx, 2)
+		assert.Error(t, err)
+		tgUser, err := restored.telegramUsers.Get(ctx, 200)
+		assert.NoError(t, err)
+		assert.Equal(t, users.User{Id: 1, Name: "Alice", Email: "alice@example.com"}, tgUser.User)
+		reservation, err := restored.reservations.Get(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, reservation.UserId)
+		_, err = restored.tokens.Get(ctx, "h1")
+		assert.Error(t, err)
+	})
+
+	t.Run("it reports corrupted snapshot", func(t *testing.T) {
+		path := t.TempDir() + "/snapshot.json"
+		s, err := restore(t, path)
+		assert.NoError(t, err)
+		assert.NoError(t, s.subjects.Add(ctx, reservations.Subject{Id: 1, Name: "Bench", WorkspaceId: workspaces.Default}))
+		assert.NoError(t, s.snapshotter.Save())
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, []byte(string(data[:len(data)-10])), 0666))
+		_, err = restore(t, path)
+		assert.Error(t, err)
+
+		tampered := []byte(string(data))
+		tampered[len(tampered)/2] = ' '
+		assert.NoError(t, os.WriteFile(path, tampered, 0666))
+		_, err = restore(t, path)
+		assert.Error(t, err)
+	})
+
+	t.Run("it skips the journal entries already in the snapshot", func(t *testing.T) {
+		path := t.TempDir() + "/snapshot.json"
+		s, err := restore(t, path)
+		assert.NoError(t, err)
+		assert.NoError(t, s.subjects.Add(ctx, reservations.Subject{Id: 1, Name: "Bench", WorkspaceId: workspaces.Default}))
+		assert.NoError(t, s.reservations.Add(ctx, reservations.Reservation{Id: 1, UserId: 1, SubjectId: 1, Start: start, End: start.Add(time.Hour)}))
+		old, err := os.ReadFile(path + ".journal")
+		assert.NoError(t, err)
+		assert.NoError(t, s.snapshotter.Save())
+		assert.NoError(t, s.subjects.Add(ctx, reservations.Subject{Id: 2, Name: "Phone", WorkspaceId: workspaces.Default}))
+
+		// as if saving stopped before the journal was truncated
+		current, err := os.ReadFile(path + ".journal")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path + ".journal", append(old, current...), 0666))
+
+		restored, err := restore(t, path)
+		assert.NoError(t, err)
+		subjects, err := restored.subjects.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(subjects))
+		list, err := restored.reservations.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(list))
+	})
+
+	t.Run("it reports partial journal entry", func(t *testing.T) {
+		path := t.TempDir() + "/snapshot.json"
+		s, err := restore(t, path)
+		assert.NoError(t, err)
+		assert.NoError(t, s.subjects.Add(ctx, reservations.Subject{Id: 1, Name: "Bench", WorkspaceId: workspaces.Default}))
+		assert.NoError(t, s.subjects.Add(ctx, reservations.Subject{Id: 2, Name: "Phone", WorkspaceId: workspaces.Default}))
+
+		data, err := os.ReadFile(path + ".journal")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path + ".journal", data[:len(data)-5], 0666))
+
+		_, err = restore(t, path)
+		assert.Error(t, err)
+	})
+}
@@ -1,26 +1,62 @@
 package inmemory
 
 import (
+	"context"
 	"fmt"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
 	"github.com/SneedusSnake/Reservations/internal/utils"
 )
 
+const (
+	opAddSubject = "subjects.add"
+	opRemoveSubject = "subjects.remove"
+	opAddSubjectTag = "subjects.add_tag"
+	opAddSubjectAlias = "subjects.add_alias"
+	opSetSubjectParent = "subjects.set_parent"
+	opShareSubject = "subjects.share"
+	opUnshareSubject = "subjects.unshare"
+)
+
 type SubjectsStore struct {
 	counter int
 	subjects reservations.Subjects
 	tags map[string][]int
+	// shares are the workspaces a subject is shared with besides its own
+	shares map[int][]int
+	aliases map[int][]string
 	mu sync.Mutex
+	journal *Journal
+}
+
+type subjectsState struct {
+	Counter int `json:"counter"`
+	Subjects reservations.Subjects `json:"subjects"`
+	Tags map[string][]int `json:"tags"`
+	Shares map[int][]int `json:"shares,omitempty"`
+	Aliases map[int][]string `json:"aliases,omitempty"`
+}
+
+type subjectEntry struct {
+	Id int `json:"id"`
+	Tag string `json:"tag,omitempty"`
+	Alias string `json:"alias,omitempty"`
+	ParentId int `json:"parent_id,omitempty"`
+	WorkspaceId int `json:"workspace_id,omitempty"`
 }
 
 func NewSubjectsStore() *SubjectsStore {
-	return &SubjectsStore{counter: 0, subjects: reservations.Subjects{}, tags: make(map[string][]int)}
+	return &SubjectsStore{counter: 0, subjects: reservations.Subjects{}, tags: make(map[string][]int), shares: make(map[int][]int), aliases: make(map[int][]string)}
 }
 
-func (s *SubjectsStore) NextIdentity() (int, error) {
+func (s *SubjectsStore) NextIdentity(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.counter++
@@ -28,14 +64,31 @@ func (s *SubjectsStore) NextIdentity() (int, error) {
 	return s.counter, nil;
 }
 
-func (s *SubjectsStore) Add(subject reservations.Subject) error {
+func (s *SubjectsStore) AdvanceIdentity(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter = max(s.counter, id)
+
+	return nil
+}
+
+func (s *SubjectsStore) Add(ctx context.Context, subject reservations.Subject) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.subjects = append(s.subjects, subject)
-	return nil;
+	return s.journal.append(opAddSubject, subject)
 }
 
-func (s *SubjectsStore) Get(id int) (reservations.Subject, error) {
+func (s *SubjectsStore) Get(ctx context.Context, id int) (reservations.Subject, error) {
+	if err := ctx.Err(); err != nil {
+		return reservations.Subject{}, err
+	}
 	for _, subject := range s.subjects {
 		if subject.Id == id {
 			return subject, nil
@@ -44,31 +97,100 @@ func (s *SubjectsStore) Get(id int) (reservations.Subject, error) {
 	return reservations.Subject{}, fmt.Errorf("Subject with id %d not found", id)
 }
 
-func (s *SubjectsStore) List() (reservations.Subjects, error) {
+func (s *SubjectsStore) List(ctx context.Context) (reservations.Subjects, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return s.subjects, nil
 }
 
-func (s *SubjectsStore) Remove(id int) error {
+func (s *SubjectsStore) InWorkspace(ctx context.Context, workspaceId int) (reservations.Subjects, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	subjects := reservations.Subjects{}
+
+	for _, subject := range s.subjects {
+		if s.visible(subject, workspaceId) {
+			subjects = append(subjects, subject)
+		}
+	}
+
+	return subjects, nil
+}
+
+func (s *SubjectsStore) Share(ctx context.Context, id int, workspaceId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subject, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if s.visible(subject, workspaceId) {
+		return fmt.Errorf("Subject %s is already in workspace %d", subject.Name, workspaceId)
+	}
+	s.shares[id] = append(s.shares[id], workspaceId)
+
+	return s.journal.append(opShareSubject, subjectEntry{Id: id, WorkspaceId: workspaceId})
+}
+
+func (s *SubjectsStore) Unshare(ctx context.Context, id int, workspaceId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index := slices.Index(s.shares[id], workspaceId)
+	if index < 0 {
+		return fmt.Errorf("Subject with id %d is not shared with workspace %d", id, workspaceId)
+	}
+	s.shares[id] = slices.Delete(s.shares[id], index, index + 1)
+
+	return s.journal.append(opUnshareSubject, subjectEntry{Id: id, WorkspaceId: workspaceId})
+}
+
+func (s *SubjectsStore) SharedWith(ctx context.Context, id int) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	workspaceIds := slices.Clone(s.shares[id])
+	slices.Sort(workspaceIds)
+
+	return workspaceIds, nil
+}
+
+func (s *SubjectsStore) visible(subject reservations.Subject, workspaceId int) bool {
+	return subject.WorkspaceId == workspaceId || slices.Contains(s.shares[subject.Id], workspaceId)
+}
+
+func (s *SubjectsStore) Remove(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	for index, subject := range s.subjects {
 		if subject.Id == id {
 			s.subjects = append(s.subjects[:index], s.subjects[index+1:]...)
-			return nil
+			delete(s.shares, id)
+			delete(s.aliases, id)
+			return s.journal.append(opRemoveSubject, subjectEntry{Id: id})
 		}
 	}
 	return fmt.Errorf("Subject with id %d was not found", id)
 }
 
-func (s *SubjectsStore) AddTag(id int, tag string) error {
+func (s *SubjectsStore) AddTag(ctx context.Context, id int, tag string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	subject, err := s.Get(id)
+	subject, err := s.Get(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	tags, err := s.GetTags(id)
+	tags, err := s.GetTags(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -78,11 +200,11 @@ func (s *SubjectsStore) AddTag(id int, tag string) error {
 
 	s.tags[tag] = append(s.tags[tag], subject.Id)
 
-	return nil;
+	return s.journal.append(opAddSubjectTag, subjectEntry{Id: id, Tag: tag})
 }
 
-func (s *SubjectsStore) GetTags(id int) ([]string, error) {
-	_, err := s.Get(id)
+func (s *SubjectsStore) GetTags(ctx context.Context, id int) ([]string, error) {
+	_, err := s.Get(ctx, id)
 	if err != nil {
 		return []string{}, err
 	}
@@ -97,7 +219,36 @@ func (s *SubjectsStore) GetTags(id int) ([]string, error) {
 	return tags, nil
 }
 
-func (s *SubjectsStore) GetByTags(tags []string) (reservations.Subjects, error) {
+func (s *SubjectsStore) AddAlias(ctx context.Context, id int, alias string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subject, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(s.aliases[id], alias) {
+		return fmt.Errorf("alias %s already exists for subject %s", alias, subject.Name)
+	}
+	s.aliases[id] = append(s.aliases[id], alias)
+
+	return s.journal.append(opAddSubjectAlias, subjectEntry{Id: id, Alias: alias})
+}
+
+func (s *SubjectsStore) GetAliases(ctx context.Context, id int) ([]string, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return []string{}, err
+	}
+
+	aliases := slices.Clone(s.aliases[id])
+	slices.Sort(aliases)
+
+	return aliases, nil
+}
+
+func (s *SubjectsStore) GetByTags(ctx context.Context, workspaceId int, tags []string) (reservations.Subjects, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	subjects := make([]reservations.Subject, 0)
 	subjectIds := s.tags[tags[0]]
 
@@ -106,15 +257,17 @@ func (s *SubjectsStore) GetByTags(tags []string) (reservations.Subjects, error)
 	}
 
 	for _, id := range subjectIds {
-		subject, _ := s.Get(id)
-		subjects = append(subjects, subject)
+		subject, _ := s.Get(ctx, id)
+		if s.visible(subject, workspaceId) {
+			subjects = append(subjects, subject)
+		}
 	}
 
 	return subjects, nil
 }
 
-func (s *SubjectsStore) GetByName(name string) (reservations.Subject, error) {
-	subjects, err := s.List()
+func (s *SubjectsStore) GetByName(ctx context.Context, workspaceId int, name string) (reservations.Subject, error) {
+	subjects, err := s.InWorkspace(ctx, workspaceId)
 	if err != nil {
 		return reservations.Subject{}, err
 	}
@@ -127,3 +280,83 @@ func (s *SubjectsStore) GetByName(name string) (reservations.Subject, error) {
 
 	return reservations.Subject{}, fmt.Errorf("Subject with name %s was not found", name)
 }
+
+func (s *SubjectsStore) FindByName(ctx context.Context, workspaceId int, name string) (reservations.Subjects, error) {
+	subjects, err := s.InWorkspace(ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	found := reservations.Subjects{}
+
+	for _, subject := range subjects {
+		aliased := slices.ContainsFunc(s.aliases[subject.Id], func(alias string) bool { return strings.EqualFold(alias, name) })
+		if strings.EqualFold(subject.Name, name) || aliased {
+			found = append(found, subject)
+		}
+	}
+
+	return found, nil
+}
+
+func (s *SubjectsStore) SetParent(ctx context.Context, id int, parentId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for index, subject := range s.subjects {
+		if subject.Id == id {
+			s.subjects[index].ParentId = parentId
+			return s.journal.append(opSetSubjectParent, subjectEntry{Id: id, ParentId: parentId})
+		}
+	}
+	return fmt.Errorf("Subject with id %d was not found", id)
+}
+
+func (s *SubjectsStore) Children(ctx context.Context, id int) (reservations.Subjects, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	children := reservations.Subjects{}
+
+	for _, subject := range s.subjects {
+		if subject.ParentId == id {
+			children = append(children, subject)
+		}
+	}
+
+	return children, nil
+}
+
+// state must be called with the store locked
+func (s *SubjectsStore) state() subjectsState {
+	return subjectsState{Counter: s.counter, Subjects: s.subjects, Tags: s.tags, Shares: s.shares, Aliases: s.aliases}
+}
+
+func (s *SubjectsStore) restore(state subjectsState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter = state.Counter
+	s.subjects = state.Subjects
+	s.tags = state.Tags
+	s.shares = state.Shares
+	s.aliases = state.Aliases
+	// snapshots taken before workspaces existed
+	for index, subject := range s.subjects {
+		if subject.WorkspaceId == 0 {
+			s.subjects[index].WorkspaceId = workspaces.Default
+		}
+	}
+	if s.subjects == nil {
+		s.subjects = reservations.Subjects{}
+	}
+	if s.tags == nil {
+		s.tags = make(map[string][]int)
+	}
+	if s.shares == nil {
+		s.shares = make(map[int][]int)
+	}
+	if s.aliases == nil {
+		s.aliases = make(map[int][]string)
+	}
+}
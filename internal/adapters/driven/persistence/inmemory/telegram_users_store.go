@@ -1,35 +1,58 @@
 package inmemory
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
 
 	 "github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
 	"github.com/SneedusSnake/Reservations/internal/ports/users"
 )
 
+const (
+	opAddTelegramUser = "telegram_users.add"
+	opReassignTelegramUser = "telegram_users.reassign_user"
+)
+
 type TelegramUsersStore struct {
 	users users.UsersRepository
 	links map[int64]int
+	mu sync.Mutex
+	journal *Journal
+}
+
+type telegramUserEntry struct {
+	TelegramId int64 `json:"telegram_id"`
+	UserId int `json:"user_id"`
 }
 
 func NewTelegramUsersStore(s users.UsersRepository) *TelegramUsersStore {
-	return &TelegramUsersStore{s, make(map[int64]int)}
+	return &TelegramUsersStore{users: s, links: make(map[int64]int)}
 }
 
-func (s *TelegramUsersStore) Add(u telegram.TelegramUser) error {
-	s.links[u.TelegramId] = u.Id
+func (s *TelegramUsersStore) Add(ctx context.Context, u telegram.TelegramUser) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-	return nil
+	return s.link(u.TelegramId, u.Id)
 }
 
-func (s *TelegramUsersStore) Get(tgId int64) (telegram.TelegramUser, error) {
+func (s *TelegramUsersStore) Get(ctx context.Context, tgId int64) (telegram.TelegramUser, error) {
+	if err := ctx.Err(); err != nil {
+		return telegram.TelegramUser{}, err
+	}
+	s.mu.Lock()
 	userId, ok := s.links[tgId]
+	s.mu.Unlock()
 
 	if !ok {
 		return telegram.TelegramUser{}, fmt.Errorf("No user with telegram id %d was found", tgId)
 	}
 
-	u, err := s.users.Get(userId)
+	u, err := s.users.Get(ctx, userId)
 
 	if err != nil {
 		return telegram.TelegramUser{}, err
@@ -38,3 +61,69 @@ func (s *TelegramUsersStore) Get(tgId int64) (telegram.TelegramUser, error) {
 	return telegram.TelegramUser{TelegramId: tgId, User: u}, nil
 }
 
+func (s *TelegramUsersStore) List(ctx context.Context) ([]telegram.TelegramUser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	tgIds := slices.Sorted(maps.Keys(s.links))
+	s.mu.Unlock()
+
+	var result []telegram.TelegramUser
+	for _, tgId := range tgIds {
+		u, err := s.Get(ctx, tgId)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, u)
+	}
+
+	return result, nil
+}
+
+func (s *TelegramUsersStore) CountByUser(ctx context.Context, userId int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, id := range s.links {
+		if id == userId {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *TelegramUsersStore) ReassignUser(ctx context.Context, sourceId int, targetId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reassignLinks(s.links, sourceId, targetId)
+
+	return s.journal.append(opReassignTelegramUser, reassignEntry{SourceId: sourceId, TargetId: targetId})
+}
+
+func (s *TelegramUsersStore) link(tgId int64, userId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links[tgId] = userId
+
+	return s.journal.append(opAddTelegramUser, telegramUserEntry{TelegramId: tgId, UserId: userId})
+}
+
+// state must be called with the store locked
+func (s *TelegramUsersStore) state() map[int64]int {
+	return maps.Clone(s.links)
+}
+
+func (s *TelegramUsersStore) restore(links map[int64]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links = make(map[int64]int)
+	maps.Copy(s.links, links)
+}
@@ -0,0 +1,159 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+)
+
+const (
+	opAddToken = "tokens.add"
+	opRemoveToken = "tokens.remove"
+	opRemoveUserTokens = "tokens.remove_by_user"
+)
+
+type TokensStore struct {
+	tokens map[string]users.Token
+	mu sync.Mutex
+	journal *Journal
+}
+
+type tokenEntry struct {
+	Hash string `json:"hash,omitempty"`
+	UserId int `json:"user_id,omitempty"`
+	Kind string `json:"kind,omitempty"`
+}
+
+func NewTokensStore() *TokensStore {
+	return &TokensStore{tokens: make(map[string]users.Token)}
+}
+
+func (s *TokensStore) Add(ctx context.Context, t users.Token) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tokens[t.Hash]; ok {
+		return fmt.Errorf("Token already exists")
+	}
+	s.tokens[t.Hash] = t
+
+	return s.journal.append(opAddToken, t)
+}
+
+func (s *TokensStore) Get(ctx context.Context, hash string) (users.Token, error) {
+	if err := ctx.Err(); err != nil {
+		return users.Token{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[hash]
+	if !ok {
+		return users.Token{}, fmt.Errorf("Token was not found")
+	}
+
+	return t, nil
+}
+
+func (s *TokensStore) Remove(ctx context.Context, hash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, hash)
+
+	return s.journal.append(opRemoveToken, tokenEntry{Hash: hash})
+}
+
+func (s *TokensStore) RemoveByUser(ctx context.Context, userId int, kind string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeByUser(userId, kind)
+
+	return s.journal.append(opRemoveUserTokens, tokenEntry{UserId: userId, Kind: kind})
+}
+
+// ReassignUser drops the tokens of the source, sessions and one-time codes are not carried over to the target
+func (s *TokensStore) ReassignUser(ctx context.Context, sourceId int, targetId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeByUser(sourceId, "")
+
+	return s.journal.append(opRemoveUserTokens, tokenEntry{UserId: sourceId})
+}
+
+// removeByUser removes tokens of every kind when kind is empty, it must be called with the store locked
+func (s *TokensStore) removeByUser(userId int, kind string) {
+	maps.DeleteFunc(s.tokens, func(_ string, t users.Token) bool {
+		return t.UserId == userId && (kind == "" || t.Kind == kind)
+	})
+}
+
+func (s *TokensStore) lock() {
+	s.mu.Lock()
+}
+
+func (s *TokensStore) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *TokensStore) partState() any {
+	return maps.Clone(s.tokens)
+}
+
+func (s *TokensStore) restorePart(data json.RawMessage) error {
+	tokens := make(map[string]users.Token)
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = tokens
+
+	return nil
+}
+
+func (s *TokensStore) applyEntry(entry journalEntry) (bool, error) {
+	switch entry.Op {
+	case opAddToken:
+		var t users.Token
+		return true, decode(entry, &t, func() error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.tokens[t.Hash] = t
+
+			return nil
+		})
+	case opRemoveToken, opRemoveUserTokens:
+		var data tokenEntry
+		return true, decode(entry, &data, func() error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if entry.Op == opRemoveToken {
+				delete(s.tokens, data.Hash)
+			} else {
+				s.removeByUser(data.UserId, data.Kind)
+			}
+
+			return nil
+		})
+	}
+
+	return false, nil
+}
+
+func (s *TokensStore) setJournal(journal *Journal) {
+	s.journal = journal
+}
@@ -0,0 +1,17 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+)
+
+func TestInMemoryTokensStore(t *testing.T) {
+	contract := users.TokensRepositoryContract{
+		NewStore: func() users.TokensRepository {
+			return inmemory.NewTokensStore()
+		},
+	}
+	contract.Test(t)
+}
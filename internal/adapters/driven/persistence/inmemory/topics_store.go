@@ -0,0 +1,136 @@
+package inmemory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+)
+
+const (
+	opBindTopic = "topics.bind"
+	opRemoveTopic = "topics.remove"
+)
+
+type TopicsStore struct {
+	topics []telegram.Topic
+	mu sync.Mutex
+	journal *Journal
+}
+
+type topicsState struct {
+	Topics []telegram.Topic `json:"topics"`
+}
+
+type topicEntry struct {
+	ChatId int64 `json:"chat_id"`
+	ThreadId int `json:"thread_id"`
+}
+
+func NewTopicsStore() *TopicsStore {
+	return &TopicsStore{}
+}
+
+func (s *TopicsStore) Bind(ctx context.Context, topic telegram.Topic) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.topics = slices.DeleteFunc(s.topics, func(existing telegram.Topic) bool {
+		return existing.ChatId == topic.ChatId && existing.ThreadId == topic.ThreadId
+	})
+	s.topics = append(s.topics, topic)
+	slices.SortFunc(s.topics, func(a, b telegram.Topic) int {
+		return cmp.Or(cmp.Compare(a.ChatId, b.ChatId), cmp.Compare(a.ThreadId, b.ThreadId))
+	})
+
+	return s.journal.append(opBindTopic, topic)
+}
+
+func (s *TopicsStore) Get(ctx context.Context, chatId int64, threadId int) (telegram.Topic, error) {
+	if err := ctx.Err(); err != nil {
+		return telegram.Topic{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, topic := range s.topics {
+		if topic.ChatId == chatId && topic.ThreadId == threadId {
+			return topic, nil
+		}
+	}
+
+	return telegram.Topic{}, telegram.ErrTopicNotBound
+}
+
+func (s *TopicsStore) List(ctx context.Context) ([]telegram.Topic, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.topics), nil
+}
+
+func (s *TopicsStore) Remove(ctx context.Context, chatId int64, threadId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.topics = slices.DeleteFunc(s.topics, func(topic telegram.Topic) bool {
+		return topic.ChatId == chatId && topic.ThreadId == threadId
+	})
+
+	return s.journal.append(opRemoveTopic, topicEntry{ChatId: chatId, ThreadId: threadId})
+}
+
+func (s *TopicsStore) lock() {
+	s.mu.Lock()
+}
+
+func (s *TopicsStore) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *TopicsStore) partState() any {
+	return topicsState{Topics: s.topics}
+}
+
+func (s *TopicsStore) restorePart(data json.RawMessage) error {
+	var state topicsState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.topics = state.Topics
+
+	return nil
+}
+
+func (s *TopicsStore) applyEntry(entry journalEntry) (bool, error) {
+	ctx := context.Background()
+
+	switch entry.Op {
+	case opBindTopic:
+		var topic telegram.Topic
+		return true, decode(entry, &topic, func() error { return s.Bind(ctx, topic) })
+	case opRemoveTopic:
+		var data topicEntry
+		return true, decode(entry, &data, func() error { return s.Remove(ctx, data.ChatId, data.ThreadId) })
+	}
+
+	return false, nil
+}
+
+func (s *TopicsStore) setJournal(journal *Journal) {
+	s.journal = journal
+}
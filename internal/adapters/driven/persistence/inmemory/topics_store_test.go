@@ -0,0 +1,17 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+)
+
+func TestInMemoryTopicsStore(t *testing.T) {
+	contract := telegram.TopicsRepositoryContract{
+		NewStore: func() telegram.TopicsRepository {
+			return inmemory.NewTopicsStore()
+		},
+	}
+	contract.Test(t)
+}
@@ -0,0 +1,27 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+
+	reservationPorts "github.com/SneedusSnake/Reservations/internal/ports/reservations"
+)
+
+type UnitOfWork struct {
+	reservations *ReservationsStore
+	mu sync.Mutex
+}
+
+func NewUnitOfWork(reservations *ReservationsStore) *UnitOfWork {
+	return &UnitOfWork{reservations: reservations}
+}
+
+func (u *UnitOfWork) Execute(ctx context.Context, subjectIds []int, fn func(reservations reservationPorts.ReservationsRepository) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return fn(u.reservations)
+}
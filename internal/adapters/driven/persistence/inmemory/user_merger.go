@@ -0,0 +1,65 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+)
+
+// UserReassigner is a store keeping records of users, it journals the reassignment itself
+// after applying it, so that only the journal write can fail once the context is checked
+type UserReassigner interface {
+	ReassignUser(ctx context.Context, sourceId int, targetId int) error
+}
+
+type reassignEntry struct {
+	SourceId int `json:"source_id"`
+	TargetId int `json:"target_id"`
+}
+
+// UserMerger merges users across the in-memory stores. Unlike the SQL mergers it is not atomic,
+// instead nothing can stop it halfway: the context is only checked up front and the stores reassign
+// in memory before journaling, so a failed journal write is reported once every store is merged
+type UserMerger struct {
+	users *UsersStore
+	stores []UserReassigner
+	mu sync.Mutex
+}
+
+func NewUserMerger(users *UsersStore, stores ...UserReassigner) *UserMerger {
+	return &UserMerger{users: users, stores: stores}
+}
+
+func (m *UserMerger) Merge(ctx context.Context, sourceId int, target users.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := m.users.Get(ctx, sourceId); err != nil {
+		return err
+	}
+	if _, err := m.users.Get(ctx, target.Id); err != nil {
+		return err
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	var errs []error
+	for _, store := range m.stores {
+		errs = append(errs, store.ReassignUser(ctx, sourceId, target.Id))
+	}
+	errs = append(errs, m.users.Remove(ctx, sourceId), m.users.Update(ctx, target))
+
+	return errors.Join(errs...)
+}
+
+func reassignLinks[K comparable](links map[K]int, sourceId int, targetId int) {
+	for id, userId := range links {
+		if userId == sourceId {
+			links[id] = targetId
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+)
+
+func TestInMemoryUserMerger(t *testing.T) {
+	reservations.UserMergerContract{
+		NewStores: func() reservations.MergerStores {
+			usersStore := inmemory.NewUsersStore()
+			reservationsStore := inmemory.NewReservationStore()
+			accessTokensStore := inmemory.NewAccessTokensStore()
+			workspacesStore := inmemory.NewWorkspacesStore()
+
+			return reservations.MergerStores{
+				Merger: inmemory.NewUserMerger(
+					usersStore,
+					reservationsStore,
+					accessTokensStore,
+					inmemory.NewTelegramUsersStore(usersStore),
+					inmemory.NewTokensStore(),
+					workspacesStore,
+				),
+				Users: usersStore,
+				AccessTokens: accessTokensStore,
+				Reservations: reservationsStore,
+				Workspaces: workspacesStore,
+			}
+		},
+	}.Test(t)
+}
@@ -1,37 +1,84 @@
 package inmemory
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/SneedusSnake/Reservations/internal/domain/users"
 )
 
+const (
+	opAddUser = "users.add"
+	opUpdateUser = "users.update"
+	opRemoveUser = "users.remove"
+)
+
 type UsersStore struct {
 	counter int
 	users  []users.User
+	mu sync.Mutex
+	journal *Journal
+}
+
+type userEntry struct {
+	Id int `json:"id"`
+}
+
+type usersState struct {
+	Counter int `json:"counter"`
+	Users []users.User `json:"users"`
 }
 
 func NewUsersStore() *UsersStore {
 	return &UsersStore{}
 }
 
-func (s *UsersStore) NextIdentity() (int, error) {
+func (s *UsersStore) NextIdentity(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.counter++
 	return s.counter, nil
 }
 
-func (s *UsersStore) Add(u users.User) error {
-	existingUser, err := s.Get(u.Id)
+func (s *UsersStore) AdvanceIdentity(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter = max(s.counter, id)
+
+	return nil
+}
+
+func (s *UsersStore) Add(ctx context.Context, u users.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existingUser, err := s.Get(ctx, u.Id)
 	if err == nil {
 		return fmt.Errorf("User with id %d already exists: %v", u.Id, existingUser)
 	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err = s.checkEmail(u); err != nil {
+		return err
+	}
 
 	s.users = append(s.users, u)
 
-	return nil
+	return s.journal.append(opAddUser, u)
 }
 
-func (s *UsersStore) Get(id int) (users.User, error) {
+func (s *UsersStore) Get(ctx context.Context, id int) (users.User, error) {
+	if err := ctx.Err(); err != nil {
+		return users.User{}, err
+	}
 	for _, u := range s.users {
 		if u.Id == id {
 			return u, nil
@@ -41,6 +88,79 @@ func (s *UsersStore) Get(id int) (users.User, error) {
 	return users.User{}, fmt.Errorf("User with id %d was not found", id)
 }
 
-func (s *UsersStore) Remove(id int) error {
+func (s *UsersStore) GetByEmail(ctx context.Context, email string) (users.User, error) {
+	if err := ctx.Err(); err != nil {
+		return users.User{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.users {
+		if email != "" && u.Email == email {
+			return u, nil
+		}
+	}
+
+	return users.User{}, fmt.Errorf("User with email %s was not found", email)
+}
+
+func (s *UsersStore) Update(ctx context.Context, u users.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.users, func(existing users.User) bool { return existing.Id == u.Id })
+	if i == -1 {
+		return fmt.Errorf("User with id %d was not found", u.Id)
+	}
+	if err := s.checkEmail(u); err != nil {
+		return err
+	}
+	s.users[i] = u
+
+	return s.journal.append(opUpdateUser, u)
+}
+
+// checkEmail must be called with the store locked
+func (s *UsersStore) checkEmail(u users.User) error {
+	for _, existing := range s.users {
+		if u.Email != "" && existing.Email == u.Email && existing.Id != u.Id {
+			return fmt.Errorf("User with email %s already exists", u.Email)
+		}
+	}
+
 	return nil
 }
+
+func (s *UsersStore) List(ctx context.Context) ([]users.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.users), nil
+}
+
+func (s *UsersStore) Remove(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = slices.DeleteFunc(s.users, func(u users.User) bool { return u.Id == id })
+
+	return s.journal.append(opRemoveUser, userEntry{Id: id})
+}
+
+// state must be called with the store locked
+func (s *UsersStore) state() usersState {
+	return usersState{Counter: s.counter, Users: s.users}
+}
+
+func (s *UsersStore) restore(state usersState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter = state.Counter
+	s.users = state.Users
+}
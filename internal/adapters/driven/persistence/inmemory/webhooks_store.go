@@ -0,0 +1,243 @@
+package inmemory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/webhooks"
+)
+
+const (
+	opAddWebhook = "webhooks.add"
+	opRemoveWebhook = "webhooks.remove"
+	opAddDeadLetter = "dead_letters.add"
+)
+
+type WebhooksStore struct {
+	counter int
+	webhooks []webhooks.Webhook
+	mu sync.Mutex
+	journal *Journal
+}
+
+type webhooksState struct {
+	Counter int `json:"counter"`
+	Webhooks []webhooks.Webhook `json:"webhooks"`
+}
+
+type webhookEntry struct {
+	Id int `json:"id"`
+}
+
+func NewWebhooksStore() *WebhooksStore {
+	return &WebhooksStore{}
+}
+
+func (s *WebhooksStore) NextIdentity(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter++
+
+	return s.counter, nil
+}
+
+func (s *WebhooksStore) Add(ctx context.Context, w webhooks.Webhook) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if slices.ContainsFunc(s.webhooks, func(existing webhooks.Webhook) bool { return existing.Id == w.Id }) {
+		return fmt.Errorf("Webhook with id %d already exists", w.Id)
+	}
+	s.webhooks = append(s.webhooks, w)
+
+	return s.journal.append(opAddWebhook, w)
+}
+
+func (s *WebhooksStore) Get(ctx context.Context, id int) (webhooks.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return webhooks.Webhook{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, w := range s.webhooks {
+		if w.Id == id {
+			return w, nil
+		}
+	}
+
+	return webhooks.Webhook{}, fmt.Errorf("Webhook with id %d was not found", id)
+}
+
+func (s *WebhooksStore) List(ctx context.Context) ([]webhooks.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.webhooks), nil
+}
+
+func (s *WebhooksStore) Remove(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.webhooks = slices.DeleteFunc(s.webhooks, func(w webhooks.Webhook) bool { return w.Id == id })
+
+	return s.journal.append(opRemoveWebhook, webhookEntry{Id: id})
+}
+
+func (s *WebhooksStore) lock() {
+	s.mu.Lock()
+}
+
+func (s *WebhooksStore) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *WebhooksStore) partState() any {
+	return webhooksState{Counter: s.counter, Webhooks: s.webhooks}
+}
+
+func (s *WebhooksStore) restorePart(data json.RawMessage) error {
+	var state webhooksState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter = state.Counter
+	s.webhooks = state.Webhooks
+
+	return nil
+}
+
+func (s *WebhooksStore) applyEntry(entry journalEntry) (bool, error) {
+	ctx := context.Background()
+
+	switch entry.Op {
+	case opAddWebhook:
+		var w webhooks.Webhook
+		return true, decode(entry, &w, func() error {
+			s.counter = max(s.counter, w.Id)
+			return s.Add(ctx, w)
+		})
+	case opRemoveWebhook:
+		var data webhookEntry
+		return true, decode(entry, &data, func() error { return s.Remove(ctx, data.Id) })
+	}
+
+	return false, nil
+}
+
+func (s *WebhooksStore) setJournal(journal *Journal) {
+	s.journal = journal
+}
+
+type DeadLettersStore struct {
+	counter int
+	letters []webhooks.DeadLetter
+	mu sync.Mutex
+	journal *Journal
+}
+
+type deadLettersState struct {
+	Counter int `json:"counter"`
+	Letters []webhooks.DeadLetter `json:"letters"`
+}
+
+func NewDeadLettersStore() *DeadLettersStore {
+	return &DeadLettersStore{}
+}
+
+func (s *DeadLettersStore) NextIdentity(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter++
+
+	return s.counter, nil
+}
+
+func (s *DeadLettersStore) Add(ctx context.Context, l webhooks.DeadLetter) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters = append(s.letters, l)
+
+	return s.journal.append(opAddDeadLetter, l)
+}
+
+func (s *DeadLettersStore) List(ctx context.Context, limit int) ([]webhooks.DeadLetter, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	letters := slices.Clone(s.letters)
+	s.mu.Unlock()
+
+	slices.SortStableFunc(letters, func(a, b webhooks.DeadLetter) int {
+		return cmp.Or(b.FailedAt.Compare(a.FailedAt), cmp.Compare(b.Id, a.Id))
+	})
+
+	return letters[:min(limit, len(letters))], nil
+}
+
+func (s *DeadLettersStore) lock() {
+	s.mu.Lock()
+}
+
+func (s *DeadLettersStore) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *DeadLettersStore) partState() any {
+	return deadLettersState{Counter: s.counter, Letters: s.letters}
+}
+
+func (s *DeadLettersStore) restorePart(data json.RawMessage) error {
+	var state deadLettersState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter = state.Counter
+	s.letters = state.Letters
+
+	return nil
+}
+
+func (s *DeadLettersStore) applyEntry(entry journalEntry) (bool, error) {
+	if entry.Op != opAddDeadLetter {
+		return false, nil
+	}
+
+	var l webhooks.DeadLetter
+	return true, decode(entry, &l, func() error {
+		s.counter = max(s.counter, l.Id)
+		return s.Add(context.Background(), l)
+	})
+}
+
+func (s *DeadLettersStore) setJournal(journal *Journal) {
+	s.journal = journal
+}
@@ -0,0 +1,26 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/ports/webhooks"
+)
+
+func TestInMemoryWebhooksStore(t *testing.T) {
+	contract := webhooks.WebhooksRepositoryContract{
+		NewStore: func() webhooks.WebhooksRepository {
+			return inmemory.NewWebhooksStore()
+		},
+	}
+	contract.Test(t)
+}
+
+func TestInMemoryDeadLettersStore(t *testing.T) {
+	contract := webhooks.DeadLettersRepositoryContract{
+		NewStore: func() webhooks.DeadLettersRepository {
+			return inmemory.NewDeadLettersStore()
+		},
+	}
+	contract.Test(t)
+}
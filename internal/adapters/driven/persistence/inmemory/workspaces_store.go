@@ -0,0 +1,283 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+)
+
+const (
+	opAddWorkspace = "workspaces.add"
+	opBindWorkspace = "workspaces.bind"
+	opAddWorkspaceAdmin = "workspaces.add_admin"
+	opRemoveWorkspaceAdmin = "workspaces.remove_admin"
+	opReassignWorkspaceAdmins = "workspaces.reassign_user"
+)
+
+type WorkspacesStore struct {
+	counter int
+	workspaces []workspaces.Workspace
+	chats map[string]int
+	// admins are the user ids of the admins of each workspace
+	admins map[int][]int
+	mu sync.Mutex
+	journal *Journal
+}
+
+type workspacesState struct {
+	Counter int `json:"counter"`
+	Workspaces []workspaces.Workspace `json:"workspaces"`
+	Chats map[string]int `json:"chats"`
+	Admins map[int][]int `json:"admins"`
+}
+
+type bindEntry struct {
+	Chat string `json:"chat"`
+	WorkspaceId int `json:"workspace_id"`
+}
+
+type adminEntry struct {
+	WorkspaceId int `json:"workspace_id"`
+	UserId int `json:"user_id"`
+}
+
+// NewWorkspacesStore starts with the default workspace
+func NewWorkspacesStore() *WorkspacesStore {
+	return &WorkspacesStore{
+		counter: workspaces.Default,
+		workspaces: []workspaces.Workspace{{Id: workspaces.Default, Name: "default"}},
+		chats: make(map[string]int),
+		admins: make(map[int][]int),
+	}
+}
+
+func (s *WorkspacesStore) NextIdentity(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter++
+
+	return s.counter, nil
+}
+
+func (s *WorkspacesStore) AdvanceIdentity(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter = max(s.counter, id)
+
+	return nil
+}
+
+func (s *WorkspacesStore) Add(ctx context.Context, w workspaces.Workspace) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if slices.ContainsFunc(s.workspaces, func(existing workspaces.Workspace) bool { return existing.Id == w.Id || existing.Name == w.Name }) {
+		return fmt.Errorf("Workspace %s already exists", w.Name)
+	}
+	s.workspaces = append(s.workspaces, w)
+
+	return s.journal.append(opAddWorkspace, w)
+}
+
+func (s *WorkspacesStore) Get(ctx context.Context, id int) (workspaces.Workspace, error) {
+	return s.find(ctx, func(w workspaces.Workspace) bool { return w.Id == id }, fmt.Sprintf("Workspace with id %d was not found", id))
+}
+
+func (s *WorkspacesStore) GetByName(ctx context.Context, name string) (workspaces.Workspace, error) {
+	return s.find(ctx, func(w workspaces.Workspace) bool { return w.Name == name }, fmt.Sprintf("Workspace %s was not found", name))
+}
+
+func (s *WorkspacesStore) find(ctx context.Context, match func(w workspaces.Workspace) bool, notFound string) (workspaces.Workspace, error) {
+	if err := ctx.Err(); err != nil {
+		return workspaces.Workspace{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.workspaces, match)
+	if i == -1 {
+		return workspaces.Workspace{}, fmt.Errorf("%s", notFound)
+	}
+
+	return s.workspaces[i], nil
+}
+
+func (s *WorkspacesStore) List(ctx context.Context) ([]workspaces.Workspace, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.workspaces), nil
+}
+
+func (s *WorkspacesStore) Bind(ctx context.Context, chat string, workspaceId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats[chat] = workspaceId
+
+	return s.journal.append(opBindWorkspace, bindEntry{Chat: chat, WorkspaceId: workspaceId})
+}
+
+func (s *WorkspacesStore) ForChat(ctx context.Context, chat string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.chats[chat]; ok {
+		return id, nil
+	}
+
+	return workspaces.Default, nil
+}
+
+func (s *WorkspacesStore) Bindings(ctx context.Context) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return maps.Clone(s.chats), nil
+}
+
+func (s *WorkspacesStore) AddAdmin(ctx context.Context, workspaceId int, userId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.Contains(s.admins[workspaceId], userId) {
+		s.admins[workspaceId] = append(s.admins[workspaceId], userId)
+	}
+
+	return s.journal.append(opAddWorkspaceAdmin, adminEntry{WorkspaceId: workspaceId, UserId: userId})
+}
+
+func (s *WorkspacesStore) RemoveAdmin(ctx context.Context, workspaceId int, userId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.admins[workspaceId] = slices.DeleteFunc(s.admins[workspaceId], func(id int) bool { return id == userId })
+
+	return s.journal.append(opRemoveWorkspaceAdmin, adminEntry{WorkspaceId: workspaceId, UserId: userId})
+}
+
+func (s *WorkspacesStore) IsAdmin(ctx context.Context, workspaceId int, userId int) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Contains(s.admins[workspaceId], userId), nil
+}
+
+// ReassignUser makes the target an admin of the workspaces of the source
+func (s *WorkspacesStore) ReassignUser(ctx context.Context, sourceId int, targetId int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for workspaceId, admins := range s.admins {
+		if !slices.Contains(admins, sourceId) {
+			continue
+		}
+		admins = slices.DeleteFunc(admins, func(id int) bool { return id == sourceId || id == targetId })
+		s.admins[workspaceId] = append(admins, targetId)
+	}
+
+	return s.journal.append(opReassignWorkspaceAdmins, reassignEntry{SourceId: sourceId, TargetId: targetId})
+}
+
+func (s *WorkspacesStore) lock() {
+	s.mu.Lock()
+}
+
+func (s *WorkspacesStore) unlock() {
+	s.mu.Unlock()
+}
+
+func (s *WorkspacesStore) partState() any {
+	admins := make(map[int][]int)
+	for workspaceId, userIds := range s.admins {
+		admins[workspaceId] = slices.Clone(userIds)
+	}
+
+	return workspacesState{Counter: s.counter, Workspaces: s.workspaces, Chats: maps.Clone(s.chats), Admins: admins}
+}
+
+func (s *WorkspacesStore) restorePart(data json.RawMessage) error {
+	var state workspacesState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counter = state.Counter
+	s.workspaces = state.Workspaces
+	s.chats = state.Chats
+	if s.chats == nil {
+		s.chats = make(map[string]int)
+	}
+	s.admins = state.Admins
+	if s.admins == nil {
+		s.admins = make(map[int][]int)
+	}
+
+	return nil
+}
+
+func (s *WorkspacesStore) applyEntry(entry journalEntry) (bool, error) {
+	ctx := context.Background()
+
+	switch entry.Op {
+	case opAddWorkspace:
+		var w workspaces.Workspace
+		return true, decode(entry, &w, func() error {
+			s.counter = max(s.counter, w.Id)
+			return s.Add(ctx, w)
+		})
+	case opBindWorkspace:
+		var data bindEntry
+		return true, decode(entry, &data, func() error { return s.Bind(ctx, data.Chat, data.WorkspaceId) })
+	case opAddWorkspaceAdmin:
+		var data adminEntry
+		return true, decode(entry, &data, func() error { return s.AddAdmin(ctx, data.WorkspaceId, data.UserId) })
+	case opRemoveWorkspaceAdmin:
+		var data adminEntry
+		return true, decode(entry, &data, func() error { return s.RemoveAdmin(ctx, data.WorkspaceId, data.UserId) })
+	case opReassignWorkspaceAdmins:
+		var data reassignEntry
+		return true, decode(entry, &data, func() error { return s.ReassignUser(ctx, data.SourceId, data.TargetId) })
+	}
+
+	return false, nil
+}
+
+func (s *WorkspacesStore) setJournal(journal *Journal) {
+	s.journal = journal
+}
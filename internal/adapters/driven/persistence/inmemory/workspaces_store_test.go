@@ -0,0 +1,17 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/ports/workspaces"
+)
+
+func TestInMemoryWorkspacesStore(t *testing.T) {
+	contract := workspaces.WorkspacesRepositoryContract{
+		NewStore: func() workspaces.WorkspacesRepository {
+			return inmemory.NewWorkspacesStore()
+		},
+	}
+	contract.Test(t)
+}
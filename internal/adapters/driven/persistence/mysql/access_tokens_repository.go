@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+)
+
+type AccessTokensRepository struct {
+	connection *sql.DB
+	sequence *sequence
+}
+
+func NewAccessTokensRepository(connection *sql.DB) *AccessTokensRepository {
+	return &AccessTokensRepository{
+		connection: connection,
+		sequence: &sequence{
+			name: "access_token_seq",
+			connection: connection,
+		},
+	}
+}
+
+func (r *AccessTokensRepository) NextIdentity(ctx context.Context) (int, error) {
+	return r.sequence.Next(ctx)
+}
+
+func (r *AccessTokensRepository) Add(ctx context.Context, t users.AccessToken) error {
+	var expiresAt any
+	if !t.ExpiresAt.IsZero() {
+		expiresAt = t.ExpiresAt
+	}
+	_, err := r.connection.ExecContext(
+		ctx,
+		"INSERT INTO access_tokens(id, user_id, workspace_id, name, hash, scopes, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		t.Id, t.UserId, t.WorkspaceId, t.Name, t.Hash, strings.Join(t.Scopes, ","), expiresAt, t.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *AccessTokensRepository) Get(ctx context.Context, id int) (users.AccessToken, error) {
+	row := r.connection.QueryRowContext(ctx, "SELECT id, user_id, workspace_id, name, hash, scopes, expires_at, created_at FROM access_tokens WHERE id = ?", id)
+
+	t, err := scanAccessToken(row)
+	if err == sql.ErrNoRows {
+		return users.AccessToken{}, fmt.Errorf("Access token with id %d was not found", id)
+	}
+
+	return t, err
+}
+
+func (r *AccessTokensRepository) GetByHash(ctx context.Context, hash string) (users.AccessToken, error) {
+	row := r.connection.QueryRowContext(ctx, "SELECT id, user_id, workspace_id, name, hash, scopes, expires_at, created_at FROM access_tokens WHERE hash = ?", hash)
+
+	t, err := scanAccessToken(row)
+	if err == sql.ErrNoRows {
+		return users.AccessToken{}, fmt.Errorf("Access token was not found")
+	}
+
+	return t, err
+}
+
+func (r *AccessTokensRepository) List(ctx context.Context) ([]users.AccessToken, error) {
+	var result []users.AccessToken
+
+	rows, err := r.connection.QueryContext(ctx, "SELECT id, user_id, workspace_id, name, hash, scopes, expires_at, created_at FROM access_tokens ORDER BY id")
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanAccessToken(rows)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *AccessTokensRepository) Remove(ctx context.Context, id int) error {
+	_, err := r.connection.ExecContext(ctx, "DELETE FROM access_tokens WHERE id = ?", id)
+
+	return err
+}
+
+func scanAccessToken(row interface{ Scan(dest ...any) error }) (users.AccessToken, error) {
+	var t users.AccessToken
+	var scopes string
+	var expiresAt sql.NullTime
+	if err := row.Scan(&t.Id, &t.UserId, &t.WorkspaceId, &t.Name, &t.Hash, &scopes, &expiresAt, &t.CreatedAt); err != nil {
+		return users.AccessToken{}, err
+	}
+	if scopes != "" {
+		t.Scopes = strings.Split(scopes, ",")
+	}
+	t.ExpiresAt = expiresAt.Time
+
+	return t, nil
+}
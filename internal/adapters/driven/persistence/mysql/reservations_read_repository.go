@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"slices"
@@ -18,10 +19,10 @@ func NewReservationsReadRepository(connection *sql.DB) *ReservationsReadReposito
 	return &ReservationsReadRepository{connection: connection}
 }
 
-func (r *ReservationsReadRepository) Get(id int) (readmodel.Reservation, error) {
+func (r *ReservationsReadRepository) Get(ctx context.Context, id int) (readmodel.Reservation, error) {
 	var result readmodel.Reservation
 	
-	row := r.connection.QueryRow(baseQuery() + " WHERE r.id = ?", id)
+	row := r.connection.QueryRowContext(ctx, baseQuery() + " WHERE r.id = ?", id)
 
 	if err := row.Scan(
 		&result.Id,
@@ -39,12 +40,12 @@ func (r *ReservationsReadRepository) Get(id int) (readmodel.Reservation, error)
 	return result, nil
 }
 
-func (r *ReservationsReadRepository) Active(t time.Time, tags ...string) ([]readmodel.Reservation, error) {
+func (r *ReservationsReadRepository) Active(ctx context.Context, workspaceId int, t time.Time, tags ...string) ([]readmodel.Reservation, error) {
 	var result []readmodel.Reservation
 	var params []any
-	params = append(params, t, t)
+	params = append(params, t, t, workspaceId, workspaceId)
 	query := baseQuery()
-	conditions := ` WHERE 1=1 AND r.start <= ? AND r.end >= ?`
+	conditions := ` WHERE 1=1 AND r.start <= ? AND r.end > ? AND (s.workspace_id = ? OR s.id IN (SELECT subject_id FROM subject_workspaces WHERE workspace_id = ?))`
 
 	if len(tags) > 0 {
 		slices.Sort(tags)
@@ -55,7 +56,7 @@ func (r *ReservationsReadRepository) Active(t time.Time, tags ...string) ([]read
 		params = append(params, strings.Join(tags, ","))
 		conditions += ` AND t.tags LIKE ?`
 	}
-	rows, err := r.connection.Query(
+	rows, err := r.connection.QueryContext(ctx, 
 		query + conditions,
 		params...,
 	)
@@ -82,6 +83,34 @@ func (r *ReservationsReadRepository) Active(t time.Time, tags ...string) ([]read
 	return result, nil
 }
 
+func (r *ReservationsReadRepository) ForSubject(ctx context.Context, subjectId int) ([]readmodel.Reservation, error) {
+	return r.list(ctx, feedQuery("subject_id"), subjectId, subjectId)
+}
+
+func (r *ReservationsReadRepository) ForUser(ctx context.Context, userId int) ([]readmodel.Reservation, error) {
+	return r.list(ctx, feedQuery("user_id"), userId, userId)
+}
+
+func (r *ReservationsReadRepository) list(ctx context.Context, query string, params ...any) ([]readmodel.Reservation, error) {
+	var result []readmodel.Reservation
+
+	rows, err := r.connection.QueryContext(ctx, query, params...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model readmodel.Reservation
+		if err = rows.Scan(&model.Id, &model.Subject, &model.User, &model.Start, &model.End, &model.Cancelled); err != nil {
+			return result, err
+		}
+		result = append(result, model)
+	}
+
+	return result, rows.Err()
+}
+
 func baseQuery() string {
 	return `
 		SELECT r.id, s.name, u.name, r.start, r.end FROM reservations r
@@ -89,3 +118,19 @@ func baseQuery() string {
 		JOIN subjects s on s.id = r.subject_id
 	`
 }
+
+// feedQuery selects the reservations and the cancelled ones having the id in the column, ordered by start
+func feedQuery(column string) string {
+	return fmt.Sprintf(`
+		SELECT r.id, s.name, u.name, r.start, r.end, FALSE FROM reservations r
+		JOIN users u on u.id = r.user_id
+		JOIN subjects s on s.id = r.subject_id
+		WHERE r.%[1]s = ?
+		UNION ALL
+		SELECT r.id, s.name, u.name, r.start, r.end, TRUE FROM cancelled_reservations r
+		JOIN users u on u.id = r.user_id
+		JOIN subjects s on s.id = r.subject_id
+		WHERE r.%[1]s = ?
+		ORDER BY 4, 1
+	`, column)
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -9,7 +10,7 @@ import (
 )
 
 type ReservationsRepository struct {
-	connection *sql.DB
+	connection executor
 	sequence *sequence
 }
 
@@ -23,14 +24,18 @@ func NewReservationsRepository(connection *sql.DB) *ReservationsRepository {
 	}
 }
 
-func (r *ReservationsRepository) NextIdentity() (int, error) {
-	return r.sequence.Next()
+func (r *ReservationsRepository) NextIdentity(ctx context.Context) (int, error) {
+	return r.sequence.Next(ctx)
 }
 
-func (r *ReservationsRepository) List() (reservations.Reservations, error) {
+func (r *ReservationsRepository) AdvanceIdentity(ctx context.Context, id int) error {
+	return r.sequence.Advance(ctx, id)
+}
+
+func (r *ReservationsRepository) List(ctx context.Context) (reservations.Reservations, error) {
 	var result reservations.Reservations 
 
-	rows, err := r.connection.Query("SELECT*FROM reservations")
+	rows, err := r.connection.QueryContext(ctx, "SELECT*FROM reservations")
 
 	if err != nil {
 		return result, err
@@ -53,17 +58,30 @@ func (r *ReservationsRepository) List() (reservations.Reservations, error) {
 	return result, err
 }
 
-func (r *ReservationsRepository) Add(record reservations.Reservation) error {
-	_, err := r.connection.Exec("INSERT INTO reservations(id, user_id, subject_id, start, end) VALUES(?,?,?,?,?)", record.Id, record.UserId, record.SubjectId, record.Start, record.End)
+func (r *ReservationsRepository) Add(ctx context.Context, record reservations.Reservation) error {
+	_, err := r.connection.ExecContext(ctx, "INSERT INTO reservations(id, user_id, subject_id, start, end) VALUES(?,?,?,?,?)", record.Id, record.UserId, record.SubjectId, record.Start, record.End)
 
 	return err
 }
 
-func (r *ReservationsRepository) Get(id int) (reservations.Reservation, error) {
+func (r *ReservationsRepository) AddMany(ctx context.Context, records reservations.Reservations) error {
+	return transaction(ctx, r.connection, func(tx executor) error {
+		for _, record := range records {
+			_, err := tx.ExecContext(ctx, "INSERT INTO reservations(id, user_id, subject_id, start, end) VALUES(?,?,?,?,?)", record.Id, record.UserId, record.SubjectId, record.Start, record.End)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *ReservationsRepository) Get(ctx context.Context, id int) (reservations.Reservation, error) {
 	var result reservations.Reservation
 	var err error
 
-	row := r.connection.QueryRow("SELECT*FROM reservations WHERE id = ?", id)
+	row := r.connection.QueryRowContext(ctx, "SELECT*FROM reservations WHERE id = ?", id)
 
 	if err = row.Scan(&result.Id, &result.UserId, &result.SubjectId, &result.Start, &result.End); err != nil {
 		if err == sql.ErrNoRows {
@@ -74,21 +92,35 @@ func (r *ReservationsRepository) Get(id int) (reservations.Reservation, error) {
 	return result, err
 }
 
-func (r *ReservationsRepository) Remove(id int) error {
-	_, err := r.connection.Exec("DELETE FROM reservations WHERE id = ?", id)
+func (r *ReservationsRepository) Remove(ctx context.Context, id int) error {
+	return transaction(ctx, r.connection, func(tx executor) error {
+		queries := []string{
+			"DELETE FROM cancelled_reservations WHERE id = ?",
+			`INSERT INTO cancelled_reservations(id, user_id, subject_id, start, end) SELECT id, user_id, subject_id, start, end FROM reservations WHERE id = ?`,
+			"DELETE FROM reservations WHERE id = ?",
+		}
+		for _, query := range queries {
+			if _, err := tx.ExecContext(ctx, query, id); err != nil {
+				return err
+			}
+		}
 
-	return err
+		return nil
+	})
 }
 
-func (r *ReservationsRepository) ForPeriod(from time.Time, to time.Time) (reservations.Reservations, error) {
+func (r *ReservationsRepository) ForPeriod(ctx context.Context, from time.Time, to time.Time) (reservations.Reservations, error) {
 	var result reservations.Reservations 
 
-	rows, err := r.connection.Query(
-		"SELECT*FROM reservations where (start BETWEEN ? AND ?) OR (end BETWEEN ? AND ?)",
-		from,
+	if reservations.NewInterval(from, to).IsEmpty() {
+		return result, nil
+	}
+
+	// reservations are half-open intervals [start, end), see reservations.Interval
+	rows, err := r.connection.QueryContext(ctx, 
+		"SELECT*FROM reservations WHERE start < ? AND end > ? AND start < end",
 		to,
 		from,
-		to,
 	)
 
 	if err != nil {
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 )
@@ -10,13 +11,20 @@ type sequence struct {
 	connection *sql.DB
 }
 
-func (seq *sequence) Next() (int, error) {
-	result, err := seq.connection.Exec(fmt.Sprintf("UPDATE %s SET value = LAST_INSERT_ID(value+1)", seq.name))
+func (seq *sequence) Next(ctx context.Context) (int, error) {
+	result, err := seq.connection.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET value = LAST_INSERT_ID(value+1)", seq.name))
 	if err != nil {
-		return 0, nil
+		return 0, err
 	}
 
 	id, err := result.LastInsertId()
 
 	return int(id), err
 }
+
+// Advance makes Next hand out values above id
+func (seq *sequence) Advance(ctx context.Context, id int) error {
+	_, err := seq.connection.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET value = GREATEST(value, ?)", seq.name), id)
+
+	return err
+}
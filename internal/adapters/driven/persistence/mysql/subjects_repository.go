@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"slices"
@@ -24,22 +25,26 @@ func NewSubjectsRepository(connection *sql.DB) *SubjectsRepository {
 	}
 }
 
-func (s *SubjectsRepository) NextIdentity() (int, error) {
-	return s.sequence.Next()
+func (s *SubjectsRepository) NextIdentity(ctx context.Context) (int, error) {
+	return s.sequence.Next(ctx)
 }
 
-func (s *SubjectsRepository) Add(subject reservations.Subject) error {
-	_, err := s.connection.Exec("INSERT INTO subjects(id, name) VALUES (?, ?)", subject.Id, subject.Name)
+func (s *SubjectsRepository) AdvanceIdentity(ctx context.Context, id int) error {
+	return s.sequence.Advance(ctx, id)
+}
+
+func (s *SubjectsRepository) Add(ctx context.Context, subject reservations.Subject) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO subjects(id, name, parent_id, workspace_id) VALUES (?, ?, ?, ?)", subject.Id, subject.Name, nullableId(subject.ParentId), subject.WorkspaceId)
 
 	return err
 }
 
-func (s *SubjectsRepository) Get(id int) (reservations.Subject, error) {
+func (s *SubjectsRepository) Get(ctx context.Context, id int) (reservations.Subject, error) {
 	subject := reservations.Subject{}
 
-	row := s.connection.QueryRow("SELECT*FROM subjects WHERE id = ?", id)
+	row := s.connection.QueryRowContext(ctx, subjectsQuery() + " WHERE id = ?", id)
 
-	if err := row.Scan(&subject.Id, &subject.Name); err != nil {
+	if err := row.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId); err != nil {
 		if err == sql.ErrNoRows {
 			return reservations.Subject{}, fmt.Errorf("Subject with id %d was not found", id)
 		}
@@ -50,17 +55,17 @@ func (s *SubjectsRepository) Get(id int) (reservations.Subject, error) {
 	return subject, nil
 }
 
-func (s *SubjectsRepository) List() (reservations.Subjects, error) {
+func (s *SubjectsRepository) List(ctx context.Context) (reservations.Subjects, error) {
 	var subjects reservations.Subjects
 
-	rows, err := s.connection.Query("SELECT*FROM subjects")
+	rows, err := s.connection.QueryContext(ctx, subjectsQuery())
 	if err != nil {
 		return subjects, err
 	}
 	
 	for rows.Next() {
 		var subject reservations.Subject
-		if err = rows.Scan(&subject.Id, &subject.Name); err != nil {
+		if err = rows.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId); err != nil {
 			return subjects, err
 		}
 		subjects = append(subjects, subject)
@@ -69,21 +74,88 @@ func (s *SubjectsRepository) List() (reservations.Subjects, error) {
 	return subjects, nil
 }
 
-func (s *SubjectsRepository) Remove(id int) error {
-	_, err := s.connection.Exec("DELETE FROM subjects WHERE id = ?", id)
+func (s *SubjectsRepository) InWorkspace(ctx context.Context, workspaceId int) (reservations.Subjects, error) {
+	var subjects reservations.Subjects
+
+	rows, err := s.connection.QueryContext(ctx, subjectsQuery() + " WHERE " + visibleQuery() + " ORDER BY id", workspaceId, workspaceId)
+	if err != nil {
+		return subjects, err
+	}
+
+	for rows.Next() {
+		var subject reservations.Subject
+		if err = rows.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId); err != nil {
+			return subjects, err
+		}
+		subjects = append(subjects, subject)
+	}
+
+	return subjects, nil
+}
+
+func (s *SubjectsRepository) Share(ctx context.Context, id int, workspaceId int) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO subject_workspaces(subject_id, workspace_id) VALUES (?, ?)", id, workspaceId)
 
 	return err
 }
 
-func (s *SubjectsRepository) AddTag(id int, tag string) error {
-	_, err := s.connection.Exec("INSERT INTO subject_tags VALUES (?, ?)", id, tag)
+func (s *SubjectsRepository) Unshare(ctx context.Context, id int, workspaceId int) error {
+	result, err := s.connection.ExecContext(ctx, "DELETE FROM subject_workspaces WHERE subject_id = ? AND workspace_id = ?", id, workspaceId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("Subject with id %d is not shared with workspace %d", id, workspaceId)
+	}
+
+	return nil
+}
+
+func (s *SubjectsRepository) SharedWith(ctx context.Context, id int) ([]int, error) {
+	var workspaceIds []int
+	rows, err := s.connection.QueryContext(ctx, "SELECT workspace_id FROM subject_workspaces WHERE subject_id = ? ORDER BY workspace_id", id)
+	if err != nil {
+		return workspaceIds, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workspaceId int
+		if err = rows.Scan(&workspaceId); err != nil {
+			return workspaceIds, err
+		}
+		workspaceIds = append(workspaceIds, workspaceId)
+	}
+
+	return workspaceIds, rows.Err()
+}
+
+func (s *SubjectsRepository) Remove(ctx context.Context, id int) error {
+	if _, err := s.connection.ExecContext(ctx, "DELETE FROM subject_workspaces WHERE subject_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := s.connection.ExecContext(ctx, "DELETE FROM subject_aliases WHERE subject_id = ?", id); err != nil {
+		return err
+	}
+	_, err := s.connection.ExecContext(ctx, "DELETE FROM subjects WHERE id = ?", id)
+
+	return err
+}
+
+func (s *SubjectsRepository) AddTag(ctx context.Context, id int, tag string) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO subject_tags VALUES (?, ?)", id, tag)
 
 	return err
 }
 
-func (s *SubjectsRepository) GetTags(id int) ([]string, error) {
+func (s *SubjectsRepository) GetTags(ctx context.Context, id int) ([]string, error) {
 	var tags []string
-	rows, err := s.connection.Query("SELECT tag FROM subject_tags WHERE subject_id = ?", id)
+	rows, err := s.connection.QueryContext(ctx, "SELECT tag FROM subject_tags WHERE subject_id = ?", id)
 	if err != nil {
 		return tags, err
 	}
@@ -99,15 +171,42 @@ func (s *SubjectsRepository) GetTags(id int) ([]string, error) {
 	return tags, nil
 }
 
-func (s *SubjectsRepository) GetByTags(tags []string) (reservations.Subjects, error) {
+func (s *SubjectsRepository) AddAlias(ctx context.Context, id int, alias string) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO subject_aliases(subject_id, alias) VALUES (?, ?)", id, alias)
+
+	return err
+}
+
+func (s *SubjectsRepository) GetAliases(ctx context.Context, id int) ([]string, error) {
+	var aliases []string
+	rows, err := s.connection.QueryContext(ctx, "SELECT alias FROM subject_aliases WHERE subject_id = ? ORDER BY alias", id)
+	if err != nil {
+		return aliases, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alias string
+		if err = rows.Scan(&alias); err != nil {
+			return aliases, err
+		}
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, rows.Err()
+}
+
+func (s *SubjectsRepository) GetByTags(ctx context.Context, workspaceId int, tags []string) (reservations.Subjects, error) {
 	var subjects reservations.Subjects
 	slices.Sort(tags)
 
-	rows, err := s.connection.Query(`
-		SELECT s.id, s.name, t.tags FROM subjects AS s
+	rows, err := s.connection.QueryContext(ctx, `
+		SELECT s.id, s.name, COALESCE(s.parent_id, 0), s.workspace_id, t.tags FROM subjects AS s
 		JOIN (SELECT subject_id, GROUP_CONCAT(tag ORDER BY tag) AS tags FROM subject_tags GROUP BY subject_id) t ON t.subject_id = s.id
-		WHERE t.tags LIKE ?`,
+		WHERE t.tags LIKE ? AND ` + visibleQuery(),
 		"%" + strings.Join(tags, ",") + "%",
+		workspaceId,
+		workspaceId,
 	)
 
 	if err != nil {
@@ -117,7 +216,7 @@ func (s *SubjectsRepository) GetByTags(tags []string) (reservations.Subjects, er
 	for rows.Next() {
 		var subject reservations.Subject
 		var rowTags string
-		err = rows.Scan(&subject.Id, &subject.Name, &rowTags)
+		err = rows.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId, &rowTags)
 		if err != nil {
 			return reservations.Subjects{}, err
 		}
@@ -127,12 +226,12 @@ func (s *SubjectsRepository) GetByTags(tags []string) (reservations.Subjects, er
 	return subjects, nil
 }
 
-func (s *SubjectsRepository) GetByName(name string) (reservations.Subject, error) {
+func (s *SubjectsRepository) GetByName(ctx context.Context, workspaceId int, name string) (reservations.Subject, error) {
 	subject := reservations.Subject{}
 
-	row := s.connection.QueryRow("SELECT*FROM subjects WHERE name = ?", name)
+	row := s.connection.QueryRowContext(ctx, subjectsQuery() + " WHERE name = ? AND " + visibleQuery(), name, workspaceId, workspaceId)
 
-	if err := row.Scan(&subject.Id, &subject.Name); err != nil {
+	if err := row.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId); err != nil {
 		if err == sql.ErrNoRows {
 			return reservations.Subject{}, fmt.Errorf("Subject with name %s was not found", name)
 		}
@@ -142,3 +241,83 @@ func (s *SubjectsRepository) GetByName(name string) (reservations.Subject, error
 
 	return subject, nil
 }
+
+func (s *SubjectsRepository) FindByName(ctx context.Context, workspaceId int, name string) (reservations.Subjects, error) {
+	var subjects reservations.Subjects
+
+	rows, err := s.connection.QueryContext(ctx, subjectsQuery() + " WHERE " + namedQuery() + " AND " + visibleQuery() + " ORDER BY id", name, name, workspaceId, workspaceId)
+	if err != nil {
+		return subjects, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var subject reservations.Subject
+		if err = rows.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId); err != nil {
+			return subjects, err
+		}
+		subjects = append(subjects, subject)
+	}
+
+	return subjects, rows.Err()
+}
+
+func (s *SubjectsRepository) SetParent(ctx context.Context, id int, parentId int) error {
+	result, err := s.connection.ExecContext(ctx, "UPDATE subjects SET parent_id = ? WHERE id = ?", nullableId(parentId), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		if _, err = s.Get(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SubjectsRepository) Children(ctx context.Context, id int) (reservations.Subjects, error) {
+	subjects := reservations.Subjects{}
+
+	rows, err := s.connection.QueryContext(ctx, subjectsQuery() + " WHERE parent_id = ?", id)
+	if err != nil {
+		return subjects, err
+	}
+
+	for rows.Next() {
+		var subject reservations.Subject
+		if err = rows.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId); err != nil {
+			return subjects, err
+		}
+		subjects = append(subjects, subject)
+	}
+
+	return subjects, nil
+}
+
+func subjectsQuery() string {
+	return "SELECT id, name, COALESCE(parent_id, 0), workspace_id FROM subjects"
+}
+
+// namedQuery matches subjects named or aliased so ignoring case, the name is bound twice
+func namedQuery() string {
+	return "(LOWER(name) = LOWER(?) OR id IN (SELECT subject_id FROM subject_aliases WHERE LOWER(alias) = LOWER(?)))"
+}
+
+// visibleQuery matches subjects owned by or shared with the workspace, its id is bound twice
+func visibleQuery() string {
+	return "(workspace_id = ? OR id IN (SELECT subject_id FROM subject_workspaces WHERE workspace_id = ?))"
+}
+
+func nullableId(id int) any {
+	if id == 0 {
+		return nil
+	}
+
+	return id
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -18,22 +19,22 @@ func NewTelegramUsersRepository(connection *sql.DB) *TelegramUsersRepository {
 	}
 }
 
-func (s *TelegramUsersRepository) Add(u telegram.TelegramUser) error {
-	_, err := s.connection.Exec("INSERT INTO telegram_users(telegram_id, user_id) VALUES (?, ?)", u.TelegramId, u.Id)
+func (s *TelegramUsersRepository) Add(ctx context.Context, u telegram.TelegramUser) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO telegram_users(telegram_id, user_id) VALUES (?, ?)", u.TelegramId, u.Id)
 
 	return err
 }
 
-func (s *TelegramUsersRepository) Get(tgId int64) (telegram.TelegramUser, error) {
+func (s *TelegramUsersRepository) Get(ctx context.Context, tgId int64) (telegram.TelegramUser, error) {
 	var u telegram.TelegramUser
 
-	row := s.connection.QueryRow(`
-		SELECT u.*, tg.telegram_id FROM users u 
+	row := s.connection.QueryRowContext(ctx, `
+		SELECT u.id, u.name, COALESCE(u.email, ''), COALESCE(u.password, ''), u.time_zone, u.language, tg.telegram_id FROM users u 
 		JOIN telegram_users tg ON u.id = tg.user_id
 		WHERE tg.telegram_id = ?
 	`, tgId)
 
-	if err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TelegramId); err != nil {
+	if err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language, &u.TelegramId); err != nil {
 		if err == sql.ErrNoRows {
 			return u, fmt.Errorf("User with telegram id %d was not found", tgId)
 		}
@@ -43,3 +44,34 @@ func (s *TelegramUsersRepository) Get(tgId int64) (telegram.TelegramUser, error)
 
 	return u, nil
 }
+
+func (s *TelegramUsersRepository) List(ctx context.Context) ([]telegram.TelegramUser, error) {
+	var result []telegram.TelegramUser
+
+	rows, err := s.connection.QueryContext(ctx, `
+		SELECT u.id, u.name, COALESCE(u.email, ''), COALESCE(u.password, ''), u.time_zone, u.language, tg.telegram_id FROM users u
+		JOIN telegram_users tg ON u.id = tg.user_id
+		ORDER BY tg.telegram_id
+	`)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u telegram.TelegramUser
+		if err = rows.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language, &u.TelegramId); err != nil {
+			return result, err
+		}
+		result = append(result, u)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *TelegramUsersRepository) CountByUser(ctx context.Context, userId int) (int, error) {
+	var count int
+	err := s.connection.QueryRowContext(ctx, "SELECT COUNT(*) FROM telegram_users WHERE user_id = ?", userId).Scan(&count)
+
+	return count, err
+}
@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	reservationPorts "github.com/SneedusSnake/Reservations/internal/ports/reservations"
+)
+
+type UnitOfWork struct {
+	connection *sql.DB
+}
+
+func NewUnitOfWork(connection *sql.DB) *UnitOfWork {
+	return &UnitOfWork{connection: connection}
+}
+
+// Execute locks the rows of the given subjects with SELECT ... FOR UPDATE until the transaction ends.
+// Subjects are locked in ascending order of ids to avoid deadlocks between concurrent transactions
+func (u *UnitOfWork) Execute(ctx context.Context, subjectIds []int, fn func(reservations reservationPorts.ReservationsRepository) error) error {
+	tx, err := u.connection.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if len(subjectIds) > 0 {
+		ids := slices.Sorted(slices.Values(subjectIds))
+		params := make([]any, len(ids))
+		for i, id := range ids {
+			params[i] = id
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+		rows, err := tx.QueryContext(ctx, "SELECT id FROM subjects WHERE id IN (" + placeholders + ") ORDER BY id FOR UPDATE", params...)
+		if err != nil {
+			return err
+		}
+		rows.Close()
+	}
+
+	reservations := &ReservationsRepository{
+		connection: tx,
+		sequence: &sequence{
+			name: "reservation_seq",
+			connection: u.connection,
+		},
+	}
+	if err = fn(reservations); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
 	"database/sql"
 	"github.com/SneedusSnake/Reservations/internal/domain/users"
@@ -21,22 +22,26 @@ func NewUsersRepository(connection *sql.DB) *UsersRepository {
 	}
 }
 
-func (s *UsersRepository) NextIdentity() (int, error) {
-	return s.sequence.Next()
+func (s *UsersRepository) NextIdentity(ctx context.Context) (int, error) {
+	return s.sequence.Next(ctx)
 }
 
-func (s *UsersRepository) Add(u users.User) error {
-	_, err := s.connection.Exec("INSERT INTO users(id, name, email, password) VALUES (?, ?, ?, ?)", u.Id, u.Name, u.Email, u.Password)
+func (s *UsersRepository) AdvanceIdentity(ctx context.Context, id int) error {
+	return s.sequence.Advance(ctx, id)
+}
+
+func (s *UsersRepository) Add(ctx context.Context, u users.User) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO users(id, name, email, password, time_zone, language) VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)", u.Id, u.Name, u.Email, u.Password, u.TimeZone, u.Language)
 
 	return err
 }
 
-func (s *UsersRepository) Get(id int) (users.User, error) {
+func (s *UsersRepository) Get(ctx context.Context, id int) (users.User, error) {
 	u := users.User{}
 
-	row := s.connection.QueryRow("SELECT*FROM users WHERE id = ?", id)
+	row := s.connection.QueryRowContext(ctx, "SELECT id, name, COALESCE(email, ''), COALESCE(password, ''), time_zone, language FROM users WHERE id = ?", id)
 
-	if err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password); err != nil {
+	if err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language); err != nil {
 		if err == sql.ErrNoRows {
 			return users.User{}, fmt.Errorf("User with id %d was not found", id)
 		}
@@ -47,8 +52,62 @@ func (s *UsersRepository) Get(id int) (users.User, error) {
 	return u, nil
 }
 
-func (s *UsersRepository) Remove(id int) error {
-	_, err := s.connection.Exec("DELETE FROM users WHERE id = ?", id)
+func (s *UsersRepository) GetByEmail(ctx context.Context, email string) (users.User, error) {
+	u := users.User{}
+
+	row := s.connection.QueryRowContext(ctx, "SELECT id, name, email, COALESCE(password, ''), time_zone, language FROM users WHERE email = ?", email)
+
+	if err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language); err != nil {
+		if err == sql.ErrNoRows {
+			return users.User{}, fmt.Errorf("User with email %s was not found", email)
+		}
+
+		return users.User{}, err
+	}
+
+	return u, nil
+}
+
+func (s *UsersRepository) Update(ctx context.Context, u users.User) error {
+	result, err := s.connection.ExecContext(
+		ctx,
+		"UPDATE users SET name = ?, email = NULLIF(?, ''), password = NULLIF(?, ''), time_zone = ?, language = ? WHERE id = ?",
+		u.Name, u.Email, u.Password, u.TimeZone, u.Language, u.Id,
+	)
+	if err != nil {
+		return err
+	}
+	// MySQL does not count the rows left as they were
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		_, err = s.Get(ctx, u.Id)
+		return err
+	}
+
+	return nil
+}
+
+func (s *UsersRepository) List(ctx context.Context) ([]users.User, error) {
+	var result []users.User
+
+	rows, err := s.connection.QueryContext(ctx, "SELECT id, name, COALESCE(email, ''), COALESCE(password, ''), time_zone, language FROM users ORDER BY id")
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u users.User
+		if err = rows.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language); err != nil {
+			return result, err
+		}
+		result = append(result, u)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *UsersRepository) Remove(ctx context.Context, id int) error {
+	_, err := s.connection.ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
 
 	return err
 }
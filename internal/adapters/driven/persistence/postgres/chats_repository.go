@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+)
+
+type ChatsRepository struct {
+	connection *sql.DB
+}
+
+func NewChatsRepository(connection *sql.DB) *ChatsRepository {
+	return &ChatsRepository{connection: connection}
+}
+
+func (r *ChatsRepository) Save(ctx context.Context, chat telegram.Chat) error {
+	_, err := r.connection.ExecContext(ctx, "INSERT INTO telegram_chats(chat_id, language) VALUES ($1, $2) ON CONFLICT (chat_id) DO UPDATE SET language = EXCLUDED.language", chat.Id, chat.Language)
+
+	return err
+}
+
+func (r *ChatsRepository) Get(ctx context.Context, id int64) (telegram.Chat, error) {
+	var chat telegram.Chat
+
+	row := r.connection.QueryRowContext(ctx, "SELECT chat_id, language FROM telegram_chats WHERE chat_id = $1", id)
+	err := row.Scan(&chat.Id, &chat.Language)
+	if err == sql.ErrNoRows {
+		return telegram.Chat{}, telegram.ErrChatNotFound
+	}
+
+	return chat, err
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	readmodel "github.com/SneedusSnake/Reservations/internal/read_model"
+)
+
+type ReservationsReadRepository struct {
+	connection *sql.DB
+}
+
+func NewReservationsReadRepository(connection *sql.DB) *ReservationsReadRepository {
+	return &ReservationsReadRepository{connection: connection}
+}
+
+func (r *ReservationsReadRepository) Get(ctx context.Context, id int) (readmodel.Reservation, error) {
+	var result readmodel.Reservation
+
+	row := r.connection.QueryRowContext(ctx, baseQuery() + " WHERE r.id = $1", id)
+
+	if err := row.Scan(
+		&result.Id,
+		&result.Subject,
+		&result.User,
+		&result.Start,
+		&result.End,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("Reservation with id %d was not found", id)
+		}
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (r *ReservationsReadRepository) Active(ctx context.Context, workspaceId int, t time.Time, tags ...string) ([]readmodel.Reservation, error) {
+	var result []readmodel.Reservation
+	params := []any{t, workspaceId}
+	query := baseQuery() + ` WHERE r.start <= $1 AND r."end" > $1 AND (s.workspace_id = $2 OR s.id IN (SELECT subject_id FROM subject_workspaces WHERE workspace_id = $2))`
+
+	if len(tags) > 0 {
+		query += ` AND s.id IN (SELECT subject_id FROM subject_tags WHERE tag = ANY($3) GROUP BY subject_id HAVING COUNT(DISTINCT tag) = $4)`
+		params = append(params, tags, len(tags))
+	}
+
+	rows, err := r.connection.QueryContext(ctx, query + " ORDER BY r.id", params...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model readmodel.Reservation
+		err = rows.Scan(
+			&model.Id,
+			&model.Subject,
+			&model.User,
+			&model.Start,
+			&model.End,
+		)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, model)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *ReservationsReadRepository) ForSubject(ctx context.Context, subjectId int) ([]readmodel.Reservation, error) {
+	return r.list(ctx, feedQuery("subject_id"), subjectId)
+}
+
+func (r *ReservationsReadRepository) ForUser(ctx context.Context, userId int) ([]readmodel.Reservation, error) {
+	return r.list(ctx, feedQuery("user_id"), userId)
+}
+
+func (r *ReservationsReadRepository) list(ctx context.Context, query string, params ...any) ([]readmodel.Reservation, error) {
+	var result []readmodel.Reservation
+
+	rows, err := r.connection.QueryContext(ctx, query, params...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model readmodel.Reservation
+		if err = rows.Scan(&model.Id, &model.Subject, &model.User, &model.Start, &model.End, &model.Cancelled); err != nil {
+			return result, err
+		}
+		result = append(result, model)
+	}
+
+	return result, rows.Err()
+}
+
+func baseQuery() string {
+	return `
+		SELECT r.id, s.name, u.name, r.start, r."end" FROM reservations r
+		JOIN users u on u.id = r.user_id
+		JOIN subjects s on s.id = r.subject_id
+	`
+}
+
+// feedQuery selects the reservations and the cancelled ones having the id in the column, ordered by start
+func feedQuery(column string) string {
+	return fmt.Sprintf(`
+		SELECT r.id, s.name, u.name, r.start, r."end", FALSE FROM reservations r
+		JOIN users u on u.id = r.user_id
+		JOIN subjects s on s.id = r.subject_id
+		WHERE r.%[1]s = $1
+		UNION ALL
+		SELECT r.id, s.name, u.name, r.start, r."end", TRUE FROM cancelled_reservations r
+		JOIN users u on u.id = r.user_id
+		JOIN subjects s on s.id = r.subject_id
+		WHERE r.%[1]s = $1
+		ORDER BY 4, 1
+	`, column)
+}
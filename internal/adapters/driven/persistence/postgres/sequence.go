@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type sequence struct {
+	name string
+	connection *sql.DB
+}
+
+func (seq *sequence) Next(ctx context.Context) (int, error) {
+	var id int
+	err := seq.connection.QueryRowContext(ctx, "SELECT nextval($1)", seq.name).Scan(&id)
+
+	return id, err
+}
+
+// Advance makes Next hand out values above id
+func (seq *sequence) Advance(ctx context.Context, id int) error {
+	_, err := seq.connection.ExecContext(ctx, fmt.Sprintf("SELECT setval($1, GREATEST($2, last_value)) FROM %s", seq.name), seq.name, id)
+
+	return err
+}
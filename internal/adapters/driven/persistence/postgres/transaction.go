@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+// executor is implemented by both *sql.DB and *sql.Tx, so repositories can run inside a transaction
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// transaction runs fn in a new transaction, or in the ongoing one if connection is already a transaction
+func transaction(ctx context.Context, connection executor, fn func(tx executor) error) error {
+	db, ok := connection.(*sql.DB)
+	if !ok {
+		return fn(connection)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
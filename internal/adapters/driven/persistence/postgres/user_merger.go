@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+)
+
+// userTables reference users by their user_id column
+var userTables = []string{"reservations", "cancelled_reservations", "telegram_users", "chat_users", "access_tokens", "workspace_admins"}
+
+type UserMerger struct {
+	connection *sql.DB
+}
+
+func NewUserMerger(connection *sql.DB) *UserMerger {
+	return &UserMerger{connection: connection}
+}
+
+// Merge runs in a transaction holding row locks on both users
+func (m *UserMerger) Merge(ctx context.Context, sourceId int, target users.User) error {
+	tx, err := m.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// concurrent merges of the same users wait for this one instead of moving records to a removed user
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM users WHERE id IN ($1, $2) ORDER BY id FOR UPDATE", sourceId, target.Id)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+
+	// the admin rows of the source would collide with those of the target in the workspaces both manage
+	_, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM workspace_admins WHERE user_id = $1 AND workspace_id IN (SELECT workspace_id FROM (SELECT workspace_id FROM workspace_admins WHERE user_id = $2) AS target)",
+		sourceId,
+		target.Id,
+	)
+	if err != nil {
+		return err
+	}
+	for _, table := range userTables {
+		if _, err = tx.ExecContext(ctx, "UPDATE " + table + " SET user_id = $1 WHERE user_id = $2", target.Id, sourceId); err != nil {
+			return err
+		}
+	}
+	// sessions and one-time codes of the source are not carried over
+	if _, err = tx.ExecContext(ctx, "DELETE FROM user_tokens WHERE user_id = $1", sourceId); err != nil {
+		return err
+	}
+	// the source goes first, the target may take over its email
+	if _, err = tx.ExecContext(ctx, "DELETE FROM users WHERE id = $1", sourceId); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE users SET name = $1, email = NULLIF($2, ''), password = NULLIF($3, ''), time_zone = $4, language = $5 WHERE id = $6",
+		target.Name, target.Email, target.Password, target.TimeZone, target.Language, target.Id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
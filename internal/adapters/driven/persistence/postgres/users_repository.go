@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+)
+
+type UsersRepository struct {
+	connection *sql.DB
+	sequence *sequence
+}
+
+func NewUsersRepository(connection *sql.DB) *UsersRepository {
+	return &UsersRepository{
+		connection: connection,
+		sequence: &sequence{
+			name: "user_seq",
+			connection: connection,
+		},
+	}
+}
+
+func (s *UsersRepository) NextIdentity(ctx context.Context) (int, error) {
+	return s.sequence.Next(ctx)
+}
+
+func (s *UsersRepository) AdvanceIdentity(ctx context.Context, id int) error {
+	return s.sequence.Advance(ctx, id)
+}
+
+func (s *UsersRepository) Add(ctx context.Context, u users.User) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO users(id, name, email, password, time_zone, language) VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6)", u.Id, u.Name, u.Email, u.Password, u.TimeZone, u.Language)
+
+	return err
+}
+
+func (s *UsersRepository) Get(ctx context.Context, id int) (users.User, error) {
+	u := users.User{}
+
+	row := s.connection.QueryRowContext(ctx, "SELECT id, name, COALESCE(email, ''), COALESCE(password, ''), time_zone, language FROM users WHERE id = $1", id)
+
+	if err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language); err != nil {
+		if err == sql.ErrNoRows {
+			return users.User{}, fmt.Errorf("User with id %d was not found", id)
+		}
+
+		return users.User{}, err
+	}
+
+	return u, nil
+}
+
+func (s *UsersRepository) GetByEmail(ctx context.Context, email string) (users.User, error) {
+	u := users.User{}
+
+	row := s.connection.QueryRowContext(ctx, "SELECT id, name, email, COALESCE(password, ''), time_zone, language FROM users WHERE email = $1", email)
+
+	if err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language); err != nil {
+		if err == sql.ErrNoRows {
+			return users.User{}, fmt.Errorf("User with email %s was not found", email)
+		}
+
+		return users.User{}, err
+	}
+
+	return u, nil
+}
+
+func (s *UsersRepository) Update(ctx context.Context, u users.User) error {
+	result, err := s.connection.ExecContext(
+		ctx,
+		"UPDATE users SET name = $1, email = NULLIF($2, ''), password = NULLIF($3, ''), time_zone = $4, language = $5 WHERE id = $6",
+		u.Name, u.Email, u.Password, u.TimeZone, u.Language, u.Id,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		_, err = s.Get(ctx, u.Id)
+		return err
+	}
+
+	return nil
+}
+
+func (s *UsersRepository) List(ctx context.Context) ([]users.User, error) {
+	var result []users.User
+
+	rows, err := s.connection.QueryContext(ctx, "SELECT id, name, COALESCE(email, ''), COALESCE(password, ''), time_zone, language FROM users ORDER BY id")
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u users.User
+		if err = rows.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language); err != nil {
+			return result, err
+		}
+		result = append(result, u)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *UsersRepository) Remove(ctx context.Context, id int) error {
+	_, err := s.connection.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+
+	return err
+}
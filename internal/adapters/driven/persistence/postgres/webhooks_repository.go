@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/webhooks"
+)
+
+type WebhooksRepository struct {
+	connection *sql.DB
+	sequence *sequence
+}
+
+func NewWebhooksRepository(connection *sql.DB) *WebhooksRepository {
+	return &WebhooksRepository{
+		connection: connection,
+		sequence: &sequence{
+			name: "webhook_seq",
+			connection: connection,
+		},
+	}
+}
+
+func (r *WebhooksRepository) NextIdentity(ctx context.Context) (int, error) {
+	return r.sequence.Next(ctx)
+}
+
+func (r *WebhooksRepository) Add(ctx context.Context, w webhooks.Webhook) error {
+	_, err := r.connection.ExecContext(ctx, "INSERT INTO webhooks(id, url, secret, events) VALUES ($1, $2, $3, $4)", w.Id, w.Url, w.Secret, strings.Join(w.Events, ","))
+
+	return err
+}
+
+func (r *WebhooksRepository) Get(ctx context.Context, id int) (webhooks.Webhook, error) {
+	row := r.connection.QueryRowContext(ctx, "SELECT id, url, secret, events FROM webhooks WHERE id = $1", id)
+
+	w, err := scanWebhook(row)
+	if err == sql.ErrNoRows {
+		return webhooks.Webhook{}, fmt.Errorf("Webhook with id %d was not found", id)
+	}
+
+	return w, err
+}
+
+func (r *WebhooksRepository) List(ctx context.Context) ([]webhooks.Webhook, error) {
+	var result []webhooks.Webhook
+
+	rows, err := r.connection.QueryContext(ctx, "SELECT id, url, secret, events FROM webhooks ORDER BY id")
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, w)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *WebhooksRepository) Remove(ctx context.Context, id int) error {
+	_, err := r.connection.ExecContext(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+
+	return err
+}
+
+func scanWebhook(row interface{ Scan(dest ...any) error }) (webhooks.Webhook, error) {
+	var w webhooks.Webhook
+	var events string
+	if err := row.Scan(&w.Id, &w.Url, &w.Secret, &events); err != nil {
+		return webhooks.Webhook{}, err
+	}
+	if events != "" {
+		w.Events = strings.Split(events, ",")
+	}
+
+	return w, nil
+}
+
+type DeadLettersRepository struct {
+	connection *sql.DB
+	sequence *sequence
+}
+
+func NewDeadLettersRepository(connection *sql.DB) *DeadLettersRepository {
+	return &DeadLettersRepository{
+		connection: connection,
+		sequence: &sequence{
+			name: "dead_letter_seq",
+			connection: connection,
+		},
+	}
+}
+
+func (r *DeadLettersRepository) NextIdentity(ctx context.Context) (int, error) {
+	return r.sequence.Next(ctx)
+}
+
+func (r *DeadLettersRepository) Add(ctx context.Context, l webhooks.DeadLetter) error {
+	_, err := r.connection.ExecContext(
+		ctx,
+		"INSERT INTO dead_letters(id, webhook_id, event_type, payload, attempts, last_error, failed_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		l.Id, l.WebhookId, l.Event, l.Payload, l.Attempts, l.Error, l.FailedAt,
+	)
+
+	return err
+}
+
+func (r *DeadLettersRepository) List(ctx context.Context, limit int) ([]webhooks.DeadLetter, error) {
+	var result []webhooks.DeadLetter
+
+	rows, err := r.connection.QueryContext(
+		ctx,
+		"SELECT id, webhook_id, event_type, payload, attempts, last_error, failed_at FROM dead_letters ORDER BY failed_at DESC, id DESC LIMIT $1",
+		limit,
+	)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l webhooks.DeadLetter
+		if err = rows.Scan(&l.Id, &l.WebhookId, &l.Event, &l.Payload, &l.Attempts, &l.Error, &l.FailedAt); err != nil {
+			return result, err
+		}
+		result = append(result, l)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+)
+
+// ChatUsersRepository links the accounts of one chat platform to users, the platforms share the chat_users table
+type ChatUsersRepository struct {
+	connection *sql.DB
+	platform string
+}
+
+func NewChatUsersRepository(connection *sql.DB, platform string) *ChatUsersRepository {
+	return &ChatUsersRepository{
+		connection: connection,
+		platform: platform,
+	}
+}
+
+func (s *ChatUsersRepository) Add(ctx context.Context, u users.ChatUser) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO chat_users(platform, external_id, user_id) VALUES (?, ?, ?)", s.platform, u.ExternalId, u.Id)
+
+	return err
+}
+
+func (s *ChatUsersRepository) Get(ctx context.Context, externalId string) (users.ChatUser, error) {
+	var u users.ChatUser
+
+	row := s.connection.QueryRowContext(ctx, `
+		SELECT u.id, u.name, COALESCE(u.email, ''), COALESCE(u.password, ''), u.time_zone, u.language, cu.external_id FROM users u
+		JOIN chat_users cu ON u.id = cu.user_id
+		WHERE cu.platform = ? AND cu.external_id = ?
+	`, s.platform, externalId)
+
+	if err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language, &u.ExternalId); err != nil {
+		if err == sql.ErrNoRows {
+			return u, fmt.Errorf("User with %s id %s was not found", s.platform, externalId)
+		}
+
+		return u, err
+	}
+
+	return u, nil
+}
+
+func (s *ChatUsersRepository) List(ctx context.Context) ([]users.ChatUser, error) {
+	var result []users.ChatUser
+
+	rows, err := s.connection.QueryContext(ctx, `
+		SELECT u.id, u.name, COALESCE(u.email, ''), COALESCE(u.password, ''), u.time_zone, u.language, cu.external_id FROM users u
+		JOIN chat_users cu ON u.id = cu.user_id
+		WHERE cu.platform = ?
+		ORDER BY cu.external_id
+	`, s.platform)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u users.ChatUser
+		if err = rows.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language, &u.ExternalId); err != nil {
+			return result, err
+		}
+		result = append(result, u)
+	}
+
+	return result, rows.Err()
+}
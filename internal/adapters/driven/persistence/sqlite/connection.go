@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"database/sql"
+	"net/url"
+
+	_ "modernc.org/sqlite"
+)
+
+// Open opens the database file at path. Transactions begin immediately, taking the write lock up front,
+// while concurrent writers wait for it instead of failing with SQLITE_BUSY
+func Open(path string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:" + path + "?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+)
+
+type ReservationsRepository struct {
+	connection executor
+	sequence *sequence
+}
+
+func NewReservationsRepository(connection *sql.DB) *ReservationsRepository {
+	return &ReservationsRepository{
+		connection: connection,
+		sequence: &sequence{
+			name: "reservation_seq",
+			connection: connection,
+		},
+	}
+}
+
+func (r *ReservationsRepository) NextIdentity(ctx context.Context) (int, error) {
+	return r.sequence.Next(ctx)
+}
+
+func (r *ReservationsRepository) AdvanceIdentity(ctx context.Context, id int) error {
+	return r.sequence.Advance(ctx, id)
+}
+
+func (r *ReservationsRepository) List(ctx context.Context) (reservations.Reservations, error) {
+	rows, err := r.connection.QueryContext(ctx, reservationsQuery() + " ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReservations(rows)
+}
+
+func (r *ReservationsRepository) Add(ctx context.Context, record reservations.Reservation) error {
+	_, err := r.connection.ExecContext(ctx, insertReservationQuery(), record.Id, record.UserId, record.SubjectId, timestamp(record.Start), timestamp(record.End))
+
+	return err
+}
+
+func (r *ReservationsRepository) AddMany(ctx context.Context, records reservations.Reservations) error {
+	return transaction(ctx, r.connection, func(tx executor) error {
+		for _, record := range records {
+			_, err := tx.ExecContext(ctx, insertReservationQuery(), record.Id, record.UserId, record.SubjectId, timestamp(record.Start), timestamp(record.End))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *ReservationsRepository) Get(ctx context.Context, id int) (reservations.Reservation, error) {
+	var result reservations.Reservation
+	var err error
+
+	row := r.connection.QueryRowContext(ctx, reservationsQuery() + " WHERE id = ?", id)
+
+	if err = row.Scan(&result.Id, &result.UserId, &result.SubjectId, &result.Start, &result.End); err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("Reservation with id %d was not found", id)
+		}
+	}
+
+	return result, err
+}
+
+func (r *ReservationsRepository) Remove(ctx context.Context, id int) error {
+	return transaction(ctx, r.connection, func(tx executor) error {
+		queries := []string{
+			"DELETE FROM cancelled_reservations WHERE id = ?",
+			`INSERT INTO cancelled_reservations(id, user_id, subject_id, start, "end") SELECT id, user_id, subject_id, start, "end" FROM reservations WHERE id = ?`,
+			"DELETE FROM reservations WHERE id = ?",
+		}
+		for _, query := range queries {
+			if _, err := tx.ExecContext(ctx, query, id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *ReservationsRepository) ForPeriod(ctx context.Context, from time.Time, to time.Time) (reservations.Reservations, error) {
+	if reservations.NewInterval(from, to).IsEmpty() {
+		return nil, nil
+	}
+
+	// reservations are half-open intervals [start, end), see reservations.Interval
+	rows, err := r.connection.QueryContext(ctx,
+		reservationsQuery() + ` WHERE start < ? AND "end" > ? AND start < "end" ORDER BY id`,
+		timestamp(to),
+		timestamp(from),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanReservations(rows)
+}
+
+func scanReservations(rows *sql.Rows) (reservations.Reservations, error) {
+	var result reservations.Reservations
+	defer rows.Close()
+
+	for rows.Next() {
+		var record reservations.Reservation
+		if err := rows.Scan(
+			&record.Id,
+			&record.UserId,
+			&record.SubjectId,
+			&record.Start,
+			&record.End,
+		); err != nil {
+			return result, err
+		}
+		result = append(result, record)
+	}
+
+	return result, rows.Err()
+}
+
+func reservationsQuery() string {
+	return `SELECT id, user_id, subject_id, start, "end" FROM reservations`
+}
+
+func insertReservationQuery() string {
+	return `INSERT INTO reservations(id, user_id, subject_id, start, "end") VALUES (?, ?, ?, ?, ?)`
+}
+
+// timestamp keeps t as a UTC instant in a sortable text form, so that times compare correctly in queries
+// whatever the zone of the server. The columns are read back as UTC
+func timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.999999999")
+}
@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+type sequence struct {
+	name string
+	connection executor
+}
+
+func (seq *sequence) Next(ctx context.Context) (int, error) {
+	var id int
+	err := seq.connection.QueryRowContext(ctx, fmt.Sprintf("UPDATE %s SET value = value + 1 RETURNING value", seq.name)).Scan(&id)
+
+	return id, err
+}
+
+// Advance makes Next hand out values above id
+func (seq *sequence) Advance(ctx context.Context, id int) error {
+	_, err := seq.connection.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET value = MAX(value, ?)", seq.name), id)
+
+	return err
+}
@@ -0,0 +1,299 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+)
+
+type SubjectsRepository struct {
+	connection *sql.DB
+	sequence *sequence
+}
+
+func NewSubjectsRepository(connection *sql.DB) *SubjectsRepository {
+	return &SubjectsRepository{
+		connection: connection,
+		sequence: &sequence{
+			name: "subject_seq",
+			connection: connection,
+		},
+	}
+}
+
+func (s *SubjectsRepository) NextIdentity(ctx context.Context) (int, error) {
+	return s.sequence.Next(ctx)
+}
+
+func (s *SubjectsRepository) AdvanceIdentity(ctx context.Context, id int) error {
+	return s.sequence.Advance(ctx, id)
+}
+
+func (s *SubjectsRepository) Add(ctx context.Context, subject reservations.Subject) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO subjects(id, name, parent_id, workspace_id) VALUES (?, ?, ?, ?)", subject.Id, subject.Name, nullableId(subject.ParentId), subject.WorkspaceId)
+
+	return err
+}
+
+func (s *SubjectsRepository) Get(ctx context.Context, id int) (reservations.Subject, error) {
+	subject := reservations.Subject{}
+
+	row := s.connection.QueryRowContext(ctx, subjectsQuery() + " WHERE id = ?", id)
+
+	if err := row.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId); err != nil {
+		if err == sql.ErrNoRows {
+			return reservations.Subject{}, fmt.Errorf("Subject with id %d was not found", id)
+		}
+
+		return reservations.Subject{}, err
+	}
+
+	return subject, nil
+}
+
+func (s *SubjectsRepository) List(ctx context.Context) (reservations.Subjects, error) {
+	rows, err := s.connection.QueryContext(ctx, subjectsQuery() + " ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSubjects(rows)
+}
+
+func (s *SubjectsRepository) InWorkspace(ctx context.Context, workspaceId int) (reservations.Subjects, error) {
+	rows, err := s.connection.QueryContext(ctx, subjectsQuery() + " WHERE " + visibleQuery() + " ORDER BY id", workspaceId, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSubjects(rows)
+}
+
+func (s *SubjectsRepository) Share(ctx context.Context, id int, workspaceId int) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO subject_workspaces(subject_id, workspace_id) VALUES (?, ?)", id, workspaceId)
+
+	return err
+}
+
+func (s *SubjectsRepository) Unshare(ctx context.Context, id int, workspaceId int) error {
+	result, err := s.connection.ExecContext(ctx, "DELETE FROM subject_workspaces WHERE subject_id = ? AND workspace_id = ?", id, workspaceId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("Subject with id %d is not shared with workspace %d", id, workspaceId)
+	}
+
+	return nil
+}
+
+func (s *SubjectsRepository) SharedWith(ctx context.Context, id int) ([]int, error) {
+	var workspaceIds []int
+	rows, err := s.connection.QueryContext(ctx, "SELECT workspace_id FROM subject_workspaces WHERE subject_id = ? ORDER BY workspace_id", id)
+	if err != nil {
+		return workspaceIds, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workspaceId int
+		if err = rows.Scan(&workspaceId); err != nil {
+			return workspaceIds, err
+		}
+		workspaceIds = append(workspaceIds, workspaceId)
+	}
+
+	return workspaceIds, rows.Err()
+}
+
+func (s *SubjectsRepository) Remove(ctx context.Context, id int) error {
+	if _, err := s.connection.ExecContext(ctx, "DELETE FROM subject_workspaces WHERE subject_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := s.connection.ExecContext(ctx, "DELETE FROM subject_aliases WHERE subject_id = ?", id); err != nil {
+		return err
+	}
+	_, err := s.connection.ExecContext(ctx, "DELETE FROM subjects WHERE id = ?", id)
+
+	return err
+}
+
+func (s *SubjectsRepository) AddTag(ctx context.Context, id int, tag string) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO subject_tags(subject_id, tag) VALUES (?, ?)", id, tag)
+
+	return err
+}
+
+func (s *SubjectsRepository) GetTags(ctx context.Context, id int) ([]string, error) {
+	var tags []string
+	rows, err := s.connection.QueryContext(ctx, "SELECT tag FROM subject_tags WHERE subject_id = ?", id)
+	if err != nil {
+		return tags, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag string
+		if err = rows.Scan(&tag); err != nil {
+			return tags, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (s *SubjectsRepository) AddAlias(ctx context.Context, id int, alias string) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO subject_aliases(subject_id, alias) VALUES (?, ?)", id, alias)
+
+	return err
+}
+
+func (s *SubjectsRepository) GetAliases(ctx context.Context, id int) ([]string, error) {
+	var aliases []string
+	rows, err := s.connection.QueryContext(ctx, "SELECT alias FROM subject_aliases WHERE subject_id = ? ORDER BY alias", id)
+	if err != nil {
+		return aliases, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alias string
+		if err = rows.Scan(&alias); err != nil {
+			return aliases, err
+		}
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, rows.Err()
+}
+
+func (s *SubjectsRepository) GetByTags(ctx context.Context, workspaceId int, tags []string) (reservations.Subjects, error) {
+	query, params := taggedSubjectsQuery(tags)
+	params = append(params, workspaceId, workspaceId)
+	rows, err := s.connection.QueryContext(ctx, subjectsQuery() + " WHERE id IN (" + query + ") AND " + visibleQuery() + " ORDER BY id", params...)
+	if err != nil {
+		return reservations.Subjects{}, err
+	}
+
+	return scanSubjects(rows)
+}
+
+func (s *SubjectsRepository) GetByName(ctx context.Context, workspaceId int, name string) (reservations.Subject, error) {
+	subject := reservations.Subject{}
+
+	row := s.connection.QueryRowContext(ctx, subjectsQuery() + " WHERE name = ? AND " + visibleQuery(), name, workspaceId, workspaceId)
+
+	if err := row.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId); err != nil {
+		if err == sql.ErrNoRows {
+			return reservations.Subject{}, fmt.Errorf("Subject with name %s was not found", name)
+		}
+
+		return reservations.Subject{}, err
+	}
+
+	return subject, nil
+}
+
+func (s *SubjectsRepository) FindByName(ctx context.Context, workspaceId int, name string) (reservations.Subjects, error) {
+	rows, err := s.connection.QueryContext(ctx, subjectsQuery() + " WHERE " + namedQuery() + " AND " + visibleQuery() + " ORDER BY id", name, name, workspaceId, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSubjects(rows)
+}
+
+func (s *SubjectsRepository) SetParent(ctx context.Context, id int, parentId int) error {
+	result, err := s.connection.ExecContext(ctx, "UPDATE subjects SET parent_id = ? WHERE id = ?", nullableId(parentId), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		if _, err = s.Get(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SubjectsRepository) Children(ctx context.Context, id int) (reservations.Subjects, error) {
+	rows, err := s.connection.QueryContext(ctx, subjectsQuery() + " WHERE parent_id = ? ORDER BY id", id)
+	if err != nil {
+		return reservations.Subjects{}, err
+	}
+
+	subjects, err := scanSubjects(rows)
+	if subjects == nil {
+		subjects = reservations.Subjects{}
+	}
+
+	return subjects, err
+}
+
+func scanSubjects(rows *sql.Rows) (reservations.Subjects, error) {
+	var subjects reservations.Subjects
+	defer rows.Close()
+
+	for rows.Next() {
+		var subject reservations.Subject
+		if err := rows.Scan(&subject.Id, &subject.Name, &subject.ParentId, &subject.WorkspaceId); err != nil {
+			return subjects, err
+		}
+		subjects = append(subjects, subject)
+	}
+
+	return subjects, rows.Err()
+}
+
+func subjectsQuery() string {
+	return "SELECT id, name, COALESCE(parent_id, 0), workspace_id FROM subjects"
+}
+
+// namedQuery matches subjects named or aliased so ignoring case, the name is bound twice.
+// sqlite folds the case of ASCII letters only
+func namedQuery() string {
+	return "(LOWER(name) = LOWER(?) OR id IN (SELECT subject_id FROM subject_aliases WHERE LOWER(alias) = LOWER(?)))"
+}
+
+// visibleQuery matches subjects owned by or shared with the workspace, its id is bound twice
+func visibleQuery() string {
+	return "(workspace_id = ? OR id IN (SELECT subject_id FROM subject_workspaces WHERE workspace_id = ?))"
+}
+
+// taggedSubjectsQuery selects ids of subjects having every one of the given tags
+func taggedSubjectsQuery(tags []string) (string, []any) {
+	var params []any
+	for _, tag := range tags {
+		params = append(params, tag)
+	}
+	params = append(params, len(tags))
+
+	return "SELECT subject_id FROM subject_tags WHERE tag IN (" + placeholders(len(tags)) + ") GROUP BY subject_id HAVING COUNT(DISTINCT tag) = ?", params
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func nullableId(id int) any {
+	if id == 0 {
+		return nil
+	}
+
+	return id
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+)
+
+type TelegramUsersRepository struct {
+	connection *sql.DB
+}
+
+func NewTelegramUsersRepository(connection *sql.DB) *TelegramUsersRepository {
+	return &TelegramUsersRepository{
+		connection: connection,
+	}
+}
+
+func (s *TelegramUsersRepository) Add(ctx context.Context, u telegram.TelegramUser) error {
+	_, err := s.connection.ExecContext(ctx, "INSERT INTO telegram_users(telegram_id, user_id) VALUES (?, ?)", u.TelegramId, u.Id)
+
+	return err
+}
+
+func (s *TelegramUsersRepository) Get(ctx context.Context, tgId int64) (telegram.TelegramUser, error) {
+	var u telegram.TelegramUser
+
+	row := s.connection.QueryRowContext(ctx, `
+		SELECT u.id, u.name, COALESCE(u.email, ''), COALESCE(u.password, ''), u.time_zone, u.language, tg.telegram_id FROM users u
+		JOIN telegram_users tg ON u.id = tg.user_id
+		WHERE tg.telegram_id = ?
+	`, tgId)
+
+	if err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language, &u.TelegramId); err != nil {
+		if err == sql.ErrNoRows {
+			return u, fmt.Errorf("User with telegram id %d was not found", tgId)
+		}
+
+		return u, err
+	}
+
+	return u, nil
+}
+
+func (s *TelegramUsersRepository) List(ctx context.Context) ([]telegram.TelegramUser, error) {
+	var result []telegram.TelegramUser
+
+	rows, err := s.connection.QueryContext(ctx, `
+		SELECT u.id, u.name, COALESCE(u.email, ''), COALESCE(u.password, ''), u.time_zone, u.language, tg.telegram_id FROM users u
+		JOIN telegram_users tg ON u.id = tg.user_id
+		ORDER BY tg.telegram_id
+	`)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u telegram.TelegramUser
+		if err = rows.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.TimeZone, &u.Language, &u.TelegramId); err != nil {
+			return result, err
+		}
+		result = append(result, u)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *TelegramUsersRepository) CountByUser(ctx context.Context, userId int) (int, error) {
+	var count int
+	err := s.connection.QueryRowContext(ctx, "SELECT COUNT(*) FROM telegram_users WHERE user_id = ?", userId).Scan(&count)
+
+	return count, err
+}
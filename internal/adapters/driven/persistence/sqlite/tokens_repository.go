@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+)
+
+type TokensRepository struct {
+	connection *sql.DB
+}
+
+func NewTokensRepository(connection *sql.DB) *TokensRepository {
+	return &TokensRepository{
+		connection: connection,
+	}
+}
+
+func (r *TokensRepository) Add(ctx context.Context, t users.Token) error {
+	_, err := r.connection.ExecContext(
+		ctx,
+		"INSERT INTO user_tokens(hash, kind, user_id, expires_at) VALUES (?, ?, ?, ?)",
+		t.Hash, t.Kind, t.UserId, timestamp(t.ExpiresAt),
+	)
+
+	return err
+}
+
+func (r *TokensRepository) Get(ctx context.Context, hash string) (users.Token, error) {
+	var t users.Token
+
+	row := r.connection.QueryRowContext(ctx, "SELECT hash, kind, user_id, expires_at FROM user_tokens WHERE hash = ?", hash)
+	if err := row.Scan(&t.Hash, &t.Kind, &t.UserId, &t.ExpiresAt); err != nil {
+		if err == sql.ErrNoRows {
+			return t, fmt.Errorf("Token was not found")
+		}
+
+		return t, err
+	}
+
+	return t, nil
+}
+
+func (r *TokensRepository) Remove(ctx context.Context, hash string) error {
+	_, err := r.connection.ExecContext(ctx, "DELETE FROM user_tokens WHERE hash = ?", hash)
+
+	return err
+}
+
+func (r *TokensRepository) RemoveByUser(ctx context.Context, userId int, kind string) error {
+	_, err := r.connection.ExecContext(ctx, "DELETE FROM user_tokens WHERE user_id = ? AND kind = ?", userId, kind)
+
+	return err
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+)
+
+type TopicsRepository struct {
+	connection *sql.DB
+}
+
+func NewTopicsRepository(connection *sql.DB) *TopicsRepository {
+	return &TopicsRepository{connection: connection}
+}
+
+func (r *TopicsRepository) Bind(ctx context.Context, topic telegram.Topic) error {
+	_, err := r.connection.ExecContext(
+		ctx,
+		"INSERT INTO telegram_topics(chat_id, thread_id, tag, subject_ids) VALUES (?, ?, ?, ?) ON CONFLICT(chat_id, thread_id) DO UPDATE SET tag = excluded.tag, subject_ids = excluded.subject_ids",
+		topic.ChatId, topic.ThreadId, topic.Tag, joinIds(topic.SubjectIds),
+	)
+
+	return err
+}
+
+func (r *TopicsRepository) Get(ctx context.Context, chatId int64, threadId int) (telegram.Topic, error) {
+	row := r.connection.QueryRowContext(ctx, "SELECT chat_id, thread_id, tag, subject_ids FROM telegram_topics WHERE chat_id = ? AND thread_id = ?", chatId, threadId)
+
+	topic, err := scanTopic(row)
+	if err == sql.ErrNoRows {
+		return telegram.Topic{}, telegram.ErrTopicNotBound
+	}
+
+	return topic, err
+}
+
+func (r *TopicsRepository) List(ctx context.Context) ([]telegram.Topic, error) {
+	var result []telegram.Topic
+
+	rows, err := r.connection.QueryContext(ctx, "SELECT chat_id, thread_id, tag, subject_ids FROM telegram_topics ORDER BY chat_id, thread_id")
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		topic, err := scanTopic(rows)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, topic)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *TopicsRepository) Remove(ctx context.Context, chatId int64, threadId int) error {
+	_, err := r.connection.ExecContext(ctx, "DELETE FROM telegram_topics WHERE chat_id = ? AND thread_id = ?", chatId, threadId)
+
+	return err
+}
+
+func scanTopic(row interface{ Scan(dest ...any) error }) (telegram.Topic, error) {
+	var topic telegram.Topic
+	var subjectIds string
+	if err := row.Scan(&topic.ChatId, &topic.ThreadId, &topic.Tag, &subjectIds); err != nil {
+		return telegram.Topic{}, err
+	}
+	for _, id := range strings.Split(subjectIds, ",") {
+		if id == "" {
+			continue
+		}
+		subjectId, err := strconv.Atoi(id)
+		if err != nil {
+			return telegram.Topic{}, err
+		}
+		topic.SubjectIds = append(topic.SubjectIds, subjectId)
+	}
+
+	return topic, nil
+}
+
+func joinIds(ids []int) string {
+	var parts []string
+	for _, id := range ids {
+		parts = append(parts, strconv.Itoa(id))
+	}
+
+	return strings.Join(parts, ",")
+}
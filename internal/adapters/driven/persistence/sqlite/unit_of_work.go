@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	reservationPorts "github.com/SneedusSnake/Reservations/internal/ports/reservations"
+)
+
+type UnitOfWork struct {
+	connection *sql.DB
+}
+
+func NewUnitOfWork(connection *sql.DB) *UnitOfWork {
+	return &UnitOfWork{connection: connection}
+}
+
+// Execute runs fn in an immediate transaction, see Open. SQLite has no row locks,
+// so the write lock on the whole database serializes units of work regardless of subjects
+func (u *UnitOfWork) Execute(ctx context.Context, subjectIds []int, fn func(reservations reservationPorts.ReservationsRepository) error) error {
+	tx, err := u.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	reservations := &ReservationsRepository{
+		connection: tx,
+		sequence: &sequence{
+			name: "reservation_seq",
+			connection: tx,
+		},
+	}
+	if err = fn(reservations); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+)
+
+type WorkspacesRepository struct {
+	connection *sql.DB
+	sequence *sequence
+}
+
+func NewWorkspacesRepository(connection *sql.DB) *WorkspacesRepository {
+	return &WorkspacesRepository{
+		connection: connection,
+		sequence: &sequence{
+			name: "workspace_seq",
+			connection: connection,
+		},
+	}
+}
+
+func (r *WorkspacesRepository) NextIdentity(ctx context.Context) (int, error) {
+	return r.sequence.Next(ctx)
+}
+
+func (r *WorkspacesRepository) AdvanceIdentity(ctx context.Context, id int) error {
+	return r.sequence.Advance(ctx, id)
+}
+
+func (r *WorkspacesRepository) Add(ctx context.Context, w workspaces.Workspace) error {
+	_, err := r.connection.ExecContext(ctx, "INSERT INTO workspaces(id, name) VALUES (?, ?)", w.Id, w.Name)
+
+	return err
+}
+
+func (r *WorkspacesRepository) Get(ctx context.Context, id int) (workspaces.Workspace, error) {
+	var w workspaces.Workspace
+
+	err := r.connection.QueryRowContext(ctx, "SELECT id, name FROM workspaces WHERE id = ?", id).Scan(&w.Id, &w.Name)
+	if err == sql.ErrNoRows {
+		return workspaces.Workspace{}, fmt.Errorf("Workspace with id %d was not found", id)
+	}
+
+	return w, err
+}
+
+func (r *WorkspacesRepository) GetByName(ctx context.Context, name string) (workspaces.Workspace, error) {
+	var w workspaces.Workspace
+
+	err := r.connection.QueryRowContext(ctx, "SELECT id, name FROM workspaces WHERE name = ?", name).Scan(&w.Id, &w.Name)
+	if err == sql.ErrNoRows {
+		return workspaces.Workspace{}, fmt.Errorf("Workspace %s was not found", name)
+	}
+
+	return w, err
+}
+
+func (r *WorkspacesRepository) List(ctx context.Context) ([]workspaces.Workspace, error) {
+	var result []workspaces.Workspace
+
+	rows, err := r.connection.QueryContext(ctx, "SELECT id, name FROM workspaces ORDER BY id")
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var w workspaces.Workspace
+		if err = rows.Scan(&w.Id, &w.Name); err != nil {
+			return result, err
+		}
+		result = append(result, w)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *WorkspacesRepository) Bind(ctx context.Context, chat string, workspaceId int) error {
+	_, err := r.connection.ExecContext(
+		ctx,
+		"INSERT INTO workspace_chats(chat, workspace_id) VALUES (?, ?) ON CONFLICT(chat) DO UPDATE SET workspace_id = excluded.workspace_id",
+		chat,
+		workspaceId,
+	)
+
+	return err
+}
+
+func (r *WorkspacesRepository) ForChat(ctx context.Context, chat string) (int, error) {
+	var id int
+
+	err := r.connection.QueryRowContext(ctx, "SELECT workspace_id FROM workspace_chats WHERE chat = ?", chat).Scan(&id)
+	if err == sql.ErrNoRows {
+		return workspaces.Default, nil
+	}
+
+	return id, err
+}
+
+func (r *WorkspacesRepository) Bindings(ctx context.Context) (map[string]int, error) {
+	bindings := make(map[string]int)
+
+	rows, err := r.connection.QueryContext(ctx, "SELECT chat, workspace_id FROM workspace_chats")
+	if err != nil {
+		return bindings, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var chat string
+		var workspaceId int
+		if err = rows.Scan(&chat, &workspaceId); err != nil {
+			return bindings, err
+		}
+		bindings[chat] = workspaceId
+	}
+
+	return bindings, rows.Err()
+}
+
+func (r *WorkspacesRepository) AddAdmin(ctx context.Context, workspaceId int, userId int) error {
+	_, err := r.connection.ExecContext(ctx, "INSERT INTO workspace_admins(workspace_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING", workspaceId, userId)
+
+	return err
+}
+
+func (r *WorkspacesRepository) RemoveAdmin(ctx context.Context, workspaceId int, userId int) error {
+	_, err := r.connection.ExecContext(ctx, "DELETE FROM workspace_admins WHERE workspace_id = ? AND user_id = ?", workspaceId, userId)
+
+	return err
+}
+
+func (r *WorkspacesRepository) IsAdmin(ctx context.Context, workspaceId int, userId int) (bool, error) {
+	var count int
+
+	err := r.connection.QueryRowContext(ctx, "SELECT COUNT(*) FROM workspace_admins WHERE workspace_id = ? AND user_id = ?", workspaceId, userId).Scan(&count)
+
+	return count > 0, err
+}
@@ -0,0 +1,280 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/webhooks"
+	"github.com/SneedusSnake/Reservations/internal/logging"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	webhooksPort "github.com/SneedusSnake/Reservations/internal/ports/webhooks"
+)
+
+const (
+	EventHeader = "X-Webhook-Event"
+	DeliveryHeader = "X-Webhook-Delivery"
+	// SignatureHeader holds "sha256=" followed by the hex HMAC-SHA256 of the body keyed with the webhook secret
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+type Options struct {
+	// MaxAttempts is the number of delivery attempts before an event is dead-lettered, at least one is made
+	MaxAttempts int
+	// Backoff is the delay after the first failed attempt, it doubles after every next one up to MaxBackoff
+	Backoff time.Duration
+	MaxBackoff time.Duration
+	// Timeout limits a single delivery attempt
+	Timeout time.Duration
+	Workers int
+	QueueSize int
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MaxAttempts: 5,
+		Backoff: time.Second,
+		MaxBackoff: time.Minute,
+		Timeout: 10 * time.Second,
+		Workers: 2,
+		QueueSize: 100,
+	}
+}
+
+// Dispatcher publishes events to the subscribed webhooks in the background
+type Dispatcher struct {
+	webhooks webhooksPort.WebhooksRepository
+	deadLetters webhooksPort.DeadLettersRepository
+	client *http.Client
+	clock ports.Clock
+	options Options
+	queue chan delivery
+}
+
+type delivery struct {
+	webhook webhooks.Webhook
+	id string
+	event string
+	body []byte
+}
+
+type payload struct {
+	Id string `json:"id"`
+	Type string `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data any `json:"data"`
+}
+
+// permanentError is a failure retrying will not fix
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func NewDispatcher(
+	webhooks webhooksPort.WebhooksRepository,
+	deadLetters webhooksPort.DeadLettersRepository,
+	client *http.Client,
+	clock ports.Clock,
+	options Options,
+) *Dispatcher {
+	options.MaxAttempts = max(options.MaxAttempts, 1)
+
+	return &Dispatcher{
+		webhooks: webhooks,
+		deadLetters: deadLetters,
+		client: client,
+		clock: clock,
+		options: options,
+		queue: make(chan delivery, options.QueueSize),
+	}
+}
+
+// Publish queues the event for every webhook subscribed to it, deliveries not fitting the queue are dead-lettered
+func (d *Dispatcher) Publish(ctx context.Context, event ports.Event) {
+	log := logging.FromContext(ctx)
+	hooks, err := d.webhooks.List(ctx)
+	if err != nil {
+		log.Printf("Could not publish %s: %s", event.Type, err)
+		return
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = d.clock.Current()
+	}
+	for _, hook := range hooks {
+		if !hook.Accepts(event.Type) {
+			continue
+		}
+
+		id, err := deliveryId()
+		if err != nil {
+			log.Printf("Could not publish %s: %s", event.Type, err)
+			return
+		}
+		body, err := json.Marshal(payload{Id: id, Type: event.Type, OccurredAt: event.OccurredAt, Data: event.Data})
+		if err != nil {
+			log.Printf("Could not publish %s: %s", event.Type, err)
+			return
+		}
+
+		next := delivery{webhook: hook, id: id, event: event.Type, body: body}
+		select {
+		case d.queue <- next:
+		default:
+			d.deadLetter(ctx, next, 0, errors.New("Delivery queue is full"))
+		}
+	}
+}
+
+// Run delivers queued events until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range max(d.options.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case next := <-d.queue:
+					d.deliver(ctx, next)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Drain delivers the queued events and returns once the queue is empty or ctx is done
+func (d *Dispatcher) Drain(ctx context.Context) {
+	for {
+		select {
+		case next := <-d.queue:
+			d.deliver(ctx, next)
+		default:
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, next delivery) {
+	var err error
+	attempt := 0
+	for attempt < d.options.MaxAttempts {
+		attempt++
+		if err = d.send(ctx, next); err == nil {
+			return
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) || attempt == d.options.MaxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(d.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			d.deadLetter(ctx, next, attempt, fmt.Errorf("%w, last error: %w", ctx.Err(), err))
+			return
+		}
+	}
+
+	d.deadLetter(ctx, next, attempt, err)
+}
+
+func (d *Dispatcher) send(ctx context.Context, next delivery) error {
+	ctx, cancel := context.WithTimeout(ctx, d.options.Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, next.webhook.Url, bytes.NewReader(next.body))
+	if err != nil {
+		return permanentError{err}
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Reservations-Webhooks")
+	request.Header.Set(EventHeader, next.event)
+	request.Header.Set(DeliveryHeader, next.id)
+	request.Header.Set(SignatureHeader, Sign(next.webhook.Secret, next.body))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64 << 10))
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("Webhook responded with %s", response.Status)
+	if response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusRequestTimeout && response.StatusCode != http.StatusTooManyRequests {
+		return permanentError{err}
+	}
+
+	return err
+}
+
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.options.Backoff
+	for i := 1; i < attempt && delay < d.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.options.MaxBackoff)
+}
+
+// deadLetter keeps the undelivered event, even when ctx is already cancelled
+func (d *Dispatcher) deadLetter(ctx context.Context, next delivery, attempts int, cause error) {
+	ctx = context.WithoutCancel(ctx)
+	log := logging.FromContext(ctx)
+	log.Printf("Could not deliver %s %s to webhook %d: %s", next.event, next.id, next.webhook.Id, cause)
+
+	id, err := d.deadLetters.NextIdentity(ctx)
+	if err == nil {
+		err = d.deadLetters.Add(ctx, webhooks.DeadLetter{
+			Id: id,
+			WebhookId: next.webhook.Id,
+			Event: next.event,
+			Payload: string(next.body),
+			Attempts: attempts,
+			Error: cause.Error(),
+			FailedAt: d.clock.Current(),
+		})
+	}
+	if err != nil {
+		log.Printf("Could not keep dead letter of %s: %s", next.id, err)
+	}
+}
+
+// Sign returns the signature header value of the body, receivers compare it with hmac.Equal
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func deliveryId() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
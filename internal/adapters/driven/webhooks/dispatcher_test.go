@@ -0,0 +1,222 @@
+package webhooks_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/webhooks"
+	domain "github.com/SneedusSnake/Reservations/internal/domain/webhooks"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	"github.com/alecthomas/assert/v2"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Current() time.Time {
+	return c.now
+}
+
+type received struct {
+	header http.Header
+	body []byte
+}
+
+type fixture struct {
+	dispatcher *webhooks.Dispatcher
+	hooks *inmemory.WebhooksStore
+	deadLetters *inmemory.DeadLettersStore
+	received chan received
+	attempts atomic.Int32
+}
+
+// setup runs a dispatcher delivering to a receiver responding with the given statuses in turn, then with 200
+func setup(t *testing.T, statuses ...int) *fixture {
+	f := &fixture{
+		hooks: inmemory.NewWebhooksStore(),
+		deadLetters: inmemory.NewDeadLettersStore(),
+		received: make(chan received, 10),
+	}
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(f.attempts.Add(1))
+		if attempt <= len(statuses) {
+			w.WriteHeader(statuses[attempt-1])
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.received <- received{header: r.Header, body: body}
+	}))
+	t.Cleanup(receiver.Close)
+
+	f.dispatcher = webhooks.NewDispatcher(f.hooks, f.deadLetters, receiver.Client(), fixedClock{time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}, webhooks.Options{
+		MaxAttempts: 3,
+		Backoff: time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+		Timeout: time.Second,
+		Workers: 1,
+		QueueSize: 10,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		f.dispatcher.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	f.addHook(t, 1, receiver.URL, "reservation.*")
+	f.addHook(t, 2, receiver.URL + "/subjects", "subject.created")
+
+	return f
+}
+
+func (f *fixture) addHook(t *testing.T, id int, url string, events ...string) {
+	assert.NoError(t, f.hooks.Add(context.Background(), domain.Webhook{Id: id, Url: url, Secret: "secret", Events: events}))
+}
+
+func (f *fixture) deadLettersEventually(t *testing.T) []domain.DeadLetter {
+	for range 200 {
+		letters, err := f.deadLetters.List(context.Background(), 10)
+		assert.NoError(t, err)
+		if len(letters) > 0 {
+			return letters
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("No dead letters")
+
+	return nil
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	event := ports.Event{Type: "reservation.created", Data: map[string]int{"id": 7}}
+
+	t.Run("it posts signed events to the subscribed webhooks", func(t *testing.T) {
+		f := setup(t)
+
+		f.dispatcher.Publish(ctx, event)
+
+		select {
+		case r := <-f.received:
+			assert.True(t, hmac.Equal([]byte(webhooks.Sign("secret", r.body)), []byte(r.header.Get(webhooks.SignatureHeader))))
+			assert.Equal(t, "reservation.created", r.header.Get(webhooks.EventHeader))
+			assert.Equal(t, "application/json", r.header.Get("Content-Type"))
+
+			var payload map[string]any
+			assert.NoError(t, json.Unmarshal(r.body, &payload))
+			assert.Equal[any](t, "reservation.created", payload["type"])
+			assert.Equal[any](t, "2026-03-01T10:00:00Z", payload["occurred_at"])
+			assert.Equal[any](t, r.header.Get(webhooks.DeliveryHeader), payload["id"])
+			assert.Equal[any](t, map[string]any{"id": float64(7)}, payload["data"])
+		case <-time.After(time.Second):
+			t.Fatal("Event was not delivered")
+		}
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int32(1), f.attempts.Load())
+	})
+
+	t.Run("it retries failed deliveries", func(t *testing.T) {
+		f := setup(t, http.StatusBadGateway, http.StatusTooManyRequests)
+
+		f.dispatcher.Publish(ctx, event)
+
+		select {
+		case <-f.received:
+			assert.Equal(t, int32(3), f.attempts.Load())
+		case <-time.After(time.Second):
+			t.Fatal("Event was not delivered")
+		}
+		letters, err := f.deadLetters.List(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(letters))
+	})
+
+	t.Run("it dead-letters events after the last attempt", func(t *testing.T) {
+		f := setup(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusServiceUnavailable)
+
+		f.dispatcher.Publish(ctx, event)
+
+		letters := f.deadLettersEventually(t)
+		assert.Equal(t, 1, len(letters))
+		assert.Equal(t, 1, letters[0].WebhookId)
+		assert.Equal(t, "reservation.created", letters[0].Event)
+		assert.Equal(t, 3, letters[0].Attempts)
+		assert.Contains(t, letters[0].Error, "503")
+		assert.Contains(t, letters[0].Payload, `"type":"reservation.created"`)
+	})
+
+	t.Run("it does not retry rejected deliveries", func(t *testing.T) {
+		f := setup(t, http.StatusGone)
+
+		f.dispatcher.Publish(ctx, event)
+
+		letters := f.deadLettersEventually(t)
+		assert.Equal(t, 1, letters[0].Attempts)
+		assert.Equal(t, int32(1), f.attempts.Load())
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	f := &fixture{hooks: inmemory.NewWebhooksStore(), deadLetters: inmemory.NewDeadLettersStore()}
+	var sent []time.Time
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, time.Now())
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+	f.addHook(t, 1, receiver.URL)
+	dispatcher := webhooks.NewDispatcher(f.hooks, f.deadLetters, receiver.Client(), fixedClock{}, webhooks.Options{
+		MaxAttempts: 4,
+		Backoff: 20 * time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+		Timeout: time.Second,
+		QueueSize: 1,
+	})
+
+	dispatcher.Publish(context.Background(), ports.Event{Type: "subject.created"})
+	dispatcher.Drain(context.Background())
+
+	assert.Equal(t, 4, len(sent))
+	for i, minimum := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond} {
+		assert.True(t, sent[i+1].Sub(sent[i]) >= minimum, "attempt %d was retried too early", i+2)
+	}
+	letters, err := f.deadLetters.List(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, letters[0].Attempts)
+}
+
+func TestAttemptsAtLeastOnce(t *testing.T) {
+	f := &fixture{hooks: inmemory.NewWebhooksStore(), deadLetters: inmemory.NewDeadLettersStore()}
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+	f.addHook(t, 1, receiver.URL)
+	dispatcher := webhooks.NewDispatcher(f.hooks, f.deadLetters, receiver.Client(), fixedClock{}, webhooks.Options{
+		Timeout: time.Second,
+		QueueSize: 1,
+	})
+
+	dispatcher.Publish(context.Background(), ports.Event{Type: "subject.created"})
+	dispatcher.Drain(context.Background())
+
+	assert.Equal(t, int32(1), f.attempts.Load())
+	letters, err := f.deadLetters.List(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, letters[0].Attempts)
+	assert.Contains(t, letters[0].Error, "500")
+}
@@ -0,0 +1,196 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	SignatureHeader = "X-Signature-Ed25519"
+	TimestampHeader = "X-Signature-Timestamp"
+
+	InteractionPing = 1
+	InteractionCommand = 2
+	InteractionComponent = 3
+
+	ResponsePong = 1
+	ResponseMessage = 4
+
+	OptionString = 3
+	OptionInteger = 4
+
+	ComponentActionRow = 1
+	ComponentButton = 2
+
+	ButtonPrimary = 1
+	ButtonDanger = 4
+
+	// FlagEphemeral shows the message to the invoking user only
+	FlagEphemeral = 1 << 6
+
+	// maxContent and maxLabel are the message and button label length limits of Discord
+	maxContent = 2000
+	maxLabel = 80
+)
+
+var ErrInvalidSignature = errors.New("Invalid discord request signature")
+
+// Interaction is what Discord posts to the interactions endpoint when a user runs a command or clicks a button
+type Interaction struct {
+	Id string `json:"id"`
+	Type int `json:"type"`
+	Token string `json:"token"`
+	ChannelId string `json:"channel_id"`
+	Data InteractionData `json:"data"`
+	// Member is set in guilds, User in direct messages
+	Member *Member `json:"member,omitempty"`
+	User *User `json:"user,omitempty"`
+}
+
+type InteractionData struct {
+	Name string `json:"name,omitempty"`
+	Options []Option `json:"options,omitempty"`
+	CustomId string `json:"custom_id,omitempty"`
+}
+
+type Option struct {
+	Name string `json:"name"`
+	Type int `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type Member struct {
+	User User `json:"user"`
+}
+
+type User struct {
+	Id string `json:"id"`
+	Username string `json:"username"`
+	GlobalName string `json:"global_name,omitempty"`
+}
+
+// Invoker is the user who ran the command or clicked the button
+func (i Interaction) Invoker() User {
+	if i.Member != nil {
+		return i.Member.User
+	}
+	if i.User != nil {
+		return *i.User
+	}
+
+	return User{}
+}
+
+// DisplayName prefers the name shown in Discord over the unique username
+func (u User) DisplayName() string {
+	if u.GlobalName != "" {
+		return u.GlobalName
+	}
+
+	return u.Username
+}
+
+func (d InteractionData) String(name string) string {
+	for _, option := range d.Options {
+		if option.Name != name {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(option.Value, &value); err != nil {
+			return string(option.Value)
+		}
+		return value
+	}
+
+	return ""
+}
+
+func (d InteractionData) Int(name string) (int, bool) {
+	value, err := strconv.Atoi(d.String(name))
+
+	return value, err == nil
+}
+
+type Response struct {
+	Type int `json:"type"`
+	Data *Message `json:"data,omitempty"`
+}
+
+type Message struct {
+	Content string `json:"content"`
+	Flags int `json:"flags,omitempty"`
+	Components []Component `json:"components,omitempty"`
+}
+
+type Component struct {
+	Type int `json:"type"`
+	Style int `json:"style,omitempty"`
+	Label string `json:"label,omitempty"`
+	CustomId string `json:"custom_id,omitempty"`
+	Components []Component `json:"components,omitempty"`
+}
+
+func reply(content string) Message {
+	return Message{Content: truncate(content)}
+}
+
+func ephemeral(content string) Message {
+	return Message{Content: truncate(content), Flags: FlagEphemeral}
+}
+
+func button(label string, style int, customId string) Component {
+	if runes := []rune(label); len(runes) > maxLabel {
+		label = string(runes[:maxLabel - 3]) + "..."
+	}
+
+	return Component{Type: ComponentButton, Style: style, Label: label, CustomId: customId}
+}
+
+// rows lays the buttons out in action rows of five, the most Discord allows
+func rows(buttons []Component) []Component {
+	var result []Component
+	for len(buttons) > 0 {
+		n := min(len(buttons), 5)
+		result = append(result, Component{Type: ComponentActionRow, Components: buttons[:n]})
+		buttons = buttons[n:]
+	}
+
+	return result
+}
+
+func truncate(content string) string {
+	runes := []rune(content)
+	if len(runes) <= maxContent {
+		return content
+	}
+
+	return string(runes[:maxContent - 4]) + "\n..."
+}
+
+// Verify checks the Ed25519 signature Discord puts on every interaction with the public key of the application
+func Verify(publicKey ed25519.PublicKey, header http.Header, body []byte) error {
+	signature, err := hex.DecodeString(header.Get(SignatureHeader))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return ErrInvalidSignature
+	}
+	message := append([]byte(header.Get(TimestampHeader)), body...)
+	if !ed25519.Verify(publicKey, message, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// ParsePublicKey reads the hex public key shown in the Discord developer portal
+func ParsePublicKey(key string) (ed25519.PublicKey, error) {
+	decoded, err := hex.DecodeString(key)
+	if err != nil || len(decoded) != ed25519.PublicKeySize {
+		return nil, errors.New("Invalid discord public key")
+	}
+
+	return ed25519.PublicKey(decoded), nil
+}
@@ -0,0 +1,155 @@
+package discord
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/timeparse"
+)
+
+// ApplicationCommand is a slash command as registered with Discord
+type ApplicationCommand struct {
+	Name string `json:"name"`
+	Description string `json:"description"`
+	Options []CommandOption `json:"options,omitempty"`
+}
+
+type CommandOption struct {
+	Type int `json:"type"`
+	Name string `json:"name"`
+	Description string `json:"description"`
+	Required bool `json:"required,omitempty"`
+}
+
+func stringOption(name string, description string, required bool) CommandOption {
+	return CommandOption{Type: OptionString, Name: name, Description: description, Required: required}
+}
+
+// Commands are the slash commands of the bot, registered on start
+func Commands() []ApplicationCommand {
+	return []ApplicationCommand{
+		{Name: "add_subject", Description: "Add a subject", Options: []CommandOption{
+			stringOption("name", "Name of the subject", true),
+		}},
+		{Name: "add_tags", Description: "Tag a subject", Options: []CommandOption{
+			stringOption("subject", "Name of the subject", true),
+			stringOption("tags", "Tags separated by spaces", true),
+		}},
+		{Name: "list", Description: "List the subjects"},
+		{Name: "tags", Description: "List the tags of a subject", Options: []CommandOption{
+			stringOption("subject", "Name of the subject", true),
+		}},
+		{Name: "add_component", Description: "Make a subject a component of another one", Options: []CommandOption{
+			stringOption("parent", "Name of the parent subject", true),
+			stringOption("component", "Name of the component subject", true),
+		}},
+		{Name: "components", Description: "List the components of a subject", Options: []CommandOption{
+			stringOption("subject", "Name of the subject", true),
+		}},
+		{Name: "reserve", Description: "Reserve subjects", Options: []CommandOption{
+			stringOption("subjects", "Names of the subjects separated by commas", true),
+			stringOption("period", "How long or when, such as 30, 2h, until 17:00 or tomorrow 9-11", true),
+		}},
+		{Name: "remove", Description: "Remove your reservation of a subject", Options: []CommandOption{
+			stringOption("subject", "Name of the subject", true),
+		}},
+		{Name: "reserved", Description: "List the active reservations", Options: []CommandOption{
+			stringOption("tags", "Only subjects with these tags, separated by spaces", false),
+		}},
+	}
+}
+
+type AddSubject struct {
+	Name string
+}
+
+type AddTags struct {
+	SubjectName string
+	Tags []string
+}
+
+type AddComponent struct {
+	ParentName string
+	ComponentName string
+}
+
+type CreateReservation struct {
+	SubjectNames []string
+	From time.Time
+	To time.Time
+}
+
+type ActiveReservations struct {
+	Tags []string
+}
+
+func ParseAddSubject(data InteractionData) (AddSubject, error) {
+	name := strings.TrimSpace(data.String("name"))
+	if name == "" {
+		return AddSubject{}, fmt.Errorf("Invalid format for add subject command. Expected: /add_subject name:<name>")
+	}
+
+	return AddSubject{Name: name}, nil
+}
+
+func ParseAddTags(data InteractionData) (AddTags, error) {
+	subject := strings.TrimSpace(data.String("subject"))
+	tags := strings.Fields(data.String("tags"))
+	if subject == "" || len(tags) == 0 {
+		return AddTags{}, fmt.Errorf("Invalid format for add tags command. Expected: /add_tags subject:<subject_name> tags:<tag1> [tag2]...")
+	}
+
+	return AddTags{SubjectName: subject, Tags: tags}, nil
+}
+
+func ParseAddComponent(data InteractionData) (AddComponent, error) {
+	parent := strings.TrimSpace(data.String("parent"))
+	component := strings.TrimSpace(data.String("component"))
+	if parent == "" || component == "" {
+		return AddComponent{}, fmt.Errorf("Invalid format for add component command. Expected: /add_component parent:<parent_name> component:<component_name>")
+	}
+
+	return AddComponent{ParentName: parent, ComponentName: component}, nil
+}
+
+// ParseSubjectName reads the subject option of the commands acting on a single subject
+func ParseSubjectName(data InteractionData) (string, error) {
+	name := strings.TrimSpace(data.String("subject"))
+	if name == "" {
+		return "", fmt.Errorf("Invalid format for %s command. Expected: /%s subject:<subject_name>", data.Name, data.Name)
+	}
+
+	return name, nil
+}
+
+// ParseCreateReservation reads the period of the reservation relative to now, see timeparse.Formats
+func ParseCreateReservation(data InteractionData, now time.Time) (CreateReservation, error) {
+	error := func () (CreateReservation, error) {
+		return CreateReservation{}, fmt.Errorf("Invalid format for reserve command. Expected: /reserve subjects:<subject_name>[,<subject_name>...] period:<period>")
+	}
+
+	subjects := strings.TrimSpace(data.String("subjects"))
+	if subjects == "" {
+		return error()
+	}
+	subjectNames := strings.Split(subjects, ",")
+	if slices.Contains(subjectNames, "") {
+		return error()
+	}
+	input := strings.TrimSpace(data.String("period"))
+	if input == "" {
+		return error()
+	}
+	period, err := timeparse.Parse(input, now)
+	if err != nil {
+		return CreateReservation{}, err
+	}
+
+	return CreateReservation{SubjectNames: subjectNames, From: period.From, To: period.To}, nil
+}
+
+func ParseActiveReservations(data InteractionData) (ActiveReservations, error) {
+	return ActiveReservations{Tags: strings.Fields(data.String("tags"))}, nil
+}
@@ -0,0 +1,58 @@
+package discord_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/discord"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestInputParsers(t *testing.T) {
+	data := func(name string, options ...string) discord.InteractionData {
+		d := discord.InteractionData{Name: name}
+		for i := 0; i < len(options); i += 2 {
+			d.Options = append(d.Options, discord.Option{Name: options[i], Value: json.RawMessage(options[i+1])})
+		}
+		return d
+	}
+	now := time.Date(2026, 4, 5, 12, 0, 0, 0, time.UTC)
+
+	t.Run("it parses AddTags command", func(t *testing.T) {
+		cmd, err := discord.ParseAddTags(data("add_tags", "subject", `"Test"`, "tags", `"tag1  tag2"`))
+		assert.NoError(t, err)
+		assert.Equal(t, discord.AddTags{SubjectName: "Test", Tags: []string{"tag1", "tag2"}}, cmd)
+
+		_, err = discord.ParseAddTags(data("add_tags", "subject", `"Test"`))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses CreateReservation command", func(t *testing.T) {
+		cmd, err := discord.ParseCreateReservation(data("reserve", "subjects", `"Bench,Phone"`, "period", `"1h30m"`), now)
+		assert.NoError(t, err)
+		assert.Equal(t, discord.CreateReservation{SubjectNames: []string{"Bench", "Phone"}, From: now, To: now.Add(90 * time.Minute)}, cmd)
+	})
+
+	t.Run("it returns error given wrong format provided to CreateReservation", func(t *testing.T) {
+		for _, options := range [][]string{
+			{},
+			{"subjects", `"Bench"`},
+			{"subjects", `"Bench,"`, "period", `"30"`},
+			{"subjects", `"Bench"`, "period", `"0"`},
+			{"subjects", `"Bench"`, "period", `"thirty"`},
+		} {
+			_, err := discord.ParseCreateReservation(data("reserve", options...), now)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("it parses the subject of single subject commands", func(t *testing.T) {
+		name, err := discord.ParseSubjectName(data("remove", "subject", `" Bench "`))
+		assert.NoError(t, err)
+		assert.Equal(t, "Bench", name)
+
+		_, err = discord.ParseSubjectName(data("remove"))
+		assert.EqualError(t, err, "Invalid format for remove command. Expected: /remove subject:<subject_name>")
+	})
+}
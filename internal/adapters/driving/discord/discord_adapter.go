@@ -0,0 +1,380 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+)
+
+// Platform keys the users linked to Discord accounts, see application.ChatUserService
+const Platform = "discord"
+
+const (
+	ButtonReserve = "reserve"
+	ButtonRelease = "release"
+
+	// buttonReservation is the duration in minutes of the reservations made with the Reserve buttons
+	buttonReservation = 30
+	// maxListButtons is the number of buttons fitting a message
+	maxListButtons = 25
+)
+
+// discordAdapter works with the subjects of the workspace the channel is bound to, see ChatKey
+type discordAdapter struct {
+	subjectService *application.SubjectService
+	reservationsService *application.ReservationService
+	userService *application.UserService
+	discordUserService *application.ChatUserService
+	workspaceService *application.WorkspaceService
+	clock ports.Clock
+}
+
+func NewAdapter(
+	subjectService *application.SubjectService,
+	reservationService *application.ReservationService,
+	userService *application.UserService,
+	discordUserService *application.ChatUserService,
+	workspaceService *application.WorkspaceService,
+	clock ports.Clock,
+) *discordAdapter {
+	return &discordAdapter{
+		subjectService: subjectService,
+		reservationsService: reservationService,
+		userService: userService,
+		discordUserService: discordUserService,
+		workspaceService: workspaceService,
+		clock: clock,
+	}
+}
+
+// ChatKey identifies a Discord channel among the chats bound to workspaces
+func ChatKey(channelId string) string {
+	return Platform + ":" + channelId
+}
+
+// workspace returns the id of the workspace of the channel the interaction comes from
+func (da *discordAdapter) workspace(ctx context.Context, channelId string) (int, error) {
+	return da.workspaceService.ForChat(ctx, ChatKey(channelId))
+}
+
+// Register routes the slash commands of Commands and the buttons of the adapter messages to their handlers
+func (da *discordAdapter) Register(h *Handler) {
+	h.HandleCommand("add_subject", da.AddSubjectHandler)
+	h.HandleCommand("add_tags", da.AddSubjectTagsHandler)
+	h.HandleCommand("list", da.ListSubjectsHandler)
+	h.HandleCommand("tags", da.ListSubjectTagsHandler)
+	h.HandleCommand("add_component", da.AddComponentHandler)
+	h.HandleCommand("components", da.ListComponentsHandler)
+	h.HandleCommand("reserve", da.CreateReservationHandler)
+	h.HandleCommand("remove", da.RemoveReservationHandler)
+	h.HandleCommand("reserved", da.ActiveReservationsHandler)
+	h.HandleButton(ButtonReserve, da.ReserveButtonHandler)
+	h.HandleButton(ButtonRelease, da.ReleaseButtonHandler)
+}
+
+func (da *discordAdapter) AddSubjectHandler(ctx context.Context, interaction Interaction) (Message, error) {
+	input, err := ParseAddSubject(interaction.Data)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := da.workspace(ctx, interaction.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	if _, err = da.subjectService.Create(ctx, application.CreateSubject{Name: input.Name, WorkspaceId: workspaceId}); err != nil {
+		return Message{}, err
+	}
+
+	return reply(fmt.Sprintf("Subject %s added", input.Name)), nil
+}
+
+func (da *discordAdapter) AddSubjectTagsHandler(ctx context.Context, interaction Interaction) (Message, error) {
+	input, err := ParseAddTags(interaction.Data)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := da.workspace(ctx, interaction.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subject, err := da.subjectService.GetByName(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return Message{}, err
+	}
+	if err = da.subjectService.AddTags(ctx, application.AddTags{SubjectId: subject.Id, Tags: input.Tags}); err != nil {
+		return Message{}, err
+	}
+
+	return reply(fmt.Sprintf("tags: %s added to %s", strings.Join(input.Tags, ", "), input.SubjectName)), nil
+}
+
+// ListSubjectsHandler lists the subjects with a button reserving each of them
+func (da *discordAdapter) ListSubjectsHandler(ctx context.Context, interaction Interaction) (Message, error) {
+	workspaceId, err := da.workspace(ctx, interaction.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subjects, err := da.subjectService.List(ctx, workspaceId)
+	if err != nil {
+		return Message{}, err
+	}
+
+	message := reply(subjects.Names())
+	if len(subjects) > maxListButtons {
+		return message, nil
+	}
+	var buttons []Component
+	for _, subject := range subjects {
+		buttons = append(buttons, button(subject.Name, ButtonPrimary, fmt.Sprintf("%s:%d", ButtonReserve, subject.Id)))
+	}
+	message.Components = rows(buttons)
+
+	return message, nil
+}
+
+func (da *discordAdapter) ListSubjectTagsHandler(ctx context.Context, interaction Interaction) (Message, error) {
+	name, err := ParseSubjectName(interaction.Data)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := da.workspace(ctx, interaction.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subject, err := da.subjectService.GetByName(ctx, workspaceId, name)
+	if err != nil {
+		return Message{}, err
+	}
+	tags, err := da.subjectService.ListTags(ctx, subject.Id)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return reply(strings.Join(tags, "\n")), nil
+}
+
+func (da *discordAdapter) AddComponentHandler(ctx context.Context, interaction Interaction) (Message, error) {
+	input, err := ParseAddComponent(interaction.Data)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := da.workspace(ctx, interaction.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	parent, err := da.subjectService.GetByName(ctx, workspaceId, input.ParentName)
+	if err != nil {
+		return Message{}, err
+	}
+	component, err := da.subjectService.GetByName(ctx, workspaceId, input.ComponentName)
+	if err != nil {
+		return Message{}, err
+	}
+
+	err = da.subjectService.AddComponent(ctx, application.AddComponent{ParentId: parent.Id, ComponentId: component.Id})
+	if err != nil {
+		return Message{}, err
+	}
+
+	return reply(fmt.Sprintf("%s added as a component of %s", component.Name, parent.Name)), nil
+}
+
+func (da *discordAdapter) ListComponentsHandler(ctx context.Context, interaction Interaction) (Message, error) {
+	name, err := ParseSubjectName(interaction.Data)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := da.workspace(ctx, interaction.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subject, err := da.subjectService.GetByName(ctx, workspaceId, name)
+	if err != nil {
+		return Message{}, err
+	}
+	components, err := da.subjectService.ListComponents(ctx, subject.Id)
+	if err != nil {
+		return Message{}, err
+	}
+
+	if len(components) == 0 {
+		return reply(fmt.Sprintf("%s has no components", subject.Name)), nil
+	}
+
+	return reply(components.Names()), nil
+}
+
+func (da *discordAdapter) CreateReservationHandler(ctx context.Context, interaction Interaction) (Message, error) {
+	user, err := da.user(ctx, interaction.Invoker())
+	if err != nil {
+		return Message{}, err
+	}
+	now := da.clock.Current()
+	input, err := ParseCreateReservation(interaction.Data, now.In(user.Location(now.Location())))
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+
+	workspaceId, err := da.workspace(ctx, interaction.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	var subjectIds []int
+	for _, name := range input.SubjectNames {
+		subject, err := da.subjectService.GetByName(ctx, workspaceId, name)
+		if err != nil {
+			return Message{}, err
+		}
+		subjectIds = append(subjectIds, subject.Id)
+	}
+
+	return da.reserve(ctx, user, subjectIds, input.From, input.To)
+}
+
+// ReserveButtonHandler reserves the subject of the clicked button
+func (da *discordAdapter) ReserveButtonHandler(ctx context.Context, interaction Interaction, value string) (Message, error) {
+	subjectId, err := strconv.Atoi(value)
+	if err != nil {
+		return Message{}, fmt.Errorf("Invalid subject id %q", value)
+	}
+
+	user, err := da.user(ctx, interaction.Invoker())
+	if err != nil {
+		return Message{}, err
+	}
+	now := da.clock.Current()
+
+	return da.reserve(ctx, user, []int{subjectId}, now, now.Add(buttonReservation*time.Minute))
+}
+
+func (da *discordAdapter) user(ctx context.Context, invoker User) (users.ChatUser, error) {
+	return da.discordUserService.GetOrCreate(ctx, application.CreateChatUser{Id: invoker.Id, Name: invoker.DisplayName()})
+}
+
+// reserve replies with a Release button on success
+func (da *discordAdapter) reserve(ctx context.Context, user users.ChatUser, subjectIds []int, from time.Time, to time.Time) (Message, error) {
+	var subjectNames []string
+	var values []string
+	for _, id := range subjectIds {
+		subject, err := da.subjectService.Get(ctx, id)
+		if err != nil {
+			return Message{}, err
+		}
+		subjectNames = append(subjectNames, subject.Name)
+		values = append(values, strconv.Itoa(id))
+	}
+
+	cmd := application.CreateReservations{UserId: user.Id, SubjectIds: subjectIds, From: from, To: to}
+	rs, err := da.reservationsService.CreateBatch(ctx, cmd)
+	if err != nil {
+		if batchErr, ok := err.(application.BatchReservationError); ok {
+			return reply(da.conflictsMessage(ctx, batchErr, len(subjectIds) > 1)), nil
+		}
+		return Message{}, err
+	}
+
+	message := reply(fmt.Sprintf("Reservation for %s acquired by %s until %s", strings.Join(subjectNames, ", "), user.Name, rs[0].End.Format(time.DateTime)))
+	message.Components = rows([]Component{button("Release", ButtonDanger, ButtonRelease + ":" + strings.Join(values, ","))})
+
+	return message, nil
+}
+
+func (da *discordAdapter) conflictsMessage(ctx context.Context, batchErr application.BatchReservationError, withSubjects bool) string {
+	var lines []string
+	for _, conflict := range batchErr.Conflicts {
+		r, _ := da.reservationsService.Get(ctx, conflict.ReservationIds[0])
+		u, _ := da.userService.Get(ctx, r.UserId)
+		line := fmt.Sprintf("Already reserved by %s until %s", u.Name, r.End.Format(time.DateTime))
+		if withSubjects {
+			subject, _ := da.subjectService.Get(ctx, conflict.SubjectId)
+			line = subject.Name + ": " + line
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (da *discordAdapter) RemoveReservationHandler(ctx context.Context, interaction Interaction) (Message, error) {
+	name, err := ParseSubjectName(interaction.Data)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := da.workspace(ctx, interaction.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subject, err := da.subjectService.GetByName(ctx, workspaceId, name)
+	if err != nil {
+		return Message{}, err
+	}
+	user, err := da.discordUserService.Get(ctx, interaction.Invoker().Id)
+	if err != nil {
+		return Message{}, err
+	}
+
+	err = da.reservationsService.Remove(ctx, application.RemoveReservations{UserId: user.Id, SubjectId: subject.Id})
+	if err != nil {
+		return Message{}, err
+	}
+
+	return reply(fmt.Sprintf("Reservation for %s removed", subject.Name)), nil
+}
+
+// ReleaseButtonHandler removes the reservations of the clicking user for the subjects of the clicked Release button
+func (da *discordAdapter) ReleaseButtonHandler(ctx context.Context, interaction Interaction, value string) (Message, error) {
+	user, err := da.discordUserService.Get(ctx, interaction.Invoker().Id)
+	if err != nil {
+		return ephemeral("You have no active reservations"), nil
+	}
+
+	var released []string
+	for _, id := range strings.Split(value, ",") {
+		subjectId, err := strconv.Atoi(id)
+		if err != nil {
+			return Message{}, fmt.Errorf("Invalid subject id %q", id)
+		}
+		subject, err := da.subjectService.Get(ctx, subjectId)
+		if err != nil {
+			return Message{}, err
+		}
+		if err = da.reservationsService.Remove(ctx, application.RemoveReservations{UserId: user.Id, SubjectId: subjectId}); err != nil {
+			continue
+		}
+		released = append(released, subject.Name)
+	}
+
+	if len(released) == 0 {
+		return ephemeral("You have no active reservations for these subjects"), nil
+	}
+
+	return reply(fmt.Sprintf("Reservation for %s removed", strings.Join(released, ", "))), nil
+}
+
+func (da *discordAdapter) ActiveReservationsHandler(ctx context.Context, interaction Interaction) (Message, error) {
+	input, err := ParseActiveReservations(interaction.Data)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := da.workspace(ctx, interaction.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	list, err := da.reservationsService.ActiveReservations(ctx, workspaceId, da.clock.Current(), input.Tags...)
+	if err != nil {
+		return Message{}, err
+	}
+
+	text := "Subject\tReserved Until\t\tUser\n"
+	for _, reservation := range list {
+		text += fmt.Sprintf("%s\t%s\t\t%s\n", reservation.Subject, reservation.End.Format(time.DateTime), reservation.User)
+	}
+
+	return reply(text), nil
+}
@@ -0,0 +1,134 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/logging"
+)
+
+const maxRequestSize = 1 << 20
+
+type CommandHandler func(ctx context.Context, interaction Interaction) (Message, error)
+
+// ButtonHandler handles clicks on the buttons whose custom id starts with its prefix, value is the rest of the id
+type ButtonHandler func(ctx context.Context, interaction Interaction, value string) (Message, error)
+
+// Handler serves the interactions endpoint configured in the Discord developer portal
+type Handler struct {
+	publicKey ed25519.PublicKey
+	timeout time.Duration
+	commands map[string]CommandHandler
+	buttons map[string]ButtonHandler
+}
+
+func NewHandler(publicKey ed25519.PublicKey, timeout time.Duration) *Handler {
+	return &Handler{
+		publicKey: publicKey,
+		timeout: timeout,
+		commands: make(map[string]CommandHandler),
+		buttons: make(map[string]ButtonHandler),
+	}
+}
+
+func (h *Handler) HandleCommand(name string, handler CommandHandler) {
+	h.commands[name] = handler
+}
+
+func (h *Handler) HandleButton(prefix string, handler ButtonHandler) {
+	h.buttons[prefix] = handler
+}
+
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /discord/interactions", h.serveInteraction)
+}
+
+func (h *Handler) serveInteraction(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if err = Verify(h.publicKey, r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	var interaction Interaction
+	if err = json.Unmarshal(body, &interaction); err != nil {
+		http.Error(w, "Invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	if interaction.Type == InteractionPing {
+		writeResponse(w, Response{Type: ResponsePong})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+	message, err := h.dispatch(ctx, interaction)
+	if err != nil {
+		logging.FromContext(ctx).Printf("Interaction %s %s%s: %s", interaction.Id, interaction.Data.Name, interaction.Data.CustomId, err)
+		message = ephemeral("An error occured")
+	}
+
+	writeResponse(w, Response{Type: ResponseMessage, Data: &message})
+}
+
+func (h *Handler) dispatch(ctx context.Context, interaction Interaction) (Message, error) {
+	switch interaction.Type {
+	case InteractionCommand:
+		if handler, ok := h.commands[interaction.Data.Name]; ok {
+			return handler(ctx, interaction)
+		}
+		return ephemeral(fmt.Sprintf("Unknown command /%s", interaction.Data.Name)), nil
+	case InteractionComponent:
+		prefix, value, _ := strings.Cut(interaction.Data.CustomId, ":")
+		if handler, ok := h.buttons[prefix]; ok {
+			return handler(ctx, interaction, value)
+		}
+		return ephemeral("This button is no longer supported"), nil
+	}
+
+	return Message{}, fmt.Errorf("Unsupported interaction type %d", interaction.Type)
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RegisterCommands overwrites the global slash commands of the application with the given ones
+func RegisterCommands(ctx context.Context, client *http.Client, apiUrl string, applicationId string, botToken string, commands []ApplicationCommand) error {
+	body, err := json.Marshal(commands)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/applications/%s/commands", strings.TrimSuffix(apiUrl, "/"), applicationId)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bot " + botToken)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64 << 10))
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Discord responded with %s to commands registration", response.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package discord_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/discord"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/alecthomas/assert/v2"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Current() time.Time {
+	return c.now
+}
+
+type fixture struct {
+	app *httptest.Server
+	key ed25519.PrivateKey
+}
+
+func setup(t *testing.T) *fixture {
+	ctx := context.Background()
+	subjectsStore := inmemory.NewSubjectsStore()
+	usersStore := inmemory.NewUsersStore()
+	reservationsStore := inmemory.NewReservationStore()
+	readStore := inmemory.NewReservationReadStore(reservationsStore, usersStore, subjectsStore)
+	clock := fixedClock{time.Date(2026, 4, 5, 12, 0, 0, 0, time.UTC)}
+
+	subjects := application.NewSubjectService(subjectsStore, nil)
+	userService := application.NewUserService(usersStore)
+	reservationService := application.NewReservationService(
+		subjectsStore,
+		reservationsStore,
+		readStore,
+		usersStore,
+		inmemory.NewUnitOfWork(reservationsStore),
+		clock,
+		nil,
+	)
+	_, err := subjects.Create(ctx, application.CreateSubject{Name: "Bench", WorkspaceId: workspaces.Default})
+	assert.NoError(t, err)
+	_, err = subjects.Create(ctx, application.CreateSubject{Name: "Phone", WorkspaceId: workspaces.Default})
+	assert.NoError(t, err)
+
+	public, private, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	handler := discord.NewHandler(public, time.Second)
+	discord.NewAdapter(
+		subjects,
+		reservationService,
+		userService,
+		application.NewChatUserService(inmemory.NewChatUsersStore(discord.Platform, usersStore), userService),
+		application.NewWorkspaceService(inmemory.NewWorkspacesStore()),
+		clock,
+	).Register(handler)
+	mux := http.NewServeMux()
+	handler.Register(mux)
+
+	f := &fixture{app: httptest.NewServer(mux), key: private}
+	t.Cleanup(f.app.Close)
+
+	return f
+}
+
+func (f *fixture) post(t *testing.T, interaction map[string]any, key ed25519.PrivateKey) *http.Response {
+	body, err := json.Marshal(interaction)
+	assert.NoError(t, err)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request, err := http.NewRequest(http.MethodPost, f.app.URL + "/discord/interactions", bytes.NewReader(body))
+	assert.NoError(t, err)
+	request.Header.Set(discord.TimestampHeader, timestamp)
+	request.Header.Set(discord.SignatureHeader, hex.EncodeToString(ed25519.Sign(key, append([]byte(timestamp), body...))))
+
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		response.Body.Close()
+	})
+
+	return response
+}
+
+func (f *fixture) interact(t *testing.T, user string, interactionType int, data map[string]any) discord.Message {
+	response := f.post(t, map[string]any{
+		"id": "1",
+		"type": interactionType,
+		"token": "token",
+		"channel_id": "42",
+		"data": data,
+		"member": map[string]any{"user": map[string]string{"id": user + "-id", "username": user}},
+	}, f.key)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var decoded discord.Response
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&decoded))
+	assert.Equal(t, discord.ResponseMessage, decoded.Type)
+
+	return *decoded.Data
+}
+
+func (f *fixture) command(t *testing.T, user string, name string, options ...map[string]any) discord.Message {
+	return f.interact(t, user, discord.InteractionCommand, map[string]any{"name": name, "options": options})
+}
+
+func (f *fixture) click(t *testing.T, user string, customId string) discord.Message {
+	return f.interact(t, user, discord.InteractionComponent, map[string]any{"custom_id": customId})
+}
+
+func option(name string, value any) map[string]any {
+	return map[string]any{"name": name, "value": value}
+}
+
+func TestDiscordHandler(t *testing.T) {
+	t.Run("it answers pings", func(t *testing.T) {
+		f := setup(t)
+
+		response := f.post(t, map[string]any{"id": "1", "type": discord.InteractionPing}, f.key)
+
+		body, err := io.ReadAll(response.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":1}`, string(bytes.TrimSpace(body)))
+	})
+
+	t.Run("it rejects interactions with an invalid signature", func(t *testing.T) {
+		f := setup(t)
+		_, another, err := ed25519.GenerateKey(nil)
+		assert.NoError(t, err)
+
+		response := f.post(t, map[string]any{"id": "1", "type": discord.InteractionPing}, another)
+
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	})
+
+	t.Run("it lists subjects with reserve buttons", func(t *testing.T) {
+		f := setup(t)
+
+		message := f.command(t, "alice", "list")
+
+		assert.Equal(t, "Bench\nPhone", message.Content)
+		assert.Equal(t, 1, len(message.Components))
+		assert.Equal(t, "reserve:2", message.Components[0].Components[1].CustomId)
+	})
+
+	t.Run("it reserves subjects on behalf of the discord user", func(t *testing.T) {
+		f := setup(t)
+
+		message := f.command(t, "alice", "reserve", option("subjects", "Bench,Phone"), option("period", "30"))
+		assert.Equal(t, "Reservation for Bench, Phone acquired by alice until 2026-04-05 12:30:00", message.Content)
+
+		message = f.command(t, "bob", "reserve", option("subjects", "Phone"), option("period", "10m"))
+		assert.Equal(t, "Already reserved by alice until 2026-04-05 12:30:00", message.Content)
+
+		message = f.command(t, "bob", "reserved")
+		assert.Contains(t, message.Content, "Bench\t2026-04-05 12:30:00\t\talice")
+	})
+
+	t.Run("it replies to malformed commands with the usage to the invoking user only", func(t *testing.T) {
+		f := setup(t)
+
+		message := f.command(t, "alice", "reserve", option("subjects", "Bench"))
+
+		assert.Equal(t, discord.FlagEphemeral, message.Flags)
+		assert.Contains(t, message.Content, "Expected: /reserve")
+	})
+
+	t.Run("it reserves and releases subjects with buttons", func(t *testing.T) {
+		f := setup(t)
+
+		message := f.click(t, "alice", "reserve:2")
+		assert.Equal(t, "Reservation for Phone acquired by alice until 2026-04-05 12:30:00", message.Content)
+		release := message.Components[0].Components[0].CustomId
+		assert.Equal(t, "release:2", release)
+
+		message = f.click(t, "bob", release)
+		assert.Equal(t, discord.FlagEphemeral, message.Flags)
+
+		message = f.click(t, "alice", release)
+		assert.Equal(t, "Reservation for Phone removed", message.Content)
+	})
+}
+
+func TestRegisterCommands(t *testing.T) {
+	var registered []discord.ApplicationCommand
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v10/applications/123/commands", r.URL.Path)
+		assert.Equal(t, "Bot token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&registered))
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	err := discord.RegisterCommands(context.Background(), server.Client(), server.URL + "/api/v10", "123", "token", discord.Commands())
+
+	assert.NoError(t, err)
+	assert.Equal(t, len(discord.Commands()), len(registered))
+	assert.Equal(t, "reserve", registered[6].Name)
+	assert.Equal(t, "period", registered[6].Options[1].Name)
+	assert.Equal(t, discord.OptionString, registered[6].Options[1].Type)
+}
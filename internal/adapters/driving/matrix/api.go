@@ -0,0 +1,173 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EventMessage = "m.room.message"
+	MsgTypeText = "m.text"
+	MsgTypeNotice = "m.notice"
+	FormatHtml = "org.matrix.custom.html"
+
+	apiPrefix = "/_matrix/client/v3"
+)
+
+type SyncResponse struct {
+	NextBatch string `json:"next_batch"`
+	Rooms Rooms `json:"rooms"`
+}
+
+type Rooms struct {
+	Join map[string]JoinedRoom `json:"join,omitempty"`
+	Invite map[string]json.RawMessage `json:"invite,omitempty"`
+}
+
+type JoinedRoom struct {
+	Timeline Timeline `json:"timeline"`
+}
+
+type Timeline struct {
+	Events []Event `json:"events"`
+}
+
+type Event struct {
+	Type string `json:"type"`
+	EventId string `json:"event_id"`
+	Sender string `json:"sender"`
+	OriginServerTs int64 `json:"origin_server_ts"`
+	Content json.RawMessage `json:"content"`
+}
+
+// MessageContent is the content of m.room.message events, FormattedBody is the html version of Body
+type MessageContent struct {
+	MsgType string `json:"msgtype"`
+	Body string `json:"body"`
+	Format string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+	RelatesTo *RelatesTo `json:"m.relates_to,omitempty"`
+}
+
+type RelatesTo struct {
+	InReplyTo *InReplyTo `json:"m.in_reply_to,omitempty"`
+}
+
+type InReplyTo struct {
+	EventId string `json:"event_id"`
+}
+
+// Reply is sent back as a notice so that other bots don't react to it
+type Reply struct {
+	Text string
+	Html string
+}
+
+func text(body string) Reply {
+	return Reply{Text: body, Html: strings.ReplaceAll(html.EscapeString(body), "\n", "<br>")}
+}
+
+// table renders the rows as an html table
+func table(header []string, rows [][]string) string {
+	var formatted strings.Builder
+	formatted.WriteString("<table><thead><tr>")
+	for _, column := range header {
+		formatted.WriteString("<th>" + html.EscapeString(column) + "</th>")
+	}
+	formatted.WriteString("</tr></thead><tbody>")
+	for _, row := range rows {
+		formatted.WriteString("<tr>")
+		for _, cell := range row {
+			formatted.WriteString("<td>" + html.EscapeString(cell) + "</td>")
+		}
+		formatted.WriteString("</tr>")
+	}
+	formatted.WriteString("</tbody></table>")
+
+	return formatted.String()
+}
+
+// Client talks to a homeserver through the Matrix client-server API with the access token of the bot account
+type Client struct {
+	http *http.Client
+	homeserver string
+	accessToken string
+}
+
+func NewClient(httpClient *http.Client, homeserver string, accessToken string) *Client {
+	return &Client{http: httpClient, homeserver: strings.TrimSuffix(homeserver, "/"), accessToken: accessToken}
+}
+
+// Sync returns the events after since, waiting up to timeout for new ones
+func (c *Client) Sync(ctx context.Context, since string, timeout time.Duration) (SyncResponse, error) {
+	query := url.Values{}
+	query.Set("timeout", strconv.FormatInt(timeout.Milliseconds(), 10))
+	if since != "" {
+		query.Set("since", since)
+	}
+
+	var response SyncResponse
+	err := c.do(ctx, http.MethodGet, "/sync?" + query.Encode(), nil, &response)
+
+	return response, err
+}
+
+// Send posts a message to the room, the homeserver ignores retries with the same txnId
+func (c *Client) Send(ctx context.Context, roomId string, txnId string, content MessageContent) error {
+	path := fmt.Sprintf("/rooms/%s/send/%s/%s", url.PathEscape(roomId), EventMessage, url.PathEscape(txnId))
+
+	return c.do(ctx, http.MethodPut, path, content, nil)
+}
+
+func (c *Client) Join(ctx context.Context, roomId string) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/rooms/%s/join", url.PathEscape(roomId)), struct{}{}, nil)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body any, result any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, c.homeserver + apiPrefix + path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer " + c.accessToken)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		var matrixErr struct {
+			ErrCode string `json:"errcode"`
+			Error string `json:"error"`
+		}
+		json.NewDecoder(io.LimitReader(response.Body, 64 << 10)).Decode(&matrixErr)
+		return fmt.Errorf("Homeserver responded with %s to %s %s: %s %s", response.Status, method, strings.SplitN(path, "?", 2)[0], matrixErr.ErrCode, matrixErr.Error)
+	}
+	if result == nil {
+		io.Copy(io.Discard, io.LimitReader(response.Body, 64 << 10))
+		return nil
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
@@ -0,0 +1,163 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/logging"
+)
+
+// Message is a command posted in one of the rooms of the bot
+type Message struct {
+	RoomId string
+	EventId string
+	Sender string
+	// Body is the command line, always starting with /
+	Body string
+}
+
+// Localpart is the name part of the sender id, alice for @alice:example.org
+func (m Message) Localpart() string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(m.Sender, "@"), ":")
+
+	return name
+}
+
+type HandlerFunc func(ctx context.Context, message Message) (Reply, error)
+
+// Bot runs the commands posted in the rooms it is invited to and replies to them
+type Bot struct {
+	client *Client
+	userId string
+	timeout time.Duration
+	// PollTimeout is how long a sync waits for new events, RetryDelay the pause after a failed one
+	PollTimeout time.Duration
+	RetryDelay time.Duration
+	handlers map[string]HandlerFunc
+}
+
+func NewBot(client *Client, userId string, timeout time.Duration) *Bot {
+	return &Bot{
+		client: client,
+		userId: userId,
+		timeout: timeout,
+		PollTimeout: 30 * time.Second,
+		RetryDelay: 5 * time.Second,
+		handlers: make(map[string]HandlerFunc),
+	}
+}
+
+// Handle registers the handler of the command, given without its prefix
+func (b *Bot) Handle(command string, handler HandlerFunc) {
+	b.handlers[command] = handler
+}
+
+// Start syncs with the homeserver until ctx is done. Messages sent before the start are not replayed
+func (b *Bot) Start(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	started := time.Now().UnixMilli()
+	since := ""
+
+	for ctx.Err() == nil {
+		timeout := b.PollTimeout
+		if since == "" {
+			timeout = 0
+		}
+		syncCtx, cancel := context.WithTimeout(ctx, timeout + b.timeout)
+		response, err := b.client.Sync(syncCtx, since, timeout)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Printf("Matrix sync failed: %s", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(b.RetryDelay):
+			}
+			continue
+		}
+
+		if since == "" {
+			logger.Printf("Listening to matrix rooms as %s", b.userId)
+		}
+		since = response.NextBatch
+		b.process(ctx, response, started)
+	}
+}
+
+func (b *Bot) process(ctx context.Context, response SyncResponse, started int64) {
+	for roomId := range response.Rooms.Invite {
+		if err := b.client.Join(ctx, roomId); err != nil {
+			logging.FromContext(ctx).Printf("Could not join room %s: %s", roomId, err)
+		}
+	}
+
+	for roomId, room := range response.Rooms.Join {
+		for _, event := range room.Timeline.Events {
+			if event.Type != EventMessage || event.Sender == b.userId || event.OriginServerTs < started {
+				continue
+			}
+			var content MessageContent
+			if err := json.Unmarshal(event.Content, &content); err != nil || content.MsgType != MsgTypeText {
+				continue
+			}
+			command, ok := parseCommand(content.Body)
+			if !ok {
+				continue
+			}
+			if handler, ok := b.handlers[command]; ok {
+				b.handle(ctx, handler, Message{RoomId: roomId, EventId: event.EventId, Sender: event.Sender, Body: "/" + strings.TrimSpace(content.Body)[1:]})
+			}
+		}
+	}
+}
+
+// parseCommand accepts ! as well as /, as Element keeps messages starting with an unknown /command to itself
+func parseCommand(body string) (string, bool) {
+	body = strings.TrimSpace(body)
+	if !strings.HasPrefix(body, "/") && !strings.HasPrefix(body, "!") {
+		return "", false
+	}
+	command := strings.Fields(body[1:])
+	if len(command) == 0 {
+		return "", false
+	}
+
+	return command[0], true
+}
+
+func (b *Bot) handle(ctx context.Context, handler HandlerFunc, message Message) {
+	base := logging.FromContext(ctx)
+	logger := log.New(base.Writer(), fmt.Sprintf("%s[event %s] ", base.Prefix(), message.EventId), base.Flags())
+	ctx = logging.WithLogger(ctx, logger)
+	requestCtx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	reply, err := handler(requestCtx, message)
+	if err != nil {
+		logger.Print(err)
+		reply = text("An error occured")
+	}
+	if reply.Text == "" {
+		return
+	}
+
+	content := MessageContent{
+		MsgType: MsgTypeNotice,
+		Body: reply.Text,
+		Format: FormatHtml,
+		FormattedBody: reply.Html,
+		RelatesTo: &RelatesTo{InReplyTo: &InReplyTo{EventId: message.EventId}},
+	}
+	sendCtx, cancelSend := context.WithTimeout(ctx, b.timeout)
+	defer cancelSend()
+	if err = b.client.Send(sendCtx, message.RoomId, "reply-" + message.EventId, content); err != nil {
+		logger.Printf("Could not reply: %s", err)
+	}
+}
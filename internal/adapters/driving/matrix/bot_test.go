@@ -0,0 +1,297 @@
+package matrix_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/matrix"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/alecthomas/assert/v2"
+)
+
+const (
+	botId = "@reservations:example.org"
+	room = "!lab:example.org"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Current() time.Time {
+	return c.now
+}
+
+type sentMessage struct {
+	RoomId string
+	TxnId string
+	Content matrix.MessageContent
+}
+
+// homeserver implements just enough of the client-server API for the bot: /sync, /send and /join
+type homeserver struct {
+	mu sync.Mutex
+	events []roomEvent
+	invites []string
+	joined []string
+	sent []sentMessage
+	// synced is closed on the first sync, the bot replies to the messages posted after it
+	synced chan struct{}
+	syncOnce sync.Once
+}
+
+type roomEvent struct {
+	roomId string
+	event matrix.Event
+}
+
+func (hs *homeserver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"errcode":"M_UNKNOWN_TOKEN","error":"Invalid access token"}`)
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /_matrix/client/v3/sync", hs.sync)
+	mux.HandleFunc("PUT /_matrix/client/v3/rooms/{room}/send/m.room.message/{txn}", hs.send)
+	mux.HandleFunc("POST /_matrix/client/v3/rooms/{room}/join", hs.join)
+	mux.ServeHTTP(w, r)
+}
+
+func (hs *homeserver) sync(w http.ResponseWriter, r *http.Request) {
+	hs.syncOnce.Do(func() {
+		close(hs.synced)
+	})
+	since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+	timeout, _ := strconv.Atoi(r.URL.Query().Get("timeout"))
+	deadline := time.Now().Add(time.Duration(timeout) * time.Millisecond)
+	for {
+		hs.mu.Lock()
+		if len(hs.events) > since || len(hs.invites) > 0 || time.Now().After(deadline) {
+			break
+		}
+		hs.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	defer hs.mu.Unlock()
+
+	response := matrix.SyncResponse{
+		NextBatch: strconv.Itoa(len(hs.events)),
+		Rooms: matrix.Rooms{Join: make(map[string]matrix.JoinedRoom), Invite: make(map[string]json.RawMessage)},
+	}
+	for _, e := range hs.events[since:] {
+		joined := response.Rooms.Join[e.roomId]
+		joined.Timeline.Events = append(joined.Timeline.Events, e.event)
+		response.Rooms.Join[e.roomId] = joined
+	}
+	for _, invite := range hs.invites {
+		response.Rooms.Invite[invite] = json.RawMessage(`{}`)
+	}
+	hs.invites = nil
+	json.NewEncoder(w).Encode(response)
+}
+
+func (hs *homeserver) send(w http.ResponseWriter, r *http.Request) {
+	var content matrix.MessageContent
+	if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.sent = append(hs.sent, sentMessage{RoomId: r.PathValue("room"), TxnId: r.PathValue("txn"), Content: content})
+	fmt.Fprintf(w, `{"event_id":"$sent%d"}`, len(hs.sent))
+}
+
+func (hs *homeserver) join(w http.ResponseWriter, r *http.Request) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.joined = append(hs.joined, r.PathValue("room"))
+	fmt.Fprintf(w, `{"room_id":%q}`, r.PathValue("room"))
+}
+
+func (hs *homeserver) post(sender string, msgType string, body string, at time.Time) string {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	content, _ := json.Marshal(matrix.MessageContent{MsgType: msgType, Body: body})
+	id := fmt.Sprintf("$event%d", len(hs.events) + 1)
+	hs.events = append(hs.events, roomEvent{roomId: room, event: matrix.Event{
+		Type: matrix.EventMessage,
+		EventId: id,
+		Sender: sender,
+		OriginServerTs: at.UnixMilli(),
+		Content: content,
+	}})
+
+	return id
+}
+
+func (hs *homeserver) say(t *testing.T, sender string, body string) sentMessage {
+	t.Helper()
+	id := hs.post(sender, matrix.MsgTypeText, body, time.Now())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		hs.mu.Lock()
+		for _, sent := range hs.sent {
+			if sent.Content.RelatesTo != nil && sent.Content.RelatesTo.InReplyTo.EventId == id {
+				hs.mu.Unlock()
+				return sent
+			}
+		}
+		hs.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("The bot did not reply to %q", body)
+
+	return sentMessage{}
+}
+
+func (hs *homeserver) sentCount() int {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	return len(hs.sent)
+}
+
+// setup starts the bot once the messages posted by before are in the room
+func setup(t *testing.T, before func(hs *homeserver)) *homeserver {
+	ctx := context.Background()
+	subjectsStore := inmemory.NewSubjectsStore()
+	usersStore := inmemory.NewUsersStore()
+	reservationsStore := inmemory.NewReservationStore()
+	readStore := inmemory.NewReservationReadStore(reservationsStore, usersStore, subjectsStore)
+	clock := fixedClock{time.Date(2026, 4, 10, 12, 0, 0, 0, time.UTC)}
+
+	subjects := application.NewSubjectService(subjectsStore, nil)
+	userService := application.NewUserService(usersStore)
+	reservationService := application.NewReservationService(
+		subjectsStore,
+		reservationsStore,
+		readStore,
+		usersStore,
+		inmemory.NewUnitOfWork(reservationsStore),
+		clock,
+		nil,
+	)
+	for _, name := range []string{"Bench", "Phone"} {
+		_, err := subjects.Create(ctx, application.CreateSubject{Name: name, WorkspaceId: workspaces.Default})
+		assert.NoError(t, err)
+	}
+
+	hs := &homeserver{synced: make(chan struct{})}
+	if before != nil {
+		before(hs)
+	}
+	server := httptest.NewServer(hs)
+	t.Cleanup(server.Close)
+
+	b := matrix.NewBot(matrix.NewClient(server.Client(), server.URL, "secret"), botId, time.Second)
+	b.PollTimeout = 100 * time.Millisecond
+	b.RetryDelay = 10 * time.Millisecond
+	matrix.NewAdapter(
+		subjects,
+		reservationService,
+		userService,
+		application.NewChatUserService(inmemory.NewChatUsersStore(matrix.Platform, usersStore), userService),
+		application.NewWorkspaceService(inmemory.NewWorkspacesStore()),
+		clock,
+	).Register(b)
+
+	botCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.Start(botCtx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	<-hs.synced
+
+	return hs
+}
+
+func TestMatrixBot(t *testing.T) {
+	t.Run("it replies to commands with formatted notices", func(t *testing.T) {
+		hs := setup(t, nil)
+
+		reply := hs.say(t, "@alice:example.org", "/list")
+
+		assert.Equal(t, room, reply.RoomId)
+		assert.Equal(t, matrix.MsgTypeNotice, reply.Content.MsgType)
+		assert.Equal(t, "Bench\nPhone", reply.Content.Body)
+		assert.Equal(t, matrix.FormatHtml, reply.Content.Format)
+		assert.Equal(t, "<ul><li>Bench</li><li>Phone</li></ul>", reply.Content.FormattedBody)
+	})
+
+	t.Run("it reserves subjects on behalf of the matrix user", func(t *testing.T) {
+		hs := setup(t, nil)
+
+		reply := hs.say(t, "@alice:example.org", "/reserve Bench,Phone 30")
+		assert.Equal(t, "Reservation for Bench, Phone acquired by alice until 2026-04-10 12:30:00", reply.Content.Body)
+
+		reply = hs.say(t, "@bob:example.org", "!reserve Phone 10")
+		assert.Equal(t, "Already reserved by alice until 2026-04-10 12:30:00", reply.Content.Body)
+
+		reply = hs.say(t, "@bob:example.org", "/reserved")
+		assert.Contains(t, reply.Content.Body, "Bench\t2026-04-10 12:30:00\t\talice")
+		assert.Contains(t, reply.Content.FormattedBody, "<td>Bench</td><td>2026-04-10 12:30:00</td><td>alice</td>")
+
+		reply = hs.say(t, "@alice:example.org", "/remove Bench")
+		assert.Equal(t, "Reservation for Bench removed", reply.Content.Body)
+	})
+
+	t.Run("it replies with the usage to malformed commands", func(t *testing.T) {
+		hs := setup(t, nil)
+
+		reply := hs.say(t, "@alice:example.org", "/reserve Bench")
+
+		assert.Contains(t, reply.Content.Body, "Expected: /reserve")
+	})
+
+	t.Run("it replies with an error when the command fails", func(t *testing.T) {
+		hs := setup(t, nil)
+
+		reply := hs.say(t, "@alice:example.org", "/remove Bench")
+
+		assert.Equal(t, "An error occured", reply.Content.Body)
+	})
+
+	t.Run("it ignores old messages, its own messages and other chatter", func(t *testing.T) {
+		hs := setup(t, func(hs *homeserver) {
+			hs.post("@alice:example.org", matrix.MsgTypeText, "/list", time.Now().Add(-time.Hour))
+		})
+
+		hs.post(botId, matrix.MsgTypeText, "/list", time.Now())
+		hs.post("@alice:example.org", matrix.MsgTypeText, "hello there", time.Now())
+		hs.post("@alice:example.org", matrix.MsgTypeNotice, "/list", time.Now())
+		hs.post("@alice:example.org", matrix.MsgTypeText, "/unknown", time.Now())
+		hs.say(t, "@alice:example.org", "/components Bench")
+
+		assert.Equal(t, 1, hs.sentCount())
+	})
+
+	t.Run("it joins the rooms it is invited to", func(t *testing.T) {
+		hs := setup(t, func(hs *homeserver) {
+			hs.invites = []string{"!new:example.org"}
+		})
+
+		hs.say(t, "@alice:example.org", "/list")
+
+		hs.mu.Lock()
+		defer hs.mu.Unlock()
+		assert.Equal(t, []string{"!new:example.org"}, hs.joined)
+	})
+}
@@ -0,0 +1,325 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	"github.com/go-telegram/bot/models"
+)
+
+// Platform keys the users linked to Matrix accounts, see application.ChatUserService
+const Platform = "matrix"
+
+// matrixAdapter works with the subjects of the workspace the room is bound to, see ChatKey
+type matrixAdapter struct {
+	subjectService *application.SubjectService
+	reservationsService *application.ReservationService
+	userService *application.UserService
+	matrixUserService *application.ChatUserService
+	workspaceService *application.WorkspaceService
+	clock ports.Clock
+}
+
+func NewAdapter(
+	subjectService *application.SubjectService,
+	reservationService *application.ReservationService,
+	userService *application.UserService,
+	matrixUserService *application.ChatUserService,
+	workspaceService *application.WorkspaceService,
+	clock ports.Clock,
+) *matrixAdapter {
+	return &matrixAdapter{
+		subjectService: subjectService,
+		reservationsService: reservationService,
+		userService: userService,
+		matrixUserService: matrixUserService,
+		workspaceService: workspaceService,
+		clock: clock,
+	}
+}
+
+// ChatKey identifies a Matrix room among the chats bound to workspaces
+func ChatKey(roomId string) string {
+	return Platform + ":" + roomId
+}
+
+// workspace returns the id of the workspace of the room the message was sent to
+func (ma *matrixAdapter) workspace(ctx context.Context, roomId string) (int, error) {
+	return ma.workspaceService.ForChat(ctx, ChatKey(roomId))
+}
+
+// Register routes the commands of the Telegram bot to their handlers
+func (ma *matrixAdapter) Register(b *Bot) {
+	b.Handle("add_subject", ma.AddSubjectHandler)
+	b.Handle("add_tags", ma.AddSubjectTagsHandler)
+	b.Handle("list", ma.ListSubjectsHandler)
+	b.Handle("tags", ma.ListSubjectTagsHandler)
+	b.Handle("add_component", ma.AddComponentHandler)
+	b.Handle("components", ma.ListComponentsHandler)
+	b.Handle("reserve", ma.CreateReservationHandler)
+	b.Handle("remove", ma.RemoveReservationHandler)
+	b.Handle("reserved", ma.ActiveReservationsHandler)
+}
+
+// update wraps the message for the command parsers of the Telegram adapter, which share its grammar
+func update(message Message) *models.Update {
+	return &models.Update{Message: &models.Message{Text: message.Body}}
+}
+
+func (ma *matrixAdapter) AddSubjectHandler(ctx context.Context, message Message) (Reply, error) {
+	input, err := telegram.ParseAddSubject(update(message))
+	if err != nil {
+		return text(err.Error()), nil
+	}
+	workspaceId, err := ma.workspace(ctx, message.RoomId)
+	if err != nil {
+		return Reply{}, err
+	}
+	if _, err = ma.subjectService.Create(ctx, application.CreateSubject{Name: input.Name, WorkspaceId: workspaceId}); err != nil {
+		return Reply{}, err
+	}
+
+	return text(fmt.Sprintf("Subject %s added", input.Name)), nil
+}
+
+func (ma *matrixAdapter) AddSubjectTagsHandler(ctx context.Context, message Message) (Reply, error) {
+	input, err := telegram.ParseAddTags(update(message))
+	if err != nil {
+		return text(err.Error()), nil
+	}
+	workspaceId, err := ma.workspace(ctx, message.RoomId)
+	if err != nil {
+		return Reply{}, err
+	}
+	subject, err := ma.subjectService.GetByName(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return Reply{}, err
+	}
+	if err = ma.subjectService.AddTags(ctx, application.AddTags{SubjectId: subject.Id, Tags: input.Tags}); err != nil {
+		return Reply{}, err
+	}
+
+	return text(fmt.Sprintf("tags: %s added to %s", strings.Join(input.Tags, ", "), input.SubjectName)), nil
+}
+
+func (ma *matrixAdapter) ListSubjectsHandler(ctx context.Context, message Message) (Reply, error) {
+	workspaceId, err := ma.workspace(ctx, message.RoomId)
+	if err != nil {
+		return Reply{}, err
+	}
+	subjects, err := ma.subjectService.List(ctx, workspaceId)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	var names []string
+	for _, subject := range subjects {
+		names = append(names, subject.Name)
+	}
+
+	return list(subjects.Names(), names), nil
+}
+
+func (ma *matrixAdapter) ListSubjectTagsHandler(ctx context.Context, message Message) (Reply, error) {
+	input, err := telegram.ParseListTags(update(message))
+	if err != nil {
+		return text(err.Error()), nil
+	}
+	workspaceId, err := ma.workspace(ctx, message.RoomId)
+	if err != nil {
+		return Reply{}, err
+	}
+	subject, err := ma.subjectService.GetByName(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return Reply{}, err
+	}
+	tags, err := ma.subjectService.ListTags(ctx, subject.Id)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	return list(strings.Join(tags, "\n"), tags), nil
+}
+
+func (ma *matrixAdapter) AddComponentHandler(ctx context.Context, message Message) (Reply, error) {
+	input, err := telegram.ParseAddComponent(update(message))
+	if err != nil {
+		return text(err.Error()), nil
+	}
+	workspaceId, err := ma.workspace(ctx, message.RoomId)
+	if err != nil {
+		return Reply{}, err
+	}
+	parent, err := ma.subjectService.GetByName(ctx, workspaceId, input.ParentName)
+	if err != nil {
+		return Reply{}, err
+	}
+	component, err := ma.subjectService.GetByName(ctx, workspaceId, input.ComponentName)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	err = ma.subjectService.AddComponent(ctx, application.AddComponent{ParentId: parent.Id, ComponentId: component.Id})
+	if err != nil {
+		return Reply{}, err
+	}
+
+	return text(fmt.Sprintf("%s added as a component of %s", component.Name, parent.Name)), nil
+}
+
+func (ma *matrixAdapter) ListComponentsHandler(ctx context.Context, message Message) (Reply, error) {
+	input, err := telegram.ParseListComponents(update(message))
+	if err != nil {
+		return text(err.Error()), nil
+	}
+	workspaceId, err := ma.workspace(ctx, message.RoomId)
+	if err != nil {
+		return Reply{}, err
+	}
+	subject, err := ma.subjectService.GetByName(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return Reply{}, err
+	}
+	components, err := ma.subjectService.ListComponents(ctx, subject.Id)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	if len(components) == 0 {
+		return text(fmt.Sprintf("%s has no components", subject.Name)), nil
+	}
+
+	var names []string
+	for _, component := range components {
+		names = append(names, component.Name)
+	}
+
+	return list(components.Names(), names), nil
+}
+
+func (ma *matrixAdapter) CreateReservationHandler(ctx context.Context, message Message) (Reply, error) {
+	input, err := telegram.ParseCreateReservation(update(message), ma.clock.Current())
+	if err != nil {
+		return text(err.Error()), nil
+	}
+	user, err := ma.matrixUserService.GetOrCreate(ctx, application.CreateChatUser{Id: message.Sender, Name: message.Localpart()})
+	if err != nil {
+		return Reply{}, err
+	}
+
+	workspaceId, err := ma.workspace(ctx, message.RoomId)
+	if err != nil {
+		return Reply{}, err
+	}
+	var subjectIds []int
+	for _, name := range input.SubjectNames {
+		subject, err := ma.subjectService.GetByName(ctx, workspaceId, name)
+		if err != nil {
+			return Reply{}, err
+		}
+		subjectIds = append(subjectIds, subject.Id)
+	}
+
+	cmd := application.CreateReservations{UserId: user.Id, SubjectIds: subjectIds, From: input.From, To: input.To}
+	rs, err := ma.reservationsService.CreateBatch(ctx, cmd)
+	if err != nil {
+		if batchErr, ok := err.(application.BatchReservationError); ok {
+			return text(ma.conflictsMessage(ctx, batchErr, len(subjectIds) > 1)), nil
+		}
+		return Reply{}, err
+	}
+
+	subjects := strings.Join(input.SubjectNames, ", ")
+	until := rs[0].End.Format(time.DateTime)
+	return Reply{
+		Text: fmt.Sprintf("Reservation for %s acquired by %s until %s", subjects, user.Name, until),
+		Html: fmt.Sprintf("Reservation for <b>%s</b> acquired by %s until <b>%s</b>", html.EscapeString(subjects), html.EscapeString(user.Name), until),
+	}, nil
+}
+
+func (ma *matrixAdapter) conflictsMessage(ctx context.Context, batchErr application.BatchReservationError, withSubjects bool) string {
+	var lines []string
+	for _, conflict := range batchErr.Conflicts {
+		r, _ := ma.reservationsService.Get(ctx, conflict.ReservationIds[0])
+		u, _ := ma.userService.Get(ctx, r.UserId)
+		line := fmt.Sprintf("Already reserved by %s until %s", u.Name, r.End.Format(time.DateTime))
+		if withSubjects {
+			subject, _ := ma.subjectService.Get(ctx, conflict.SubjectId)
+			line = subject.Name + ": " + line
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (ma *matrixAdapter) RemoveReservationHandler(ctx context.Context, message Message) (Reply, error) {
+	input, err := telegram.ParseRemoveReservation(update(message))
+	if err != nil {
+		return text(err.Error()), nil
+	}
+	workspaceId, err := ma.workspace(ctx, message.RoomId)
+	if err != nil {
+		return Reply{}, err
+	}
+	subject, err := ma.subjectService.GetByName(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return Reply{}, err
+	}
+	user, err := ma.matrixUserService.Get(ctx, message.Sender)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	err = ma.reservationsService.Remove(ctx, application.RemoveReservations{UserId: user.Id, SubjectId: subject.Id})
+	if err != nil {
+		return Reply{}, err
+	}
+
+	return text(fmt.Sprintf("Reservation for %s removed", subject.Name)), nil
+}
+
+func (ma *matrixAdapter) ActiveReservationsHandler(ctx context.Context, message Message) (Reply, error) {
+	input, err := telegram.ParseActiveReservations(update(message))
+	if err != nil {
+		return text(err.Error()), nil
+	}
+	workspaceId, err := ma.workspace(ctx, message.RoomId)
+	if err != nil {
+		return Reply{}, err
+	}
+	reservations, err := ma.reservationsService.ActiveReservations(ctx, workspaceId, ma.clock.Current(), input.Tags...)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	body := "Subject\tReserved Until\t\tUser\n"
+	var rows [][]string
+	for _, reservation := range reservations {
+		end := reservation.End.Format(time.DateTime)
+		body += fmt.Sprintf("%s\t%s\t\t%s\n", reservation.Subject, end, reservation.User)
+		rows = append(rows, []string{reservation.Subject, end, reservation.User})
+	}
+
+	return Reply{Text: body, Html: table([]string{"Subject", "Reserved Until", "User"}, rows)}, nil
+}
+
+// list renders the items as an html list, body is the plain text of the other adapters
+func list(body string, items []string) Reply {
+	if len(items) == 0 {
+		return text(body)
+	}
+
+	formatted := "<ul>"
+	for _, item := range items {
+		formatted += "<li>" + html.EscapeString(item) + "</li>"
+	}
+
+	return Reply{Text: body, Html: formatted + "</ul>"}
+}
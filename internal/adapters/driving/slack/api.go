@@ -0,0 +1,129 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	TimestampHeader = "X-Slack-Request-Timestamp"
+	// SignatureHeader holds "v0=" followed by the hex HMAC-SHA256 of "v0:<timestamp>:<body>" keyed with the signing secret
+	SignatureHeader = "X-Slack-Signature"
+
+	ResponseInChannel = "in_channel"
+	ResponseEphemeral = "ephemeral"
+
+	// maxRequestAge rejects replayed requests, the limit Slack recommends
+	maxRequestAge = 5 * time.Minute
+)
+
+var ErrInvalidSignature = errors.New("Invalid slack request signature")
+
+// SlashCommand is the form Slack posts when a user runs a slash command
+type SlashCommand struct {
+	Command string
+	Text string
+	TeamId string
+	ChannelId string
+	UserId string
+	UserName string
+	ResponseUrl string
+}
+
+// Interaction is the payload Slack posts when a user clicks a button of a message
+type Interaction struct {
+	Type string `json:"type"`
+	User struct {
+		Id string `json:"id"`
+		UserName string `json:"username"`
+		Name string `json:"name"`
+	} `json:"user"`
+	Channel struct {
+		Id string `json:"id"`
+	} `json:"channel"`
+	ResponseUrl string `json:"response_url"`
+	Actions []Action `json:"actions"`
+}
+
+type Action struct {
+	ActionId string `json:"action_id"`
+	Value string `json:"value"`
+}
+
+// Message is a reply to a command or an interaction in the Slack message format
+type Message struct {
+	ResponseType string `json:"response_type,omitempty"`
+	ReplaceOriginal bool `json:"replace_original,omitempty"`
+	Text string `json:"text"`
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+type Block struct {
+	Type string `json:"type"`
+	Text *Text `json:"text,omitempty"`
+	Accessory *Element `json:"accessory,omitempty"`
+	Elements []Element `json:"elements,omitempty"`
+}
+
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type Element struct {
+	Type string `json:"type"`
+	Text *Text `json:"text,omitempty"`
+	ActionId string `json:"action_id,omitempty"`
+	Value string `json:"value,omitempty"`
+	Style string `json:"style,omitempty"`
+}
+
+func reply(text string) Message {
+	return Message{ResponseType: ResponseInChannel, Text: text}
+}
+
+func ephemeral(text string) Message {
+	return Message{ResponseType: ResponseEphemeral, Text: text}
+}
+
+func section(text string) Block {
+	return Block{Type: "section", Text: &Text{Type: "mrkdwn", Text: text}}
+}
+
+func button(label string, actionId string, value string) Element {
+	return Element{Type: "button", Text: &Text{Type: "plain_text", Text: label}, ActionId: actionId, Value: value}
+}
+
+// Sign returns the signature header value of a request body sent at the given unix timestamp
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that the request body was signed by Slack recently
+func Verify(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get(TimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(header.Get(SignatureHeader))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
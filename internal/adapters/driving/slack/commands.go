@@ -0,0 +1,133 @@
+package slack
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/timeparse"
+)
+
+type AddSubject struct {
+	Name string
+}
+
+type AddTags struct {
+	SubjectName string
+	Tags []string
+}
+
+type ListTags struct {
+	SubjectName string
+}
+
+type AddComponent struct {
+	ParentName string
+	ComponentName string
+}
+
+type ListComponents struct {
+	SubjectName string
+}
+
+type CreateReservation struct {
+	SubjectNames []string
+	From time.Time
+	To time.Time
+}
+
+type RemoveReservation struct {
+	SubjectName string
+}
+
+type ActiveReservations struct {
+	Tags []string
+}
+
+func ParseAddSubject(cmd SlashCommand) (AddSubject, error) {
+	name := strings.TrimSpace(cmd.Text)
+	if name == "" {
+		return AddSubject{}, fmt.Errorf("Invalid format for add subject command. Expected: /add_subject <name>")
+	}
+
+	return AddSubject{Name: name}, nil
+}
+
+func ParseAddTags(cmd SlashCommand) (AddTags, error) {
+	args := strings.Fields(cmd.Text)
+	if len(args) < 2 {
+		return AddTags{}, fmt.Errorf("Invalid format for add tags command. Expected: /add_tags <subject_name> <tag1> [tag2] [tag3]...")
+	}
+
+	return AddTags{
+		SubjectName: args[0],
+		Tags: args[1:],
+	}, nil
+}
+
+func ParseListTags(cmd SlashCommand) (ListTags, error) {
+	name := strings.TrimSpace(cmd.Text)
+	if name == "" {
+		return ListTags{}, fmt.Errorf("Invalid format for list tags command. Expected: /tags <subject_name>")
+	}
+
+	return ListTags{SubjectName: name}, nil
+}
+
+func ParseAddComponent(cmd SlashCommand) (AddComponent, error) {
+	args := strings.Fields(cmd.Text)
+	if len(args) != 2 {
+		return AddComponent{}, fmt.Errorf("Invalid format for add component command. Expected: /add_component <parent_name> <component_name>")
+	}
+
+	return AddComponent{ParentName: args[0], ComponentName: args[1]}, nil
+}
+
+func ParseListComponents(cmd SlashCommand) (ListComponents, error) {
+	name := strings.TrimSpace(cmd.Text)
+	if name == "" {
+		return ListComponents{}, fmt.Errorf("Invalid format for list components command. Expected: /components <subject_name>")
+	}
+
+	return ListComponents{SubjectName: name}, nil
+}
+
+// ParseCreateReservation reads the period of the reservation relative to now, see timeparse.Formats
+func ParseCreateReservation(cmd SlashCommand, now time.Time) (CreateReservation, error) {
+	error := func () (CreateReservation, error) {
+		return CreateReservation{}, fmt.Errorf("Invalid format for reserve command. Expected: /reserve <subject_name>[,<subject_name>...] <period>")
+	}
+
+	args := strings.Fields(cmd.Text)
+	if len(args) < 2 {
+		return error()
+	}
+	subjectNames := strings.Split(args[0], ",")
+	if slices.Contains(subjectNames, "") {
+		return error()
+	}
+	period, err := timeparse.Parse(strings.Join(args[1:], " "), now)
+	if err != nil {
+		return CreateReservation{}, err
+	}
+
+	return CreateReservation{
+		SubjectNames: subjectNames,
+		From: period.From,
+		To: period.To,
+	}, nil
+}
+
+func ParseRemoveReservation(cmd SlashCommand) (RemoveReservation, error) {
+	name := strings.TrimSpace(cmd.Text)
+	if name == "" {
+		return RemoveReservation{}, fmt.Errorf("Invalid format for remove reservation command. Expected: /remove <subject_name>")
+	}
+
+	return RemoveReservation{SubjectName: name}, nil
+}
+
+func ParseActiveReservations(cmd SlashCommand) (ActiveReservations, error) {
+	return ActiveReservations{Tags: strings.Fields(cmd.Text)}, nil
+}
@@ -0,0 +1,58 @@
+package slack_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/slack"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestInputParsers(t *testing.T) {
+	command := func(text string) slack.SlashCommand {
+		return slack.SlashCommand{Text: text}
+	}
+
+	t.Run("it parses AddTags command", func(t *testing.T) {
+		cmd, err := slack.ParseAddTags(command("Test tag1  tag2"))
+		assert.NoError(t, err)
+		assert.Equal(t, slack.AddTags{SubjectName: "Test", Tags: []string{"tag1", "tag2"}}, cmd)
+
+		_, err = slack.ParseAddTags(command("Test"))
+		assert.Error(t, err)
+	})
+
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("it parses CreateReservation command", func(t *testing.T) {
+		cmd, err := slack.ParseCreateReservation(command("Bench,Phone 30"), now)
+		assert.NoError(t, err)
+		assert.Equal(t, slack.CreateReservation{SubjectNames: []string{"Bench", "Phone"}, From: now, To: now.Add(30 * time.Minute)}, cmd)
+
+		cmd, err = slack.ParseCreateReservation(command("Bench until 17:00"), now)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 4, 1, 17, 0, 0, 0, time.UTC), cmd.To)
+	})
+
+	t.Run("it returns error given wrong format provided to CreateReservation", func(t *testing.T) {
+		for _, text := range []string{"", "Bench", "Bench thirty", "Bench, 30", "Bench 0", "Bench 30 parsecs"} {
+			_, err := slack.ParseCreateReservation(command(text), now)
+			assert.Error(t, err, text)
+		}
+	})
+
+	t.Run("it parses RemoveReservation command", func(t *testing.T) {
+		cmd, err := slack.ParseRemoveReservation(command(" Bench "))
+		assert.NoError(t, err)
+		assert.Equal(t, "Bench", cmd.SubjectName)
+
+		_, err = slack.ParseRemoveReservation(command(""))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses ActiveReservations command", func(t *testing.T) {
+		cmd, err := slack.ParseActiveReservations(command("lab phones"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"lab", "phones"}, cmd.Tags)
+	})
+}
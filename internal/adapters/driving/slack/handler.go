@@ -0,0 +1,172 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/logging"
+)
+
+const maxRequestSize = 1 << 20
+
+type CommandHandler func(ctx context.Context, cmd SlashCommand) (Message, error)
+
+type ActionHandler func(ctx context.Context, interaction Interaction, action Action) (Message, error)
+
+// Handler serves the slash commands and the interactive messages Slack posts to the app
+type Handler struct {
+	signingSecret string
+	client *http.Client
+	timeout time.Duration
+	commands map[string]CommandHandler
+	actions map[string]ActionHandler
+}
+
+func NewHandler(signingSecret string, client *http.Client, timeout time.Duration) *Handler {
+	return &Handler{
+		signingSecret: signingSecret,
+		client: client,
+		timeout: timeout,
+		commands: make(map[string]CommandHandler),
+		actions: make(map[string]ActionHandler),
+	}
+}
+
+func (h *Handler) HandleCommand(command string, handler CommandHandler) {
+	h.commands[command] = handler
+}
+
+func (h *Handler) HandleAction(actionId string, handler ActionHandler) {
+	h.actions[actionId] = handler
+}
+
+// Register serves the request URLs to configure in the Slack app settings
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /slack/commands", h.serveCommand)
+	mux.HandleFunc("POST /slack/interactions", h.serveInteraction)
+}
+
+func (h *Handler) serveCommand(w http.ResponseWriter, r *http.Request) {
+	form, ok := h.verifiedForm(w, r)
+	if !ok {
+		return
+	}
+	cmd := SlashCommand{
+		Command: form.Get("command"),
+		Text: form.Get("text"),
+		TeamId: form.Get("team_id"),
+		ChannelId: form.Get("channel_id"),
+		UserId: form.Get("user_id"),
+		UserName: form.Get("user_name"),
+		ResponseUrl: form.Get("response_url"),
+	}
+
+	handler, ok := h.commands[cmd.Command]
+	if !ok {
+		writeMessage(w, ephemeral(fmt.Sprintf("Unknown command %s", cmd.Command)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+	message, err := handler(ctx, cmd)
+	if err != nil {
+		logging.FromContext(ctx).Printf("%s %s: %s", cmd.Command, cmd.Text, err)
+		message = ephemeral("An error occured")
+	}
+
+	writeMessage(w, message)
+}
+
+// serveInteraction acknowledges the click right away and posts the reply to the response_url of the interaction
+func (h *Handler) serveInteraction(w http.ResponseWriter, r *http.Request) {
+	form, ok := h.verifiedForm(w, r)
+	if !ok {
+		return
+	}
+	var interaction Interaction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &interaction); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+
+	if interaction.Type != "block_actions" || len(interaction.Actions) == 0 {
+		return
+	}
+	action := interaction.Actions[0]
+	handler, ok := h.actions[action.ActionId]
+	if !ok {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), h.timeout)
+		defer cancel()
+		log := logging.FromContext(ctx)
+
+		message, err := handler(ctx, interaction, action)
+		if err != nil {
+			log.Printf("%s %s: %s", action.ActionId, action.Value, err)
+			message = ephemeral("An error occured")
+		}
+		if err = h.respond(ctx, interaction.ResponseUrl, message); err != nil {
+			log.Print(err)
+		}
+	}()
+}
+
+func (h *Handler) verifiedForm(w http.ResponseWriter, r *http.Request) (url.Values, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return nil, false
+	}
+	if err = Verify(h.signingSecret, r.Header, body, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return nil, false
+	}
+
+	return form, true
+}
+
+func (h *Handler) respond(ctx context.Context, responseUrl string, message Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, responseUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := h.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64 << 10))
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack responded with %s", response.Status)
+	}
+
+	return nil
+}
+
+func writeMessage(w http.ResponseWriter, message Message) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(message)
+}
@@ -0,0 +1,236 @@
+package slack_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/slack"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/alecthomas/assert/v2"
+)
+
+const secret = "signing-secret"
+
+const labChannel = "CLAB"
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Current() time.Time {
+	return c.now
+}
+
+type fixture struct {
+	app *httptest.Server
+	responses chan slack.Message
+	responseUrl string
+	// channel is the channel the commands are sent to, labChannel is bound to the lab workspace
+	channel string
+}
+
+func setup(t *testing.T) *fixture {
+	ctx := context.Background()
+	subjectsStore := inmemory.NewSubjectsStore()
+	usersStore := inmemory.NewUsersStore()
+	reservationsStore := inmemory.NewReservationStore()
+	readStore := inmemory.NewReservationReadStore(reservationsStore, usersStore, subjectsStore)
+	clock := fixedClock{time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)}
+
+	subjects := application.NewSubjectService(subjectsStore, nil)
+	userService := application.NewUserService(usersStore)
+	reservationService := application.NewReservationService(
+		subjectsStore,
+		reservationsStore,
+		readStore,
+		usersStore,
+		inmemory.NewUnitOfWork(reservationsStore),
+		clock,
+		nil,
+	)
+	_, err := subjects.Create(ctx, application.CreateSubject{Name: "Bench", WorkspaceId: workspaces.Default})
+	assert.NoError(t, err)
+	_, err = subjects.Create(ctx, application.CreateSubject{Name: "Phone", WorkspaceId: workspaces.Default})
+	assert.NoError(t, err)
+	workspaceService := application.NewWorkspaceService(inmemory.NewWorkspacesStore())
+	lab, err := workspaceService.Create(ctx, "lab")
+	assert.NoError(t, err)
+	assert.NoError(t, workspaceService.Bind(ctx, slack.ChatKey(labChannel), lab.Id))
+	_, err = subjects.Create(ctx, application.CreateSubject{Name: "Scope", WorkspaceId: lab.Id})
+	assert.NoError(t, err)
+
+	handler := slack.NewHandler(secret, http.DefaultClient, time.Second)
+	slack.NewAdapter(
+		subjects,
+		reservationService,
+		userService,
+		application.NewChatUserService(inmemory.NewChatUsersStore(slack.Platform, usersStore), userService),
+		workspaceService,
+		clock,
+	).Register(handler)
+	mux := http.NewServeMux()
+	handler.Register(mux)
+
+	f := &fixture{app: httptest.NewServer(mux), responses: make(chan slack.Message, 10), channel: "CGENERAL"}
+	t.Cleanup(f.app.Close)
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message slack.Message
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		f.responses <- message
+	}))
+	t.Cleanup(responder.Close)
+	f.responseUrl = responder.URL
+
+	return f
+}
+
+func (f *fixture) post(t *testing.T, path string, form url.Values, sign func(timestamp string, body []byte) string) *http.Response {
+	body := form.Encode()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request, err := http.NewRequest(http.MethodPost, f.app.URL + path, strings.NewReader(body))
+	assert.NoError(t, err)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set(slack.TimestampHeader, timestamp)
+	request.Header.Set(slack.SignatureHeader, sign(timestamp, []byte(body)))
+
+	response, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		response.Body.Close()
+	})
+
+	return response
+}
+
+func (f *fixture) command(t *testing.T, user string, command string, text string) slack.Message {
+	response := f.post(t, "/slack/commands", url.Values{
+		"command": {command},
+		"text": {text},
+		"user_id": {"U" + strings.ToUpper(user)},
+		"user_name": {user},
+		"channel_id": {f.channel},
+		"response_url": {f.responseUrl},
+	}, func(timestamp string, body []byte) string {
+		return slack.Sign(secret, timestamp, body)
+	})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var message slack.Message
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&message))
+
+	return message
+}
+
+func (f *fixture) click(t *testing.T, user string, action slack.Action) slack.Message {
+	payload, err := json.Marshal(map[string]any{
+		"type": "block_actions",
+		"user": map[string]string{"id": "U" + strings.ToUpper(user), "username": user},
+		"response_url": f.responseUrl,
+		"actions": []slack.Action{action},
+	})
+	assert.NoError(t, err)
+	response := f.post(t, "/slack/interactions", url.Values{"payload": {string(payload)}}, func(timestamp string, body []byte) string {
+		return slack.Sign(secret, timestamp, body)
+	})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	select {
+	case message := <-f.responses:
+		return message
+	case <-time.After(time.Second):
+		t.Fatal("No response was posted to the response_url")
+	}
+
+	return slack.Message{}
+}
+
+func TestSlackHandler(t *testing.T) {
+	t.Run("it rejects requests with an invalid signature", func(t *testing.T) {
+		f := setup(t)
+
+		response := f.post(t, "/slack/commands", url.Values{"command": {"/list"}}, func(timestamp string, body []byte) string {
+			return slack.Sign("another-secret", timestamp, body)
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	})
+
+	t.Run("it rejects replayed requests", func(t *testing.T) {
+		header := http.Header{}
+		timestamp := strconv.FormatInt(time.Now().Add(-10 * time.Minute).Unix(), 10)
+		header.Set(slack.TimestampHeader, timestamp)
+		header.Set(slack.SignatureHeader, slack.Sign(secret, timestamp, []byte("command=/list")))
+
+		assert.IsError(t, slack.Verify(secret, header, []byte("command=/list"), time.Now()), slack.ErrInvalidSignature)
+	})
+
+	t.Run("it lists subjects with reserve buttons", func(t *testing.T) {
+		f := setup(t)
+
+		message := f.command(t, "alice", "/list", "")
+
+		assert.Equal(t, slack.ResponseInChannel, message.ResponseType)
+		assert.Equal(t, "Bench\nPhone", message.Text)
+		assert.Equal(t, 2, len(message.Blocks))
+		assert.Equal(t, slack.ActionReserve, message.Blocks[0].Accessory.ActionId)
+	})
+
+	t.Run("it works with the subjects of the workspace the channel is bound to", func(t *testing.T) {
+		f := setup(t)
+		f.channel = labChannel
+
+		assert.Equal(t, "Scope", f.command(t, "alice", "/list", "").Text)
+		message := f.command(t, "alice", "/reserve", "Bench 30")
+		assert.Equal(t, slack.ResponseEphemeral, message.ResponseType)
+	})
+
+	t.Run("it reserves subjects on behalf of the slack user", func(t *testing.T) {
+		f := setup(t)
+
+		message := f.command(t, "alice", "/reserve", "Bench,Phone 30")
+		assert.Equal(t, "Reservation for Bench, Phone acquired by alice until 2026-04-01 12:30:00", message.Text)
+
+		message = f.command(t, "bob", "/reserve", "Phone 10")
+		assert.Equal(t, "Already reserved by alice until 2026-04-01 12:30:00", message.Text)
+
+		message = f.command(t, "bob", "/reserved", "")
+		assert.Contains(t, message.Text, "Bench\t2026-04-01 12:30:00\t\talice")
+		assert.Contains(t, message.Text, "Phone\t2026-04-01 12:30:00\t\talice")
+	})
+
+	t.Run("it replies with usage to malformed commands", func(t *testing.T) {
+		f := setup(t)
+
+		message := f.command(t, "alice", "/reserve", "Bench")
+
+		assert.Equal(t, slack.ResponseEphemeral, message.ResponseType)
+		assert.Contains(t, message.Text, "Expected: /reserve")
+	})
+
+	t.Run("it reserves and releases subjects with buttons", func(t *testing.T) {
+		f := setup(t)
+		reserve := f.command(t, "alice", "/list", "").Blocks[1].Accessory
+
+		message := f.click(t, "alice", slack.Action{ActionId: reserve.ActionId, Value: reserve.Value})
+		assert.Equal(t, "Reservation for Phone acquired by alice until 2026-04-01 12:30:00", message.Text)
+		release := message.Blocks[1].Elements[0]
+		assert.Equal(t, slack.ActionRelease, release.ActionId)
+
+		message = f.click(t, "bob", slack.Action{ActionId: release.ActionId, Value: release.Value})
+		assert.Equal(t, slack.ResponseEphemeral, message.ResponseType)
+
+		message = f.click(t, "alice", slack.Action{ActionId: release.ActionId, Value: release.Value})
+		assert.Equal(t, "Reservation for Phone removed", message.Text)
+		message = f.command(t, "bob", "/reserve", "Phone 10")
+		assert.Contains(t, message.Text, "acquired by bob")
+	})
+}
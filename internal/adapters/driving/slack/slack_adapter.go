@@ -0,0 +1,381 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+)
+
+// Platform keys the users linked to Slack accounts, see application.ChatUserService
+const Platform = "slack"
+
+const (
+	ActionReserve = "reserve"
+	ActionRelease = "release"
+
+	// buttonReservation is the duration in minutes of the reservations made with the Reserve button
+	buttonReservation = 30
+	// maxListButtons keeps the subjects list within the block limit of a Slack message
+	maxListButtons = 40
+)
+
+// slackAdapter works with the subjects of the workspace the channel is bound to, see ChatKey
+type slackAdapter struct {
+	subjectService *application.SubjectService
+	reservationsService *application.ReservationService
+	userService *application.UserService
+	slackUserService *application.ChatUserService
+	workspaceService *application.WorkspaceService
+	clock ports.Clock
+}
+
+func NewAdapter(
+	subjectService *application.SubjectService,
+	reservationService *application.ReservationService,
+	userService *application.UserService,
+	slackUserService *application.ChatUserService,
+	workspaceService *application.WorkspaceService,
+	clock ports.Clock,
+) *slackAdapter {
+	return &slackAdapter{
+		subjectService: subjectService,
+		reservationsService: reservationService,
+		userService: userService,
+		slackUserService: slackUserService,
+		workspaceService: workspaceService,
+		clock: clock,
+	}
+}
+
+// ChatKey identifies a Slack channel among the chats bound to workspaces
+func ChatKey(channelId string) string {
+	return Platform + ":" + channelId
+}
+
+// workspace returns the id of the workspace of the channel the command was sent to
+func (sa *slackAdapter) workspace(ctx context.Context, channelId string) (int, error) {
+	return sa.workspaceService.ForChat(ctx, ChatKey(channelId))
+}
+
+// Register routes the slash commands and the buttons of the adapter messages to their handlers
+func (sa *slackAdapter) Register(h *Handler) {
+	h.HandleCommand("/add_subject", sa.AddSubjectHandler)
+	h.HandleCommand("/add_tags", sa.AddSubjectTagsHandler)
+	h.HandleCommand("/list", sa.ListSubjectsHandler)
+	h.HandleCommand("/tags", sa.ListSubjectTagsHandler)
+	h.HandleCommand("/add_component", sa.AddComponentHandler)
+	h.HandleCommand("/components", sa.ListComponentsHandler)
+	h.HandleCommand("/reserve", sa.CreateReservationHandler)
+	h.HandleCommand("/remove", sa.RemoveReservationHandler)
+	h.HandleCommand("/reserved", sa.ActiveReservationsHandler)
+	h.HandleAction(ActionReserve, sa.ReserveActionHandler)
+	h.HandleAction(ActionRelease, sa.ReleaseActionHandler)
+}
+
+func (sa *slackAdapter) AddSubjectHandler(ctx context.Context, cmd SlashCommand) (Message, error) {
+	input, err := ParseAddSubject(cmd)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := sa.workspace(ctx, cmd.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	if _, err = sa.subjectService.Create(ctx, application.CreateSubject{Name: input.Name, WorkspaceId: workspaceId}); err != nil {
+		return Message{}, err
+	}
+
+	return reply(fmt.Sprintf("Subject %s added", input.Name)), nil
+}
+
+func (sa *slackAdapter) AddSubjectTagsHandler(ctx context.Context, cmd SlashCommand) (Message, error) {
+	input, err := ParseAddTags(cmd)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := sa.workspace(ctx, cmd.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subject, err := sa.subjectService.GetByName(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return Message{}, err
+	}
+	if err = sa.subjectService.AddTags(ctx, application.AddTags{SubjectId: subject.Id, Tags: input.Tags}); err != nil {
+		return Message{}, err
+	}
+
+	return reply(fmt.Sprintf("tags: %s added to %s", strings.Join(input.Tags, ", "), input.SubjectName)), nil
+}
+
+// ListSubjectsHandler lists the subjects with a button reserving each of them
+func (sa *slackAdapter) ListSubjectsHandler(ctx context.Context, cmd SlashCommand) (Message, error) {
+	workspaceId, err := sa.workspace(ctx, cmd.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subjects, err := sa.subjectService.List(ctx, workspaceId)
+	if err != nil {
+		return Message{}, err
+	}
+
+	message := reply(subjects.Names())
+	if len(subjects) == 0 || len(subjects) > maxListButtons {
+		return message, nil
+	}
+	for _, subject := range subjects {
+		block := section(subject.Name)
+		reserve := button(fmt.Sprintf("Reserve %d min", buttonReservation), ActionReserve, strconv.Itoa(subject.Id))
+		block.Accessory = &reserve
+		message.Blocks = append(message.Blocks, block)
+	}
+
+	return message, nil
+}
+
+func (sa *slackAdapter) ListSubjectTagsHandler(ctx context.Context, cmd SlashCommand) (Message, error) {
+	input, err := ParseListTags(cmd)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := sa.workspace(ctx, cmd.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subject, err := sa.subjectService.GetByName(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return Message{}, err
+	}
+	tags, err := sa.subjectService.ListTags(ctx, subject.Id)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return reply(strings.Join(tags, "\n")), nil
+}
+
+func (sa *slackAdapter) AddComponentHandler(ctx context.Context, cmd SlashCommand) (Message, error) {
+	input, err := ParseAddComponent(cmd)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := sa.workspace(ctx, cmd.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	parent, err := sa.subjectService.GetByName(ctx, workspaceId, input.ParentName)
+	if err != nil {
+		return Message{}, err
+	}
+	component, err := sa.subjectService.GetByName(ctx, workspaceId, input.ComponentName)
+	if err != nil {
+		return Message{}, err
+	}
+
+	err = sa.subjectService.AddComponent(ctx, application.AddComponent{ParentId: parent.Id, ComponentId: component.Id})
+	if err != nil {
+		return Message{}, err
+	}
+
+	return reply(fmt.Sprintf("%s added as a component of %s", component.Name, parent.Name)), nil
+}
+
+func (sa *slackAdapter) ListComponentsHandler(ctx context.Context, cmd SlashCommand) (Message, error) {
+	input, err := ParseListComponents(cmd)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := sa.workspace(ctx, cmd.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subject, err := sa.subjectService.GetByName(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return Message{}, err
+	}
+	components, err := sa.subjectService.ListComponents(ctx, subject.Id)
+	if err != nil {
+		return Message{}, err
+	}
+
+	if len(components) == 0 {
+		return reply(fmt.Sprintf("%s has no components", subject.Name)), nil
+	}
+
+	return reply(components.Names()), nil
+}
+
+func (sa *slackAdapter) CreateReservationHandler(ctx context.Context, cmd SlashCommand) (Message, error) {
+	user, err := sa.slackUserService.GetOrCreate(ctx, application.CreateChatUser{Id: cmd.UserId, Name: cmd.UserName})
+	if err != nil {
+		return Message{}, err
+	}
+	now := sa.clock.Current()
+	input, err := ParseCreateReservation(cmd, now.In(user.Location(now.Location())))
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+
+	workspaceId, err := sa.workspace(ctx, cmd.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	var subjectIds []int
+	for _, name := range input.SubjectNames {
+		subject, err := sa.subjectService.GetByName(ctx, workspaceId, name)
+		if err != nil {
+			return Message{}, err
+		}
+		subjectIds = append(subjectIds, subject.Id)
+	}
+
+	return sa.reserve(ctx, user, subjectIds, input.From, input.To)
+}
+
+// ReserveActionHandler reserves the subject of the clicked Reserve button
+func (sa *slackAdapter) ReserveActionHandler(ctx context.Context, interaction Interaction, action Action) (Message, error) {
+	subjectId, err := strconv.Atoi(action.Value)
+	if err != nil {
+		return Message{}, fmt.Errorf("Invalid subject id %q", action.Value)
+	}
+
+	user, err := sa.slackUserService.GetOrCreate(ctx, application.CreateChatUser{Id: interaction.User.Id, Name: interaction.User.UserName})
+	if err != nil {
+		return Message{}, err
+	}
+	now := sa.clock.Current()
+
+	return sa.reserve(ctx, user, []int{subjectId}, now, now.Add(buttonReservation*time.Minute))
+}
+
+// reserve replies with a Release button on success
+func (sa *slackAdapter) reserve(ctx context.Context, user users.ChatUser, subjectIds []int, from time.Time, to time.Time) (Message, error) {
+	var subjectNames []string
+	var values []string
+	for _, id := range subjectIds {
+		subject, err := sa.subjectService.Get(ctx, id)
+		if err != nil {
+			return Message{}, err
+		}
+		subjectNames = append(subjectNames, subject.Name)
+		values = append(values, strconv.Itoa(id))
+	}
+
+	cmd := application.CreateReservations{UserId: user.Id, SubjectIds: subjectIds, From: from, To: to}
+	rs, err := sa.reservationsService.CreateBatch(ctx, cmd)
+	if err != nil {
+		if batchErr, ok := err.(application.BatchReservationError); ok {
+			return reply(sa.conflictsMessage(ctx, batchErr, len(subjectIds) > 1)), nil
+		}
+		return Message{}, err
+	}
+
+	text := fmt.Sprintf("Reservation for %s acquired by %s until %s", strings.Join(subjectNames, ", "), user.Name, rs[0].End.Format(time.DateTime))
+	message := reply(text)
+	message.Blocks = []Block{
+		section(text),
+		{Type: "actions", Elements: []Element{button("Release", ActionRelease, strings.Join(values, ","))}},
+	}
+
+	return message, nil
+}
+
+func (sa *slackAdapter) conflictsMessage(ctx context.Context, batchErr application.BatchReservationError, withSubjects bool) string {
+	var lines []string
+	for _, conflict := range batchErr.Conflicts {
+		r, _ := sa.reservationsService.Get(ctx, conflict.ReservationIds[0])
+		u, _ := sa.userService.Get(ctx, r.UserId)
+		line := fmt.Sprintf("Already reserved by %s until %s", u.Name, r.End.Format(time.DateTime))
+		if withSubjects {
+			subject, _ := sa.subjectService.Get(ctx, conflict.SubjectId)
+			line = subject.Name + ": " + line
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (sa *slackAdapter) RemoveReservationHandler(ctx context.Context, cmd SlashCommand) (Message, error) {
+	input, err := ParseRemoveReservation(cmd)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := sa.workspace(ctx, cmd.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	subject, err := sa.subjectService.GetByName(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return Message{}, err
+	}
+	user, err := sa.slackUserService.Get(ctx, cmd.UserId)
+	if err != nil {
+		return Message{}, err
+	}
+
+	err = sa.reservationsService.Remove(ctx, application.RemoveReservations{UserId: user.Id, SubjectId: subject.Id})
+	if err != nil {
+		return Message{}, err
+	}
+
+	return reply(fmt.Sprintf("Reservation for %s removed", subject.Name)), nil
+}
+
+// ReleaseActionHandler removes the reservations of the clicking user for the subjects of the clicked Release button
+func (sa *slackAdapter) ReleaseActionHandler(ctx context.Context, interaction Interaction, action Action) (Message, error) {
+	user, err := sa.slackUserService.Get(ctx, interaction.User.Id)
+	if err != nil {
+		return ephemeral("You have no active reservations"), nil
+	}
+
+	var released []string
+	for _, value := range strings.Split(action.Value, ",") {
+		subjectId, err := strconv.Atoi(value)
+		if err != nil {
+			return Message{}, fmt.Errorf("Invalid subject id %q", value)
+		}
+		subject, err := sa.subjectService.Get(ctx, subjectId)
+		if err != nil {
+			return Message{}, err
+		}
+		if err = sa.reservationsService.Remove(ctx, application.RemoveReservations{UserId: user.Id, SubjectId: subjectId}); err != nil {
+			continue
+		}
+		released = append(released, subject.Name)
+	}
+
+	if len(released) == 0 {
+		return ephemeral("You have no active reservations for these subjects"), nil
+	}
+
+	return reply(fmt.Sprintf("Reservation for %s removed", strings.Join(released, ", "))), nil
+}
+
+func (sa *slackAdapter) ActiveReservationsHandler(ctx context.Context, cmd SlashCommand) (Message, error) {
+	input, err := ParseActiveReservations(cmd)
+	if err != nil {
+		return ephemeral(err.Error()), nil
+	}
+	workspaceId, err := sa.workspace(ctx, cmd.ChannelId)
+	if err != nil {
+		return Message{}, err
+	}
+	list, err := sa.reservationsService.ActiveReservations(ctx, workspaceId, sa.clock.Current(), input.Tags...)
+	if err != nil {
+		return Message{}, err
+	}
+
+	text := "Subject\tReserved Until\t\tUser\n"
+	for _, reservation := range list {
+		text += fmt.Sprintf("%s\t%s\t\t%s\n", reservation.Subject, reservation.End.Format(time.DateTime), reservation.User)
+	}
+
+	return reply(text), nil
+}
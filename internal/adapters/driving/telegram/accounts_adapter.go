@@ -0,0 +1,83 @@
+package telegram
+
+import (
+	"context"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// accountsAdapter links telegram users to email accounts registered on the web and merges users
+type accountsAdapter struct {
+	accountService *application.AccountService
+	telegramUserService *TelegramUserService
+}
+
+func NewAccountsAdapter(accountService *application.AccountService, telegramUserService *TelegramUserService) *accountsAdapter {
+	return &accountsAdapter{accountService: accountService, telegramUserService: telegramUserService}
+}
+
+// LinkAccountHandler replies with a one-time code to register with, only in private chats so the code is not shared
+func (aa *accountsAdapter) LinkAccountHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	if update.Message.Chat.Type != models.ChatTypePrivate {
+		return i18n.FromContext(ctx).Sprintf("error.private_only", "/link_account"), nil
+	}
+
+	user, err := aa.telegramUserService.GetOrCreate(ctx, CreateUser{update.Message.From.ID, senderName(update.Message.From)})
+	if err != nil {
+		return "", err
+	}
+	if user.Email != "" {
+		return i18n.FromContext(ctx).Sprintf("account.registered", user.Email), nil
+	}
+
+	code, err := aa.accountService.CreateLinkCode(ctx, user.Id)
+	if err != nil {
+		return "", err
+	}
+
+	minutes := int(application.LinkCodeTTL.Minutes())
+	return i18n.FromContext(ctx).Plural("account.link_code", minutes, code, minutes), nil
+}
+
+// MergeCodeHandler replies with a one-time code which moves the reservations of the sender to the account redeeming it
+func (aa *accountsAdapter) MergeCodeHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	if update.Message.Chat.Type != models.ChatTypePrivate {
+		return i18n.FromContext(ctx).Sprintf("error.private_only", "/merge_code"), nil
+	}
+
+	user, err := aa.telegramUserService.GetOrCreate(ctx, CreateUser{update.Message.From.ID, senderName(update.Message.From)})
+	if err != nil {
+		return "", err
+	}
+	code, err := aa.accountService.CreateMergeCode(ctx, user.Id)
+	if err != nil {
+		return "", err
+	}
+
+	minutes := int(application.LinkCodeTTL.Minutes())
+	return i18n.FromContext(ctx).Plural("account.merge_code", minutes, code, code, minutes), nil
+}
+
+func (aa *accountsAdapter) MergeHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	if update.Message.Chat.Type != models.ChatTypePrivate {
+		return i18n.FromContext(ctx).Sprintf("error.private_only", "/merge"), nil
+	}
+	input, err := ParseMergeUser(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	user, err := aa.telegramUserService.GetOrCreate(ctx, CreateUser{update.Message.From.ID, senderName(update.Message.From)})
+	if err != nil {
+		return "", err
+	}
+	merged, err := aa.accountService.Merge(ctx, input.Code, user.Id)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf("account.merged", merged.Name), nil
+}
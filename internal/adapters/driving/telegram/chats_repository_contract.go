@@ -0,0 +1,51 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+type ChatsRepositoryContract struct {
+	NewStore func() ChatsRepository
+}
+
+func (c ChatsRepositoryContract) Test(t *testing.T) {
+	ctx := context.Background()
+	store := c.NewStore()
+
+	t.Run("it returns ErrChatNotFound given the chat has no settings", func(t *testing.T) {
+		_, err := store.Get(ctx, -100)
+		assert.IsError(t, err, ErrChatNotFound)
+	})
+
+	t.Run("it saves and replaces the settings of chats", func(t *testing.T) {
+		group := Chat{Id: -100, Language: "ru"}
+		private := Chat{Id: 42, Language: "en"}
+		assert.NoError(t, store.Save(ctx, group))
+		assert.NoError(t, store.Save(ctx, private))
+
+		found, err := store.Get(ctx, group.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, group, found)
+
+		group.Language = ""
+		assert.NoError(t, store.Save(ctx, group))
+		found, err = store.Get(ctx, group.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, group, found)
+		found, err = store.Get(ctx, private.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, private, found)
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.IsError(t, store.Save(cancelled, Chat{Id: -200, Language: "ru"}), context.Canceled)
+		_, err := store.Get(cancelled, -100)
+		assert.IsError(t, err, context.Canceled)
+	})
+}
@@ -1,10 +1,13 @@
 package telegram
 
 import (
-	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/timeparse"
 	"github.com/go-telegram/bot/models"
 )
 
@@ -21,9 +24,38 @@ type ListTags struct {
 	SubjectName string
 }
 
-type CreateReservation struct {
+type AddAlias struct {
+	SubjectName string
+	Alias string
+}
+
+type ListAliases struct {
+	SubjectName string
+}
+
+type AddComponent struct {
+	ParentName string
+	ComponentName string
+}
+
+type ListComponents struct {
+	SubjectName string
+}
+
+type SubjectCalendar struct {
 	SubjectName string
-	Duration int
+}
+
+type CreateReservation struct {
+	SubjectNames []string
+	From time.Time
+	To time.Time
+}
+
+// ReserveAny reserves a free subject of the topic the command was sent to
+type ReserveAny struct {
+	From time.Time
+	To time.Time
 }
 
 type RemoveReservation struct {
@@ -34,10 +66,70 @@ type ActiveReservations struct {
 	Tags []string
 }
 
+type AddWebhook struct {
+	Url string
+	Events []string
+}
+
+type RemoveWebhook struct {
+	Id int
+}
+
+type CreateServiceAccount struct {
+	Name string
+	Scopes []string
+	// Days is zero for tokens which do not expire
+	Days int
+	// Workspace is empty for the default workspace
+	Workspace string
+}
+
+type RevokeToken struct {
+	Id int
+}
+
+type MergeUser struct {
+	Code string
+}
+
+type CreateWorkspace struct {
+	Name string
+}
+
+type BindWorkspace struct {
+	Name string
+}
+
+type WorkspaceAdmin struct {
+	TelegramId int64
+}
+
+type ShareSubject struct {
+	SubjectName string
+	Workspace string
+}
+
+// SetTimeZone shows the time zone of the sender when Show is set, an empty Zone resets it to the default one
+type SetTimeZone struct {
+	Zone string
+	Show bool
+}
+
+// SetLanguage shows the language of the replies when Show is set, an empty Language resets the choice
+type SetLanguage struct {
+	Language string
+	Show bool
+}
+
+type BindTopic struct {
+	Tag string
+	SubjectNames []string
+}
+
 func ParseAddSubject(update *models.Update) (AddSubject, error) {
 	parts := strings.SplitN(update.Message.Text, " ", 2)
 	if len(parts) < 2 {
-		return AddSubject{}, fmt.Errorf("Invalid format for add subject command. Expected: /add_subject <name>")
+		return AddSubject{}, usageError("add_subject")
 	}
 	name := parts[1]
 
@@ -47,7 +139,7 @@ func ParseAddSubject(update *models.Update) (AddSubject, error) {
 func ParseAddTags(update *models.Update) (AddTags, error) {
 	args := strings.SplitN(update.Message.Text, " ", 3)
 	if len(args) < 3 {
-		return AddTags{}, fmt.Errorf("Invalid format for add tags command. Expected: /add_tags <subject_name> <tag1> [tag2] [tag3]...")
+		return AddTags{}, usageError("add_tags")
 	}
 	tags := strings.Split(args[2], " ")
 
@@ -60,16 +152,72 @@ func ParseAddTags(update *models.Update) (AddTags, error) {
 func ParseListTags(update *models.Update) (ListTags, error) {
 	parts := strings.SplitN(update.Message.Text, " ", 2)
 	if len(parts) < 2 {
-		return ListTags{}, fmt.Errorf("Invalid format for list tags command. Expected: /tags <subject_name>")
+		return ListTags{}, usageError("tags")
 	}
 	name := parts[1]
 
 	return ListTags{SubjectName: name}, nil
 }
 
-func ParseCreateReservation(update *models.Update) (CreateReservation, error) {
+func ParseAddAlias(update *models.Update) (AddAlias, error) {
+	args := strings.SplitN(update.Message.Text, " ", 3)
+	if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+		return AddAlias{}, usageError("add_alias")
+	}
+
+	return AddAlias{SubjectName: args[1], Alias: strings.TrimSpace(args[2])}, nil
+}
+
+func ParseListAliases(update *models.Update) (ListAliases, error) {
+	parts := strings.SplitN(update.Message.Text, " ", 2)
+	if len(parts) < 2 {
+		return ListAliases{}, usageError("aliases")
+	}
+
+	return ListAliases{SubjectName: parts[1]}, nil
+}
+
+func ParseAddComponent(update *models.Update) (AddComponent, error) {
+	parts := strings.Split(update.Message.Text, " ")
+	if len(parts) != 3 {
+		return AddComponent{}, usageError("add_component")
+	}
+
+	return AddComponent{ParentName: parts[1], ComponentName: parts[2]}, nil
+}
+
+func ParseListComponents(update *models.Update) (ListComponents, error) {
+	parts := strings.SplitN(update.Message.Text, " ", 2)
+	if len(parts) < 2 {
+		return ListComponents{}, usageError("components")
+	}
+
+	return ListComponents{SubjectName: parts[1]}, nil
+}
+
+func ParseSubjectCalendar(update *models.Update) (SubjectCalendar, error) {
+	parts := strings.SplitN(update.Message.Text, " ", 2)
+	if len(parts) < 2 {
+		return SubjectCalendar{}, usageError("calendar")
+	}
+
+	return SubjectCalendar{SubjectName: parts[1]}, nil
+}
+
+// IsCalendarUpload matches messages carrying an .ics document
+func IsCalendarUpload(update *models.Update) bool {
+	if update.Message == nil || update.Message.Document == nil {
+		return false
+	}
+	document := update.Message.Document
+
+	return strings.HasSuffix(strings.ToLower(document.FileName), ".ics") || document.MimeType == "text/calendar"
+}
+
+// ParseCreateReservation reads the period of the reservation relative to now, see timeparse.Formats
+func ParseCreateReservation(update *models.Update, now time.Time) (CreateReservation, error) {
 	error := func () (CreateReservation, error) {
-		return CreateReservation{}, fmt.Errorf("Invalid format for reserve command. Expected: /reserve <subject_name> <duration_in_minutes>")
+		return CreateReservation{}, usageError("reserve", timeparse.Formats)
 	}
 
 	parts := strings.SplitN(update.Message.Text, " ", 3)
@@ -77,22 +225,39 @@ func ParseCreateReservation(update *models.Update) (CreateReservation, error) {
 	if len(parts) < 3 {
 		return error()
 	}
-	subjectName := parts[1]
-	minutes, err := strconv.Atoi(parts[2])
+	subjectNames := strings.Split(parts[1], ",")
+	if slices.Contains(subjectNames, "") {
+		return error()
+	}
+	period, err := timeparse.Parse(parts[2], now)
 
 	if err != nil {
-		return error()
+		return CreateReservation{}, err
 	}
 	return CreateReservation{
-		SubjectName: subjectName,
-		Duration: minutes,
+		SubjectNames: subjectNames,
+		From: period.From,
+		To: period.To,
 	}, nil
 }
 
+func ParseReserveAny(update *models.Update, now time.Time) (ReserveAny, error) {
+	parts := strings.SplitN(update.Message.Text, " ", 2)
+	if len(parts) != 2 {
+		return ReserveAny{}, usageError("reserve_any", timeparse.Formats)
+	}
+	period, err := timeparse.Parse(parts[1], now)
+	if err != nil {
+		return ReserveAny{}, err
+	}
+
+	return ReserveAny{From: period.From, To: period.To}, nil
+}
+
 func ParseRemoveReservation(update *models.Update) (RemoveReservation, error) {
 	parts := strings.SplitN(update.Message.Text, " ", 2)
 	if len(parts) < 2 {
-		return RemoveReservation{}, fmt.Errorf("Invalid format for remove reservation command. Expected: /remove <subject_name>")
+		return RemoveReservation{}, usageError("remove")
 	}
 	name := parts[1]
 
@@ -109,3 +274,226 @@ func ParseActiveReservations(update *models.Update) (ActiveReservations, error)
 		Tags: tags,
 	}, nil
 }
+
+func ParseAddWebhook(update *models.Update) (AddWebhook, error) {
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) < 2 || len(parts) > 3 {
+		return AddWebhook{}, usageError("webhook_add")
+	}
+
+	var events []string
+	if len(parts) == 3 {
+		events = strings.Split(parts[2], ",")
+	}
+
+	return AddWebhook{Url: parts[1], Events: events}, nil
+}
+
+func ParseRemoveWebhook(update *models.Update) (RemoveWebhook, error) {
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return RemoveWebhook{}, usageError("webhook_remove")
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return RemoveWebhook{}, usageError("webhook_remove")
+	}
+
+	return RemoveWebhook{Id: id}, nil
+}
+
+func ParseCreateServiceAccount(update *models.Update) (CreateServiceAccount, error) {
+	usage := usageError("service_account")
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) < 3 || len(parts) > 5 {
+		return CreateServiceAccount{}, usage
+	}
+
+	cmd := CreateServiceAccount{Name: parts[1], Scopes: strings.Split(parts[2], ",")}
+	rest := parts[3:]
+	if len(rest) > 0 {
+		days, err := strconv.Atoi(rest[0])
+		if err == nil && days <= 0 {
+			return CreateServiceAccount{}, usage
+		}
+		if err == nil {
+			cmd.Days = days
+			rest = rest[1:]
+		}
+	}
+	if len(rest) > 1 {
+		return CreateServiceAccount{}, usage
+	}
+	if len(rest) == 1 {
+		cmd.Workspace = rest[0]
+	}
+
+	return cmd, nil
+}
+
+func ParseRevokeToken(update *models.Update) (RevokeToken, error) {
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return RevokeToken{}, usageError("token_revoke")
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return RevokeToken{}, usageError("token_revoke")
+	}
+
+	return RevokeToken{Id: id}, nil
+}
+
+func ParseMergeUser(update *models.Update) (MergeUser, error) {
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return MergeUser{}, usageError("merge")
+	}
+
+	return MergeUser{Code: parts[1]}, nil
+}
+
+func ParseCreateWorkspace(update *models.Update) (CreateWorkspace, error) {
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return CreateWorkspace{}, usageError("workspace_create")
+	}
+
+	return CreateWorkspace{Name: parts[1]}, nil
+}
+
+func ParseBindWorkspace(update *models.Update) (BindWorkspace, error) {
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return BindWorkspace{}, usageError("workspace_bind")
+	}
+
+	return BindWorkspace{Name: parts[1]}, nil
+}
+
+func ParseAddWorkspaceAdmin(update *models.Update) (WorkspaceAdmin, error) {
+	return parseWorkspaceAdmin(update, "workspace_admin")
+}
+
+func ParseRemoveWorkspaceAdmin(update *models.Update) (WorkspaceAdmin, error) {
+	return parseWorkspaceAdmin(update, "workspace_admin_remove")
+}
+
+func parseWorkspaceAdmin(update *models.Update, command string) (WorkspaceAdmin, error) {
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return WorkspaceAdmin{}, usageError(command)
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return WorkspaceAdmin{}, usageError(command)
+	}
+
+	return WorkspaceAdmin{TelegramId: id}, nil
+}
+
+func ParseShareSubject(update *models.Update) (ShareSubject, error) {
+	return parseSubjectWorkspace(update, "share")
+}
+
+func ParseUnshareSubject(update *models.Update) (ShareSubject, error) {
+	return parseSubjectWorkspace(update, "unshare")
+}
+
+// parseSubjectWorkspace takes the workspace from the last word, subject names may contain spaces
+func parseSubjectWorkspace(update *models.Update, command string) (ShareSubject, error) {
+	usage := usageError(command)
+	parts := strings.SplitN(update.Message.Text, " ", 2)
+	if len(parts) < 2 {
+		return ShareSubject{}, usage
+	}
+	args := strings.TrimSpace(parts[1])
+	index := strings.LastIndex(args, " ")
+	if index == -1 {
+		return ShareSubject{}, usage
+	}
+
+	return ShareSubject{SubjectName: strings.TrimSpace(args[:index]), Workspace: args[index + 1:]}, nil
+}
+
+func ParseBindTopicTag(update *models.Update) (BindTopic, error) {
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) != 2 {
+		return BindTopic{}, usageError("topic_tag")
+	}
+
+	return BindTopic{Tag: parts[1]}, nil
+}
+
+func ParseBindTopicSubjects(update *models.Update) (BindTopic, error) {
+	usage := usageError("topic_subjects")
+	parts := strings.SplitN(update.Message.Text, " ", 2)
+	if len(parts) < 2 {
+		return BindTopic{}, usage
+	}
+	subjectNames := strings.Split(parts[1], ",")
+	for i, name := range subjectNames {
+		subjectNames[i] = strings.TrimSpace(name)
+	}
+	if slices.Contains(subjectNames, "") {
+		return BindTopic{}, usage
+	}
+
+	return BindTopic{SubjectNames: subjectNames}, nil
+}
+
+func ParseSetTimeZone(update *models.Update) (SetTimeZone, error) {
+	parts := strings.Fields(update.Message.Text)
+	switch {
+	case len(parts) == 1:
+		return SetTimeZone{Show: true}, nil
+	case len(parts) > 2:
+		return SetTimeZone{}, usageError("timezone")
+	case strings.EqualFold(parts[1], "default"):
+		return SetTimeZone{}, nil
+	}
+
+	return SetTimeZone{Zone: parts[1]}, nil
+}
+
+func ParseSetLanguage(update *models.Update) (SetLanguage, error) {
+	parts := strings.Fields(update.Message.Text)
+	if len(parts) == 1 {
+		return SetLanguage{Show: true}, nil
+	}
+	language, err := parseLanguage(parts, usageError("language", languagesList()))
+
+	return SetLanguage{Language: language}, err
+}
+
+func ParseSetChatLanguage(update *models.Update) (SetLanguage, error) {
+	language, err := parseLanguage(strings.Fields(update.Message.Text), usageError("chat_language", languagesList()))
+
+	return SetLanguage{Language: language}, err
+}
+
+// parseLanguage reads the language from the only argument, default resets it
+func parseLanguage(parts []string, usage error) (string, error) {
+	if len(parts) != 2 {
+		return "", usage
+	}
+	if strings.EqualFold(parts[1], "default") {
+		return "", nil
+	}
+	language, ok := i18n.Match(parts[1])
+	if !ok {
+		return "", usage
+	}
+
+	return language, nil
+}
+
+// usageError is returned by the parsers for input not matching the syntax of the command
+func usageError(command string, args ...any) error {
+	return i18n.Errorf("usage." + command, append([]any{syntax(command)}, args...)...)
+}
+
+// syntax is the usage line of the command, shown in the usage errors and in /help
+func syntax(command string) i18n.Key {
+	return i18n.Key("syntax." + command)
+}
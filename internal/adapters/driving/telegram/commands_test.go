@@ -2,6 +2,7 @@ package telegram_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
 	"github.com/alecthomas/assert/v2"
@@ -9,6 +10,8 @@ import (
 )
 
 func TestInputParsers(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 30, 0, 0, time.UTC)
+
 	t.Run("it parses AddSubject command", func(t *testing.T) {
 		update := telegramUpdate("/add_subject Test")
 		cmd, err := telegram.ParseAddSubject(update)
@@ -55,25 +58,112 @@ func TestInputParsers(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("it parses alias commands", func(t *testing.T) {
+		cmd, err := telegram.ParseAddAlias(telegramUpdate("/add_alias Subject#1 first bench"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.AddAlias{SubjectName: "Subject#1", Alias: "first bench"}, cmd)
+		_, err = telegram.ParseAddAlias(telegramUpdate("/add_alias Subject#1"))
+		assert.Error(t, err)
+		_, err = telegram.ParseAddAlias(telegramUpdate("/add_alias Subject#1 "))
+		assert.Error(t, err)
+
+		list, err := telegram.ParseListAliases(telegramUpdate("/aliases Subject#1"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Subject#1", list.SubjectName)
+		_, err = telegram.ParseListAliases(telegramUpdate("/aliases"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses AddComponent command", func(t *testing.T) {
+		update := telegramUpdate("/add_component Bench Phone")
+		cmd, err := telegram.ParseAddComponent(update)
+		assert.NoError(t, err)
+		assert.Equal(t, cmd.ParentName, "Bench")
+		assert.Equal(t, cmd.ComponentName, "Phone")
+	})
+
+	t.Run("it returns error given wrong arguments provided to AddComponent", func(t *testing.T) {
+		update := telegramUpdate("/add_component Bench")
+		_, err := telegram.ParseAddComponent(update)
+		assert.Error(t, err)
+
+		update = telegramUpdate("/add_component Bench Phone Hub")
+		_, err = telegram.ParseAddComponent(update)
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses ListComponents command", func(t *testing.T) {
+		update := telegramUpdate("/components Bench")
+		cmd, err := telegram.ParseListComponents(update)
+		assert.NoError(t, err)
+		assert.Equal(t, cmd.SubjectName, "Bench")
+	})
+
+	t.Run("it parses SubjectCalendar command", func(t *testing.T) {
+		update := telegramUpdate("/calendar Bench #1")
+		cmd, err := telegram.ParseSubjectCalendar(update)
+		assert.NoError(t, err)
+		assert.Equal(t, cmd.SubjectName, "Bench #1")
+
+		_, err = telegram.ParseSubjectCalendar(telegramUpdate("/calendar"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it matches calendar uploads", func(t *testing.T) {
+		update := telegramUpdate("")
+		assert.False(t, telegram.IsCalendarUpload(update))
+
+		update.Message.Document = &models.Document{FileName: "week.ICS"}
+		assert.True(t, telegram.IsCalendarUpload(update))
+
+		update.Message.Document = &models.Document{FileName: "week", MimeType: "text/calendar"}
+		assert.True(t, telegram.IsCalendarUpload(update))
+
+		update.Message.Document = &models.Document{FileName: "week.pdf", MimeType: "application/pdf"}
+		assert.False(t, telegram.IsCalendarUpload(update))
+	})
+
 	t.Run("it parses CreateReservation command", func(t *testing.T) {
 		update := telegramUpdate("/reserve Test 10")
-		cmd, err := telegram.ParseCreateReservation(update)
+		cmd, err := telegram.ParseCreateReservation(update, now)
 		assert.NoError(t, err)
-		assert.Equal(t, cmd.SubjectName, "Test")
-		assert.Equal(t, cmd.Duration, 10)
+		assert.Equal(t, cmd.SubjectNames, []string{"Test"})
+		assert.Equal(t, cmd.From, now)
+		assert.Equal(t, cmd.To, now.Add(10 * time.Minute))
+	})
+
+	t.Run("it parses CreateReservation command for several subjects", func(t *testing.T) {
+		update := telegramUpdate("/reserve Subject#1,Subject#2 30")
+		cmd, err := telegram.ParseCreateReservation(update, now)
+		assert.NoError(t, err)
+		assert.Equal(t, cmd.SubjectNames, []string{"Subject#1", "Subject#2"})
+		assert.Equal(t, cmd.To, now.Add(30 * time.Minute))
+	})
+
+	t.Run("it parses CreateReservation command with a written period", func(t *testing.T) {
+		update := telegramUpdate("/reserve Bench tomorrow 9-11")
+		cmd, err := telegram.ParseCreateReservation(update, now)
+		assert.NoError(t, err)
+		assert.Equal(t, cmd.SubjectNames, []string{"Bench"})
+		assert.Equal(t, cmd.From, time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC))
+		assert.Equal(t, cmd.To, time.Date(2026, 3, 5, 11, 0, 0, 0, time.UTC))
 	})
 	
 	t.Run("it returns error given wrong arguments provided to CreateReservation", func(t *testing.T) {
 		update := telegramUpdate("/reserve")
-		_, err := telegram.ParseCreateReservation(update)
+		_, err := telegram.ParseCreateReservation(update, now)
 		assert.Error(t, err)
 
 		update = telegramUpdate("/reserve Test")
-		_, err = telegram.ParseCreateReservation(update)
+		_, err = telegram.ParseCreateReservation(update, now)
 		assert.Error(t, err)
 
 		update = telegramUpdate("/reserve Test invalid_duration")
-		_, err = telegram.ParseCreateReservation(update)
+		_, err = telegram.ParseCreateReservation(update, now)
+		assert.Error(t, err)
+
+		update = telegramUpdate("/reserve Subject#1, 30")
+		_, err = telegram.ParseCreateReservation(update, now)
 		assert.Error(t, err)
 	})
 
@@ -90,6 +180,143 @@ func TestInputParsers(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("it parses AddWebhook command", func(t *testing.T) {
+		cmd, err := telegram.ParseAddWebhook(telegramUpdate("/webhook_add https://ci.example.com/hook reservation.*,subject.created"))
+		assert.NoError(t, err)
+		assert.Equal(t, "https://ci.example.com/hook", cmd.Url)
+		assert.Equal(t, []string{"reservation.*", "subject.created"}, cmd.Events)
+
+		cmd, err = telegram.ParseAddWebhook(telegramUpdate("/webhook_add https://ci.example.com/hook"))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(cmd.Events))
+
+		_, err = telegram.ParseAddWebhook(telegramUpdate("/webhook_add"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses RemoveWebhook command", func(t *testing.T) {
+		cmd, err := telegram.ParseRemoveWebhook(telegramUpdate("/webhook_remove 3"))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, cmd.Id)
+
+		_, err = telegram.ParseRemoveWebhook(telegramUpdate("/webhook_remove jenkins"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses CreateServiceAccount command", func(t *testing.T) {
+		cmd, err := telegram.ParseCreateServiceAccount(telegramUpdate("/service_account ci reservations:write,subjects:admin 30"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.CreateServiceAccount{Name: "ci", Scopes: []string{"reservations:write", "subjects:admin"}, Days: 30}, cmd)
+
+		cmd, err = telegram.ParseCreateServiceAccount(telegramUpdate("/service_account ci reservations:read"))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, cmd.Days)
+
+		_, err = telegram.ParseCreateServiceAccount(telegramUpdate("/service_account ci"))
+		assert.Error(t, err)
+		cmd, err = telegram.ParseCreateServiceAccount(telegramUpdate("/service_account ci reservations:read lab"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.CreateServiceAccount{Name: "ci", Scopes: []string{"reservations:read"}, Workspace: "lab"}, cmd)
+		cmd, err = telegram.ParseCreateServiceAccount(telegramUpdate("/service_account ci reservations:read 30 lab"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.CreateServiceAccount{Name: "ci", Scopes: []string{"reservations:read"}, Days: 30, Workspace: "lab"}, cmd)
+
+		_, err = telegram.ParseCreateServiceAccount(telegramUpdate("/service_account ci reservations:read 0"))
+		assert.Error(t, err)
+		_, err = telegram.ParseCreateServiceAccount(telegramUpdate("/service_account ci reservations:read lab 30"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses RevokeToken command", func(t *testing.T) {
+		cmd, err := telegram.ParseRevokeToken(telegramUpdate("/token_revoke 3"))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, cmd.Id)
+
+		_, err = telegram.ParseRevokeToken(telegramUpdate("/token_revoke ci"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses MergeUser command", func(t *testing.T) {
+		cmd, err := telegram.ParseMergeUser(telegramUpdate("/merge 0a1b2c"))
+		assert.NoError(t, err)
+		assert.Equal(t, "0a1b2c", cmd.Code)
+
+		_, err = telegram.ParseMergeUser(telegramUpdate("/merge"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses workspace commands", func(t *testing.T) {
+		created, err := telegram.ParseCreateWorkspace(telegramUpdate("/workspace_create hardware"))
+		assert.NoError(t, err)
+		assert.Equal(t, "hardware", created.Name)
+		_, err = telegram.ParseCreateWorkspace(telegramUpdate("/workspace_create hardware team"))
+		assert.Error(t, err)
+
+		bound, err := telegram.ParseBindWorkspace(telegramUpdate("/workspace_bind qa"))
+		assert.NoError(t, err)
+		assert.Equal(t, "qa", bound.Name)
+		_, err = telegram.ParseBindWorkspace(telegramUpdate("/workspace_bind"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses WorkspaceAdmin commands", func(t *testing.T) {
+		cmd, err := telegram.ParseAddWorkspaceAdmin(telegramUpdate("/workspace_admin 12345"))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12345), cmd.TelegramId)
+		cmd, err = telegram.ParseRemoveWorkspaceAdmin(telegramUpdate("/workspace_admin_remove 12345"))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12345), cmd.TelegramId)
+
+		_, err = telegram.ParseAddWorkspaceAdmin(telegramUpdate("/workspace_admin @alice"))
+		assert.Error(t, err)
+		_, err = telegram.ParseRemoveWorkspaceAdmin(telegramUpdate("/workspace_admin_remove"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses ShareSubject command", func(t *testing.T) {
+		cmd, err := telegram.ParseShareSubject(telegramUpdate("/share Bench #1 hardware"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.ShareSubject{SubjectName: "Bench #1", Workspace: "hardware"}, cmd)
+
+		cmd, err = telegram.ParseUnshareSubject(telegramUpdate("/unshare Phone qa"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.ShareSubject{SubjectName: "Phone", Workspace: "qa"}, cmd)
+
+		_, err = telegram.ParseShareSubject(telegramUpdate("/share Phone"))
+		assert.Error(t, err)
+		_, err = telegram.ParseShareSubject(telegramUpdate("/share"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses ReserveAny command", func(t *testing.T) {
+		cmd, err := telegram.ParseReserveAny(telegramUpdate("/reserve 30"), now)
+		assert.NoError(t, err)
+		assert.Equal(t, now.Add(30 * time.Minute), cmd.To)
+
+		cmd, err = telegram.ParseReserveAny(telegramUpdate("/reserve until 17:00"), now)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 4, 17, 0, 0, 0, time.UTC), cmd.To)
+
+		_, err = telegram.ParseReserveAny(telegramUpdate("/reserve Bench 30"), now)
+		assert.Error(t, err)
+		_, err = telegram.ParseReserveAny(telegramUpdate("/reserve Bench"), now)
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses topic commands", func(t *testing.T) {
+		cmd, err := telegram.ParseBindTopicTag(telegramUpdate("/topic_tag android"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.BindTopic{Tag: "android"}, cmd)
+		_, err = telegram.ParseBindTopicTag(telegramUpdate("/topic_tag"))
+		assert.Error(t, err)
+
+		cmd, err = telegram.ParseBindTopicSubjects(telegramUpdate("/topic_subjects Pixel 7, Pixel 8"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.BindTopic{SubjectNames: []string{"Pixel 7", "Pixel 8"}}, cmd)
+		_, err = telegram.ParseBindTopicSubjects(telegramUpdate("/topic_subjects Pixel 7,"))
+		assert.Error(t, err)
+	})
+
 	t.Run("it parses ActiveReservations command", func(t *testing.T) {
 		update := telegramUpdate("/reserved")
 		cmd, err := telegram.ParseActiveReservations(update)
@@ -101,6 +328,42 @@ func TestInputParsers(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, cmd.Tags, []string{"tag1", "tag2", "tag3"})
 	})
+
+	t.Run("it parses SetTimeZone command", func(t *testing.T) {
+		cmd, err := telegram.ParseSetTimeZone(telegramUpdate("/timezone Europe/Berlin"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.SetTimeZone{Zone: "Europe/Berlin"}, cmd)
+
+		cmd, err = telegram.ParseSetTimeZone(telegramUpdate("/timezone"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.SetTimeZone{Show: true}, cmd)
+
+		cmd, err = telegram.ParseSetTimeZone(telegramUpdate("/timezone Default"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.SetTimeZone{}, cmd)
+
+		_, err = telegram.ParseSetTimeZone(telegramUpdate("/timezone Europe Berlin"))
+		assert.Error(t, err)
+	})
+
+	t.Run("it parses language commands", func(t *testing.T) {
+		cmd, err := telegram.ParseSetLanguage(telegramUpdate("/language RU"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.SetLanguage{Language: "ru"}, cmd)
+
+		cmd, err = telegram.ParseSetLanguage(telegramUpdate("/language"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.SetLanguage{Show: true}, cmd)
+
+		cmd, err = telegram.ParseSetChatLanguage(telegramUpdate("/chat_language default"))
+		assert.NoError(t, err)
+		assert.Equal(t, telegram.SetLanguage{}, cmd)
+
+		_, err = telegram.ParseSetLanguage(telegramUpdate("/language klingon"))
+		assert.Error(t, err)
+		_, err = telegram.ParseSetChatLanguage(telegramUpdate("/chat_language"))
+		assert.Error(t, err)
+	})
 }
 
 func telegramUpdate(text string) *models.Update {
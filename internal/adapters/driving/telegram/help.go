@@ -0,0 +1,153 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// Access is who may run a command, each level includes the ones before it
+type Access int
+
+const (
+	AccessEveryone Access = iota
+	AccessChatAdmins
+	// AccessWorkspaceAdmins are the admins of the workspace the chat is bound to
+	AccessWorkspaceAdmins
+	// AccessAdmins are the admins of the bot, they manage every workspace
+	AccessAdmins
+)
+
+// AccessOf tells the access level of the sender of the update
+type AccessOf func(ctx context.Context, b *bot.Bot, update *models.Update) (Access, error)
+
+// Command is an entry of the registry. The catalogs keep its description as command.<name>
+// and its usage as syntax.<name>, the same one its parser puts in the usage errors
+type Command struct {
+	Name string
+	Match bot.MatchType
+	Access Access
+	Handler UpdateHandler
+}
+
+// Registry keeps the commands of the bot in the order their handlers are matched,
+// it drives the handlers, /help and the command menus of telegram clients
+type Registry struct {
+	commands []Command
+	accessOf AccessOf
+}
+
+func NewRegistry(accessOf AccessOf) *Registry {
+	return &Registry{accessOf: accessOf}
+}
+
+func (r *Registry) Add(commands ...Command) {
+	r.commands = append(r.commands, commands...)
+}
+
+func (r *Registry) Commands() []Command {
+	return r.commands
+}
+
+// Restrict answers the senders below the access level of the command instead of running it
+func (r *Registry) Restrict(command Command) UpdateHandler {
+	if command.Access == AccessEveryone {
+		return command.Handler
+	}
+
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+		access, err := r.accessOf(ctx, b, update)
+		if err != nil {
+			return "", err
+		}
+		if access < command.Access {
+			switch command.Access {
+			case AccessAdmins:
+				return i18n.FromContext(ctx).Sprintf("error.admins_only"), nil
+			case AccessWorkspaceAdmins:
+				return i18n.FromContext(ctx).Sprintf("error.workspace_admins_only"), nil
+			}
+			return i18n.FromContext(ctx).Sprintf("error.chat_admins_only"), nil
+		}
+
+		return command.Handler(ctx, b, update)
+	}
+}
+
+func (r *Registry) HelpHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	access, err := r.accessOf(ctx, b, update)
+	if err != nil {
+		return "", err
+	}
+
+	return r.Help(i18n.FromContext(ctx), access), nil
+}
+
+func (r *Registry) StartHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	access, err := r.accessOf(ctx, b, update)
+	if err != nil {
+		return "", err
+	}
+	p := i18n.FromContext(ctx)
+	name := ""
+	if update.Message.From != nil {
+		name = senderName(update.Message.From)
+	}
+
+	return p.Sprintf("help.start", name) + "\n\n" + r.Help(p, access), nil
+}
+
+// Help lists the commands available at the access level with their usage
+func (r *Registry) Help(p i18n.Printer, access Access) string {
+	lines := []string{p.Sprintf("help.header")}
+	for _, command := range r.commands {
+		if command.Access <= access {
+			lines = append(lines, p.Sprintf("help.line", syntax(command.Name), description(command.Name)))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Menus are the command lists suggested by telegram clients, one per scope and language.
+// Chat administrators get their commands in every group, admins of the bot get all of them in their private chat
+func (r *Registry) Menus(admins []int64) []bot.SetMyCommandsParams {
+	var menus []bot.SetMyCommandsParams
+	for _, code := range i18n.Languages() {
+		p := i18n.For(code)
+		// the lists without a language serve every language without its own
+		languageCode := code
+		if code == i18n.Default {
+			languageCode = ""
+		}
+
+		menus = append(
+			menus,
+			bot.SetMyCommandsParams{Commands: r.menu(p, AccessEveryone), Scope: &models.BotCommandScopeDefault{}, LanguageCode: languageCode},
+			bot.SetMyCommandsParams{Commands: r.menu(p, AccessChatAdmins), Scope: &models.BotCommandScopeAllChatAdministrators{}, LanguageCode: languageCode},
+		)
+		for _, admin := range admins {
+			menus = append(menus, bot.SetMyCommandsParams{Commands: r.menu(p, AccessAdmins), Scope: &models.BotCommandScopeChat{ChatID: admin}, LanguageCode: languageCode})
+		}
+	}
+
+	return menus
+}
+
+func (r *Registry) menu(p i18n.Printer, access Access) []models.BotCommand {
+	var commands []models.BotCommand
+	for _, command := range r.commands {
+		if command.Access <= access {
+			commands = append(commands, models.BotCommand{Command: command.Name, Description: p.Sprintf(string(description(command.Name)))})
+		}
+	}
+
+	return commands
+}
+
+func description(command string) i18n.Key {
+	return i18n.Key("command." + command)
+}
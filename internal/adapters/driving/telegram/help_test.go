@@ -0,0 +1,80 @@
+package telegram_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/alecthomas/assert/v2"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func TestRegistry(t *testing.T) {
+	access := telegram.AccessEveryone
+	registry := telegram.NewRegistry(func(ctx context.Context, b *bot.Bot, update *models.Update) (telegram.Access, error) {
+		return access, nil
+	})
+	reply := func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+		return "done", nil
+	}
+	registry.Add(
+		telegram.Command{Name: "help", Match: bot.MatchTypeExact, Handler: registry.HelpHandler},
+		telegram.Command{Name: "reserve", Match: bot.MatchTypePrefix, Handler: reply},
+		telegram.Command{Name: "topic_tag", Match: bot.MatchTypePrefix, Access: telegram.AccessChatAdmins, Handler: reply},
+		telegram.Command{Name: "tokens", Match: bot.MatchTypeExact, Access: telegram.AccessAdmins, Handler: reply},
+	)
+	t.Cleanup(func() { access = telegram.AccessEveryone })
+
+	t.Run("it lists the commands of the sender with their usage", func(t *testing.T) {
+		help, err := registry.HelpHandler(context.Background(), nil, telegramUpdate("/help"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Commands:\n/help - List the commands\n/reserve <subject_name>[,<subject_name>...] <period> - Reserve subjects", help)
+
+		access = telegram.AccessAdmins
+		help, err = registry.HelpHandler(i18n.WithPrinter(context.Background(), i18n.For("ru")), nil, telegramUpdate("/help"))
+
+		assert.NoError(t, err)
+		lines := strings.Split(help, "\n")
+		assert.Equal(t, 5, len(lines))
+		assert.Equal(t, "/topic_tag <тег> — Привязать тему к объектам с тегом", lines[3])
+		assert.Equal(t, "/tokens — Список токенов доступа", lines[4])
+	})
+
+	t.Run("it answers senders below the access level of the command", func(t *testing.T) {
+		for _, c := range []struct {
+			access telegram.Access
+			command int
+			expected string
+		}{
+			{telegram.AccessEveryone, 2, "This command is available to chat admins only"},
+			{telegram.AccessChatAdmins, 2, "done"},
+			{telegram.AccessChatAdmins, 3, "This command is available to admins only"},
+			{telegram.AccessAdmins, 3, "done"},
+		} {
+			access = c.access
+			text, err := registry.Restrict(registry.Commands()[c.command])(context.Background(), nil, telegramUpdate("/tokens"))
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, text)
+		}
+	})
+
+	t.Run("it builds the command menus of every scope and language", func(t *testing.T) {
+		menus := registry.Menus([]int64{42})
+
+		assert.Equal(t, 3 * len(i18n.Languages()), len(menus))
+		assert.Equal(t, "", menus[0].LanguageCode)
+		assert.Equal[models.BotCommandScope](t, &models.BotCommandScopeDefault{}, menus[0].Scope)
+		assert.Equal(t, []models.BotCommand{{Command: "help", Description: "List the commands"}, {Command: "reserve", Description: "Reserve subjects"}}, menus[0].Commands)
+		assert.Equal[models.BotCommandScope](t, &models.BotCommandScopeAllChatAdministrators{}, menus[1].Scope)
+		assert.Equal(t, 3, len(menus[1].Commands))
+		assert.Equal[models.BotCommandScope](t, &models.BotCommandScopeChat{ChatID: int64(42)}, menus[2].Scope)
+		assert.Equal(t, 4, len(menus[2].Commands))
+		assert.Equal(t, "ru", menus[3].LanguageCode)
+		assert.Equal(t, "Забронировать объекты", menus[3].Commands[1].Description)
+	})
+}
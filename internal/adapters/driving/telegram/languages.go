@@ -0,0 +1,124 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+var ErrChatNotFound = errors.New("Chat was not found")
+
+// Chat keeps the settings of a telegram chat, Language is empty for chats following their senders
+type Chat struct {
+	Id int64
+	Language string
+}
+
+type ChatsRepository interface {
+	// Save replaces the settings of the chat
+	Save(ctx context.Context, chat Chat) error
+	// Get returns ErrChatNotFound for chats without settings
+	Get(ctx context.Context, id int64) (Chat, error)
+}
+
+type LanguageService struct {
+	chats ChatsRepository
+	telegramUserService *TelegramUserService
+}
+
+func NewLanguageService(chats ChatsRepository, telegramUserService *TelegramUserService) *LanguageService {
+	return &LanguageService{chats: chats, telegramUserService: telegramUserService}
+}
+
+// Printer picks the language of the reply: the one of a group chat, the one chosen by the sender
+// and then the language of the sender's telegram client
+func (s *LanguageService) Printer(ctx context.Context, update *models.Update) i18n.Printer {
+	if update.Message == nil {
+		return i18n.For(i18n.Default)
+	}
+	if isGroup(update.Message.Chat) {
+		if chat, err := s.chats.Get(ctx, update.Message.Chat.ID); err == nil && chat.Language != "" {
+			return i18n.For(chat.Language)
+		}
+	}
+	if update.Message.From == nil {
+		return i18n.For(i18n.Default)
+	}
+	if user, err := s.telegramUserService.Get(ctx, update.Message.From.ID); err == nil && user.Language != "" {
+		return i18n.For(user.Language)
+	}
+
+	return i18n.For(update.Message.From.LanguageCode)
+}
+
+// ForChat returns the printer of messages nobody asked for, such as notifications
+func (s *LanguageService) ForChat(ctx context.Context, chatId int64) i18n.Printer {
+	chat, err := s.chats.Get(ctx, chatId)
+	if err != nil {
+		return i18n.For(i18n.Default)
+	}
+
+	return i18n.For(chat.Language)
+}
+
+func (s *LanguageService) SetChatLanguage(ctx context.Context, chatId int64, code string) error {
+	return s.chats.Save(ctx, Chat{Id: chatId, Language: code})
+}
+
+// languagesAdapter picks the languages of users and chats, callers restrict chat languages to chat admins
+type languagesAdapter struct {
+	languageService *LanguageService
+	telegramUserService *TelegramUserService
+	userService *application.UserService
+}
+
+func NewLanguagesAdapter(languageService *LanguageService, telegramUserService *TelegramUserService, userService *application.UserService) *languagesAdapter {
+	return &languagesAdapter{languageService: languageService, telegramUserService: telegramUserService, userService: userService}
+}
+
+func (la *languagesAdapter) LanguageHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseSetLanguage(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	if input.Show {
+		p := i18n.FromContext(ctx)
+		return p.Sprintf("language.current", i18n.Name(p.Language())), nil
+	}
+
+	user, err := la.telegramUserService.GetOrCreate(ctx, CreateUser{update.Message.From.ID, senderName(update.Message.From)})
+	if err != nil {
+		return "", err
+	}
+	if _, err = la.userService.SetLanguage(ctx, user.Id, input.Language); err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	p := la.languageService.Printer(ctx, update)
+
+	return p.Sprintf("language.set", i18n.Name(p.Language())), nil
+}
+
+func (la *languagesAdapter) ChatLanguageHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseSetChatLanguage(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	if err = la.languageService.SetChatLanguage(ctx, update.Message.Chat.ID, input.Language); err != nil {
+		return "", err
+	}
+	if input.Language == "" {
+		return la.languageService.Printer(ctx, update).Sprintf("language.chat_reset"), nil
+	}
+	p := i18n.For(input.Language)
+
+	return p.Sprintf("language.chat_set", i18n.Name(p.Language())), nil
+}
+
+func languagesList() string {
+	return strings.Join(i18n.Languages(), ", ")
+}
@@ -0,0 +1,154 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// SubjectChoiceData starts the callback data of the buttons choosing among subjects going by the same name
+const SubjectChoiceData = "subject:"
+
+// maxChoices is how many choices are kept waiting, the oldest ones expire first
+const maxChoices = 100
+
+// SubjectChooser answers the subject names that did not resolve. Ambiguous names get a button per subject,
+// the command is then run again with the name of the chosen one
+type SubjectChooser struct {
+	choices map[int]subjectChoice
+	counter int
+	mu sync.Mutex
+}
+
+// subjectChoice is a command waiting for its sender to choose among the subjects matching the name
+type subjectChoice struct {
+	message models.Message
+	name string
+	subjects reservations.Subjects
+	printer i18n.Printer
+}
+
+func NewSubjectChooser() *SubjectChooser {
+	return &SubjectChooser{choices: make(map[int]subjectChoice)}
+}
+
+// Answer replies to the errors of application.SubjectService.Resolve, other errors are returned as they are
+func (c *SubjectChooser) Answer(ctx context.Context, b *bot.Bot, update *models.Update, err error) (string, error) {
+	p := i18n.FromContext(ctx)
+	switch e := err.(type) {
+	case application.SubjectNotFoundError:
+		if len(e.Suggestions) == 0 {
+			return p.Sprintf("subject.not_found", e.Name), nil
+		}
+		return p.Sprintf("subject.did_you_mean", e.Name, strings.Join(e.Suggestions, ", ")), nil
+	case application.AmbiguousSubjectError:
+		id := c.wait(subjectChoice{message: *update.Message, name: e.Name, subjects: e.Subjects, printer: p})
+		var buttons [][]models.InlineKeyboardButton
+		for _, subject := range e.Subjects {
+			buttons = append(buttons, []models.InlineKeyboardButton{{Text: subject.Name, CallbackData: fmt.Sprintf("%s%d:%d", SubjectChoiceData, id, subject.Id)}})
+		}
+		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			MessageThreadID: update.Message.MessageThreadID,
+			Text: p.Sprintf("subject.choose", e.Name),
+			ReplyMarkup: &models.InlineKeyboardMarkup{InlineKeyboard: buttons},
+		})
+		return "", err
+	}
+
+	return "", err
+}
+
+// ChoiceHandler runs the command of the choice again with the chosen subject in place of the ambiguous name
+func (c *SubjectChooser) ChoiceHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	query := update.CallbackQuery
+	var id, subjectId int
+	fmt.Sscanf(strings.TrimPrefix(query.Data, SubjectChoiceData), "%d:%d", &id, &subjectId)
+
+	c.mu.Lock()
+	choice, ok := c.choices[id]
+	mine := ok && choice.message.From != nil && choice.message.From.ID == query.From.ID
+	if mine {
+		delete(c.choices, id)
+	}
+	c.mu.Unlock()
+
+	answer := &bot.AnswerCallbackQueryParams{CallbackQueryID: query.ID}
+	subject, chosen := choice.subject(subjectId)
+	switch {
+	case !ok || (mine && !chosen):
+		answer.Text = i18n.For(query.From.LanguageCode).Sprintf("subject.choice_expired")
+	case !mine:
+		answer.Text = choice.printer.Sprintf("subject.choice_not_yours")
+	}
+	b.AnswerCallbackQuery(ctx, answer)
+	if !mine || !chosen {
+		return
+	}
+
+	if prompt := query.Message.Message; prompt != nil {
+		b.DeleteMessage(ctx, &bot.DeleteMessageParams{ChatID: prompt.Chat.ID, MessageID: prompt.ID})
+	}
+	message := choice.message
+	message.Text = replaceName(message.Text, choice.name, subject.Name)
+	b.ProcessUpdate(ctx, &models.Update{ID: update.ID, Message: &message})
+}
+
+func (c *SubjectChooser) wait(choice subjectChoice) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counter++
+	c.choices[c.counter] = choice
+	delete(c.choices, c.counter - maxChoices)
+
+	return c.counter
+}
+
+func (choice subjectChoice) subject(id int) (reservations.Subject, bool) {
+	for _, subject := range choice.subjects {
+		if subject.Id == id {
+			return subject, true
+		}
+	}
+
+	return reservations.Subject{}, false
+}
+
+// isResolutionError tells the errors Answer replies to
+func isResolutionError(err error) bool {
+	switch err.(type) {
+	case application.SubjectNotFoundError, application.AmbiguousSubjectError:
+		return true
+	}
+
+	return false
+}
+
+// replaceName puts the chosen name in place of the first whole occurrence of the name among the arguments of the command
+func replaceName(text string, name string, chosen string) string {
+	command, args, _ := strings.Cut(text, " ")
+	bound := func(i int) bool {
+		return i < 0 || i >= len(args) || args[i] == ' ' || args[i] == ','
+	}
+
+	for from := 0; from < len(args); {
+		index := strings.Index(args[from:], name)
+		if index < 0 {
+			break
+		}
+		index += from
+		if bound(index - 1) && bound(index + len(name)) {
+			return command + " " + args[:index] + chosen + args[index + len(name):]
+		}
+		from = index + 1
+	}
+
+	return text
+}
@@ -0,0 +1,85 @@
+package telegram_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/alecthomas/assert/v2"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func TestSubjectChooser(t *testing.T) {
+	ctx := context.Background()
+	var markup models.InlineKeyboardMarkup
+	var answers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			json.Unmarshal([]byte(r.FormValue("reply_markup")), &markup)
+			fmt.Fprint(w, `{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`)
+		case strings.HasSuffix(r.URL.Path, "/answerCallbackQuery"):
+			answers = append(answers, r.FormValue("text"))
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+		default:
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	b, err := bot.New("token", bot.WithServerURL(server.URL), bot.WithSkipGetMe(), bot.WithNotAsyncHandlers())
+	assert.NoError(t, err)
+	var replayed []string
+	b.RegisterHandler(bot.HandlerTypeMessageText, "/reserve", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		replayed = append(replayed, update.Message.Text)
+	})
+	chooser := telegram.NewSubjectChooser()
+
+	t.Run("it suggests the closest names", func(t *testing.T) {
+		text, err := chooser.Answer(ctx, b, telegramUpdate("/remove bnch"), application.SubjectNotFoundError{Name: "bnch", Suggestions: []string{"Bench", "bench"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "Subject bnch was not found. Did you mean Bench, bench?", text)
+
+		text, err = chooser.Answer(ctx, b, telegramUpdate("/remove phone"), application.SubjectNotFoundError{Name: "phone"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Subject phone was not found", text)
+
+		_, err = chooser.Answer(ctx, b, telegramUpdate("/remove phone"), fmt.Errorf("Connection lost"))
+		assert.EqualError(t, err, "Connection lost")
+	})
+
+	t.Run("it runs the command again with the chosen subject", func(t *testing.T) {
+		update := telegramUpdate("/reserve workbench,bench 1h")
+		update.Message.From = &models.User{ID: 7}
+		subjects := reservations.Subjects{{Id: 3, Name: "Bench"}, {Id: 4, Name: "BENCH"}}
+
+		text, err := chooser.Answer(ctx, b, update, application.AmbiguousSubjectError{Name: "bench", Subjects: subjects})
+		assert.NoError(t, err)
+		assert.Equal(t, "", text)
+		assert.Equal(t, 2, len(markup.InlineKeyboard))
+		assert.Equal(t, "BENCH", markup.InlineKeyboard[1][0].Text)
+		choose := func(userId int64) {
+			chooser.ChoiceHandler(ctx, b, &models.Update{CallbackQuery: &models.CallbackQuery{
+				ID: "query",
+				From: models.User{ID: userId},
+				Data: markup.InlineKeyboard[1][0].CallbackData,
+			}})
+		}
+
+		choose(8)
+		assert.Equal(t, 0, len(replayed))
+		choose(7)
+		assert.Equal(t, []string{"/reserve workbench,BENCH 1h"}, replayed)
+		choose(7)
+		assert.Equal(t, 1, len(replayed))
+		assert.Equal(t, []string{"Only the sender of the command can choose", "", "This choice has expired, send the command again"}, answers)
+	})
+}
@@ -1,82 +1,214 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/SneedusSnake/Reservations/internal/ports"
 	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/ical"
+	readmodel "github.com/SneedusSnake/Reservations/internal/read_model"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 )
 
 type telegramAdapter struct {
 	subjectService *application.SubjectService
+	subjectChooser *SubjectChooser
+	workspaceService *application.WorkspaceService
+	topicService *TopicService
 	reservationsService *application.ReservationService
 	userService *application.UserService
 	telegramUserService *TelegramUserService
+	calendarService *application.CalendarService
+	calendarImportService *application.CalendarImportService
+	feedLinks FeedLinks
 	clock ports.Clock
+	// zone renders times in group chats and for users without a time zone of their own
+	zone *time.Location
 	log *log.Logger
+	// pendingImports keeps the last previewed calendar of each telegram user until it is confirmed
+	pendingImports map[int64]pendingImport
+	importsMu sync.Mutex
+}
+
+// pendingImport is confirmed in the workspace it was previewed in, wherever /import_confirm is sent
+type pendingImport struct {
+	calendar ical.Calendar
+	workspaceId int
+}
+
+const maxCalendarSize = 1 << 20
+
+// FeedLinks builds public calendar feed URLs, nil when feeds are not served
+type FeedLinks interface {
+	Subject(subjectId int) string
+	User(userId int) string
 }
 
 type UpdateHandler func(ctx context.Context, b *bot.Bot, update *models.Update) (string, error)
 
 func NewAdapter(
 	subjectService *application.SubjectService,
+	subjectChooser *SubjectChooser,
+	workspaceService *application.WorkspaceService,
+	topicService *TopicService,
 	reservationService *application.ReservationService,
 	userService *application.UserService,
 	telegramUserService *TelegramUserService,
+	calendarService *application.CalendarService,
+	calendarImportService *application.CalendarImportService,
+	feedLinks FeedLinks,
 	clock ports.Clock,
+	zone *time.Location,
 	log *log.Logger,
 ) *telegramAdapter {
 	return &telegramAdapter{
 		subjectService: subjectService,
+		subjectChooser: subjectChooser,
+		workspaceService: workspaceService,
+		topicService: topicService,
 		reservationsService: reservationService,
 		telegramUserService: telegramUserService,
 		userService: userService,
+		calendarService: calendarService,
+		calendarImportService: calendarImportService,
+		feedLinks: feedLinks,
 		clock: clock,
+		zone: zone,
 		log: log,
+		pendingImports: make(map[int64]pendingImport),
 	}
 }
 
+// workspace returns the id of the workspace of the chat the update was sent to
+func (ta *telegramAdapter) workspace(ctx context.Context, update *models.Update) (int, error) {
+	return ta.workspaceService.ForChat(ctx, ChatKey(update.Message.Chat.ID))
+}
+
+// scope returns the subjects of the forum topic the update was sent to, scoped is false outside bound topics
+func (ta *telegramAdapter) scope(ctx context.Context, update *models.Update, workspaceId int) (subjects reservations.Subjects, scoped bool, err error) {
+	topic, ok, err := ta.topicService.Get(ctx, update.Message.Chat.ID, update.Message.MessageThreadID)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	subjects, err = ta.topicService.Subjects(ctx, topic, workspaceId)
+
+	return subjects, true, err
+}
+
 func (ta *telegramAdapter) AddSubjectHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
 	input, err := ParseAddSubject(update)
 	if err != nil {
-		return err.Error(), nil
+		return i18n.FromContext(ctx).Error(err), nil
 	}
-	_, err = ta.subjectService.Create(input.Name)
+	workspaceId, err := ta.workspace(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	_, err = ta.subjectService.Create(ctx, application.CreateSubject{Name: input.Name, WorkspaceId: workspaceId})
 
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("Subject %s added", input.Name), nil
+	return i18n.FromContext(ctx).Sprintf("subject.added", input.Name), nil
 }
 
 func (ta *telegramAdapter) AddSubjectTagsHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
 	input, err := ParseAddTags(update)
 	if err != nil {
-		return err.Error(), nil
+		return i18n.FromContext(ctx).Error(err), nil
 	}
-	subject, err := ta.subjectService.GetByName(input.SubjectName)
+	workspaceId, err := ta.workspace(ctx, update)
 	if err != nil {
 		return "", err
 	}
+	subject, err := ta.subjectService.Resolve(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return ta.subjectChooser.Answer(ctx, b, update, err)
+	}
 	cmd := application.AddTags{SubjectId: subject.Id, Tags: input.Tags}
-	err = ta.subjectService.AddTags(cmd)
+	err = ta.subjectService.AddTags(ctx, cmd)
+
+	if err != nil {
+		return "", err
+	}
+
+	return i18n.FromContext(ctx).Sprintf("subject.tags_added", strings.Join(input.Tags, ", "), subject.Name), nil
+}
+
+func (ta *telegramAdapter) AddAliasHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseAddAlias(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	workspaceId, err := ta.workspace(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	subject, err := ta.subjectService.Resolve(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return ta.subjectChooser.Answer(ctx, b, update, err)
+	}
 
+	err = ta.subjectService.AddAlias(ctx, application.AddAlias{SubjectId: subject.Id, WorkspaceId: workspaceId, Alias: input.Alias})
+	if errors.Is(err, application.ErrSubjectExists) {
+		return i18n.FromContext(ctx).Sprintf("subject.alias_taken", input.Alias), nil
+	}
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("tags: %s added to %s", strings.Join(input.Tags, ", "), input.SubjectName), nil
+	return i18n.FromContext(ctx).Sprintf("subject.alias_added", subject.Name, input.Alias), nil
+}
+
+func (ta *telegramAdapter) ListAliasesHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseListAliases(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	workspaceId, err := ta.workspace(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	subject, err := ta.subjectService.Resolve(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return ta.subjectChooser.Answer(ctx, b, update, err)
+	}
+	aliases, err := ta.subjectService.ListAliases(ctx, subject.Id)
+	if err != nil {
+		return "", err
+	}
+
+	if len(aliases) == 0 {
+		return i18n.FromContext(ctx).Sprintf("subject.no_aliases", subject.Name), nil
+	}
+
+	return strings.Join(aliases, "\n"), nil
 }
 
 func (ta *telegramAdapter) ListSubjectsHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
-	subjects, err := ta.subjectService.List()
+	workspaceId, err := ta.workspace(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	subjects, scoped, err := ta.scope(ctx, update, workspaceId)
+	if err == nil && !scoped {
+		subjects, err = ta.subjectService.List(ctx, workspaceId)
+	}
 
 	if err != nil {
 		return "", err
@@ -88,13 +220,17 @@ func (ta *telegramAdapter) ListSubjectsHandler(ctx context.Context, b *bot.Bot,
 func (ta *telegramAdapter) ListSubjectTagsHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
 	input, err := ParseListTags(update)
 	if err != nil {
-		return err.Error(), nil
+		return i18n.FromContext(ctx).Error(err), nil
 	}
-	subject, err := ta.subjectService.GetByName(input.SubjectName)
+	workspaceId, err := ta.workspace(ctx, update)
 	if err != nil {
 		return "", err
 	}
-	tags, err := ta.subjectService.ListTags(subject.Id)
+	subject, err := ta.subjectService.Resolve(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return ta.subjectChooser.Answer(ctx, b, update, err)
+	}
+	tags, err := ta.subjectService.ListTags(ctx, subject.Id)
 	if err != nil {
 		return "", err
 	}
@@ -102,81 +238,361 @@ func (ta *telegramAdapter) ListSubjectTagsHandler(ctx context.Context, b *bot.Bo
 	return strings.Join(tags, "\n"), nil
 }
 
-func (ta *telegramAdapter) CreateReservationHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
-	input, err := ParseCreateReservation(update)
+func (ta *telegramAdapter) AddComponentHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseAddComponent(update)
 	if err != nil {
-		return err.Error(), nil
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	workspaceId, err := ta.workspace(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	parent, err := ta.subjectService.Resolve(ctx, workspaceId, input.ParentName)
+	if err != nil {
+		return ta.subjectChooser.Answer(ctx, b, update, err)
+	}
+	component, err := ta.subjectService.Resolve(ctx, workspaceId, input.ComponentName)
+	if err != nil {
+		return ta.subjectChooser.Answer(ctx, b, update, err)
 	}
 
-	subject, err := ta.subjectService.GetByName(input.SubjectName)
+	err = ta.subjectService.AddComponent(ctx, application.AddComponent{ParentId: parent.Id, ComponentId: component.Id})
 	if err != nil {
 		return "", err
 	}
 
-	user, err := ta.telegramUserService.Get(update.Message.From.ID)
+	return i18n.FromContext(ctx).Sprintf("subject.component_added", component.Name, parent.Name), nil
+}
 
+func (ta *telegramAdapter) ListComponentsHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseListComponents(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	workspaceId, err := ta.workspace(ctx, update)
 	if err != nil {
-		user, err = ta.telegramUserService.Create(CreateUser{update.Message.From.ID, update.Message.From.FirstName})
+		return "", err
+	}
+	subject, err := ta.subjectService.Resolve(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return ta.subjectChooser.Answer(ctx, b, update, err)
+	}
+	components, err := ta.subjectService.ListComponents(ctx, subject.Id)
+	if err != nil {
+		return "", err
+	}
+
+	if len(components) == 0 {
+		return i18n.FromContext(ctx).Sprintf("subject.no_components", subject.Name), nil
+	}
+
+	return components.Names(), nil
+}
+
+func (ta *telegramAdapter) CreateReservationHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	workspaceId, err := ta.workspace(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	reader := ta.reader(ctx, update)
+	now := ta.clock.Current().In(reader.Location(ta.zone))
+	if anyInput, anyErr := ParseReserveAny(update, now); anyErr == nil {
+		subjects, scoped, err := ta.scope(ctx, update, workspaceId)
 		if err != nil {
 			return "", err
 		}
+		if scoped {
+			return ta.reserveAny(ctx, update, subjects, anyInput)
+		}
+	}
+	input, err := ParseCreateReservation(update, now)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	var subjectIds []int
+	var subjectNames []string
+	for _, name := range input.SubjectNames {
+		subject, err := ta.subjectService.Resolve(ctx, workspaceId, name)
+		if err != nil {
+			return ta.subjectChooser.Answer(ctx, b, update, err)
+		}
+		subjectIds = append(subjectIds, subject.Id)
+		subjectNames = append(subjectNames, subject.Name)
+	}
+
+	user, err := ta.telegramUserService.GetOrCreate(ctx, CreateUser{update.Message.From.ID, senderName(update.Message.From)})
+	if err != nil {
+		return "", err
 	}
 
-	cmd := application.CreateReservation{UserId: user.Id, SubjectId: subject.Id, From: ta.clock.Current(), To: ta.clock.Current().Add(time.Duration(input.Duration)*time.Minute)}
-	r, err := ta.reservationsService.Create(cmd)
+	cmd := application.CreateReservations{UserId: user.Id, SubjectIds: subjectIds, From: input.From, To: input.To}
+	rs, err := ta.reservationsService.CreateBatch(ctx, cmd)
 
 	if err != nil {
-		if reservedErr, ok := err.(application.AlreadyReservedError); ok {
-			r, _ := ta.reservationsService.Get(reservedErr.ReservationIds[0])
-			u, _ := ta.userService.Get(r.UserId)
-			return fmt.Sprintf("Already reserved by %s until %s", u.Name, r.End.Format(time.DateTime)), nil
+		if batchErr, ok := err.(application.BatchReservationError); ok {
+			return ta.conflictsMessage(ctx, update, user.User, batchErr, len(subjectIds) > 1), nil
 		}
 		return "", err
 	}
 
-	return fmt.Sprintf("Reservation for %s acquired by %s until %s", subject.Name, user.Name, r.End.Format(time.DateTime)), nil
+	return i18n.FromContext(ctx).Sprintf("reservation.acquired", strings.Join(subjectNames, ", "), user.Name, ta.formatTime(ctx, update, user.User, rs[0].End)), nil
+}
+
+// reserveAny reserves the first free subject of the topic
+func (ta *telegramAdapter) reserveAny(ctx context.Context, update *models.Update, subjects reservations.Subjects, input ReserveAny) (string, error) {
+	user, err := ta.telegramUserService.GetOrCreate(ctx, CreateUser{update.Message.From.ID, senderName(update.Message.From)})
+	if err != nil {
+		return "", err
+	}
+
+	for _, subject := range subjects {
+		r, err := ta.reservationsService.Create(ctx, application.CreateReservation{SubjectId: subject.Id, UserId: user.Id, From: input.From, To: input.To})
+		if _, reserved := err.(application.AlreadyReservedError); reserved {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return i18n.FromContext(ctx).Sprintf("reservation.acquired", subject.Name, user.Name, ta.formatTime(ctx, update, user.User, r.End)), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf("reservation.topic_full"), nil
+}
+
+func (ta *telegramAdapter) conflictsMessage(ctx context.Context, update *models.Update, reader users.User, batchErr application.BatchReservationError, withSubjects bool) string {
+	var lines []string
+	for _, conflict := range batchErr.Conflicts {
+		r, _ := ta.reservationsService.Get(ctx, conflict.ReservationIds[0])
+		u, _ := ta.userService.Get(ctx, r.UserId)
+		line := i18n.FromContext(ctx).Sprintf("reservation.already_reserved", u.Name, ta.formatTime(ctx, update, reader, r.End))
+		if withSubjects {
+			subject, _ := ta.subjectService.Get(ctx, conflict.SubjectId)
+			line = subject.Name + ": " + line
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
 }
 
 func (ta *telegramAdapter) RemoveReservationHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
 	input, err := ParseRemoveReservation(update)
 	if err != nil {
-		return err.Error(), nil
+		return i18n.FromContext(ctx).Error(err), nil
 	}
-	subject, err := ta.subjectService.GetByName(input.SubjectName)
+	workspaceId, err := ta.workspace(ctx, update)
 	if err != nil {
 		return "", err
 	}
+	subject, err := ta.subjectService.Resolve(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return ta.subjectChooser.Answer(ctx, b, update, err)
+	}
 
-	user, err := ta.telegramUserService.Get(update.Message.From.ID)
+	user, err := ta.telegramUserService.Get(ctx, update.Message.From.ID)
 
 	if err != nil {
 		return "", err
 	}
 
-	err = ta.reservationsService.Remove(application.RemoveReservations{UserId: user.Id, SubjectId: subject.Id})
+	err = ta.reservationsService.Remove(ctx, application.RemoveReservations{UserId: user.Id, SubjectId: subject.Id})
 	
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("Reservation for %s removed", subject.Name), nil
+	return i18n.FromContext(ctx).Sprintf("reservation.removed", subject.Name), nil
 }
 
 func (ta *telegramAdapter) ActiveReservationsHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
 	input, err := ParseActiveReservations(update)
 	if err != nil {
-		return err.Error(), nil
+		return i18n.FromContext(ctx).Error(err), nil
 	}
-	list, err := ta.reservationsService.ActiveReservations(ta.clock.Current(), input.Tags...)
+	workspaceId, err := ta.workspace(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	list, err := ta.reservationsService.ActiveReservations(ctx, workspaceId, ta.clock.Current(), input.Tags...)
 
 	if err != nil {
 		return "", err
 	}
+	if len(input.Tags) == 0 {
+		subjects, scoped, err := ta.scope(ctx, update, workspaceId)
+		if err != nil {
+			return "", err
+		}
+		if scoped {
+			list = slices.DeleteFunc(list, func(r readmodel.Reservation) bool {
+				return !slices.ContainsFunc(subjects, func(s reservations.Subject) bool { return s.Name == r.Subject })
+			})
+		}
+	}
 
-	text := "Subject\tReserved Until\t\tUser\n"
+	reader := ta.reader(ctx, update)
+	text := i18n.FromContext(ctx).Sprintf("reservations.header") + "\n"
 	for _, reservation := range list {
-		text += fmt.Sprintf("%s\t%s\t\t%s\n", reservation.Subject, reservation.End.Format(time.DateTime), reservation.User)
+		text += fmt.Sprintf("%s\t%s\t\t%s\n", reservation.Subject, ta.formatTime(ctx, update, reader, reservation.End), reservation.User)
 	}
 
 	return text, nil
 }
+
+func (ta *telegramAdapter) SubjectCalendarHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseSubjectCalendar(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	workspaceId, err := ta.workspace(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	subject, err := ta.subjectService.Resolve(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return ta.subjectChooser.Answer(ctx, b, update, err)
+	}
+	feed, err := ta.calendarService.SubjectFeed(ctx, subject.Id, ta.calendarService.SubjectFeedToken(subject.Id))
+	if err != nil {
+		return "", err
+	}
+
+	caption := ""
+	if ta.feedLinks != nil {
+		caption = i18n.FromContext(ctx).Sprintf("calendar.subscribe", ta.feedLinks.Subject(subject.Id))
+	}
+
+	return "", ta.sendCalendar(ctx, b, update, feed, subject.Name + ".ics", caption)
+}
+
+func (ta *telegramAdapter) UserCalendarHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	user, err := ta.telegramUserService.Get(ctx, update.Message.From.ID)
+	if err != nil {
+		return i18n.FromContext(ctx).Sprintf("calendar.no_reservations"), nil
+	}
+	feed, err := ta.calendarService.UserFeed(ctx, user.Id, ta.calendarService.UserFeedToken(user.Id))
+	if err != nil {
+		return "", err
+	}
+
+	caption := ""
+	if ta.feedLinks != nil {
+		caption = i18n.FromContext(ctx).Sprintf("calendar.subscribe", ta.feedLinks.User(user.Id))
+	}
+
+	return "", ta.sendCalendar(ctx, b, update, feed, "reservations.ics", caption)
+}
+
+func (ta *telegramAdapter) sendCalendar(ctx context.Context, b *bot.Bot, update *models.Update, feed application.Feed, filename string, caption string) error {
+	var buf bytes.Buffer
+	if err := ical.ReservationsCalendar(feed.Name, feed.Reservations, ta.clock.Current()).Render(&buf); err != nil {
+		return err
+	}
+
+	_, err := b.SendDocument(ctx, &bot.SendDocumentParams{
+		ChatID: update.Message.Chat.ID,
+		MessageThreadID: update.Message.MessageThreadID,
+		Document: &models.InputFileUpload{Filename: filename, Data: &buf},
+		Caption: caption,
+	})
+
+	return err
+}
+
+// ImportCalendarHandler previews the reservations of an uploaded .ics file, they are made on /import_confirm
+func (ta *telegramAdapter) ImportCalendarHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	workspaceId, err := ta.workspace(ctx, update)
+	if err != nil {
+		return "", err
+	}
+	document := update.Message.Document
+	if document.FileSize > maxCalendarSize {
+		return i18n.FromContext(ctx).Sprintf("import.too_large"), nil
+	}
+
+	data, err := ta.download(ctx, b, document.FileID)
+	if err != nil {
+		return "", err
+	}
+	user, err := ta.telegramUserService.GetOrCreate(ctx, CreateUser{update.Message.From.ID, senderName(update.Message.From)})
+	if err != nil {
+		return "", err
+	}
+	calendar, err := ical.Parse(bytes.NewReader(data), user.Location(ta.zone))
+	if err != nil {
+		return i18n.FromContext(ctx).Sprintf("import.unreadable", err), nil
+	}
+
+	report, err := ta.calendarImportService.Import(ctx, application.ImportCalendar{UserId: user.Id, WorkspaceId: workspaceId, Calendar: calendar, DryRun: true})
+	if err != nil {
+		return "", err
+	}
+
+	ta.importsMu.Lock()
+	defer ta.importsMu.Unlock()
+	delete(ta.pendingImports, update.Message.From.ID)
+	if report.Count(application.ImportCreated) == 0 {
+		return truncate(report.String() + "\n" + i18n.FromContext(ctx).Sprintf("import.nothing")), nil
+	}
+	ta.pendingImports[update.Message.From.ID] = pendingImport{calendar: calendar, workspaceId: workspaceId}
+
+	return truncate(report.String() + "\n" + i18n.FromContext(ctx).Sprintf("import.confirm")), nil
+}
+
+func (ta *telegramAdapter) ConfirmImportHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	ta.importsMu.Lock()
+	pending, ok := ta.pendingImports[update.Message.From.ID]
+	delete(ta.pendingImports, update.Message.From.ID)
+	ta.importsMu.Unlock()
+
+	if !ok {
+		return i18n.FromContext(ctx).Sprintf("import.not_pending"), nil
+	}
+
+	user, err := ta.telegramUserService.Get(ctx, update.Message.From.ID)
+	if err != nil {
+		return "", err
+	}
+	report, err := ta.calendarImportService.Import(ctx, application.ImportCalendar{UserId: user.Id, WorkspaceId: pending.workspaceId, Calendar: pending.calendar})
+	if err != nil {
+		return "", err
+	}
+
+	return truncate(report.String()), nil
+}
+
+func (ta *telegramAdapter) download(ctx context.Context, b *bot.Bot, fileId string) ([]byte, error) {
+	file, err := b.GetFile(ctx, &bot.GetFileParams{FileID: fileId})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, b.FileDownloadLink(file), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unable to download file %s: %s", fileId, response.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(response.Body, maxCalendarSize))
+}
+
+// truncate keeps the text within the telegram message length limit
+func truncate(text string) string {
+	const limit = 4000
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+
+	return string(runes[:limit]) + "\n..."
+}
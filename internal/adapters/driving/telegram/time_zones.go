@@ -0,0 +1,60 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/timefmt"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// isGroup reports chats read by several people, which may live in different zones
+func isGroup(chat models.Chat) bool {
+	return chat.Type == models.ChatTypeGroup || chat.Type == models.ChatTypeSupergroup
+}
+
+// reader returns the sender of the update, senders unknown to the bot read times in the default zone
+func (ta *telegramAdapter) reader(ctx context.Context, update *models.Update) users.User {
+	if update.Message.From == nil {
+		return users.User{}
+	}
+	user, err := ta.telegramUserService.Get(ctx, update.Message.From.ID)
+	if err != nil {
+		return users.User{}
+	}
+
+	return user.User
+}
+
+// formatTime renders t in the zone of the reader, group chats see how soon it is and the time in the default zone
+func (ta *telegramAdapter) formatTime(ctx context.Context, update *models.Update, reader users.User, t time.Time) string {
+	if isGroup(update.Message.Chat) {
+		return timefmt.Group(i18n.FromContext(ctx), t, ta.clock.Current(), ta.zone)
+	}
+
+	return t.In(reader.Location(ta.zone)).Format(time.DateTime)
+}
+
+func (ta *telegramAdapter) TimeZoneHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseSetTimeZone(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	user, err := ta.telegramUserService.GetOrCreate(ctx, CreateUser{update.Message.From.ID, senderName(update.Message.From)})
+	if err != nil {
+		return "", err
+	}
+	if input.Show {
+		return i18n.FromContext(ctx).Sprintf("timezone.current", user.Location(ta.zone)), nil
+	}
+
+	updated, err := ta.userService.SetTimeZone(ctx, user.Id, input.Zone)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf("timezone.set", updated.Location(ta.zone)), nil
+}
@@ -0,0 +1,104 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// tokensAdapter handles the service account commands, callers restrict them to admins
+type tokensAdapter struct {
+	tokenService *application.AccessTokenService
+	workspaceService *application.WorkspaceService
+}
+
+func NewTokensAdapter(tokenService *application.AccessTokenService, workspaceService *application.WorkspaceService) *tokensAdapter {
+	return &tokensAdapter{tokenService: tokenService, workspaceService: workspaceService}
+}
+
+// CreateServiceAccountHandler replies with the token only in private chats, it cannot be shown again
+func (ta *tokensAdapter) CreateServiceAccountHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	if update.Message.Chat.Type != models.ChatTypePrivate {
+		return i18n.FromContext(ctx).Sprintf("error.private_only", "/service_account"), nil
+	}
+	input, err := ParseCreateServiceAccount(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	workspaceId := workspaces.Default
+	if input.Workspace != "" {
+		workspace, err := ta.workspaceService.GetByName(ctx, input.Workspace)
+		if err != nil {
+			return i18n.FromContext(ctx).Error(err), nil
+		}
+		workspaceId = workspace.Id
+	}
+
+	token, value, err := ta.tokenService.CreateServiceAccount(ctx, application.CreateServiceAccount{
+		Name: input.Name,
+		WorkspaceId: workspaceId,
+		Scopes: input.Scopes,
+		TTL: time.Duration(input.Days) * 24 * time.Hour,
+	})
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf(
+		"token.created",
+		input.Name,
+		token.Id,
+		strings.Join(token.Scopes, ", "),
+		expiry(ctx, token.ExpiresAt),
+		value,
+	), nil
+}
+
+func (ta *tokensAdapter) ListTokensHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	tokens, err := ta.tokenService.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return i18n.FromContext(ctx).Sprintf("token.none"), nil
+	}
+
+	var lines []string
+	for _, token := range tokens {
+		workspace, err := ta.workspaceService.Get(ctx, token.WorkspaceId)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, i18n.FromContext(ctx).Sprintf("token.line", token.Id, token.Name, token.UserId, workspace.Name, strings.Join(token.Scopes, ", "), expiry(ctx, token.ExpiresAt)))
+	}
+
+	return truncate(strings.Join(lines, "\n")), nil
+}
+
+func (ta *tokensAdapter) RevokeTokenHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseRevokeToken(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	if err = ta.tokenService.Revoke(ctx, input.Id); err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf("token.revoked", input.Id), nil
+}
+
+func expiry(ctx context.Context, expiresAt time.Time) string {
+	if expiresAt.IsZero() {
+		return i18n.FromContext(ctx).Sprintf("token.never_expires")
+	}
+
+	return i18n.FromContext(ctx).Sprintf("token.expires", expiresAt.Format(time.DateTime))
+}
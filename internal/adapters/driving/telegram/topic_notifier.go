@@ -0,0 +1,138 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/logging"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	"github.com/SneedusSnake/Reservations/internal/timefmt"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type MessageSender interface {
+	SendMessage(ctx context.Context, params *bot.SendMessageParams) (*models.Message, error)
+}
+
+type originKey struct{}
+
+type origin struct {
+	chatId int64
+	threadId int
+}
+
+// FromTopic marks the changes made in ctx as requested in the topic, which already sees the bot reply
+func FromTopic(ctx context.Context, chatId int64, threadId int) context.Context {
+	return context.WithValue(ctx, originKey{}, origin{chatId: chatId, threadId: threadId})
+}
+
+const (
+	// notificationTimeout limits sending a single notification
+	notificationTimeout = 10 * time.Second
+	notificationQueueSize = 100
+)
+
+// TopicNotifier posts reservation events into the forum topics whose scope includes the subject.
+// The messages are sent in the background by Run, so slow or failing topics do not hold up the commands
+type TopicNotifier struct {
+	topics *TopicService
+	userService *application.UserService
+	languages *LanguageService
+	clock ports.Clock
+	// zone is the default zone of the chats the topics belong to
+	zone *time.Location
+	sender MessageSender
+	queue chan *bot.SendMessageParams
+}
+
+func NewTopicNotifier(topics *TopicService, userService *application.UserService, languages *LanguageService, clock ports.Clock, zone *time.Location) *TopicNotifier {
+	return &TopicNotifier{
+		topics: topics,
+		userService: userService,
+		languages: languages,
+		clock: clock,
+		zone: zone,
+		queue: make(chan *bot.SendMessageParams, notificationQueueSize),
+	}
+}
+
+// Use sets the sender of the notifications, events are dropped until it is set. It is to be called before Run
+func (n *TopicNotifier) Use(sender MessageSender) {
+	n.sender = sender
+}
+
+// Publish queues the notifications of the event, those not fitting the queue are dropped
+func (n *TopicNotifier) Publish(ctx context.Context, event ports.Event) {
+	reservation, ok := event.Data.(application.ReservationEvent)
+	if n.sender == nil || !ok {
+		return
+	}
+	log := logging.FromContext(ctx)
+
+	topics, err := n.topics.Covering(ctx, reservation.SubjectId)
+	if err != nil {
+		log.Printf("Could not notify topics about %s: %s", event.Type, err)
+		return
+	}
+	if len(topics) == 0 {
+		return
+	}
+	user, err := n.userService.Get(ctx, reservation.UserId)
+	if err != nil {
+		log.Printf("Could not notify topics about %s: %s", event.Type, err)
+		return
+	}
+
+	from, _ := ctx.Value(originKey{}).(origin)
+	for _, topic := range topics {
+		if topic.ChatId == from.chatId && topic.ThreadId == from.threadId {
+			continue
+		}
+		text, err := n.text(n.languages.ForChat(ctx, topic.ChatId), event.Type, reservation, user.Name)
+		if err != nil {
+			log.Printf("Could not notify topics about %s: %s", event.Type, err)
+			return
+		}
+		select {
+		case n.queue <- &bot.SendMessageParams{ChatID: topic.ChatId, MessageThreadID: topic.ThreadId, Text: text}:
+		default:
+			log.Printf("Could not notify topic %d of chat %d: notification queue is full", topic.ThreadId, topic.ChatId)
+		}
+	}
+}
+
+// Run sends the queued notifications until ctx is done
+func (n *TopicNotifier) Run(ctx context.Context) {
+	for {
+		select {
+		case params := <-n.queue:
+			n.send(ctx, params)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (n *TopicNotifier) send(ctx context.Context, params *bot.SendMessageParams) {
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	if _, err := n.sender.SendMessage(ctx, params); err != nil {
+		logging.FromContext(ctx).Printf("Could not notify topic %d of chat %d: %s", params.MessageThreadID, params.ChatID, err)
+	}
+}
+
+func (n *TopicNotifier) text(p i18n.Printer, eventType string, reservation application.ReservationEvent, userName string) (string, error) {
+	switch eventType {
+	case application.EventReservationCreated:
+		return p.Sprintf("reservation.acquired", reservation.Subject, userName, timefmt.Group(p, reservation.End, n.clock.Current(), n.zone)), nil
+	case application.EventReservationRemoved:
+		return p.Sprintf("reservation.removed_by", reservation.Subject, userName), nil
+	}
+
+	return "", fmt.Errorf("Unexpected event %s", eventType)
+}
@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+)
+
+var ErrTopicNotBound = errors.New("This topic is not bound")
+
+// Topic scopes a forum topic of a supergroup to the subjects having the tag or to a set of subjects
+type Topic struct {
+	ChatId int64
+	ThreadId int
+	Tag string
+	SubjectIds []int
+}
+
+type TopicsRepository interface {
+	// Bind replaces the scope of the topic
+	Bind(ctx context.Context, topic Topic) error
+	// Get returns ErrTopicNotBound for topics without a scope
+	Get(ctx context.Context, chatId int64, threadId int) (Topic, error)
+	List(ctx context.Context) ([]Topic, error)
+	Remove(ctx context.Context, chatId int64, threadId int) error
+}
+
+type TopicService struct {
+	store TopicsRepository
+	subjectService *application.SubjectService
+	workspaceService *application.WorkspaceService
+}
+
+func NewTopicService(store TopicsRepository, subjectService *application.SubjectService, workspaceService *application.WorkspaceService) *TopicService {
+	return &TopicService{store: store, subjectService: subjectService, workspaceService: workspaceService}
+}
+
+func (s *TopicService) Bind(ctx context.Context, topic Topic) error {
+	if topic.ThreadId == 0 {
+		return i18n.Errorf("topic.forum_only")
+	}
+	if (topic.Tag == "") == (len(topic.SubjectIds) == 0) {
+		return i18n.Errorf("topic.scope")
+	}
+
+	return s.store.Bind(ctx, topic)
+}
+
+func (s *TopicService) Unbind(ctx context.Context, chatId int64, threadId int) error {
+	return s.store.Remove(ctx, chatId, threadId)
+}
+
+// Get returns the scope of the topic, ok is false when the message was not sent to a bound topic
+func (s *TopicService) Get(ctx context.Context, chatId int64, threadId int) (topic Topic, ok bool, err error) {
+	if threadId == 0 {
+		return Topic{}, false, nil
+	}
+	topic, err = s.store.Get(ctx, chatId, threadId)
+	if errors.Is(err, ErrTopicNotBound) {
+		return Topic{}, false, nil
+	}
+
+	return topic, err == nil, err
+}
+
+// Subjects returns the subjects in the scope of the topic which are visible in the workspace
+func (s *TopicService) Subjects(ctx context.Context, topic Topic, workspaceId int) (reservations.Subjects, error) {
+	if topic.Tag != "" {
+		return s.subjectService.WithTags(ctx, workspaceId, []string{topic.Tag})
+	}
+
+	subjects, err := s.subjectService.List(ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(subjects, func(subject reservations.Subject) bool {
+		return !slices.Contains(topic.SubjectIds, subject.Id)
+	}), nil
+}
+
+// Covering returns the topics whose scope includes the subject, among the topics of the chats whose workspace sees it.
+// Tags are shared by all the workspaces, so a tag alone does not put the subject in scope
+func (s *TopicService) Covering(ctx context.Context, subjectId int) ([]Topic, error) {
+	topics, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.subjectService.ListTags(ctx, subjectId)
+	if err != nil {
+		return nil, err
+	}
+	topics = slices.DeleteFunc(topics, func(topic Topic) bool {
+		return !slices.Contains(topic.SubjectIds, subjectId) && (topic.Tag == "" || !slices.Contains(tags, topic.Tag))
+	})
+
+	visible := make(map[int]bool)
+	var covering []Topic
+	for _, topic := range topics {
+		workspaceId, err := s.workspaceService.ForChat(ctx, ChatKey(topic.ChatId))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := visible[workspaceId]; !ok {
+			subjects, err := s.subjectService.List(ctx, workspaceId)
+			if err != nil {
+				return nil, err
+			}
+			visible[workspaceId] = slices.ContainsFunc(subjects, func(subject reservations.Subject) bool { return subject.Id == subjectId })
+		}
+		if visible[workspaceId] {
+			covering = append(covering, topic)
+		}
+	}
+
+	return covering, nil
+}
@@ -0,0 +1,101 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// topicsAdapter binds forum topics to tags or subjects, callers restrict the bindings to chat admins
+type topicsAdapter struct {
+	topicService *TopicService
+	workspaceService *application.WorkspaceService
+	subjectService *application.SubjectService
+	subjectChooser *SubjectChooser
+}
+
+func NewTopicsAdapter(topicService *TopicService, workspaceService *application.WorkspaceService, subjectService *application.SubjectService, subjectChooser *SubjectChooser) *topicsAdapter {
+	return &topicsAdapter{topicService: topicService, workspaceService: workspaceService, subjectService: subjectService, subjectChooser: subjectChooser}
+}
+
+func (ta *topicsAdapter) TopicHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	topic, ok, err := ta.topicService.Get(ctx, update.Message.Chat.ID, update.Message.MessageThreadID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return i18n.FromContext(ctx).Sprintf("topic.not_bound"), nil
+	}
+	if topic.Tag != "" {
+		return i18n.FromContext(ctx).Sprintf("topic.tag", topic.Tag), nil
+	}
+
+	workspaceId, err := ta.workspaceService.ForChat(ctx, ChatKey(update.Message.Chat.ID))
+	if err != nil {
+		return "", err
+	}
+	subjects, err := ta.topicService.Subjects(ctx, topic, workspaceId)
+	if err != nil {
+		return "", err
+	}
+
+	return i18n.FromContext(ctx).Sprintf("topic.subjects", subjects.Names()), nil
+}
+
+func (ta *topicsAdapter) BindTopicTagHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseBindTopicTag(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	err = ta.topicService.Bind(ctx, Topic{ChatId: update.Message.Chat.ID, ThreadId: update.Message.MessageThreadID, Tag: input.Tag})
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf("topic.bound_tag", input.Tag), nil
+}
+
+func (ta *topicsAdapter) BindTopicSubjectsHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseBindTopicSubjects(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	workspaceId, err := ta.workspaceService.ForChat(ctx, ChatKey(update.Message.Chat.ID))
+	if err != nil {
+		return "", err
+	}
+
+	var subjectIds []int
+	var subjectNames []string
+	for _, name := range input.SubjectNames {
+		subject, err := ta.subjectService.Resolve(ctx, workspaceId, name)
+		if isResolutionError(err) {
+			return ta.subjectChooser.Answer(ctx, b, update, err)
+		}
+		if err != nil {
+			return i18n.FromContext(ctx).Error(err), nil
+		}
+		subjectIds = append(subjectIds, subject.Id)
+		subjectNames = append(subjectNames, subject.Name)
+	}
+
+	err = ta.topicService.Bind(ctx, Topic{ChatId: update.Message.Chat.ID, ThreadId: update.Message.MessageThreadID, SubjectIds: subjectIds})
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf("topic.bound_subjects", strings.Join(subjectNames, ", ")), nil
+}
+
+func (ta *topicsAdapter) UnbindTopicHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	if err := ta.topicService.Unbind(ctx, update.Message.Chat.ID, update.Message.MessageThreadID); err != nil {
+		return "", err
+	}
+
+	return i18n.FromContext(ctx).Sprintf("topic.unbound"), nil
+}
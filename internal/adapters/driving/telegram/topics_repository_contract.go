@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+type TopicsRepositoryContract struct {
+	NewStore func() TopicsRepository
+}
+
+func (c TopicsRepositoryContract) Test(t *testing.T) {
+	ctx := context.Background()
+	store := c.NewStore()
+
+	t.Run("it returns ErrTopicNotBound given the topic is not bound", func(t *testing.T) {
+		_, err := store.Get(ctx, -100, 1)
+		assert.IsError(t, err, ErrTopicNotBound)
+	})
+
+	t.Run("it binds topics to tags and subjects", func(t *testing.T) {
+		android := Topic{ChatId: -100, ThreadId: 7, Tag: "android"}
+		benches := Topic{ChatId: -100, ThreadId: 3, SubjectIds: []int{2, 5}}
+		assert.NoError(t, store.Bind(ctx, android))
+		assert.NoError(t, store.Bind(ctx, benches))
+
+		found, err := store.Get(ctx, android.ChatId, android.ThreadId)
+		assert.NoError(t, err)
+		assert.Equal(t, android, found)
+		found, err = store.Get(ctx, benches.ChatId, benches.ThreadId)
+		assert.NoError(t, err)
+		assert.Equal(t, benches, found)
+
+		list, err := store.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []Topic{benches, android}, list)
+	})
+
+	t.Run("it replaces the scope of a bound topic", func(t *testing.T) {
+		ios := Topic{ChatId: -100, ThreadId: 7, Tag: "ios"}
+		assert.NoError(t, store.Bind(ctx, ios))
+
+		found, err := store.Get(ctx, ios.ChatId, ios.ThreadId)
+		assert.NoError(t, err)
+		assert.Equal(t, ios, found)
+
+		phones := Topic{ChatId: -100, ThreadId: 7, SubjectIds: []int{4}}
+		assert.NoError(t, store.Bind(ctx, phones))
+
+		found, err = store.Get(ctx, phones.ChatId, phones.ThreadId)
+		assert.NoError(t, err)
+		assert.Equal(t, phones, found)
+	})
+
+	t.Run("it removes bindings", func(t *testing.T) {
+		assert.NoError(t, store.Remove(ctx, -100, 7))
+
+		_, err := store.Get(ctx, -100, 7)
+		assert.IsError(t, err, ErrTopicNotBound)
+		list, err := store.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(list))
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.IsError(t, store.Bind(cancelled, Topic{ChatId: -100, ThreadId: 9, Tag: "lab"}), context.Canceled)
+		_, err := store.Get(cancelled, -100, 3)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.List(cancelled)
+		assert.IsError(t, err, context.Canceled)
+		assert.IsError(t, store.Remove(cancelled, -100, 3), context.Canceled)
+	})
+}
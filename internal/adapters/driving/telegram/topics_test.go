@@ -0,0 +1,105 @@
+package telegram_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	"github.com/alecthomas/assert/v2"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Current() time.Time {
+	return c.now
+}
+
+type channelSender chan *bot.SendMessageParams
+
+func (s channelSender) SendMessage(ctx context.Context, params *bot.SendMessageParams) (*models.Message, error) {
+	s <- params
+
+	return &models.Message{}, nil
+}
+
+func TestTopicService(t *testing.T) {
+	ctx := context.Background()
+	subjectService := application.NewSubjectService(inmemory.NewSubjectsStore(), nil)
+	workspaceService := application.NewWorkspaceService(inmemory.NewWorkspacesStore())
+	topics := telegram.NewTopicService(inmemory.NewTopicsStore(), subjectService, workspaceService)
+
+	t.Run("it covers the subjects only in topics of the workspaces seeing them", func(t *testing.T) {
+		qa, err := workspaceService.Create(ctx, "qa")
+		assert.NoError(t, err)
+		assert.NoError(t, workspaceService.Bind(ctx, telegram.ChatKey(-200), qa.Id))
+		bench, err := subjectService.Create(ctx, application.CreateSubject{Name: "Bench", WorkspaceId: workspaces.Default})
+		assert.NoError(t, err)
+		scope, err := subjectService.Create(ctx, application.CreateSubject{Name: "Scope", WorkspaceId: qa.Id})
+		assert.NoError(t, err)
+		for _, subject := range []int{bench.Id, scope.Id} {
+			assert.NoError(t, subjectService.AddTags(ctx, application.AddTags{SubjectId: subject, Tags: []string{"lab"}}))
+		}
+		defaultTopic := telegram.Topic{ChatId: -100, ThreadId: 1, Tag: "lab"}
+		qaTopic := telegram.Topic{ChatId: -200, ThreadId: 1, Tag: "lab"}
+		assert.NoError(t, topics.Bind(ctx, defaultTopic))
+		assert.NoError(t, topics.Bind(ctx, qaTopic))
+
+		covering, err := topics.Covering(ctx, bench.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []telegram.Topic{defaultTopic}, covering)
+		covering, err = topics.Covering(ctx, scope.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []telegram.Topic{qaTopic}, covering)
+
+		assert.NoError(t, subjectService.Share(ctx, application.ShareSubject{SubjectId: bench.Id, WorkspaceId: qa.Id}))
+		covering, err = topics.Covering(ctx, bench.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(covering))
+	})
+}
+
+func TestTopicNotifier(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	subjectService := application.NewSubjectService(inmemory.NewSubjectsStore(), nil)
+	topics := telegram.NewTopicService(inmemory.NewTopicsStore(), subjectService, application.NewWorkspaceService(inmemory.NewWorkspacesStore()))
+	usersStore := inmemory.NewUsersStore()
+	userService := application.NewUserService(usersStore)
+	languages := telegram.NewLanguageService(inmemory.NewChatsStore(), telegram.NewTelegramUserService(inmemory.NewTelegramUsersStore(usersStore), *userService))
+	notifier := telegram.NewTopicNotifier(topics, userService, languages, fixedClock{now}, time.UTC)
+	sender := make(channelSender, 1)
+	notifier.Use(sender)
+
+	bench, err := subjectService.Create(ctx, application.CreateSubject{Name: "Bench", WorkspaceId: workspaces.Default})
+	assert.NoError(t, err)
+	assert.NoError(t, topics.Bind(ctx, telegram.Topic{ChatId: -100, ThreadId: 1, SubjectIds: []int{bench.Id}}))
+	assert.NoError(t, usersStore.Add(ctx, users.User{Id: 1, Name: "Alice"}))
+	event := ports.Event{Type: application.EventReservationRemoved, Data: application.ReservationEvent{SubjectId: bench.Id, Subject: "Bench", UserId: 1}}
+
+	t.Run("it sends the notifications in the background", func(t *testing.T) {
+		notifier.Publish(ctx, event)
+		assert.Equal(t, 0, len(sender))
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go notifier.Run(runCtx)
+
+		select {
+		case params := <-sender:
+			assert.Equal(t, any(int64(-100)), params.ChatID)
+			assert.Equal(t, "Reservation for Bench by Alice removed", params.Text)
+		case <-time.After(time.Second):
+			t.Fatal("Notification was not sent")
+		}
+	})
+}
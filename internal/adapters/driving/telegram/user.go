@@ -1,8 +1,11 @@
 package telegram
 
 import (
+	"context"
+
 	"github.com/SneedusSnake/Reservations/internal/application"
 	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/go-telegram/bot/models"
 )
 
 type TelegramUser struct {
@@ -11,8 +14,11 @@ type TelegramUser struct {
 }
 
 type TelegramUsersRepository interface {
-	Add(u TelegramUser) error
-	Get(tgId int64) (TelegramUser, error)
+	Add(ctx context.Context, u TelegramUser) error
+	Get(ctx context.Context, tgId int64) (TelegramUser, error)
+	List(ctx context.Context) ([]TelegramUser, error)
+	// CountByUser returns the number of telegram ids linked to the user
+	CountByUser(ctx context.Context, userId int) (int, error)
 }
 
 type CreateUser struct {
@@ -32,13 +38,13 @@ func NewTelegramUserService(
 	return &TelegramUserService{store: store, userService: userService}
 }
 
-func (s *TelegramUserService) Get(id int64) (TelegramUser, error) {
-	return s.store.Get(id)
+func (s *TelegramUserService) Get(ctx context.Context, id int64) (TelegramUser, error) {
+	return s.store.Get(ctx, id)
 }
 
-func (s *TelegramUserService) Create(cmd CreateUser) (TelegramUser, error) {
+func (s *TelegramUserService) Create(ctx context.Context, cmd CreateUser) (TelegramUser, error) {
 
-	user, err := s.userService.Create(application.CreateUser{
+	user, err := s.userService.Create(ctx, application.CreateUser{
 		Name: cmd.Name,
 	})
 	if err != nil {
@@ -49,11 +55,47 @@ func (s *TelegramUserService) Create(cmd CreateUser) (TelegramUser, error) {
 		TelegramId: cmd.Id,
 		User: user,
 	}
-	err = s.store.Add(tgUser)
+	err = s.store.Add(ctx, tgUser)
+
+	if err != nil {
+		return TelegramUser{}, err
+	}
+
+	return tgUser, nil
+}
+
+// GetOrCreate returns the user of the telegram id, renaming it when the telegram name has changed.
+// Users with a web account or several telegram ids keep their name, it is not the telegram one to follow
+func (s *TelegramUserService) GetOrCreate(ctx context.Context, cmd CreateUser) (TelegramUser, error) {
+	tgUser, err := s.store.Get(ctx, cmd.Id)
+	if err != nil {
+		return s.Create(ctx, cmd)
+	}
+	if tgUser.Name == cmd.Name || tgUser.Email != "" {
+		return tgUser, nil
+	}
+	count, err := s.store.CountByUser(ctx, tgUser.Id)
+	if err != nil {
+		return TelegramUser{}, err
+	}
+	if count > 1 {
+		return tgUser, nil
+	}
 
+	user, err := s.userService.Rename(ctx, tgUser.Id, cmd.Name)
 	if err != nil {
 		return TelegramUser{}, err
 	}
+	tgUser.User = user
 
 	return tgUser, nil
 }
+
+// senderName is the first name of the sender, falling back to the username
+func senderName(from *models.User) string {
+	if from.FirstName != "" {
+		return from.FirstName
+	}
+
+	return from.Username
+}
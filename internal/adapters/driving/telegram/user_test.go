@@ -0,0 +1,49 @@
+package telegram_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestTelegramUserService(t *testing.T) {
+	ctx := context.Background()
+	usersStore := inmemory.NewUsersStore()
+	tgStore := inmemory.NewTelegramUsersStore(usersStore)
+	userService := application.NewUserService(usersStore)
+	service := telegram.NewTelegramUserService(tgStore, *userService)
+
+	t.Run("it follows the telegram name", func(t *testing.T) {
+		_, err := service.GetOrCreate(ctx, telegram.CreateUser{Id: 1, Name: "Alice"})
+		assert.NoError(t, err)
+
+		user, err := service.GetOrCreate(ctx, telegram.CreateUser{Id: 1, Name: "Alicia"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Alicia", user.Name)
+	})
+
+	t.Run("it keeps the name of users with several telegram ids", func(t *testing.T) {
+		user, err := service.GetOrCreate(ctx, telegram.CreateUser{Id: 2, Name: "Bob"})
+		assert.NoError(t, err)
+		assert.NoError(t, tgStore.Add(ctx, telegram.TelegramUser{TelegramId: 3, User: user.User}))
+
+		user, err = service.GetOrCreate(ctx, telegram.CreateUser{Id: 3, Name: "Bob at work"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Bob", user.Name)
+	})
+
+	t.Run("it keeps the name of users with a web account", func(t *testing.T) {
+		user, err := service.GetOrCreate(ctx, telegram.CreateUser{Id: 4, Name: "Carol"})
+		assert.NoError(t, err)
+		user.Email = "carol@example.com"
+		assert.NoError(t, usersStore.Update(ctx, user.User))
+
+		user, err = service.GetOrCreate(ctx, telegram.CreateUser{Id: 4, Name: "C"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Carol", user.Name)
+	})
+}
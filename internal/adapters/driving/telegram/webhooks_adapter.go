@@ -0,0 +1,110 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const deadLettersShown = 10
+
+// webhooksAdapter handles the webhook administration commands, callers restrict them to admins
+type webhooksAdapter struct {
+	webhookService *application.WebhookService
+}
+
+func NewWebhooksAdapter(webhookService *application.WebhookService) *webhooksAdapter {
+	return &webhooksAdapter{webhookService: webhookService}
+}
+
+// AddWebhookHandler replies with the signing secret only in private chats, it cannot be shown again
+func (wa *webhooksAdapter) AddWebhookHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	if update.Message.Chat.Type != models.ChatTypePrivate {
+		return i18n.FromContext(ctx).Sprintf("error.private_only", "/webhook_add"), nil
+	}
+	input, err := ParseAddWebhook(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	webhook, err := wa.webhookService.Register(ctx, application.RegisterWebhook{Url: input.Url, Events: input.Events})
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf(
+		"webhook.added",
+		webhook.Id,
+		events(ctx, webhook.Events),
+		webhook.Secret,
+	), nil
+}
+
+func (wa *webhooksAdapter) ListWebhooksHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	hooks, err := wa.webhookService.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(hooks) == 0 {
+		return i18n.FromContext(ctx).Sprintf("webhook.none"), nil
+	}
+
+	var lines []string
+	for _, hook := range hooks {
+		lines = append(lines, fmt.Sprintf("#%d %s (%s)", hook.Id, hook.Url, events(ctx, hook.Events)))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (wa *webhooksAdapter) RemoveWebhookHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseRemoveWebhook(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	if err = wa.webhookService.Remove(ctx, input.Id); err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf("webhook.removed", input.Id), nil
+}
+
+func (wa *webhooksAdapter) DeadLettersHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	letters, err := wa.webhookService.DeadLetters(ctx, deadLettersShown)
+	if err != nil {
+		return "", err
+	}
+	if len(letters) == 0 {
+		return i18n.FromContext(ctx).Sprintf("webhook.no_dead_letters"), nil
+	}
+
+	var lines []string
+	for _, letter := range letters {
+		lines = append(lines, i18n.FromContext(ctx).Plural(
+			"webhook.dead_letter",
+			letter.Attempts,
+			letter.FailedAt.Format(time.DateTime),
+			letter.WebhookId,
+			letter.Event,
+			letter.Attempts,
+			letter.Error,
+		))
+	}
+
+	return truncate(strings.Join(lines, "\n")), nil
+}
+
+func events(ctx context.Context, filters []string) string {
+	if len(filters) == 0 {
+		return i18n.FromContext(ctx).Sprintf("webhook.all_events")
+	}
+
+	return strings.Join(filters, ", ")
+}
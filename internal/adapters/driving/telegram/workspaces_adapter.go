@@ -0,0 +1,243 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// workspacesAdapter binds chats to workspaces and shares subjects between them, callers restrict
+// its commands to admins. BindWorkspaceHandler checks on its own that the sender manages the target workspace
+type workspacesAdapter struct {
+	workspaceService *application.WorkspaceService
+	subjectService *application.SubjectService
+	telegramUserService *TelegramUserService
+	subjectChooser *SubjectChooser
+	accessOf AccessOf
+}
+
+func NewWorkspacesAdapter(
+	workspaceService *application.WorkspaceService,
+	subjectService *application.SubjectService,
+	telegramUserService *TelegramUserService,
+	subjectChooser *SubjectChooser,
+	accessOf AccessOf,
+) *workspacesAdapter {
+	return &workspacesAdapter{
+		workspaceService: workspaceService,
+		subjectService: subjectService,
+		telegramUserService: telegramUserService,
+		subjectChooser: subjectChooser,
+		accessOf: accessOf,
+	}
+}
+
+// ChatKey identifies a telegram chat among the chats bound to workspaces
+func ChatKey(chatId int64) string {
+	return fmt.Sprintf("telegram:%d", chatId)
+}
+
+func (wa *workspacesAdapter) CurrentWorkspaceHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	workspaceId, err := wa.workspaceService.ForChat(ctx, ChatKey(update.Message.Chat.ID))
+	if err != nil {
+		return "", err
+	}
+	workspace, err := wa.workspaceService.Get(ctx, workspaceId)
+	if err != nil {
+		return "", err
+	}
+
+	return i18n.FromContext(ctx).Sprintf("workspace.current", workspace.Name), nil
+}
+
+// CreateWorkspaceHandler creates a workspace and binds the chat to it
+func (wa *workspacesAdapter) CreateWorkspaceHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseCreateWorkspace(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	workspace, err := wa.workspaceService.Create(ctx, input.Name)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	if err = wa.workspaceService.Bind(ctx, ChatKey(update.Message.Chat.ID), workspace.Id); err != nil {
+		return "", err
+	}
+
+	return i18n.FromContext(ctx).Sprintf("workspace.created", workspace.Name), nil
+}
+
+func (wa *workspacesAdapter) BindWorkspaceHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseBindWorkspace(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	workspace, err := wa.workspaceService.GetByName(ctx, input.Name)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	manages, err := wa.manages(ctx, b, update, workspace.Id)
+	if err != nil {
+		return "", err
+	}
+	if !manages {
+		return i18n.FromContext(ctx).Sprintf("workspace.not_admin", workspace.Name), nil
+	}
+	if err = wa.workspaceService.Bind(ctx, ChatKey(update.Message.Chat.ID), workspace.Id); err != nil {
+		return "", err
+	}
+
+	return i18n.FromContext(ctx).Sprintf("workspace.bound", workspace.Name), nil
+}
+
+// manages tells whether the sender is an admin of the workspace, the admins of the bot manage all of them
+func (wa *workspacesAdapter) manages(ctx context.Context, b *bot.Bot, update *models.Update, workspaceId int) (bool, error) {
+	access, err := wa.accessOf(ctx, b, update)
+	if err != nil {
+		return false, err
+	}
+	if access == AccessAdmins {
+		return true, nil
+	}
+	if update.Message.From == nil {
+		return false, nil
+	}
+	user, err := wa.telegramUserService.Get(ctx, update.Message.From.ID)
+	if err != nil {
+		return false, nil
+	}
+
+	return wa.workspaceService.IsAdmin(ctx, workspaceId, user.Id)
+}
+
+// AddWorkspaceAdminHandler lets a user manage the workspace of the chat
+func (wa *workspacesAdapter) AddWorkspaceAdminHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseAddWorkspaceAdmin(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	workspace, user, err := wa.adminCommand(ctx, update, input)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	if err = wa.workspaceService.AddAdmin(ctx, workspace.Id, user.Id); err != nil {
+		return "", err
+	}
+
+	return i18n.FromContext(ctx).Sprintf("workspace.admin_added", user.Name, workspace.Name), nil
+}
+
+func (wa *workspacesAdapter) RemoveWorkspaceAdminHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseRemoveWorkspaceAdmin(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	workspace, user, err := wa.adminCommand(ctx, update, input)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	if err = wa.workspaceService.RemoveAdmin(ctx, workspace.Id, user.Id); err != nil {
+		return "", err
+	}
+
+	return i18n.FromContext(ctx).Sprintf("workspace.admin_removed", user.Name, workspace.Name), nil
+}
+
+func (wa *workspacesAdapter) adminCommand(ctx context.Context, update *models.Update, input WorkspaceAdmin) (workspaces.Workspace, TelegramUser, error) {
+	workspaceId, err := wa.workspaceService.ForChat(ctx, ChatKey(update.Message.Chat.ID))
+	if err != nil {
+		return workspaces.Workspace{}, TelegramUser{}, err
+	}
+	workspace, err := wa.workspaceService.Get(ctx, workspaceId)
+	if err != nil {
+		return workspaces.Workspace{}, TelegramUser{}, err
+	}
+	user, err := wa.telegramUserService.Get(ctx, input.TelegramId)
+	if err != nil {
+		return workspaces.Workspace{}, TelegramUser{}, i18n.Errorf("workspace.unknown_user", input.TelegramId)
+	}
+
+	return workspace, user, nil
+}
+
+func (wa *workspacesAdapter) ListWorkspacesHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	list, err := wa.workspaceService.List(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, workspace := range list {
+		lines = append(lines, fmt.Sprintf("#%d %s", workspace.Id, workspace.Name))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// ShareSubjectHandler shares a subject of the chat's workspace with another workspace
+func (wa *workspacesAdapter) ShareSubjectHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseShareSubject(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	cmd, err := wa.shareCommand(ctx, update, input)
+	if isResolutionError(err) {
+		return wa.subjectChooser.Answer(ctx, b, update, err)
+	}
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	if err = wa.subjectService.Share(ctx, cmd); err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf("workspace.shared", input.SubjectName, input.Workspace), nil
+}
+
+func (wa *workspacesAdapter) UnshareSubjectHandler(ctx context.Context, b *bot.Bot, update *models.Update) (string, error) {
+	input, err := ParseUnshareSubject(update)
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+	cmd, err := wa.shareCommand(ctx, update, input)
+	if isResolutionError(err) {
+		return wa.subjectChooser.Answer(ctx, b, update, err)
+	}
+	if err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	if err = wa.subjectService.Unshare(ctx, cmd); err != nil {
+		return i18n.FromContext(ctx).Error(err), nil
+	}
+
+	return i18n.FromContext(ctx).Sprintf("workspace.unshared", input.SubjectName, input.Workspace), nil
+}
+
+func (wa *workspacesAdapter) shareCommand(ctx context.Context, update *models.Update, input ShareSubject) (application.ShareSubject, error) {
+	workspaceId, err := wa.workspaceService.ForChat(ctx, ChatKey(update.Message.Chat.ID))
+	if err != nil {
+		return application.ShareSubject{}, err
+	}
+	subject, err := wa.subjectService.Resolve(ctx, workspaceId, input.SubjectName)
+	if err != nil {
+		return application.ShareSubject{}, err
+	}
+	target, err := wa.workspaceService.GetByName(ctx, input.Workspace)
+	if err != nil {
+		return application.ShareSubject{}, err
+	}
+
+	return application.ShareSubject{SubjectId: subject.Id, WorkspaceId: target.Id}, nil
+}
@@ -0,0 +1,233 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/logging"
+)
+
+const SessionCookie = "session"
+
+type userKey struct{}
+
+type accountsHandler struct {
+	accounts *application.AccountService
+}
+
+type account struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+	Email string `json:"email"`
+}
+
+// RegisterAccountHandlers serves registration, login and password resets as JSON endpoints
+func RegisterAccountHandlers(mux *http.ServeMux, accounts *application.AccountService) {
+	h := &accountsHandler{accounts: accounts}
+
+	mux.HandleFunc("POST /accounts/register", h.register)
+	mux.HandleFunc("POST /accounts/login", h.login)
+	mux.HandleFunc("POST /accounts/logout", h.logout)
+	mux.Handle("GET /accounts/me", RequireSession(accounts, http.HandlerFunc(h.me)))
+	mux.Handle("POST /accounts/merge", RequireSession(accounts, http.HandlerFunc(h.merge)))
+	mux.HandleFunc("POST /accounts/password/reset_request", h.requestPasswordReset)
+	mux.HandleFunc("POST /accounts/password/reset", h.resetPassword)
+}
+
+// RequireSession answers 401 unless the request carries a valid session, as a bearer token or a cookie
+func RequireSession(accounts *application.AccountService, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := accounts.Authenticate(r.Context(), session(r))
+		if errors.Is(err, application.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+		if err != nil {
+			internalError(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user)))
+	})
+}
+
+// UserFromContext returns the user authenticated by RequireSession
+func UserFromContext(ctx context.Context) (users.User, bool) {
+	user, ok := ctx.Value(userKey{}).(users.User)
+
+	return user, ok
+}
+
+func session(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	if cookie, err := r.Cookie(SessionCookie); err == nil {
+		return cookie.Value
+	}
+
+	return ""
+}
+
+func (h *accountsHandler) register(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name string `json:"name"`
+		Email string `json:"email"`
+		Password string `json:"password"`
+		LinkCode string `json:"link_code"`
+	}
+	if !decode(w, r, &input) {
+		return
+	}
+
+	user, err := h.accounts.Register(r.Context(), application.Register{Name: input.Name, Email: input.Email, Password: input.Password, LinkCode: input.LinkCode})
+	if errors.Is(err, application.ErrEmailTaken) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	writeJson(w, http.StatusCreated, account{Id: user.Id, Name: user.Name, Email: user.Email})
+}
+
+func (h *accountsHandler) login(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Email string `json:"email"`
+		Password string `json:"password"`
+	}
+	if !decode(w, r, &input) {
+		return
+	}
+
+	token, err := h.accounts.Login(r.Context(), input.Email, input.Password)
+	if errors.Is(err, application.ErrInvalidCredentials) {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err != nil {
+		internalError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name: SessionCookie,
+		Value: token,
+		Path: "/",
+		MaxAge: int(application.SessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure: r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	writeJson(w, http.StatusOK, map[string]string{"token": token})
+}
+
+func (h *accountsHandler) logout(w http.ResponseWriter, r *http.Request) {
+	if token := session(r); token != "" {
+		if err := h.accounts.Logout(r.Context(), token); err != nil {
+			internalError(w, r, err)
+			return
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: SessionCookie, Path: "/", MaxAge: -1, HttpOnly: true})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *accountsHandler) me(w http.ResponseWriter, r *http.Request) {
+	user, _ := UserFromContext(r.Context())
+
+	writeJson(w, http.StatusOK, account{Id: user.Id, Name: user.Name, Email: user.Email})
+}
+
+// merge takes over the user who requested the code in a chat
+func (h *accountsHandler) merge(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Code string `json:"code"`
+	}
+	if !decode(w, r, &input) {
+		return
+	}
+	user, _ := UserFromContext(r.Context())
+
+	merged, err := h.accounts.Merge(r.Context(), input.Code, user.Id)
+	if errors.Is(err, application.ErrInvalidToken) {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	writeJson(w, http.StatusOK, account{Id: merged.Id, Name: merged.Name, Email: merged.Email})
+}
+
+func (h *accountsHandler) requestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Email string `json:"email"`
+	}
+	if !decode(w, r, &input) {
+		return
+	}
+
+	if err := h.accounts.RequestPasswordReset(r.Context(), input.Email); err != nil {
+		internalError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *accountsHandler) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Token string `json:"token"`
+		Password string `json:"password"`
+	}
+	if !decode(w, r, &input) {
+		return
+	}
+
+	err := h.accounts.ResetPassword(r.Context(), application.ResetPassword{Token: input.Token, Password: input.Password})
+	if errors.Is(err, application.ErrInvalidToken) {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, input any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64 << 10)).Decode(input); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("Request body must be a JSON object"))
+		return false
+	}
+
+	return true
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJson(w, status, map[string]string{"error": err.Error()})
+}
+
+func internalError(w http.ResponseWriter, r *http.Request, err error) {
+	logging.FromContext(r.Context()).Print(err)
+	writeError(w, http.StatusInternalServerError, errors.New("An error occured"))
+}
@@ -0,0 +1,129 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/web"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	"github.com/alecthomas/assert/v2"
+)
+
+type fakeMailer struct {
+	sent []ports.Mail
+}
+
+func (m *fakeMailer) Send(ctx context.Context, mail ports.Mail) error {
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+func TestAccounts(t *testing.T) {
+	mailer := &fakeMailer{}
+	usersStore := inmemory.NewUsersStore()
+	tokensStore := inmemory.NewTokensStore()
+	accounts := application.NewAccountService(usersStore, tokensStore, inmemory.NewUserMerger(usersStore, tokensStore), mailer, fixedClock{time.Now()})
+	mux := http.NewServeMux()
+	web.RegisterAccountHandlers(mux, accounts)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	post := func(t *testing.T, path string, body string) (*http.Response, map[string]any) {
+		response, err := http.Post(server.URL + path, "application/json", strings.NewReader(body))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		var decoded map[string]any
+		json.NewDecoder(response.Body).Decode(&decoded)
+
+		return response, decoded
+	}
+	me := func(t *testing.T, prepare func(r *http.Request)) (*http.Response, map[string]any) {
+		request, _ := http.NewRequest(http.MethodGet, server.URL + "/accounts/me", nil)
+		prepare(request)
+		response, err := http.DefaultClient.Do(request)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		var decoded map[string]any
+		json.NewDecoder(response.Body).Decode(&decoded)
+
+		return response, decoded
+	}
+
+	t.Run("it registers and logs in", func(t *testing.T) {
+		response, body := post(t, "/accounts/register", `{"name":"Alice","email":"alice@example.com","password":"correct horse"}`)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, "alice@example.com", body["email"])
+
+		response, body = post(t, "/accounts/register", `{"name":"Alice","email":"alice@example.com","password":"correct horse"}`)
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+		response, _ = post(t, "/accounts/login", `{"email":"alice@example.com","password":"wrong horse"}`)
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+		response, body = post(t, "/accounts/login", `{"email":"alice@example.com","password":"correct horse"}`)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		token := body["token"].(string)
+		cookies := response.Cookies()
+		assert.Equal(t, 1, len(cookies))
+		assert.True(t, cookies[0].HttpOnly)
+
+		response, body = me(t, func(r *http.Request) { r.Header.Set("Authorization", "Bearer " + token) })
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "Alice", body["name"])
+		response, _ = me(t, func(r *http.Request) { r.AddCookie(cookies[0]) })
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		response, _ = me(t, func(r *http.Request) {})
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	})
+
+	t.Run("it resets passwords", func(t *testing.T) {
+		response, _ := post(t, "/accounts/password/reset_request", `{"email":"alice@example.com"}`)
+		assert.Equal(t, http.StatusAccepted, response.StatusCode)
+		token := strings.Split(mailer.sent[0].Body, "\n")[2]
+
+		response, _ = post(t, "/accounts/password/reset", `{"token":"unknown","password":"battery staple"}`)
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+		response, _ = post(t, "/accounts/password/reset", `{"token":"` + token + `","password":"battery staple"}`)
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+
+		response, _ = post(t, "/accounts/login", `{"email":"alice@example.com","password":"battery staple"}`)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("it merges a chat user into the account", func(t *testing.T) {
+		ctx := context.Background()
+		chatId, _ := usersStore.NextIdentity(ctx)
+		assert.NoError(t, usersStore.Add(ctx, users.User{Id: chatId, Name: "alice_tg"}))
+		code, err := accounts.CreateMergeCode(ctx, chatId)
+		assert.NoError(t, err)
+		token, err := accounts.Login(ctx, "alice@example.com", "battery staple")
+		assert.NoError(t, err)
+
+		request, _ := http.NewRequest(http.MethodPost, server.URL + "/accounts/merge", strings.NewReader(`{"code":"` + code + `"}`))
+		request.Header.Set("Authorization", "Bearer " + token)
+		response, err := http.DefaultClient.Do(request)
+		assert.NoError(t, err)
+		response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		_, err = usersStore.Get(ctx, chatId)
+		assert.Error(t, err)
+
+		response, _ = post(t, "/accounts/merge", `{"code":"` + code + `"}`)
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	})
+
+	t.Run("it rejects malformed requests", func(t *testing.T) {
+		response, body := post(t, "/accounts/login", `not json`)
+
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, "Request body must be a JSON object", body["error"])
+	})
+}
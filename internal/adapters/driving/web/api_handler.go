@@ -0,0 +1,217 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	"github.com/SneedusSnake/Reservations/internal/timeparse"
+)
+
+type workspaceKey struct{}
+
+type apiHandler struct {
+	subjects *application.SubjectService
+	reservations *application.ReservationService
+	clock ports.Clock
+}
+
+type apiSubject struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+}
+
+type apiReservation struct {
+	Id int `json:"id"`
+	Subject string `json:"subject"`
+	User string `json:"user"`
+	Start time.Time `json:"start"`
+	End time.Time `json:"end"`
+}
+
+// RegisterApiHandlers serves the JSON API for scripts, authenticated with personal access tokens.
+// Reservations are made on behalf of the user the token was issued to, subjects are those of the workspace of the token
+func RegisterApiHandlers(
+	mux *http.ServeMux,
+	tokens *application.AccessTokenService,
+	subjects *application.SubjectService,
+	reservations *application.ReservationService,
+	clock ports.Clock,
+) {
+	h := &apiHandler{subjects: subjects, reservations: reservations, clock: clock}
+
+	mux.Handle("GET /api/subjects", RequireToken(tokens, users.ScopeReservationsRead, http.HandlerFunc(h.listSubjects)))
+	mux.Handle("POST /api/subjects", RequireToken(tokens, users.ScopeSubjectsAdmin, http.HandlerFunc(h.createSubject)))
+	mux.Handle("GET /api/reservations", RequireToken(tokens, users.ScopeReservationsRead, http.HandlerFunc(h.activeReservations)))
+	mux.Handle("POST /api/reservations", RequireToken(tokens, users.ScopeReservationsWrite, http.HandlerFunc(h.reserve)))
+	mux.Handle("DELETE /api/reservations/{subject}", RequireToken(tokens, users.ScopeReservationsWrite, http.HandlerFunc(h.remove)))
+}
+
+// RequireToken answers 401 unless the request carries a bearer access token granting the scope
+func RequireToken(tokens *application.AccessTokenService, scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token, user, err := tokens.Authenticate(r.Context(), value, scope)
+		if errors.Is(err, application.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+		if errors.Is(err, application.ErrMissingScope) {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		if err != nil {
+			internalError(w, r, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userKey{}, user)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, workspaceKey{}, token.WorkspaceId)))
+	})
+}
+
+// WorkspaceFromContext returns the id of the workspace of the token authenticated by RequireToken
+func WorkspaceFromContext(ctx context.Context) (int, bool) {
+	workspaceId, ok := ctx.Value(workspaceKey{}).(int)
+
+	return workspaceId, ok
+}
+
+func (h *apiHandler) listSubjects(w http.ResponseWriter, r *http.Request) {
+	workspaceId, _ := WorkspaceFromContext(r.Context())
+	list, err := h.subjects.List(r.Context(), workspaceId)
+	if err != nil {
+		internalError(w, r, err)
+		return
+	}
+
+	result := []apiSubject{}
+	for _, subject := range list {
+		result = append(result, apiSubject{Id: subject.Id, Name: subject.Name})
+	}
+
+	writeJson(w, http.StatusOK, result)
+}
+
+func (h *apiHandler) createSubject(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name string `json:"name"`
+	}
+	if !decode(w, r, &input) {
+		return
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		writeError(w, http.StatusUnprocessableEntity, errors.New("Name is required"))
+		return
+	}
+
+	workspaceId, _ := WorkspaceFromContext(r.Context())
+	subject, err := h.subjects.Create(r.Context(), application.CreateSubject{Name: strings.TrimSpace(input.Name), WorkspaceId: workspaceId})
+	if errors.Is(err, application.ErrSubjectExists) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		internalError(w, r, err)
+		return
+	}
+
+	writeJson(w, http.StatusCreated, apiSubject{Id: subject.Id, Name: subject.Name})
+}
+
+func (h *apiHandler) activeReservations(w http.ResponseWriter, r *http.Request) {
+	workspaceId, _ := WorkspaceFromContext(r.Context())
+	list, err := h.reservations.ActiveReservations(r.Context(), workspaceId, h.clock.Current(), r.URL.Query()["tag"]...)
+	if err != nil {
+		internalError(w, r, err)
+		return
+	}
+
+	result := []apiReservation{}
+	for _, reservation := range list {
+		result = append(result, apiReservation{Id: reservation.Id, Subject: reservation.Subject, User: reservation.User, Start: reservation.Start, End: reservation.End})
+	}
+
+	writeJson(w, http.StatusOK, result)
+}
+
+func (h *apiHandler) reserve(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Subjects []string `json:"subjects"`
+		// Period is written as in the chats, such as "30", "2h" or "tomorrow 9-11", see timeparse.Formats
+		Period string `json:"period"`
+	}
+	if !decode(w, r, &input) {
+		return
+	}
+	if len(input.Subjects) == 0 || input.Period == "" {
+		writeError(w, http.StatusUnprocessableEntity, errors.New("Expected subjects and a period"))
+		return
+	}
+	user, _ := UserFromContext(r.Context())
+	now := h.clock.Current()
+	period, err := timeparse.Parse(input.Period, now.In(user.Location(now.Location())))
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	workspaceId, _ := WorkspaceFromContext(r.Context())
+	var subjectIds []int
+	names := make(map[int]string)
+	for _, name := range input.Subjects {
+		subject, err := h.subjects.GetByName(r.Context(), workspaceId, name)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		subjectIds = append(subjectIds, subject.Id)
+		names[subject.Id] = subject.Name
+	}
+
+	created, err := h.reservations.CreateBatch(r.Context(), application.CreateReservations{
+		UserId: user.Id,
+		SubjectIds: subjectIds,
+		From: period.From,
+		To: period.To,
+	})
+	var batchErr application.BatchReservationError
+	if errors.As(err, &batchErr) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	result := []apiReservation{}
+	for _, reservation := range created {
+		result = append(result, apiReservation{Id: reservation.Id, Subject: names[reservation.SubjectId], User: user.Name, Start: reservation.Start, End: reservation.End})
+	}
+
+	writeJson(w, http.StatusCreated, result)
+}
+
+func (h *apiHandler) remove(w http.ResponseWriter, r *http.Request) {
+	workspaceId, _ := WorkspaceFromContext(r.Context())
+	subject, err := h.subjects.GetByName(r.Context(), workspaceId, r.PathValue("subject"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	user, _ := UserFromContext(r.Context())
+	err = h.reservations.Remove(r.Context(), application.RemoveReservations{UserId: user.Id, SubjectId: subject.Id})
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
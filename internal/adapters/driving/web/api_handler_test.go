@@ -0,0 +1,117 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/web"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestApi(t *testing.T) {
+	ctx := context.Background()
+	clock := fixedClock{time.Date(2026, 4, 20, 12, 0, 0, 0, time.UTC)}
+	subjectsStore := inmemory.NewSubjectsStore()
+	usersStore := inmemory.NewUsersStore()
+	reservationsStore := inmemory.NewReservationStore()
+	readStore := inmemory.NewReservationReadStore(reservationsStore, usersStore, subjectsStore)
+	subjects := application.NewSubjectService(subjectsStore, nil)
+	reservationService := application.NewReservationService(subjectsStore, reservationsStore, readStore, usersStore, inmemory.NewUnitOfWork(reservationsStore), clock, nil)
+	workspacesStore := inmemory.NewWorkspacesStore()
+	workspaceService := application.NewWorkspaceService(workspacesStore)
+	tokens := application.NewAccessTokenService(usersStore, workspacesStore, inmemory.NewAccessTokensStore(), clock)
+	_, err := subjects.Create(ctx, application.CreateSubject{Name: "Bench", WorkspaceId: workspaces.Default})
+	assert.NoError(t, err)
+	lab, err := workspaceService.Create(ctx, "lab")
+	assert.NoError(t, err)
+	_, err = subjects.Create(ctx, application.CreateSubject{Name: "Scope", WorkspaceId: lab.Id})
+	assert.NoError(t, err)
+
+	_, ci, err := tokens.CreateServiceAccount(ctx, application.CreateServiceAccount{Name: "ci", Scopes: []string{users.ScopeReservationsWrite}})
+	assert.NoError(t, err)
+	_, admin, err := tokens.CreateServiceAccount(ctx, application.CreateServiceAccount{Name: "admin", Scopes: []string{users.ScopeSubjectsAdmin}})
+	assert.NoError(t, err)
+	_, labCi, err := tokens.CreateServiceAccount(ctx, application.CreateServiceAccount{Name: "lab ci", WorkspaceId: lab.Id, Scopes: []string{users.ScopeReservationsWrite}})
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	web.RegisterApiHandlers(mux, tokens, subjects, reservationService, clock)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	call := func(t *testing.T, method string, path string, token string, body string) (int, string) {
+		request, _ := http.NewRequest(method, server.URL + path, strings.NewReader(body))
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer " + token)
+		}
+		response, err := http.DefaultClient.Do(request)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		var decoded json.RawMessage
+		json.NewDecoder(response.Body).Decode(&decoded)
+
+		return response.StatusCode, string(decoded)
+	}
+
+	t.Run("it requires a token granting the scope", func(t *testing.T) {
+		status, _ := call(t, http.MethodGet, "/api/reservations", "", "")
+		assert.Equal(t, http.StatusUnauthorized, status)
+		status, _ = call(t, http.MethodGet, "/api/reservations", "rsv_unknown", "")
+		assert.Equal(t, http.StatusUnauthorized, status)
+		status, _ = call(t, http.MethodPost, "/api/subjects", ci, `{"name":"Phone"}`)
+		assert.Equal(t, http.StatusForbidden, status)
+		status, _ = call(t, http.MethodPost, "/api/reservations", admin, `{"subjects":["Bench"],"period":"30m"}`)
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("it reserves on behalf of the service account", func(t *testing.T) {
+		status, body := call(t, http.MethodPost, "/api/reservations", ci, `{"subjects":["Bench"],"period":"30m"}`)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Contains(t, body, `"subject":"Bench","user":"ci"`)
+
+		status, _ = call(t, http.MethodPost, "/api/reservations", ci, `{"subjects":["Bench"],"period":"30m"}`)
+		assert.Equal(t, http.StatusConflict, status)
+		status, _ = call(t, http.MethodPost, "/api/reservations", ci, `{"subjects":["Bench"],"period":"thirty"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+		status, body = call(t, http.MethodGet, "/api/reservations", ci, "")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, `"end":"2026-04-20T12:30:00Z"`)
+
+		status, _ = call(t, http.MethodDelete, "/api/reservations/Bench", ci, "")
+		assert.Equal(t, http.StatusNoContent, status)
+		status, body = call(t, http.MethodGet, "/api/reservations", ci, "")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "[]", body)
+	})
+
+	t.Run("it creates subjects", func(t *testing.T) {
+		status, body := call(t, http.MethodPost, "/api/subjects", admin, `{"name":"Phone"}`)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Contains(t, body, `"name":"Phone"`)
+
+		status, body = call(t, http.MethodGet, "/api/subjects", ci, "")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `[{"id":1,"name":"Bench"},{"id":3,"name":"Phone"}]`, body)
+	})
+
+	t.Run("it works with the subjects of the workspace of the token", func(t *testing.T) {
+		status, body := call(t, http.MethodGet, "/api/subjects", labCi, "")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `[{"id":2,"name":"Scope"}]`, body)
+
+		status, _ = call(t, http.MethodPost, "/api/reservations", labCi, `{"subjects":["Bench"],"period":"30m"}`)
+		assert.Equal(t, http.StatusNotFound, status)
+		status, _ = call(t, http.MethodPost, "/api/reservations", labCi, `{"subjects":["Scope"],"period":"30m"}`)
+		assert.Equal(t, http.StatusCreated, status)
+	})
+}
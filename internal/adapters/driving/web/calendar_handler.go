@@ -0,0 +1,68 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/ical"
+	"github.com/SneedusSnake/Reservations/internal/logging"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+)
+
+type calendarHandler struct {
+	calendars *application.CalendarService
+	clock ports.Clock
+}
+
+// RegisterCalendarHandlers serves the iCalendar feeds at the paths built by FeedLinks
+func RegisterCalendarHandlers(mux *http.ServeMux, calendars *application.CalendarService, clock ports.Clock) {
+	h := &calendarHandler{calendars: calendars, clock: clock}
+
+	mux.HandleFunc("GET /calendar/subjects/{id}/{file}", h.feed(calendars.SubjectFeed))
+	mux.HandleFunc("GET /calendar/users/{id}/{file}", h.feed(calendars.UserFeed))
+}
+
+func (h *calendarHandler) feed(load func(ctx context.Context, id int, token string) (application.Feed, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		token, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+		if err != nil || !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		feed, err := load(r.Context(), id, token)
+		if errors.Is(err, application.ErrInvalidFeedToken) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			logging.FromContext(r.Context()).Print(err)
+			http.Error(w, "An error occured", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "reservations.ics"))
+		ical.ReservationsCalendar(feed.Name, feed.Reservations, h.clock.Current()).Render(w)
+	}
+}
+
+// FeedLinks builds public feed URLs
+type FeedLinks struct {
+	BaseUrl string
+	Calendars *application.CalendarService
+}
+
+func (l FeedLinks) Subject(subjectId int) string {
+	return fmt.Sprintf("%s/calendar/subjects/%d/%s.ics", strings.TrimSuffix(l.BaseUrl, "/"), subjectId, l.Calendars.SubjectFeedToken(subjectId))
+}
+
+func (l FeedLinks) User(userId int) string {
+	return fmt.Sprintf("%s/calendar/users/%d/%s.ics", strings.TrimSuffix(l.BaseUrl, "/"), userId, l.Calendars.UserFeedToken(userId))
+}
@@ -0,0 +1,110 @@
+package web_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/web"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/alecthomas/assert/v2"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Current() time.Time {
+	return c.now
+}
+
+func TestCalendarFeeds(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	subjectsStore := inmemory.NewSubjectsStore()
+	usersStore := inmemory.NewUsersStore()
+	reservationsStore := inmemory.NewReservationStore()
+	readStore := inmemory.NewReservationReadStore(reservationsStore, usersStore, subjectsStore)
+	assert.NoError(t, subjectsStore.Add(ctx, reservations.Subject{Id: 1, Name: "Bench"}))
+	assert.NoError(t, subjectsStore.Add(ctx, reservations.Subject{Id: 2, Name: "Phone"}))
+	assert.NoError(t, usersStore.Add(ctx, users.User{Id: 1, Name: "Alice"}))
+	assert.NoError(t, reservationsStore.Add(ctx, reservations.Reservation{Id: 1, UserId: 1, SubjectId: 1, Start: now, End: now.Add(time.Hour)}))
+	assert.NoError(t, reservationsStore.Add(ctx, reservations.Reservation{Id: 2, UserId: 1, SubjectId: 2, Start: now, End: now.Add(time.Hour)}))
+
+	calendars, err := application.NewCalendarService(readStore, subjectsStore, usersStore, "secret")
+	assert.NoError(t, err)
+	mux := http.NewServeMux()
+	web.RegisterCalendarHandlers(mux, calendars, fixedClock{now})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	links := web.FeedLinks{BaseUrl: server.URL, Calendars: calendars}
+
+	get := func(t *testing.T, url string) (*http.Response, string) {
+		response, err := http.Get(url)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		assert.NoError(t, err)
+
+		return response, string(body)
+	}
+
+	t.Run("it serves subject feed", func(t *testing.T) {
+		response, body := get(t, links.Subject(1))
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "text/calendar; charset=utf-8", response.Header.Get("Content-Type"))
+		assert.Contains(t, body, "X-WR-CALNAME:Bench\r\n")
+		assert.Contains(t, body, "UID:reservation-1@reservations\r\n")
+		assert.NotContains(t, body, "UID:reservation-2@reservations")
+		assert.Contains(t, body, "DTSTART:20260301T120000Z\r\nDTEND:20260301T130000Z\r\n")
+	})
+
+	t.Run("it serves user feed", func(t *testing.T) {
+		response, body := get(t, links.User(1))
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT"))
+	})
+
+	t.Run("it hides feeds behind their tokens", func(t *testing.T) {
+		foreignToken := strings.Replace(links.Subject(1), "/subjects/1/", "/subjects/2/", 1)
+
+		for _, url := range []string{
+			foreignToken,
+			server.URL + "/calendar/subjects/1/0123456789abcdef0123456789abcdef.ics",
+			strings.TrimSuffix(links.User(1), ".ics"),
+		} {
+			response, _ := get(t, url)
+			assert.Equal(t, http.StatusNotFound, response.StatusCode)
+		}
+	})
+
+	t.Run("it lists removed reservations as cancelled", func(t *testing.T) {
+		assert.NoError(t, reservationsStore.Remove(ctx, 2))
+
+		_, body := get(t, links.User(1))
+		assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT"))
+		assert.Contains(t, body, "UID:reservation-2@reservations\r\n")
+		assert.Contains(t, body, "STATUS:CANCELLED\r\nSEQUENCE:1\r\n")
+	})
+
+	t.Run("it signs feeds with a random secret when none is set", func(t *testing.T) {
+		unset, err := application.NewCalendarService(readStore, subjectsStore, usersStore, "")
+		assert.NoError(t, err)
+
+		feed, err := unset.SubjectFeed(ctx, 1, unset.SubjectFeedToken(1))
+		assert.NoError(t, err)
+		assert.Equal(t, "Bench", feed.Name)
+		other, err := application.NewCalendarService(readStore, subjectsStore, usersStore, "")
+		assert.NoError(t, err)
+		assert.NotEqual(t, other.SubjectFeedToken(1), unset.SubjectFeedToken(1))
+	})
+}
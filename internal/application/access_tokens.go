@@ -0,0 +1,170 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	usersPort "github.com/SneedusSnake/Reservations/internal/ports/users"
+	workspacesPort "github.com/SneedusSnake/Reservations/internal/ports/workspaces"
+)
+
+// AccessTokenPrefix marks the personal access tokens, so they are told apart from sessions and easy to find in leaked files
+const AccessTokenPrefix = "rsv_"
+
+var ErrMissingScope = i18n.Errorf("token.missing_scope")
+
+type CreateServiceAccount struct {
+	Name string
+	// WorkspaceId is zero for the default workspace
+	WorkspaceId int
+	Scopes []string
+	// TTL is zero for tokens which do not expire
+	TTL time.Duration
+}
+
+type IssueAccessToken struct {
+	UserId int
+	// WorkspaceId is zero for the default workspace
+	WorkspaceId int
+	Name string
+	Scopes []string
+	TTL time.Duration
+}
+
+// AccessTokenService issues personal access tokens to service accounts and users, for the API and the CLI tools
+type AccessTokenService struct {
+	usersStore usersPort.UsersRepository
+	workspacesStore workspacesPort.WorkspacesRepository
+	store usersPort.AccessTokensRepository
+	clock ports.Clock
+}
+
+func NewAccessTokenService(
+	usersStore usersPort.UsersRepository,
+	workspacesStore workspacesPort.WorkspacesRepository,
+	store usersPort.AccessTokensRepository,
+	clock ports.Clock,
+) *AccessTokenService {
+	return &AccessTokenService{usersStore: usersStore, workspacesStore: workspacesStore, store: store, clock: clock}
+}
+
+// CreateServiceAccount adds a user with no chat identity nor password, along with its first token
+func (s *AccessTokenService) CreateServiceAccount(ctx context.Context, cmd CreateServiceAccount) (users.AccessToken, string, error) {
+	if strings.TrimSpace(cmd.Name) == "" {
+		return users.AccessToken{}, "", i18n.Errorf("error.name_required")
+	}
+	if err := validateScopes(cmd.Scopes); err != nil {
+		return users.AccessToken{}, "", err
+	}
+	workspaceId, err := s.workspace(ctx, cmd.WorkspaceId)
+	if err != nil {
+		return users.AccessToken{}, "", err
+	}
+	id, err := s.usersStore.NextIdentity(ctx)
+	if err != nil {
+		return users.AccessToken{}, "", err
+	}
+	user := users.User{Id: id, Name: strings.TrimSpace(cmd.Name)}
+	if err = s.usersStore.Add(ctx, user); err != nil {
+		return users.AccessToken{}, "", err
+	}
+
+	return s.Issue(ctx, IssueAccessToken{UserId: user.Id, WorkspaceId: workspaceId, Name: user.Name, Scopes: cmd.Scopes, TTL: cmd.TTL})
+}
+
+// Issue returns the token along with its value, which is not stored and cannot be shown again
+func (s *AccessTokenService) Issue(ctx context.Context, cmd IssueAccessToken) (users.AccessToken, string, error) {
+	if err := validateScopes(cmd.Scopes); err != nil {
+		return users.AccessToken{}, "", err
+	}
+	if cmd.TTL < 0 {
+		return users.AccessToken{}, "", i18n.Errorf("token.negative_lifetime")
+	}
+	if _, err := s.usersStore.Get(ctx, cmd.UserId); err != nil {
+		return users.AccessToken{}, "", err
+	}
+	workspaceId, err := s.workspace(ctx, cmd.WorkspaceId)
+	if err != nil {
+		return users.AccessToken{}, "", err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return users.AccessToken{}, "", err
+	}
+	value := AccessTokenPrefix + hex.EncodeToString(secret)
+	id, err := s.store.NextIdentity(ctx)
+	if err != nil {
+		return users.AccessToken{}, "", err
+	}
+
+	now := s.clock.Current().UTC().Truncate(time.Second)
+	token := users.AccessToken{Id: id, UserId: cmd.UserId, WorkspaceId: workspaceId, Name: cmd.Name, Hash: hashToken(value), Scopes: cmd.Scopes, CreatedAt: now}
+	if cmd.TTL > 0 {
+		token.ExpiresAt = now.Add(cmd.TTL)
+	}
+
+	return token, value, s.store.Add(ctx, token)
+}
+
+// Authenticate returns the token along with the user it acts for, given the token grants the scope
+func (s *AccessTokenService) Authenticate(ctx context.Context, value string, scope string) (users.AccessToken, users.User, error) {
+	if !strings.HasPrefix(value, AccessTokenPrefix) {
+		return users.AccessToken{}, users.User{}, ErrInvalidToken
+	}
+	token, err := s.store.GetByHash(ctx, hashToken(value))
+	if err != nil || token.Expired(s.clock.Current()) {
+		return users.AccessToken{}, users.User{}, ErrInvalidToken
+	}
+	if !token.Allows(scope) {
+		return users.AccessToken{}, users.User{}, ErrMissingScope
+	}
+	user, err := s.usersStore.Get(ctx, token.UserId)
+
+	return token, user, err
+}
+
+func (s *AccessTokenService) List(ctx context.Context) ([]users.AccessToken, error) {
+	return s.store.List(ctx)
+}
+
+func (s *AccessTokenService) Revoke(ctx context.Context, id int) error {
+	if _, err := s.store.Get(ctx, id); err != nil {
+		return err
+	}
+
+	return s.store.Remove(ctx, id)
+}
+
+// workspace returns the id of an existing workspace, zero stands for the default one
+func (s *AccessTokenService) workspace(ctx context.Context, id int) (int, error) {
+	if id == 0 {
+		id = workspaces.Default
+	}
+	if _, err := s.workspacesStore.Get(ctx, id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func validateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return i18n.Errorf("token.no_scopes", strings.Join(users.Scopes, ", "))
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(users.Scopes, scope) {
+			return i18n.Errorf("token.unknown_scope", scope, strings.Join(users.Scopes, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package application_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestAccessTokens(t *testing.T) {
+	setup := func() (*application.AccessTokenService, *FakeClock) {
+		clock := &FakeClock{now: time.Date(2026, 4, 20, 12, 0, 0, 0, time.UTC)}
+
+		return application.NewAccessTokenService(inmemory.NewUsersStore(), inmemory.NewWorkspacesStore(), inmemory.NewAccessTokensStore(), clock), clock
+	}
+
+	t.Run("it creates service accounts authenticated by their token scopes", func(t *testing.T) {
+		tokens, _ := setup()
+
+		token, value, err := tokens.CreateServiceAccount(ctx, application.CreateServiceAccount{Name: "ci", Scopes: []string{users.ScopeReservationsWrite}})
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(value, application.AccessTokenPrefix))
+		assert.NotEqual(t, value, token.Hash)
+		assert.True(t, token.ExpiresAt.IsZero())
+
+		authenticated, user, err := tokens.Authenticate(ctx, value, users.ScopeReservationsWrite)
+		assert.NoError(t, err)
+		assert.Equal(t, "ci", user.Name)
+		assert.Equal(t, token.UserId, user.Id)
+		assert.Equal(t, workspaces.Default, authenticated.WorkspaceId)
+		_, _, err = tokens.Authenticate(ctx, value, users.ScopeReservationsRead)
+		assert.NoError(t, err)
+		_, _, err = tokens.Authenticate(ctx, value, users.ScopeSubjectsAdmin)
+		assert.IsError(t, err, application.ErrMissingScope)
+		_, _, err = tokens.Authenticate(ctx, value + "0", users.ScopeReservationsWrite)
+		assert.IsError(t, err, application.ErrInvalidToken)
+	})
+
+	t.Run("it rejects unknown scopes and workspaces", func(t *testing.T) {
+		tokens, _ := setup()
+
+		_, _, err := tokens.CreateServiceAccount(ctx, application.CreateServiceAccount{Name: "ci", Scopes: []string{"everything"}})
+		assert.Error(t, err)
+		_, _, err = tokens.CreateServiceAccount(ctx, application.CreateServiceAccount{Name: "ci"})
+		assert.Error(t, err)
+		_, _, err = tokens.CreateServiceAccount(ctx, application.CreateServiceAccount{Name: "ci", WorkspaceId: 42, Scopes: []string{users.ScopeReservationsRead}})
+		assert.Error(t, err)
+	})
+
+	t.Run("tokens expire and can be revoked", func(t *testing.T) {
+		tokens, clock := setup()
+		expiring, expiringValue, err := tokens.CreateServiceAccount(ctx, application.CreateServiceAccount{Name: "ci", Scopes: []string{users.ScopeReservationsRead}, TTL: time.Hour})
+		assert.NoError(t, err)
+		revoked, revokedValue, err := tokens.Issue(ctx, application.IssueAccessToken{UserId: expiring.UserId, Name: "laptop", Scopes: []string{users.ScopeReservationsRead}})
+		assert.NoError(t, err)
+
+		assert.NoError(t, tokens.Revoke(ctx, revoked.Id))
+		_, _, err = tokens.Authenticate(ctx, revokedValue, users.ScopeReservationsRead)
+		assert.IsError(t, err, application.ErrInvalidToken)
+		assert.Error(t, tokens.Revoke(ctx, revoked.Id))
+
+		_, _, err = tokens.Authenticate(ctx, expiringValue, users.ScopeReservationsRead)
+		assert.NoError(t, err)
+		clock.Set(expiring.ExpiresAt)
+		_, _, err = tokens.Authenticate(ctx, expiringValue, users.ScopeReservationsRead)
+		assert.IsError(t, err, application.ErrInvalidToken)
+
+		list, err := tokens.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []users.AccessToken{expiring}, list)
+	})
+}
@@ -0,0 +1,315 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	usersPort "github.com/SneedusSnake/Reservations/internal/ports/users"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	SessionTTL = 30 * 24 * time.Hour
+	PasswordResetTTL = time.Hour
+	LinkCodeTTL = 15 * time.Minute
+
+	minPasswordLength = 8
+)
+
+var (
+	ErrInvalidCredentials = i18n.Errorf("account.invalid_credentials")
+	ErrInvalidToken = i18n.Errorf("account.invalid_token")
+	ErrEmailTaken = i18n.Errorf("account.email_taken")
+)
+
+type Register struct {
+	Name string
+	Email string
+	Password string
+	// LinkCode claims the user who requested the code in a chat instead of creating a new one
+	LinkCode string
+}
+
+type ResetPassword struct {
+	Token string
+	Password string
+}
+
+// AccountService manages email/password accounts and their sessions.
+// Tokens are handed out once and only their sha256 hashes are stored
+type AccountService struct {
+	usersStore usersPort.UsersRepository
+	tokensStore usersPort.TokensRepository
+	merger usersPort.UserMerger
+	mailer ports.Mailer
+	clock ports.Clock
+}
+
+func NewAccountService(
+	usersStore usersPort.UsersRepository,
+	tokensStore usersPort.TokensRepository,
+	merger usersPort.UserMerger,
+	mailer ports.Mailer,
+	clock ports.Clock,
+) *AccountService {
+	return &AccountService{usersStore: usersStore, tokensStore: tokensStore, merger: merger, mailer: mailer, clock: clock}
+}
+
+func (s *AccountService) Register(ctx context.Context, cmd Register) (users.User, error) {
+	email, err := normalizeEmail(cmd.Email)
+	if err != nil {
+		return users.User{}, err
+	}
+	password, err := hashPassword(cmd.Password)
+	if err != nil {
+		return users.User{}, err
+	}
+	if _, err = s.usersStore.GetByEmail(ctx, email); err == nil {
+		return users.User{}, ErrEmailTaken
+	}
+
+	if cmd.LinkCode != "" {
+		return s.link(ctx, cmd.LinkCode, email, password)
+	}
+
+	if strings.TrimSpace(cmd.Name) == "" {
+		return users.User{}, i18n.Errorf("error.name_required")
+	}
+	id, err := s.usersStore.NextIdentity(ctx)
+	if err != nil {
+		return users.User{}, err
+	}
+	user := users.User{Id: id, Name: strings.TrimSpace(cmd.Name), Email: email, Password: password}
+
+	return user, s.usersStore.Add(ctx, user)
+}
+
+// link sets the credentials of the user who requested the code, the user keeps its name and chat identities
+func (s *AccountService) link(ctx context.Context, code string, email string, password string) (users.User, error) {
+	token, err := s.consume(ctx, code, users.TokenAccountLink)
+	if err != nil {
+		return users.User{}, err
+	}
+	user, err := s.usersStore.Get(ctx, token.UserId)
+	if err != nil {
+		return users.User{}, err
+	}
+	if user.Email != "" {
+		return users.User{}, i18n.Errorf("account.exists", user.Name)
+	}
+	user.Email = email
+	user.Password = password
+
+	return user, s.usersStore.Update(ctx, user)
+}
+
+// CreateLinkCode returns a one-time code to register an email account for an existing user
+func (s *AccountService) CreateLinkCode(ctx context.Context, userId int) (string, error) {
+	user, err := s.usersStore.Get(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	if user.Email != "" {
+		return "", i18n.Errorf("account.exists", user.Name)
+	}
+	if err = s.tokensStore.RemoveByUser(ctx, userId, users.TokenAccountLink); err != nil {
+		return "", err
+	}
+
+	return s.issue(ctx, userId, users.TokenAccountLink, LinkCodeTTL, 6)
+}
+
+// CreateMergeCode returns a one-time code which merges the user into the user redeeming it
+func (s *AccountService) CreateMergeCode(ctx context.Context, userId int) (string, error) {
+	if _, err := s.usersStore.Get(ctx, userId); err != nil {
+		return "", err
+	}
+	if err := s.tokensStore.RemoveByUser(ctx, userId, users.TokenMerge); err != nil {
+		return "", err
+	}
+
+	return s.issue(ctx, userId, users.TokenMerge, LinkCodeTTL, 6)
+}
+
+// Merge moves everything of the user who issued the code to the target user and removes the issuer.
+// The target takes over the email account of the issuer unless it has one of its own
+func (s *AccountService) Merge(ctx context.Context, code string, targetId int) (users.User, error) {
+	token, err := s.valid(ctx, strings.ToLower(strings.TrimSpace(code)), users.TokenMerge)
+	if err != nil {
+		return users.User{}, err
+	}
+	if token.UserId == targetId {
+		return users.User{}, i18n.Errorf("account.merge_same")
+	}
+	source, err := s.usersStore.Get(ctx, token.UserId)
+	if err != nil {
+		return users.User{}, err
+	}
+	target, err := s.usersStore.Get(ctx, targetId)
+	if err != nil {
+		return users.User{}, err
+	}
+	if source.Email != "" && target.Email != "" {
+		return users.User{}, i18n.Errorf("account.merge_emails")
+	}
+
+	if target.Email == "" {
+		target.Email = source.Email
+		target.Password = source.Password
+	}
+	if target.Name == "" {
+		target.Name = source.Name
+	}
+	if target.TimeZone == "" {
+		target.TimeZone = source.TimeZone
+	}
+	if target.Language == "" {
+		target.Language = source.Language
+	}
+
+	return target, s.merger.Merge(ctx, source.Id, target)
+}
+
+// Login returns a new session token
+func (s *AccountService) Login(ctx context.Context, email string, password string) (string, error) {
+	user, err := s.usersStore.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil || user.Password == "" {
+		return "", ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return "", ErrInvalidCredentials
+	}
+
+	return s.issue(ctx, user.Id, users.TokenSession, SessionTTL, 32)
+}
+
+// Authenticate returns the user of the session
+func (s *AccountService) Authenticate(ctx context.Context, session string) (users.User, error) {
+	token, err := s.valid(ctx, session, users.TokenSession)
+	if err != nil {
+		return users.User{}, err
+	}
+
+	return s.usersStore.Get(ctx, token.UserId)
+}
+
+func (s *AccountService) Logout(ctx context.Context, session string) error {
+	return s.tokensStore.Remove(ctx, hashToken(session))
+}
+
+// RequestPasswordReset mails a reset token, unknown emails are ignored to not reveal who has an account
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.usersStore.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return nil
+	}
+	if err = s.tokensStore.RemoveByUser(ctx, user.Id, users.TokenPasswordReset); err != nil {
+		return err
+	}
+	token, err := s.issue(ctx, user.Id, users.TokenPasswordReset, PasswordResetTTL, 32)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, ports.Mail{
+		To: user.Email,
+		Subject: "Password reset",
+		Body: fmt.Sprintf("Use this token to reset your password within an hour:\n\n%s\n\nIgnore this mail if you did not ask for it.", token),
+	})
+}
+
+// ResetPassword sets a new password and ends every session of the user
+func (s *AccountService) ResetPassword(ctx context.Context, cmd ResetPassword) error {
+	password, err := hashPassword(cmd.Password)
+	if err != nil {
+		return err
+	}
+	token, err := s.consume(ctx, cmd.Token, users.TokenPasswordReset)
+	if err != nil {
+		return err
+	}
+	user, err := s.usersStore.Get(ctx, token.UserId)
+	if err != nil {
+		return err
+	}
+	user.Password = password
+	if err = s.usersStore.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.tokensStore.RemoveByUser(ctx, user.Id, users.TokenSession)
+}
+
+func (s *AccountService) issue(ctx context.Context, userId int, kind string, ttl time.Duration, size int) (string, error) {
+	secret := make([]byte, size)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	value := hex.EncodeToString(secret)
+	token := users.Token{Hash: hashToken(value), Kind: kind, UserId: userId, ExpiresAt: s.clock.Current().Add(ttl).UTC().Truncate(time.Second)}
+
+	return value, s.tokensStore.Add(ctx, token)
+}
+
+func (s *AccountService) valid(ctx context.Context, value string, kind string) (users.Token, error) {
+	if value == "" {
+		return users.Token{}, ErrInvalidToken
+	}
+	token, err := s.tokensStore.Get(ctx, hashToken(value))
+	if err != nil || token.Kind != kind {
+		return users.Token{}, ErrInvalidToken
+	}
+	if token.Expired(s.clock.Current()) {
+		s.tokensStore.Remove(ctx, token.Hash)
+		return users.Token{}, ErrInvalidToken
+	}
+
+	return token, nil
+}
+
+// consume validates a one-time token and removes it
+func (s *AccountService) consume(ctx context.Context, value string, kind string) (users.Token, error) {
+	token, err := s.valid(ctx, strings.ToLower(strings.TrimSpace(value)), kind)
+	if err != nil {
+		return token, err
+	}
+
+	return token, s.tokensStore.Remove(ctx, token.Hash)
+}
+
+func hashToken(value string) string {
+	hash := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", i18n.Errorf("account.password_short", minPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return "", i18n.Errorf("account.password_long")
+	}
+
+	return string(hash), err
+}
+
+func normalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || address.Name != "" {
+		return "", i18n.Errorf("account.invalid_email", email)
+	}
+
+	return strings.ToLower(address.Address), nil
+}
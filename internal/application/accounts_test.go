@@ -0,0 +1,185 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/ports"
+	"github.com/alecthomas/assert/v2"
+)
+
+type FakeMailer struct {
+	sent []ports.Mail
+}
+
+func (m *FakeMailer) Send(ctx context.Context, mail ports.Mail) error {
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+// token reads the token out of the last password reset mail
+func (m *FakeMailer) token() string {
+	lines := strings.Split(m.sent[len(m.sent) - 1].Body, "\n")
+
+	return lines[2]
+}
+
+func TestAccounts(t *testing.T) {
+	setup := func() (*application.AccountService, *application.UserService, *FakeMailer, *FakeClock) {
+		store := inmemory.NewUsersStore()
+		mailer := &FakeMailer{}
+		clock := &FakeClock{now: time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC)}
+
+		tokens := inmemory.NewTokensStore()
+
+		return application.NewAccountService(store, tokens, inmemory.NewUserMerger(store, tokens), mailer, clock), application.NewUserService(store), mailer, clock
+	}
+
+	t.Run("it registers accounts with hashed passwords and logs them in", func(t *testing.T) {
+		accounts, userService, _, _ := setup()
+
+		user, err := accounts.Register(ctx, application.Register{Name: "Alice", Email: " Alice@Example.com", Password: "correct horse"})
+		assert.NoError(t, err)
+		assert.Equal(t, "alice@example.com", user.Email)
+		stored, err := userService.Get(ctx, user.Id)
+		assert.NoError(t, err)
+		assert.NotEqual(t, "correct horse", stored.Password)
+
+		session, err := accounts.Login(ctx, "alice@example.com", "correct horse")
+		assert.NoError(t, err)
+		authenticated, err := accounts.Authenticate(ctx, session)
+		assert.NoError(t, err)
+		assert.Equal(t, user.Id, authenticated.Id)
+
+		assert.NoError(t, accounts.Logout(ctx, session))
+		_, err = accounts.Authenticate(ctx, session)
+		assert.IsError(t, err, application.ErrInvalidToken)
+	})
+
+	t.Run("it rejects invalid registrations and credentials", func(t *testing.T) {
+		accounts, _, _, _ := setup()
+		_, err := accounts.Register(ctx, application.Register{Name: "Alice", Email: "alice@example.com", Password: "correct horse"})
+		assert.NoError(t, err)
+
+		_, err = accounts.Register(ctx, application.Register{Name: "Mallory", Email: "ALICE@example.com", Password: "correct horse"})
+		assert.IsError(t, err, application.ErrEmailTaken)
+		_, err = accounts.Register(ctx, application.Register{Name: "Bob", Email: "bob", Password: "correct horse"})
+		assert.Error(t, err)
+		_, err = accounts.Register(ctx, application.Register{Name: "Bob", Email: "bob@example.com", Password: "short"})
+		assert.Error(t, err)
+
+		_, err = accounts.Login(ctx, "alice@example.com", "wrong horse")
+		assert.IsError(t, err, application.ErrInvalidCredentials)
+		_, err = accounts.Login(ctx, "bob@example.com", "correct horse")
+		assert.IsError(t, err, application.ErrInvalidCredentials)
+	})
+
+	t.Run("sessions expire", func(t *testing.T) {
+		accounts, _, _, clock := setup()
+		_, err := accounts.Register(ctx, application.Register{Name: "Alice", Email: "alice@example.com", Password: "correct horse"})
+		assert.NoError(t, err)
+		session, err := accounts.Login(ctx, "alice@example.com", "correct horse")
+		assert.NoError(t, err)
+
+		clock.Set(clock.Current().Add(application.SessionTTL))
+
+		_, err = accounts.Authenticate(ctx, session)
+		assert.IsError(t, err, application.ErrInvalidToken)
+	})
+
+	t.Run("it resets passwords with a mailed token and ends the sessions", func(t *testing.T) {
+		accounts, _, mailer, _ := setup()
+		_, err := accounts.Register(ctx, application.Register{Name: "Alice", Email: "alice@example.com", Password: "correct horse"})
+		assert.NoError(t, err)
+		session, err := accounts.Login(ctx, "alice@example.com", "correct horse")
+		assert.NoError(t, err)
+
+		assert.NoError(t, accounts.RequestPasswordReset(ctx, "unknown@example.com"))
+		assert.Equal(t, 0, len(mailer.sent))
+		assert.NoError(t, accounts.RequestPasswordReset(ctx, "alice@example.com"))
+		assert.Equal(t, "alice@example.com", mailer.sent[0].To)
+
+		token := mailer.token()
+		assert.NoError(t, accounts.ResetPassword(ctx, application.ResetPassword{Token: token, Password: "battery staple"}))
+		assert.IsError(t, accounts.ResetPassword(ctx, application.ResetPassword{Token: token, Password: "battery staple"}), application.ErrInvalidToken)
+
+		_, err = accounts.Authenticate(ctx, session)
+		assert.IsError(t, err, application.ErrInvalidToken)
+		_, err = accounts.Login(ctx, "alice@example.com", "correct horse")
+		assert.IsError(t, err, application.ErrInvalidCredentials)
+		_, err = accounts.Login(ctx, "alice@example.com", "battery staple")
+		assert.NoError(t, err)
+	})
+
+	t.Run("it links an account to a user created in a chat", func(t *testing.T) {
+		accounts, userService, _, clock := setup()
+		chatUser, err := userService.Create(ctx, application.CreateUser{Name: "alice_tg"})
+		assert.NoError(t, err)
+
+		code, err := accounts.CreateLinkCode(ctx, chatUser.Id)
+		assert.NoError(t, err)
+		user, err := accounts.Register(ctx, application.Register{Email: "alice@example.com", Password: "correct horse", LinkCode: strings.ToUpper(code)})
+		assert.NoError(t, err)
+		assert.Equal(t, chatUser.Id, user.Id)
+		assert.Equal(t, "alice_tg", user.Name)
+
+		_, err = accounts.CreateLinkCode(ctx, chatUser.Id)
+		assert.Error(t, err)
+		_, err = accounts.Register(ctx, application.Register{Email: "bob@example.com", Password: "correct horse", LinkCode: code})
+		assert.IsError(t, err, application.ErrInvalidToken)
+
+		other, err := userService.Create(ctx, application.CreateUser{Name: "bob_tg"})
+		assert.NoError(t, err)
+		code, err = accounts.CreateLinkCode(ctx, other.Id)
+		assert.NoError(t, err)
+		clock.Set(clock.Current().Add(application.LinkCodeTTL))
+		_, err = accounts.Register(ctx, application.Register{Email: "bob@example.com", Password: "correct horse", LinkCode: code})
+		assert.IsError(t, err, application.ErrInvalidToken)
+	})
+
+	t.Run("it merges the user who issued a code into the user redeeming it", func(t *testing.T) {
+		accounts, userService, _, _ := setup()
+		web, err := accounts.Register(ctx, application.Register{Name: "Alice", Email: "alice@example.com", Password: "correct horse"})
+		assert.NoError(t, err)
+		chatUser, err := userService.Create(ctx, application.CreateUser{Name: "alice_tg"})
+		assert.NoError(t, err)
+
+		code, err := accounts.CreateMergeCode(ctx, web.Id)
+		assert.NoError(t, err)
+		_, err = accounts.Merge(ctx, code, web.Id)
+		assert.Error(t, err)
+		merged, err := accounts.Merge(ctx, code, chatUser.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice_tg", merged.Name)
+		assert.Equal(t, "alice@example.com", merged.Email)
+
+		_, err = userService.Get(ctx, web.Id)
+		assert.Error(t, err)
+		session, err := accounts.Login(ctx, "alice@example.com", "correct horse")
+		assert.NoError(t, err)
+		authenticated, err := accounts.Authenticate(ctx, session)
+		assert.NoError(t, err)
+		assert.Equal(t, chatUser.Id, authenticated.Id)
+		_, err = accounts.Merge(ctx, code, chatUser.Id)
+		assert.IsError(t, err, application.ErrInvalidToken)
+	})
+
+	t.Run("it refuses to merge two email accounts", func(t *testing.T) {
+		accounts, _, _, _ := setup()
+		alice, err := accounts.Register(ctx, application.Register{Name: "Alice", Email: "alice@example.com", Password: "correct horse"})
+		assert.NoError(t, err)
+		bob, err := accounts.Register(ctx, application.Register{Name: "Bob", Email: "bob@example.com", Password: "correct horse"})
+		assert.NoError(t, err)
+
+		code, err := accounts.CreateMergeCode(ctx, alice.Id)
+		assert.NoError(t, err)
+		_, err = accounts.Merge(ctx, code, bob.Id)
+		assert.Error(t, err)
+		_, err = accounts.Login(ctx, "alice@example.com", "correct horse")
+		assert.NoError(t, err)
+	})
+}
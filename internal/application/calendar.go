@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+	readmodel "github.com/SneedusSnake/Reservations/internal/read_model"
+)
+
+var ErrInvalidFeedToken = errors.New("Invalid calendar feed token")
+
+// Feed is a named list of reservations published as a calendar
+type Feed struct {
+	Name string
+	Reservations []readmodel.Reservation
+}
+
+// CalendarService publishes reservations of a subject or a user as feeds.
+// Feed tokens are signatures of the feed owner, so they are stable without being stored
+// and all of them are revoked at once by changing the secret. Feeds are only published with a configured secret,
+// without one a random secret signs the feeds sent to the chats and its tokens only last until restart
+type CalendarService struct {
+	readStore reservations.ReservationsReadRepository
+	subjectsStore reservations.SubjectsRepository
+	usersStore users.UsersRepository
+	secret []byte
+}
+
+func NewCalendarService(
+	readStore reservations.ReservationsReadRepository,
+	subjectsStore reservations.SubjectsRepository,
+	usersStore users.UsersRepository,
+	secret string,
+) (*CalendarService, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("Could not generate calendar secret: %w", err)
+		}
+	}
+
+	return &CalendarService{
+		readStore: readStore,
+		subjectsStore: subjectsStore,
+		usersStore: usersStore,
+		secret: key,
+	}, nil
+}
+
+func (s *CalendarService) SubjectFeedToken(subjectId int) string {
+	return s.token("subject", subjectId)
+}
+
+func (s *CalendarService) UserFeedToken(userId int) string {
+	return s.token("user", userId)
+}
+
+func (s *CalendarService) SubjectFeed(ctx context.Context, subjectId int, token string) (Feed, error) {
+	if !s.valid(token, s.SubjectFeedToken(subjectId)) {
+		return Feed{}, ErrInvalidFeedToken
+	}
+	subject, err := s.subjectsStore.Get(ctx, subjectId)
+	if err != nil {
+		return Feed{}, err
+	}
+	list, err := s.readStore.ForSubject(ctx, subjectId)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	return Feed{Name: subject.Name, Reservations: list}, nil
+}
+
+func (s *CalendarService) UserFeed(ctx context.Context, userId int, token string) (Feed, error) {
+	if !s.valid(token, s.UserFeedToken(userId)) {
+		return Feed{}, ErrInvalidFeedToken
+	}
+	user, err := s.usersStore.Get(ctx, userId)
+	if err != nil {
+		return Feed{}, err
+	}
+	list, err := s.readStore.ForUser(ctx, userId)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	return Feed{Name: fmt.Sprintf("Reservations of %s", user.Name), Reservations: list}, nil
+}
+
+func (s *CalendarService) token(kind string, id int) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s:%d", kind, id)
+
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+func (s *CalendarService) valid(token string, expected string) bool {
+	return hmac.Equal([]byte(token), []byte(expected))
+}
@@ -0,0 +1,206 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/ical"
+	reservationsPort "github.com/SneedusSnake/Reservations/internal/ports/reservations"
+)
+
+type ImportStatus string
+
+const (
+	ImportCreated ImportStatus = "created"
+	ImportConflict ImportStatus = "conflict"
+	ImportUnmatched ImportStatus = "unmatched"
+	ImportFailed ImportStatus = "failed"
+	ImportSkipped ImportStatus = "skipped"
+)
+
+type ImportCalendar struct {
+	UserId int
+	// WorkspaceId limits the subjects the events are matched against
+	WorkspaceId int
+	Calendar ical.Calendar
+	// DryRun checks every event without reserving anything
+	DryRun bool
+}
+
+type ImportEntry struct {
+	Summary string
+	Subject string
+	From time.Time
+	To time.Time
+	Status ImportStatus
+	// ReservationId is set for created entries unless it is a dry run
+	ReservationId int
+	Reason string
+}
+
+type ImportReport struct {
+	DryRun bool
+	Entries []ImportEntry
+}
+
+func (r ImportReport) Count(status ImportStatus) int {
+	count := 0
+	for _, entry := range r.Entries {
+		if entry.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (r ImportReport) String() string {
+	var sb strings.Builder
+
+	created := "Created"
+	if r.DryRun {
+		created = "Would create"
+	}
+	fmt.Fprintf(
+		&sb,
+		"%s: %d, conflicts: %d, unmatched: %d, failed: %d, skipped: %d\n",
+		created,
+		r.Count(ImportCreated),
+		r.Count(ImportConflict),
+		r.Count(ImportUnmatched),
+		r.Count(ImportFailed),
+		r.Count(ImportSkipped),
+	)
+	for _, entry := range r.Entries {
+		name := entry.Subject
+		if name == "" {
+			name = entry.Summary
+		}
+		status := string(entry.Status)
+		if r.DryRun && entry.Status == ImportCreated {
+			status = "would create"
+		}
+		fmt.Fprintf(&sb, "%s\t%s\t%s - %s", status, name, entry.From.Format(time.DateTime), entry.To.Format(time.DateTime))
+		if entry.Reason != "" {
+			sb.WriteString(": " + entry.Reason)
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// CalendarImportService books the events of a calendar, matching subjects by event location or summary
+type CalendarImportService struct {
+	subjectsStore reservationsPort.SubjectsRepository
+	reservationService *ReservationService
+}
+
+func NewCalendarImportService(subjectsStore reservationsPort.SubjectsRepository, reservationService *ReservationService) *CalendarImportService {
+	return &CalendarImportService{subjectsStore: subjectsStore, reservationService: reservationService}
+}
+
+// Import creates a reservation per event, one at a time, so conflicting events do not prevent the others.
+// A dry run also reports events overlapping earlier events of the same subject in the calendar
+func (s *CalendarImportService) Import(ctx context.Context, cmd ImportCalendar) (ImportReport, error) {
+	report := ImportReport{DryRun: cmd.DryRun}
+	if len(cmd.Calendar.Events) == 0 {
+		return report, i18n.Errorf("import.no_events")
+	}
+
+	subjects, err := s.subjectsStore.InWorkspace(ctx, cmd.WorkspaceId)
+	if err != nil {
+		return report, err
+	}
+
+	planned := make(map[int][]reservations.Interval)
+	for _, event := range cmd.Calendar.Events {
+		entry := ImportEntry{Summary: event.Summary, From: event.Start, To: event.End}
+
+		subject, found := matchSubject(subjects, event)
+		switch {
+		case event.Status == ical.StatusCancelled:
+			entry.Status, entry.Reason = ImportSkipped, "Event is cancelled"
+		case event.RRule != "":
+			entry.Status, entry.Reason = ImportFailed, "Recurring events are not supported"
+		case !found:
+			entry.Status, entry.Reason = ImportUnmatched, "No subject matches the location or summary"
+		}
+		if entry.Status != "" {
+			report.Entries = append(report.Entries, entry)
+			continue
+		}
+		entry.Subject = subject.Name
+
+		reservation := CreateReservation{SubjectId: subject.Id, UserId: cmd.UserId, From: event.Start, To: event.End}
+		if cmd.DryRun {
+			err = s.reservationService.Check(ctx, reservation)
+			interval := reservations.NewInterval(event.Start, event.End)
+			for _, other := range planned[subject.Id] {
+				if err == nil && other.Overlaps(interval) {
+					err = i18n.Errorf("import.overlap")
+				}
+			}
+			if err == nil {
+				planned[subject.Id] = append(planned[subject.Id], interval)
+			}
+		} else {
+			var created reservations.Reservation
+			created, err = s.reservationService.Create(ctx, reservation)
+			entry.ReservationId = created.Id
+		}
+
+		var conflict AlreadyReservedError
+		switch {
+		case err == nil:
+			entry.Status = ImportCreated
+		case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+			return report, err
+		case errors.As(err, &conflict):
+			entry.Status, entry.Reason = ImportConflict, fmt.Sprintf("Conflicts with reservations %v", conflict.ReservationIds)
+		default:
+			entry.Status, entry.Reason = ImportFailed, err.Error()
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+
+	return report, nil
+}
+
+// candidates are the subject names an event may refer to, exported feeds keep the subject in the location
+func candidates(event ical.Event) []string {
+	var names []string
+	for _, name := range []string{event.Location, event.Summary} {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	if subject, _, found := strings.Cut(event.Summary, " reserved by "); found {
+		names = append(names, strings.TrimSpace(subject))
+	}
+
+	return names
+}
+
+func matchSubject(subjects reservations.Subjects, event ical.Event) (reservations.Subject, bool) {
+	for _, name := range candidates(event) {
+		for _, subject := range subjects {
+			if subject.Name == name {
+				return subject, true
+			}
+		}
+		for _, subject := range subjects {
+			if strings.EqualFold(subject.Name, name) {
+				return subject, true
+			}
+		}
+	}
+
+	return reservations.Subject{}, false
+}
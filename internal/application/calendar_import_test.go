@@ -0,0 +1,80 @@
+package application_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/ical"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestImportCalendar(t *testing.T) {
+	reservationService := getSUT()
+	importer := application.NewCalendarImportService(subjectsStore, reservationService)
+	subjects := createTestSubjects(subjectsStore, t)
+	users := createTestUsers(usersStore, t)
+	at := func(hours int) time.Time {
+		return clock.Current().Add(time.Duration(hours) * time.Hour)
+	}
+	existing := createReservation(t, subjects[1].Id, users[1].Id, at(5), at(6))
+	calendar := ical.Calendar{Events: []ical.Event{
+		{Summary: "Morning session", Location: subjects[0].Name, Start: at(1), End: at(2)},
+		{Summary: "subject#2", Start: at(5), End: at(7)},
+		{Summary: subjects[0].Name + " reserved by Test 2", Start: at(1), End: at(3)},
+		{Summary: "Team sync", Location: "Room 5", Start: at(1), End: at(2)},
+		{Summary: subjects[1].Name, Start: at(-3), End: at(-2)},
+		{Summary: subjects[1].Name, Start: at(8), End: at(9), Status: ical.StatusCancelled},
+		{Summary: subjects[1].Name, Start: at(8), End: at(9), RRule: "FREQ=DAILY"},
+	}}
+	statuses := func(report application.ImportReport) []application.ImportStatus {
+		var result []application.ImportStatus
+		for _, entry := range report.Entries {
+			result = append(result, entry.Status)
+		}
+		return result
+	}
+
+	t.Run("it previews the import without reserving anything", func(t *testing.T) {
+		report, err := importer.Import(ctx, application.ImportCalendar{UserId: users[0].Id, WorkspaceId: workspaces.Default, Calendar: calendar, DryRun: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []application.ImportStatus{
+			application.ImportCreated,
+			application.ImportConflict,
+			application.ImportFailed,
+			application.ImportUnmatched,
+			application.ImportFailed,
+			application.ImportSkipped,
+			application.ImportFailed,
+		}, statuses(report))
+		assert.Equal(t, subjects[0].Name, report.Entries[0].Subject)
+		assert.Equal(t, "Overlaps another event of the calendar", report.Entries[2].Reason)
+		rs, err := reservationsStore.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(rs))
+	})
+
+	t.Run("it reserves matched events and reports conflicts", func(t *testing.T) {
+		report, err := importer.Import(ctx, application.ImportCalendar{UserId: users[0].Id, WorkspaceId: workspaces.Default, Calendar: calendar})
+
+		assert.NoError(t, err)
+		assert.Equal(t, application.ImportCreated, report.Entries[0].Status)
+		assert.Equal(t, application.ImportConflict, report.Entries[1].Status)
+		assert.Equal(t, application.ImportConflict, report.Entries[2].Status)
+		assert.Equal(t, 1, report.Count(application.ImportCreated))
+		created, err := reservationsStore.Get(ctx, report.Entries[0].ReservationId)
+		assert.NoError(t, err)
+		assert.Equal(t, users[0].Id, created.UserId)
+		assert.Equal(t, subjects[0].Id, created.SubjectId)
+		assert.Contains(t, report.Entries[1].Reason, fmt.Sprintf("reservations [%d]", existing.Id))
+	})
+
+	t.Run("it returns an error given the calendar has no events", func(t *testing.T) {
+		_, err := importer.Import(ctx, application.ImportCalendar{UserId: users[0].Id})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"context"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	ports "github.com/SneedusSnake/Reservations/internal/ports/users"
+)
+
+type CreateChatUser struct {
+	// Id is the id of the account on the chat platform
+	Id string
+	Name string
+}
+
+// ChatUserService links the accounts of a chat platform to users, see ports.ChatUsersRepository
+type ChatUserService struct {
+	store ports.ChatUsersRepository
+	userService *UserService
+}
+
+func NewChatUserService(store ports.ChatUsersRepository, userService *UserService) *ChatUserService {
+	return &ChatUserService{store: store, userService: userService}
+}
+
+func (s *ChatUserService) Get(ctx context.Context, id string) (users.ChatUser, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *ChatUserService) Create(ctx context.Context, cmd CreateChatUser) (users.ChatUser, error) {
+	user, err := s.userService.Create(ctx, CreateUser{Name: cmd.Name})
+	if err != nil {
+		return users.ChatUser{}, err
+	}
+
+	chatUser := users.ChatUser{ExternalId: cmd.Id, User: user}
+	if err = s.store.Add(ctx, chatUser); err != nil {
+		return users.ChatUser{}, err
+	}
+
+	return chatUser, nil
+}
+
+// GetOrCreate returns the user linked to the account, linking a new one on first use
+func (s *ChatUserService) GetOrCreate(ctx context.Context, cmd CreateChatUser) (users.ChatUser, error) {
+	user, err := s.store.Get(ctx, cmd.Id)
+	if err == nil {
+		return user, nil
+	}
+
+	return s.Create(ctx, cmd)
+}
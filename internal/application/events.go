@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/ports"
+)
+
+const (
+	EventReservationCreated = "reservation.created"
+	EventReservationRemoved = "reservation.removed"
+	EventSubjectCreated = "subject.created"
+	EventSubjectTagsAdded = "subject.tags_added"
+	EventComponentAdded = "subject.component_added"
+	EventComponentRemoved = "subject.component_removed"
+)
+
+var EventTypes = []string{
+	EventReservationCreated,
+	EventReservationRemoved,
+	EventSubjectCreated,
+	EventSubjectTagsAdded,
+	EventComponentAdded,
+	EventComponentRemoved,
+}
+
+type ReservationEvent struct {
+	Id int `json:"id"`
+	SubjectId int `json:"subject_id"`
+	Subject string `json:"subject"`
+	UserId int `json:"user_id"`
+	Start time.Time `json:"start"`
+	End time.Time `json:"end"`
+}
+
+type SubjectEvent struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+	ParentId int `json:"parent_id,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	WorkspaceId int `json:"workspace_id,omitempty"`
+}
+
+// IsEventFilter reports whether the filter is an event type, a category like "reservation.*" or "*"
+func IsEventFilter(filter string) bool {
+	if filter == "*" || slices.Contains(EventTypes, filter) {
+		return true
+	}
+	category, found := strings.CutSuffix(filter, ".*")
+
+	return found && slices.ContainsFunc(EventTypes, func(event string) bool {
+		return strings.HasPrefix(event, category + ".")
+	})
+}
+
+// publish tolerates services created without a publisher
+func publish(ctx context.Context, publisher ports.EventPublisher, event ports.Event) {
+	if publisher != nil {
+		publisher.Publish(ctx, event)
+	}
+}
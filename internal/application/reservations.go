@@ -1,16 +1,19 @@
 package application
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"sync"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
 	"github.com/SneedusSnake/Reservations/internal/ports"
 	reservationsPort "github.com/SneedusSnake/Reservations/internal/ports/reservations"
 	usersPort "github.com/SneedusSnake/Reservations/internal/ports/users"
 	readmodel "github.com/SneedusSnake/Reservations/internal/read_model"
+	"github.com/SneedusSnake/Reservations/internal/utils"
 )
 
 type CreateReservation struct {
@@ -20,7 +23,15 @@ type CreateReservation struct {
 	To time.Time
 }
 
+type CreateReservations struct {
+	SubjectIds []int
+	UserId int
+	From time.Time
+	To time.Time
+}
+
 type AlreadyReservedError struct {
+	SubjectId int
 	ReservationIds []int
 }
 
@@ -28,13 +39,27 @@ func (e AlreadyReservedError) Error() string {
 	return string(fmt.Sprintf("Unable to create reservation: conflict with reservations {IDs: %v}", e.ReservationIds))
 }
 
+type BatchReservationError struct {
+	Conflicts []AlreadyReservedError
+}
+
+func (e BatchReservationError) Error() string {
+	var messages []string
+	for _, conflict := range e.Conflicts {
+		messages = append(messages, fmt.Sprintf("subject %d: %s", conflict.SubjectId, conflict.Error()))
+	}
+
+	return fmt.Sprintf("Unable to create reservations: %s", strings.Join(messages, "; "))
+}
+
 type ReservationService struct {
 	subjectsStore reservationsPort.SubjectsRepository
 	reservationsStore reservationsPort.ReservationsRepository
 	reservationsReadStore reservationsPort.ReservationsReadRepository
 	usersStore usersPort.UsersRepository
+	unitOfWork reservationsPort.UnitOfWork
 	clock ports.Clock
-	mu sync.Mutex
+	events ports.EventPublisher
 }
 
 func NewReservationService(
@@ -42,69 +67,206 @@ func NewReservationService(
 	registry reservationsPort.ReservationsRepository,
 	reservationsReadStore reservationsPort.ReservationsReadRepository,
 	usersStore usersPort.UsersRepository,
+	unitOfWork reservationsPort.UnitOfWork,
 	clock ports.Clock,
+	events ports.EventPublisher,
 ) *ReservationService {
 	return &ReservationService{
 		subjectsStore: subjStore,
 		reservationsStore: registry,
 		reservationsReadStore: reservationsReadStore,
 		usersStore: usersStore,
+		unitOfWork: unitOfWork,
 		clock: clock,
+		events: events,
+	}
+}
+
+func (s *ReservationService) Create(ctx context.Context, cmd CreateReservation) (reservations.Reservation, error) {
+	created, err := s.CreateBatch(ctx, CreateReservations{
+		SubjectIds: []int{cmd.SubjectId},
+		UserId: cmd.UserId,
+		From: cmd.From,
+		To: cmd.To,
+	})
+
+	if err != nil {
+		if batchErr, ok := err.(BatchReservationError); ok {
+			return reservations.Reservation{}, batchErr.Conflicts[0]
+		}
+		return reservations.Reservation{}, err
 	}
+
+	return created[0], nil
 }
 
-func (s *ReservationService) Create(cmd CreateReservation) (reservations.Reservation, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// Check returns the error Create would fail with, without reserving anything
+func (s *ReservationService) Check(ctx context.Context, cmd CreateReservation) error {
+	_, err := s.createBatch(ctx, CreateReservations{
+		SubjectIds: []int{cmd.SubjectId},
+		UserId: cmd.UserId,
+		From: cmd.From,
+		To: cmd.To,
+	}, true)
+
+	if batchErr, ok := err.(BatchReservationError); ok {
+		return batchErr.Conflicts[0]
+	}
+
+	return err
+}
+
+// CreateBatch reserves all given subjects for the same period. Either every subject gets reserved,
+// or none of them and BatchReservationError lists the conflicts of each unavailable subject
+func (s *ReservationService) CreateBatch(ctx context.Context, cmd CreateReservations) (reservations.Reservations, error) {
+	return s.createBatch(ctx, cmd, false)
+}
+
+func (s *ReservationService) createBatch(ctx context.Context, cmd CreateReservations, dryRun bool) (reservations.Reservations, error) {
+	subjectIds := utils.Unique(cmd.SubjectIds)
+
+	if len(subjectIds) == 0 {
+		return nil, i18n.Errorf("reservation.no_subjects")
+	}
+
+	if reservations.NewInterval(cmd.From, cmd.To).IsEmpty() {
+		return nil, i18n.Errorf("reservation.ends_before_start")
+	}
+
 	if s.clock.Current().After(cmd.From.Add(time.Minute)) {
-		return reservations.Reservation{}, errors.New("Attempt to make a reservation in the past")
+		return nil, i18n.Errorf("reservation.in_past")
 	}
 
-	_, err := s.usersStore.Get(cmd.UserId)
+	_, err := s.usersStore.Get(ctx, cmd.UserId)
 
 	if err != nil {
-		return reservations.Reservation{}, err
+		return nil, err
 	}
 
-	_, err = s.subjectsStore.Get(cmd.SubjectId)
+	hierarchies := make(map[int][]int)
+	for _, subjectId := range subjectIds {
+		hierarchy, err := s.hierarchy(ctx, subjectId)
+		if err != nil {
+			return nil, err
+		}
+
+		for otherId, other := range hierarchies {
+			if len(utils.Intersect(hierarchy, other)) > 0 {
+				return nil, i18n.Errorf("reservation.same_subject", otherId, subjectId)
+			}
+		}
+		hierarchies[subjectId] = hierarchy
+	}
+
+	var locked []int
+	for _, hierarchy := range hierarchies {
+		locked = append(locked, hierarchy...)
+	}
+
+	var created reservations.Reservations
+	err = s.unitOfWork.Execute(ctx, locked, func(store reservationsPort.ReservationsRepository) error {
+		activeReservations, err := store.ForPeriod(ctx, cmd.From, cmd.To)
+
+		if err != nil {
+			return err
+		}
+
+		var conflicts []AlreadyReservedError
+		for _, subjectId := range subjectIds {
+			subjectReservations := activeReservations.ForSubjects(hierarchies[subjectId]...)
+
+			if len(subjectReservations) > 0 {
+				var ids []int
+				for _, r := range subjectReservations {
+					ids = append(ids, r.Id)
+				}
+				conflicts = append(conflicts, AlreadyReservedError{SubjectId: subjectId, ReservationIds: ids})
+			}
+		}
+
+		if len(conflicts) > 0 {
+			return BatchReservationError{Conflicts: conflicts}
+		}
+		if dryRun {
+			return nil
+		}
+
+		for _, subjectId := range subjectIds {
+			id, err := store.NextIdentity(ctx)
+			if err != nil {
+				return err
+			}
+
+			created = append(created, reservations.Reservation{
+				Id: id,
+				UserId: cmd.UserId,
+				SubjectId: subjectId,
+				Start: cmd.From,
+				End: cmd.To,
+			})
+		}
+
+		return store.AddMany(ctx, created)
+	})
 
 	if err != nil {
-		return reservations.Reservation{}, err
+		return nil, err
+	}
+	for _, r := range created {
+		s.publishReservation(ctx, EventReservationCreated, r)
 	}
 
-	activeReservations, err := s.reservationsStore.ForPeriod(cmd.From, cmd.To)
+	return created, nil
+}
+
+func (s *ReservationService) publishReservation(ctx context.Context, eventType string, r reservations.Reservation) {
+	subject, _ := s.subjectsStore.Get(ctx, r.SubjectId)
+	publish(ctx, s.events, ports.Event{
+		Type: eventType,
+		OccurredAt: s.clock.Current(),
+		Data: ReservationEvent{Id: r.Id, SubjectId: r.SubjectId, Subject: subject.Name, UserId: r.UserId, Start: r.Start, End: r.End},
+	})
+}
 
+// hierarchy returns ids of the subject, all of its ancestors and all of its components,
+// since a reservation of any of them makes the subject unavailable
+func (s *ReservationService) hierarchy(ctx context.Context, subjectId int) ([]int, error) {
+	subject, err := s.subjectsStore.Get(ctx, subjectId)
 	if err != nil {
-		return reservations.Reservation{}, err
+		return nil, err
 	}
-	subjectReservations := activeReservations.ForSubject(cmd.SubjectId)
+	ids := []int{subject.Id}
 
-	if len(subjectReservations) > 0 {
-		var ids []int
-		for _, r := range subjectReservations {
-			ids = append(ids, r.Id)
+	for parentId := subject.ParentId; parentId != 0 && !slices.Contains(ids, parentId); {
+		parent, err := s.subjectsStore.Get(ctx, parentId)
+		if err != nil {
+			return nil, err
 		}
-		return reservations.Reservation{}, AlreadyReservedError{ReservationIds: ids}
-	}
-	id, err := s.reservationsStore.NextIdentity()
-	if err != nil {
-		return reservations.Reservation{}, err
+		ids = append(ids, parent.Id)
+		parentId = parent.ParentId
 	}
 
-	reservation := reservations.Reservation{
-		Id: id,
-		UserId: cmd.UserId,
-		SubjectId: cmd.SubjectId,
-		Start: cmd.From,
-		End: cmd.To,
+	queue := []int{subject.Id}
+	for len(queue) > 0 {
+		children, err := s.subjectsStore.Children(ctx, queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+
+		for _, child := range children {
+			if !slices.Contains(ids, child.Id) {
+				ids = append(ids, child.Id)
+				queue = append(queue, child.Id)
+			}
+		}
 	}
-	s.reservationsStore.Add(reservation)
 
-	return reservation, nil
+	return ids, nil
 }
 
-func (s *ReservationService) Get(id int) (reservations.Reservation, error) {
-	return s.reservationsStore.Get(id)
+func (s *ReservationService) Get(ctx context.Context, id int) (reservations.Reservation, error) {
+	return s.reservationsStore.Get(ctx, id)
 }
 
 type RemoveReservations struct {
@@ -112,9 +274,9 @@ type RemoveReservations struct {
 	SubjectId int
 }
 
-func (s *ReservationService) Remove(cmd RemoveReservations) error {
+func (s *ReservationService) Remove(ctx context.Context, cmd RemoveReservations) error {
 	//checking reservations for a year in advance will suffice for now
-	activeReservations, err := s.reservationsStore.ForPeriod(s.clock.Current(), s.clock.Current().Add(time.Hour*8760))
+	activeReservations, err := s.reservationsStore.ForPeriod(ctx, s.clock.Current(), s.clock.Current().Add(time.Hour*8760))
 	if err != nil {
 		return err
 	}
@@ -122,16 +284,18 @@ func (s *ReservationService) Remove(cmd RemoveReservations) error {
 	subjReservations := activeReservations.ForUser(cmd.UserId).ForSubject(cmd.SubjectId)
 
 	if len(subjReservations) == 0 {
-		return errors.New("No active reservations found")
+		return i18n.Errorf("reservation.none_active")
 	}
 
 	for _, r := range subjReservations {
-		s.reservationsStore.Remove(r.Id)
+		if err = s.reservationsStore.Remove(ctx, r.Id); err == nil {
+			s.publishReservation(ctx, EventReservationRemoved, r)
+		}
 	}
 
 	return nil
 }
 
-func (s *ReservationService) ActiveReservations(t time.Time, tags ...string) ([]readmodel.Reservation, error) {
-	return s.reservationsReadStore.Active(t, tags...)
+func (s *ReservationService) ActiveReservations(ctx context.Context, workspaceId int, t time.Time, tags ...string) ([]readmodel.Reservation, error) {
+	return s.reservationsReadStore.Active(ctx, workspaceId, t, tags...)
 }
@@ -1,6 +1,7 @@
 package application_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -8,6 +9,8 @@ import (
 	"github.com/SneedusSnake/Reservations/internal/application"
 	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
 	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/ports"
 	reservationsPort "github.com/SneedusSnake/Reservations/internal/ports/reservations"
 	"github.com/alecthomas/assert/v2"
 )
@@ -28,10 +31,20 @@ func (c *FakeClock) TimeTravel(minutes int) time.Time {
 	return c.Current().Add(time.Minute*time.Duration(minutes))
 }
 
+type FakePublisher struct {
+	events []ports.Event
+}
+
+func (p *FakePublisher) Publish(ctx context.Context, event ports.Event) {
+	p.events = append(p.events, event)
+}
+
+var ctx = context.Background()
 var subjectsStore *inmemory.SubjectsStore
 var reservationsStore *inmemory.ReservationsStore
 var usersStore *inmemory.UsersStore
 var clock *FakeClock
+var publisher *FakePublisher
 
 func TestCreateReservation(t *testing.T) {
 	handler := getSUT()
@@ -45,7 +58,7 @@ func TestCreateReservation(t *testing.T) {
 	t.Run("it returns an error if subject does not exist", func(t *testing.T) {
 		cmd := application.CreateReservation{1234, users[0].Id, futurePeriod[0], futurePeriod[1]}
 
-		_, err := handler.Create(cmd)
+		_, err := handler.Create(ctx, cmd)
 
 		assert.Error(t, err)
 	})
@@ -53,7 +66,7 @@ func TestCreateReservation(t *testing.T) {
 	t.Run("it returns an error if user does not exist", func(t *testing.T) {
 		cmd := application.CreateReservation{subjects[0].Id, 1234, futurePeriod[0], futurePeriod[1]}
 
-		_, err := handler.Create(cmd)
+		_, err := handler.Create(ctx, cmd)
 
 		assert.Error(t, err)
 	})
@@ -66,7 +79,7 @@ func TestCreateReservation(t *testing.T) {
 		cmd := application.CreateReservation{subjects[0].Id, users[0].Id, futurePeriod[0], futurePeriod[1]}
 		clock.Set(cmd.From.Add(time.Minute * 2))
 
-		_, err := handler.Create(cmd)
+		_, err := handler.Create(ctx, cmd)
 
 		assert.Error(t, err)
 	})
@@ -76,9 +89,9 @@ func TestCreateReservation(t *testing.T) {
 		subject := subjects[0]
 		cmd := application.CreateReservation{subject.Id, user.Id, futurePeriod[0], futurePeriod[1]}
 
-		result, err := handler.Create(cmd)
+		result, err := handler.Create(ctx, cmd)
 		assert.NoError(t, err)
-		r, err := reservationsStore.Get(result.Id)
+		r, err := reservationsStore.Get(ctx, result.Id)
 
 		assert.NoError(t, err)
 		assert.True(t, result.SubjectId == subject.Id)
@@ -87,7 +100,7 @@ func TestCreateReservation(t *testing.T) {
 		assert.True(t, result.End.Equal(futurePeriod[1]))
 		assert.Equal(t, result, r)
 		t.Cleanup(func() {
-			reservationsStore.Remove(result.Id)
+			reservationsStore.Remove(ctx, result.Id)
 		})
 	})
 
@@ -98,21 +111,182 @@ func TestCreateReservation(t *testing.T) {
 		r2 := createReservation(t, subject.Id, users[2].Id, futurePeriod[1].Add(-time.Minute*4), futurePeriod[1].Add(time.Minute))
 		cmd := application.CreateReservation{subject.Id, user.Id, futurePeriod[0], futurePeriod[1]}
 
-		_, err := handler.Create(cmd)
+		_, err := handler.Create(ctx, cmd)
 
 		assertAlreadyReservedError(t, err, []int{r1.Id, r2.Id})
 	})
 
+	t.Run("it returns an error given the reservation does not end after it starts", func(t *testing.T) {
+		cmd := application.CreateReservation{subjects[0].Id, users[0].Id, futurePeriod[1], futurePeriod[0]}
+		_, err := handler.Create(ctx, cmd)
+		assert.Error(t, err)
+
+		cmd = application.CreateReservation{subjects[0].Id, users[0].Id, futurePeriod[0], futurePeriod[0]}
+		_, err = handler.Create(ctx, cmd)
+		assert.Error(t, err)
+	})
+
+	t.Run("it can create back-to-back reservations", func(t *testing.T) {
+		createReservation(t, subjects[0].Id, users[1].Id, futurePeriod[0].Add(-time.Hour), futurePeriod[0])
+		createReservation(t, subjects[0].Id, users[2].Id, futurePeriod[1], futurePeriod[1].Add(time.Hour))
+
+		cmd := application.CreateReservation{subjects[0].Id, users[0].Id, futurePeriod[0], futurePeriod[1]}
+		reservation, err := handler.Create(ctx, cmd)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			reservationsStore.Remove(ctx, reservation.Id)
+		})
+	})
+
 	t.Run("it can create a reservation, given the conflicting reservation belongs to another subject", func(t *testing.T) {
 		user := users[0]
 		subject := subjects[0]
 		createReservation(t, subjects[1].Id, users[1].Id, futurePeriod[0].Add(time.Minute), futurePeriod[1].Add(time.Minute))
 
 		cmd := application.CreateReservation{subject.Id, user.Id, futurePeriod[0], futurePeriod[1]}
-		reservation, err := handler.Create(cmd)
+		reservation, err := handler.Create(ctx, cmd)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			reservationsStore.Remove(ctx, reservation.Id)
+		})
+	})
+}
+
+func TestCreateReservationConcurrently(t *testing.T) {
+	handler := getSUT()
+	subjects := createTestSubjects(subjectsStore, t)
+	users := createTestUsers(usersStore, t)
+	errs := make(chan error, 20)
+
+	for i := range 20 {
+		go func() {
+			cmd := application.CreateReservation{subjects[0].Id, users[i%len(users)].Id, clock.TimeTravel(60), clock.TimeTravel(120)}
+			_, err := handler.Create(ctx, cmd)
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for range 20 {
+		if err := <-errs; err == nil {
+			succeeded++
+		}
+	}
+
+	assert.Equal(t, 1, succeeded)
+	rs, err := reservationsStore.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rs))
+}
+
+func TestCreateBatchReservation(t *testing.T) {
+	handler := getSUT()
+	subjects := createTestSubjects(subjectsStore, t)
+	users := createTestUsers(usersStore, t)
+	third := reservations.Subject{Id: 3, Name: "Subject#3", WorkspaceId: workspaces.Default}
+	assert.NoError(t, subjectsStore.Add(ctx, third))
+	from := clock.TimeTravel(60)
+	to := clock.TimeTravel(120)
+
+	t.Run("it reserves all given subjects for the same period", func(t *testing.T) {
+		cmd := application.CreateReservations{SubjectIds: []int{subjects[0].Id, subjects[1].Id}, UserId: users[0].Id, From: from, To: to}
+
+		result, err := handler.CreateBatch(ctx, cmd)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			for _, r := range result {
+				reservationsStore.Remove(ctx, r.Id)
+			}
+		})
+
+		assert.Equal(t, 2, len(result))
+		for index, r := range result {
+			stored, err := reservationsStore.Get(ctx, r.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, r, stored)
+			assert.Equal(t, cmd.SubjectIds[index], r.SubjectId)
+			assert.Equal(t, users[0].Id, r.UserId)
+		}
+	})
+
+	t.Run("it reserves nothing and reports every conflict, given some subjects are unavailable", func(t *testing.T) {
+		r1 := createReservation(t, subjects[0].Id, users[1].Id, from, to)
+		r2 := createReservation(t, third.Id, users[2].Id, from, to)
+		cmd := application.CreateReservations{SubjectIds: []int{subjects[0].Id, subjects[1].Id, third.Id}, UserId: users[0].Id, From: from, To: to}
+
+		_, err := handler.CreateBatch(ctx, cmd)
+		assert.Error(t, err)
+		batchErr, ok := err.(application.BatchReservationError)
+		assert.True(t, ok)
+		assert.Equal(t, []application.AlreadyReservedError{
+			{SubjectId: subjects[0].Id, ReservationIds: []int{r1.Id}},
+			{SubjectId: third.Id, ReservationIds: []int{r2.Id}},
+		}, batchErr.Conflicts)
+
+		rs, err := reservationsStore.List(ctx)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, len(rs))
+	})
+
+	t.Run("it returns an error given no subjects", func(t *testing.T) {
+		_, err := handler.CreateBatch(ctx, application.CreateReservations{UserId: users[0].Id, From: from, To: to})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("it returns an error given subjects belong to the same hierarchy", func(t *testing.T) {
+		assert.NoError(t, subjectsStore.SetParent(ctx, third.Id, subjects[0].Id))
 		t.Cleanup(func() {
-			reservationsStore.Remove(reservation.Id)
+			subjectsStore.SetParent(ctx, third.Id, 0)
+		})
+		cmd := application.CreateReservations{SubjectIds: []int{subjects[0].Id, third.Id}, UserId: users[0].Id, From: from, To: to}
+
+		_, err := handler.CreateBatch(ctx, cmd)
+		assert.Error(t, err)
+
+		rs, err := reservationsStore.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(rs))
+	})
+}
+
+func TestCreateReservationForSubjectHierarchy(t *testing.T) {
+	handler := getSUT()
+	users := createTestUsers(usersStore, t)
+	bench := reservations.Subject{Id: 1, Name: "Bench"}
+	phone := reservations.Subject{Id: 2, Name: "Phone", ParentId: bench.Id}
+	sim := reservations.Subject{Id: 3, Name: "SIM card", ParentId: phone.Id}
+	hub := reservations.Subject{Id: 4, Name: "USB hub", ParentId: bench.Id}
+	for _, s := range []reservations.Subject{bench, phone, sim, hub} {
+		assert.NoError(t, subjectsStore.Add(ctx, s))
+	}
+	from := clock.TimeTravel(60)
+	to := clock.TimeTravel(120)
+
+	t.Run("it cannot reserve a component, given its parent is reserved", func(t *testing.T) {
+		r := createReservation(t, bench.Id, users[1].Id, from, to)
+
+		_, err := handler.Create(ctx, application.CreateReservation{phone.Id, users[0].Id, from, to})
+		assertAlreadyReservedError(t, err, []int{r.Id})
+
+		_, err = handler.Create(ctx, application.CreateReservation{sim.Id, users[0].Id, from, to})
+		assertAlreadyReservedError(t, err, []int{r.Id})
+	})
+
+	t.Run("it cannot reserve a parent, given any of its components is reserved", func(t *testing.T) {
+		r := createReservation(t, sim.Id, users[1].Id, from, to)
+
+		_, err := handler.Create(ctx, application.CreateReservation{bench.Id, users[0].Id, from, to})
+		assertAlreadyReservedError(t, err, []int{r.Id})
+	})
+
+	t.Run("it can reserve a sibling component, given another component is reserved", func(t *testing.T) {
+		createReservation(t, phone.Id, users[1].Id, from, to)
+
+		reservation, err := handler.Create(ctx, application.CreateReservation{hub.Id, users[0].Id, from, to})
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			reservationsStore.Remove(ctx, reservation.Id)
 		})
 	})
 }
@@ -125,7 +299,7 @@ func TestRemoveReservation(t *testing.T) {
 	t.Run("it returns error if no reservation for subject exists", func(t *testing.T) {
 		cmd := application.RemoveReservations{users[0].Id, subjects[0].Id}
 
-		err := handler.Remove(cmd)
+		err := handler.Remove(ctx, cmd)
 
 		assert.Error(t, err)
 	})
@@ -135,10 +309,10 @@ func TestRemoveReservation(t *testing.T) {
 		createReservation(t, subjects[0].Id, users[0].Id, clock.TimeTravel(5), clock.TimeTravel(10))
 		cmd := application.RemoveReservations{users[0].Id, subjects[0].Id}
 
-		err := handler.Remove(cmd)
+		err := handler.Remove(ctx, cmd)
 		assert.NoError(t, err)
 		
-		rs, err := reservationsStore.List()
+		rs, err := reservationsStore.List(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, 0, len(rs))
 	})
@@ -148,10 +322,10 @@ func TestRemoveReservation(t *testing.T) {
 		createReservation(t, subjects[0].Id, users[0].Id, clock.TimeTravel(-10), clock.TimeTravel(-5))
 		cmd := application.RemoveReservations{users[0].Id, subjects[0].Id}
 
-		err := handler.Remove(cmd)
+		err := handler.Remove(ctx, cmd)
 		assert.Error(t, err)
 
-		rs, err := reservationsStore.List()
+		rs, err := reservationsStore.List(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, 2, len(rs))
 	})
@@ -160,10 +334,10 @@ func TestRemoveReservation(t *testing.T) {
 		createReservation(t, subjects[0].Id, users[1].Id, clock.TimeTravel(5), clock.TimeTravel(10))
 		cmd := application.RemoveReservations{users[0].Id, subjects[0].Id}
 
-		err := handler.Remove(cmd)
+		err := handler.Remove(ctx, cmd)
 		assert.Error(t, err)
 
-		rs, err := reservationsStore.List()
+		rs, err := reservationsStore.List(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(rs))
 	})
@@ -172,10 +346,10 @@ func TestRemoveReservation(t *testing.T) {
 		createReservation(t, subjects[1].Id, users[0].Id, clock.TimeTravel(5), clock.TimeTravel(10))
 		cmd := application.RemoveReservations{users[0].Id, subjects[0].Id}
 
-		err := handler.Remove(cmd)
+		err := handler.Remove(ctx, cmd)
 		assert.Error(t, err)
 
-		rs, err := reservationsStore.List()
+		rs, err := reservationsStore.List(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(rs))
 	})
@@ -187,23 +361,26 @@ func getSUT() *application.ReservationService {
 	reservationsStore = inmemory.NewReservationStore()
 	clock = &FakeClock{}
 	clock.Set(time.Now())
+	publisher = &FakePublisher{}
 	return application.NewReservationService(
 		subjectsStore,
 		reservationsStore,
 		inmemory.NewReservationReadStore(reservationsStore, usersStore, subjectsStore),
 		usersStore,
+		inmemory.NewUnitOfWork(reservationsStore),
 		clock,
+		publisher,
 	)
 }
 
 func createTestSubjects(store reservationsPort.SubjectsRepository, t *testing.T) reservations.Subjects {
 	subjects := reservations.Subjects{
-		reservations.Subject{Id: 1, Name: "Subject#1"},
-		reservations.Subject{Id: 2, Name: "Subject#2"},
+		reservations.Subject{Id: 1, Name: "Subject#1", WorkspaceId: workspaces.Default},
+		reservations.Subject{Id: 2, Name: "Subject#2", WorkspaceId: workspaces.Default},
 	}
 
 	for _, s := range subjects {
-		err := store.Add(s)
+		err := store.Add(ctx, s)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -220,7 +397,7 @@ func createTestUsers(store users.UsersStore, t *testing.T) []users.User {
 	}
 
 	for _, u := range users {
-		err := store.Add(u)
+		err := store.Add(ctx, u)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -230,7 +407,7 @@ func createTestUsers(store users.UsersStore, t *testing.T) []users.User {
 }
 
 func createReservation(t *testing.T, subjectId int, userId int, start time.Time, end time.Time) reservations.Reservation {
-	id, err := reservationsStore.NextIdentity()
+	id, err := reservationsStore.NextIdentity(ctx)
 	assert.NoError(t, err)
 
 	r := reservations.Reservation{
@@ -240,11 +417,11 @@ func createReservation(t *testing.T, subjectId int, userId int, start time.Time,
 		Start:     start,
 		End:       end,
 	}
-	err = reservationsStore.Add(r)
+	err = reservationsStore.Add(ctx, r)
 	assert.NoError(t, err)
 
 	t.Cleanup(func() {
-		reservationsStore.Remove(r.Id)
+		reservationsStore.Remove(ctx, r.Id)
 	})
 
 	return r
@@ -256,3 +433,29 @@ func assertAlreadyReservedError(t *testing.T, err error, reservationIds []int) {
 	assert.True(t, ok)
 	assert.Equal(t, reservationIds, reservationError.ReservationIds)
 }
+
+func TestReservationEvents(t *testing.T) {
+	handler := getSUT()
+	subjects := createTestSubjects(subjectsStore, t)
+	users := createTestUsers(usersStore, t)
+	from, to := clock.TimeTravel(60), clock.TimeTravel(120)
+
+	created, err := handler.Create(ctx, application.CreateReservation{subjects[0].Id, users[0].Id, from, to})
+	assert.NoError(t, err)
+	_, err = handler.Create(ctx, application.CreateReservation{subjects[0].Id, users[1].Id, from, to})
+	assert.Error(t, err)
+	assert.NoError(t, handler.Remove(ctx, application.RemoveReservations{UserId: users[0].Id, SubjectId: subjects[0].Id}))
+
+	data := application.ReservationEvent{
+		Id: created.Id,
+		SubjectId: subjects[0].Id,
+		Subject: subjects[0].Name,
+		UserId: users[0].Id,
+		Start: from,
+		End: to,
+	}
+	assert.Equal(t, []ports.Event{
+		{Type: application.EventReservationCreated, OccurredAt: clock.Current(), Data: data},
+		{Type: application.EventReservationRemoved, OccurredAt: clock.Current(), Data: data},
+	}, publisher.events)
+}
@@ -1,32 +1,77 @@
 package application
 
 import (
+	"context"
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/ports"
 	reservationsPort "github.com/SneedusSnake/Reservations/internal/ports/reservations"
 )
 
+var ErrSubjectExists = i18n.Errorf("subject.exists")
+
+// suggestions is how many close names a SubjectNotFoundError offers at most
+const suggestions = 3
+
+// SubjectNotFoundError is returned when no subject goes by the name, Suggestions are the closest names in the workspace
+type SubjectNotFoundError struct {
+	Name string
+	Suggestions []string
+}
+
+func (e SubjectNotFoundError) Error() string {
+	return fmt.Sprintf("Subject with name %s was not found", e.Name)
+}
+
+// AmbiguousSubjectError is returned when the name matches several subjects once its case is ignored
+type AmbiguousSubjectError struct {
+	Name string
+	Subjects reservations.Subjects
+}
+
+func (e AmbiguousSubjectError) Error() string {
+	return fmt.Sprintf("Subject name %s matches %d subjects", e.Name, len(e.Subjects))
+}
+
 type SubjectService struct {
 	store reservationsPort.SubjectsRepository
+	events ports.EventPublisher
 }
 
-func NewSubjectService(store reservationsPort.SubjectsRepository) *SubjectService {
-	return &SubjectService{store: store}
+func NewSubjectService(store reservationsPort.SubjectsRepository, events ports.EventPublisher) *SubjectService {
+	return &SubjectService{store: store, events: events}
 }
 
-func (h *SubjectService) Create(name string) (reservations.Subject, error) {
-	id, err := h.store.NextIdentity()
+type CreateSubject struct {
+	Name string
+	WorkspaceId int
+}
+
+// Create adds the subject to the workspace, unless a subject seen there already goes by its name in any case
+func (h *SubjectService) Create(ctx context.Context, cmd CreateSubject) (reservations.Subject, error) {
+	if err := h.available(ctx, cmd.WorkspaceId, cmd.Name); err != nil {
+		return reservations.Subject{}, err
+	}
+	id, err := h.store.NextIdentity(ctx)
 	if err != nil {
 		return reservations.Subject{}, err
 	}
 	subject := reservations.Subject{
 		Id: id,
-		Name: name,
+		Name: cmd.Name,
+		WorkspaceId: cmd.WorkspaceId,
 	}
-	err = h.store.Add(subject)
+	err = h.store.Add(ctx, subject)
 
 	if err != nil {
 		return subject, err
 	}
+	publish(ctx, h.events, ports.Event{Type: EventSubjectCreated, Data: SubjectEvent{Id: subject.Id, Name: subject.Name, WorkspaceId: subject.WorkspaceId}})
 
 	return subject, nil
 }
@@ -36,25 +81,238 @@ type AddTags struct {
 	Tags []string
 }
 
-func (h *SubjectService) AddTags(cmd AddTags) error {
+func (h *SubjectService) AddTags(ctx context.Context, cmd AddTags) error {
 	for _, tag := range cmd.Tags {
-		err := h.store.AddTag(cmd.SubjectId, tag)
+		err := h.store.AddTag(ctx, cmd.SubjectId, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	subject, err := h.store.Get(ctx, cmd.SubjectId)
+	if err != nil {
+		return err
+	}
+	publish(ctx, h.events, ports.Event{
+		Type: EventSubjectTagsAdded,
+		Data: SubjectEvent{Id: subject.Id, Name: subject.Name, ParentId: subject.ParentId, Tags: cmd.Tags},
+	})
+
+	return nil
+}
+
+// List returns the subjects visible in the workspace
+func (h *SubjectService) List(ctx context.Context, workspaceId int) (reservations.Subjects, error) {
+	return h.store.InWorkspace(ctx, workspaceId)
+}
+
+// WithTags returns the subjects visible in the workspace having every one of the tags
+func (h *SubjectService) WithTags(ctx context.Context, workspaceId int, tags []string) (reservations.Subjects, error) {
+	return h.store.GetByTags(ctx, workspaceId, tags)
+}
+
+func (h *SubjectService) Get(ctx context.Context, id int) (reservations.Subject, error) {
+	return h.store.Get(ctx, id)
+}
+
+func (h *SubjectService) GetByName(ctx context.Context, workspaceId int, name string) (reservations.Subject, error) {
+	return h.store.GetByName(ctx, workspaceId, name)
+}
+
+// Resolve finds the subject by its exact name first, then by its name ignoring case or by its aliases
+func (h *SubjectService) Resolve(ctx context.Context, workspaceId int, name string) (reservations.Subject, error) {
+	if subject, err := h.store.GetByName(ctx, workspaceId, name); err == nil {
+		return subject, nil
+	}
+	found, err := h.store.FindByName(ctx, workspaceId, name)
+	if err != nil {
+		return reservations.Subject{}, err
+	}
+	if len(found) > 1 {
+		return reservations.Subject{}, AmbiguousSubjectError{Name: name, Subjects: found}
+	}
+	if len(found) == 1 {
+		return found[0], nil
+	}
+
+	subjects, err := h.store.InWorkspace(ctx, workspaceId)
+	if err != nil {
+		return reservations.Subject{}, err
+	}
+
+	return reservations.Subject{}, SubjectNotFoundError{Name: name, Suggestions: closest(name, subjects)}
+}
+
+type AddAlias struct {
+	SubjectId int
+	WorkspaceId int
+	Alias string
+}
+
+// AddAlias lets the subject be found by another name, unless a subject of the workspace already goes by it
+func (h *SubjectService) AddAlias(ctx context.Context, cmd AddAlias) error {
+	if err := h.available(ctx, cmd.WorkspaceId, cmd.Alias); err != nil {
+		return err
+	}
+
+	return h.store.AddAlias(ctx, cmd.SubjectId, cmd.Alias)
+}
+
+// available returns ErrSubjectExists when a subject visible in the workspace is named or aliased by one of the names, ignoring case
+func (h *SubjectService) available(ctx context.Context, workspaceId int, names ...string) error {
+	for _, name := range names {
+		found, err := h.store.FindByName(ctx, workspaceId, name)
+		if err != nil {
+			return err
+		}
+		if len(found) > 0 {
+			return ErrSubjectExists
+		}
+	}
+
+	return nil
+}
+
+func (h *SubjectService) ListAliases(ctx context.Context, subjectId int) ([]string, error) {
+	return h.store.GetAliases(ctx, subjectId)
+}
+
+type ShareSubject struct {
+	SubjectId int
+	WorkspaceId int
+}
+
+// Share makes the subject visible in another workspace, reservations of a shared subject conflict across workspaces
+func (h *SubjectService) Share(ctx context.Context, cmd ShareSubject) error {
+	subject, err := h.store.Get(ctx, cmd.SubjectId)
+	if err != nil {
+		return err
+	}
+	if subject.WorkspaceId == cmd.WorkspaceId {
+		return i18n.Errorf("subject.own_workspace", subject.Name)
+	}
+	aliases, err := h.store.GetAliases(ctx, subject.Id)
+	if err != nil {
+		return err
+	}
+	if err = h.available(ctx, cmd.WorkspaceId, append(aliases, subject.Name)...); err != nil {
+		return err
+	}
+
+	return h.store.Share(ctx, cmd.SubjectId, cmd.WorkspaceId)
+}
+
+func (h *SubjectService) Unshare(ctx context.Context, cmd ShareSubject) error {
+	return h.store.Unshare(ctx, cmd.SubjectId, cmd.WorkspaceId)
+}
+
+func (h *SubjectService) ListTags(ctx context.Context, subjectId int) ([]string, error) {
+	return h.store.GetTags(ctx, subjectId)
+}
+
+type AddComponent struct {
+	ParentId int
+	ComponentId int
+}
+
+func (h *SubjectService) AddComponent(ctx context.Context, cmd AddComponent) error {
+	if cmd.ParentId == cmd.ComponentId {
+		return i18n.Errorf("subject.component_itself")
+	}
+
+	component, err := h.store.Get(ctx, cmd.ComponentId)
+	if err != nil {
+		return err
+	}
+
+	parentId := cmd.ParentId
+	for parentId != 0 {
+		parent, err := h.store.Get(ctx, parentId)
 		if err != nil {
 			return err
 		}
+		if parent.Id == component.Id {
+			return i18n.Errorf("subject.component_cycle", component.Name)
+		}
+		parentId = parent.ParentId
+	}
+
+	if err = h.store.SetParent(ctx, cmd.ComponentId, cmd.ParentId); err != nil {
+		return err
 	}
+	publish(ctx, h.events, ports.Event{
+		Type: EventComponentAdded,
+		Data: SubjectEvent{Id: component.Id, Name: component.Name, ParentId: cmd.ParentId},
+	})
 
 	return nil
 }
 
-func (h *SubjectService) List() (reservations.Subjects, error) {
-	return h.store.List()
+func (h *SubjectService) RemoveComponent(ctx context.Context, componentId int) error {
+	component, err := h.store.Get(ctx, componentId)
+	if err != nil {
+		return err
+	}
+	if err = h.store.SetParent(ctx, componentId, 0); err != nil {
+		return err
+	}
+	publish(ctx, h.events, ports.Event{
+		Type: EventComponentRemoved,
+		Data: SubjectEvent{Id: component.Id, Name: component.Name, ParentId: component.ParentId},
+	})
+
+	return nil
 }
 
-func (h *SubjectService) GetByName(name string) (reservations.Subject, error) {
-	return h.store.GetByName(name)
+func (h *SubjectService) ListComponents(ctx context.Context, subjectId int) (reservations.Subjects, error) {
+	return h.store.Children(ctx, subjectId)
 }
 
-func (h *SubjectService) ListTags(subjectId int) ([]string, error) {
-	return h.store.GetTags(subjectId)
+// closest returns the names of the subjects a few edits away from the name, the closest first
+func closest(name string, subjects reservations.Subjects) []string {
+	type candidate struct {
+		name string
+		distance int
+	}
+	name = strings.ToLower(name)
+	limit := max(1, len([]rune(name)) / 3)
+	var candidates []candidate
+
+	for _, subject := range subjects {
+		if d := distance(name, strings.ToLower(subject.Name)); d <= limit {
+			candidates = append(candidates, candidate{name: subject.Name, distance: d})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int { return cmp.Compare(a.distance, b.distance) })
+
+	var names []string
+	for _, c := range candidates[:min(len(candidates), suggestions)] {
+		names = append(names, c.name)
+	}
+
+	return names
+}
+
+// distance is the Levenshtein distance between the strings
+func distance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target) + 1)
+	current := make([]int, len(target) + 1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i - 1] == target[j - 1] {
+				cost = 0
+			}
+			current[j] = min(previous[j] + 1, current[j - 1] + 1, previous[j - 1] + cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
 }
@@ -0,0 +1,174 @@
+package application_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestAddComponent(t *testing.T) {
+	store := inmemory.NewSubjectsStore()
+	service := application.NewSubjectService(store, nil)
+	subjects := createTestSubjects(store, t)
+	bench, phone := subjects[0], subjects[1]
+
+	t.Run("it adds a component to the subject", func(t *testing.T) {
+		err := service.AddComponent(ctx, application.AddComponent{ParentId: bench.Id, ComponentId: phone.Id})
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			service.RemoveComponent(ctx, phone.Id)
+		})
+
+		components, err := service.ListComponents(ctx, bench.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(components))
+		assert.Equal(t, phone.Id, components[0].Id)
+	})
+
+	t.Run("it returns an error given the subject is added to itself", func(t *testing.T) {
+		err := service.AddComponent(ctx, application.AddComponent{ParentId: bench.Id, ComponentId: bench.Id})
+		assert.Error(t, err)
+	})
+
+	t.Run("it returns an error given the components would form a cycle", func(t *testing.T) {
+		err := service.AddComponent(ctx, application.AddComponent{ParentId: bench.Id, ComponentId: phone.Id})
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			service.RemoveComponent(ctx, phone.Id)
+		})
+
+		err = service.AddComponent(ctx, application.AddComponent{ParentId: phone.Id, ComponentId: bench.Id})
+		assert.Error(t, err)
+	})
+
+	t.Run("it returns an error given the parent does not exist", func(t *testing.T) {
+		err := service.AddComponent(ctx, application.AddComponent{ParentId: 1234, ComponentId: phone.Id})
+		assert.Error(t, err)
+	})
+}
+
+func TestShareSubject(t *testing.T) {
+	store := inmemory.NewSubjectsStore()
+	service := application.NewSubjectService(store, nil)
+	subjects := createTestSubjects(store, t)
+	assert.NoError(t, store.AdvanceIdentity(ctx, len(subjects)))
+	bench := subjects[0]
+	const lab = 2
+
+	t.Run("it makes the subject visible in another workspace", func(t *testing.T) {
+		err := service.Share(ctx, application.ShareSubject{SubjectId: bench.Id, WorkspaceId: lab})
+		assert.NoError(t, err)
+
+		found, err := service.GetByName(ctx, lab, bench.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, bench.Id, found.Id)
+
+		assert.NoError(t, service.Unshare(ctx, application.ShareSubject{SubjectId: bench.Id, WorkspaceId: lab}))
+		_, err = service.GetByName(ctx, lab, bench.Name)
+		assert.Error(t, err)
+	})
+
+	t.Run("it returns an error given the subject is shared with its own workspace", func(t *testing.T) {
+		err := service.Share(ctx, application.ShareSubject{SubjectId: bench.Id, WorkspaceId: workspaces.Default})
+		assert.Error(t, err)
+	})
+
+	t.Run("it returns an error given the workspace has a subject with the same name", func(t *testing.T) {
+		_, err := service.Create(ctx, application.CreateSubject{Name: bench.Name, WorkspaceId: lab})
+		assert.NoError(t, err)
+
+		err = service.Share(ctx, application.ShareSubject{SubjectId: bench.Id, WorkspaceId: lab})
+		assert.IsError(t, err, application.ErrSubjectExists)
+	})
+
+	t.Run("it returns an error given the workspace has a subject going by another case of the name or an alias", func(t *testing.T) {
+		const qa = 3
+		phone := subjects[1]
+		scope, err := service.Create(ctx, application.CreateSubject{Name: "Scope", WorkspaceId: qa})
+		assert.NoError(t, err)
+		assert.NoError(t, service.AddAlias(ctx, application.AddAlias{SubjectId: scope.Id, WorkspaceId: qa, Alias: "handset"}))
+		assert.NoError(t, service.AddAlias(ctx, application.AddAlias{SubjectId: phone.Id, WorkspaceId: workspaces.Default, Alias: "Handset"}))
+		_, err = service.Create(ctx, application.CreateSubject{Name: strings.ToUpper(bench.Name), WorkspaceId: qa})
+		assert.NoError(t, err)
+
+		err = service.Share(ctx, application.ShareSubject{SubjectId: bench.Id, WorkspaceId: qa})
+		assert.IsError(t, err, application.ErrSubjectExists)
+		err = service.Share(ctx, application.ShareSubject{SubjectId: phone.Id, WorkspaceId: qa})
+		assert.IsError(t, err, application.ErrSubjectExists)
+	})
+}
+
+func TestResolveSubject(t *testing.T) {
+	store := inmemory.NewSubjectsStore()
+	service := application.NewSubjectService(store, nil)
+	subjects := createTestSubjects(store, t)
+	bench := reservations.Subject{Id: 3, Name: "Bench", WorkspaceId: workspaces.Default}
+	lowerBench := reservations.Subject{Id: 4, Name: "bench", WorkspaceId: workspaces.Default}
+	assert.NoError(t, store.Add(ctx, bench))
+	assert.NoError(t, store.Add(ctx, lowerBench))
+
+	t.Run("it resolves the name ignoring case", func(t *testing.T) {
+		found, err := service.Resolve(ctx, workspaces.Default, "subject#1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, subjects[0], found)
+	})
+
+	t.Run("it prefers the exact name", func(t *testing.T) {
+		found, err := service.Resolve(ctx, workspaces.Default, "bench")
+
+		assert.NoError(t, err)
+		assert.Equal(t, lowerBench, found)
+	})
+
+	t.Run("it resolves the aliases of the subject", func(t *testing.T) {
+		assert.NoError(t, service.AddAlias(ctx, application.AddAlias{SubjectId: subjects[1].Id, WorkspaceId: workspaces.Default, Alias: "second"}))
+
+		found, err := service.Resolve(ctx, workspaces.Default, "Second")
+		assert.NoError(t, err)
+		assert.Equal(t, subjects[1], found)
+		aliases, err := service.ListAliases(ctx, subjects[1].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"second"}, aliases)
+	})
+
+	t.Run("it returns an error given the alias is taken", func(t *testing.T) {
+		err := service.AddAlias(ctx, application.AddAlias{SubjectId: subjects[0].Id, WorkspaceId: workspaces.Default, Alias: "SUBJECT#2"})
+
+		assert.IsError(t, err, application.ErrSubjectExists)
+	})
+
+	t.Run("it returns an error given a subject goes by the name in any case", func(t *testing.T) {
+		for _, name := range []string{"SUBJECT#1", "SECOND"} {
+			_, err := service.Create(ctx, application.CreateSubject{Name: name, WorkspaceId: workspaces.Default})
+
+			assert.IsError(t, err, application.ErrSubjectExists)
+		}
+	})
+
+	t.Run("it returns the subjects given the name is ambiguous", func(t *testing.T) {
+		_, err := service.Resolve(ctx, workspaces.Default, "BENCH")
+
+		ambiguous, ok := err.(application.AmbiguousSubjectError)
+		assert.True(t, ok)
+		assert.Equal(t, reservations.Subjects{bench, lowerBench}, ambiguous.Subjects)
+	})
+
+	t.Run("it suggests the closest names given none matches", func(t *testing.T) {
+		_, err := service.Resolve(ctx, workspaces.Default, "subject 1")
+
+		notFound, ok := err.(application.SubjectNotFoundError)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"Subject#1", "Subject#2"}, notFound.Suggestions)
+
+		_, err = service.Resolve(ctx, workspaces.Default, "projector")
+		notFound, ok = err.(application.SubjectNotFoundError)
+		assert.True(t, ok)
+		assert.Equal(t, 0, len(notFound.Suggestions))
+	})
+}
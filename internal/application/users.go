@@ -1,7 +1,11 @@
 package application
 
 import (
+	"context"
+	"time"
+
 	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
 	ports "github.com/SneedusSnake/Reservations/internal/ports/users"
 )
 
@@ -19,12 +23,21 @@ func NewUserService(store ports.UsersRepository) *UserService {
 	return &UserService{store: store}
 }
 
-func (s *UserService) Get(id int) (users.User, error) {
-	return s.store.Get(id)
+func (s *UserService) Get(ctx context.Context, id int) (users.User, error) {
+	return s.store.Get(ctx, id)
 }
 
-func (s *UserService) Create(cmd CreateUser) (users.User, error) {
-	id, err := s.store.NextIdentity()
+// Create stores the password hashed, users created by the chat adapters have none
+func (s *UserService) Create(ctx context.Context, cmd CreateUser) (users.User, error) {
+	password := cmd.Password
+	if password != "" {
+		hash, err := hashPassword(password)
+		if err != nil {
+			return users.User{}, err
+		}
+		password = hash
+	}
+	id, err := s.store.NextIdentity(ctx)
 	if err != nil {
 		return users.User{}, err
 	}
@@ -33,8 +46,58 @@ func (s *UserService) Create(cmd CreateUser) (users.User, error) {
 		Id: id,
 		Name: cmd.Name,
 		Email: cmd.Email,
-		Password: cmd.Password,
+		Password: password,
+	}
+
+	return user, s.store.Add(ctx, user)
+}
+
+// Rename keeps the name of a user in sync with the chat it was created in
+func (s *UserService) Rename(ctx context.Context, id int, name string) (users.User, error) {
+	user, err := s.store.Get(ctx, id)
+	if err != nil {
+		return users.User{}, err
+	}
+	if name == "" || user.Name == name {
+		return user, nil
+	}
+	user.Name = name
+
+	return user, s.store.Update(ctx, user)
+}
+
+// SetTimeZone accepts IANA names such as Europe/Berlin, an empty zone resets the user to the default one
+func (s *UserService) SetTimeZone(ctx context.Context, id int, zone string) (users.User, error) {
+	if zone != "" {
+		loc, err := time.LoadLocation(zone)
+		if err != nil || zone == "Local" {
+			return users.User{}, i18n.Errorf("timezone.unknown", zone)
+		}
+		zone = loc.String()
+	}
+	user, err := s.store.Get(ctx, id)
+	if err != nil {
+		return users.User{}, err
+	}
+	user.TimeZone = zone
+
+	return user, s.store.Update(ctx, user)
+}
+
+// SetLanguage accepts the codes of the shipped languages, an empty code resets the user to the language of the chat
+func (s *UserService) SetLanguage(ctx context.Context, id int, code string) (users.User, error) {
+	if code != "" {
+		language, ok := i18n.Match(code)
+		if !ok {
+			return users.User{}, i18n.Errorf("language.unknown", code)
+		}
+		code = language
+	}
+	user, err := s.store.Get(ctx, id)
+	if err != nil {
+		return users.User{}, err
 	}
+	user.Language = code
 
-	return user, s.store.Add(user)
+	return user, s.store.Update(ctx, user)
 }
@@ -0,0 +1,33 @@
+package application_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestSetTimeZone(t *testing.T) {
+	userService := application.NewUserService(inmemory.NewUsersStore())
+	user, err := userService.Create(ctx, application.CreateUser{Name: "Alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, user.Location(time.UTC))
+
+	user, err = userService.SetTimeZone(ctx, user.Id, "Europe/Berlin")
+	assert.NoError(t, err)
+	stored, err := userService.Get(ctx, user.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", stored.TimeZone)
+	assert.Equal(t, "Europe/Berlin", stored.Location(time.UTC).String())
+
+	_, err = userService.SetTimeZone(ctx, user.Id, "Mars/Olympus")
+	assert.Error(t, err)
+	_, err = userService.SetTimeZone(ctx, user.Id, "Local")
+	assert.Error(t, err)
+
+	user, err = userService.SetTimeZone(ctx, user.Id, "")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, user.Location(time.UTC))
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/webhooks"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	webhooksPort "github.com/SneedusSnake/Reservations/internal/ports/webhooks"
+)
+
+type RegisterWebhook struct {
+	Url string
+	// Events are event types or categories like "reservation.*", every event when empty
+	Events []string
+}
+
+type WebhookService struct {
+	store webhooksPort.WebhooksRepository
+	deadLetters webhooksPort.DeadLettersRepository
+}
+
+func NewWebhookService(store webhooksPort.WebhooksRepository, deadLetters webhooksPort.DeadLettersRepository) *WebhookService {
+	return &WebhookService{store: store, deadLetters: deadLetters}
+}
+
+// Register adds a webhook with a new signing secret
+func (s *WebhookService) Register(ctx context.Context, cmd RegisterWebhook) (webhooks.Webhook, error) {
+	target, err := url.Parse(cmd.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return webhooks.Webhook{}, i18n.Errorf("webhook.invalid_url", cmd.Url)
+	}
+	for _, event := range cmd.Events {
+		if !IsEventFilter(event) {
+			return webhooks.Webhook{}, i18n.Errorf("webhook.unknown_event", event)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return webhooks.Webhook{}, err
+	}
+	id, err := s.store.NextIdentity(ctx)
+	if err != nil {
+		return webhooks.Webhook{}, err
+	}
+
+	webhook := webhooks.Webhook{Id: id, Url: cmd.Url, Secret: hex.EncodeToString(secret), Events: cmd.Events}
+
+	return webhook, s.store.Add(ctx, webhook)
+}
+
+func (s *WebhookService) List(ctx context.Context) ([]webhooks.Webhook, error) {
+	return s.store.List(ctx)
+}
+
+func (s *WebhookService) Remove(ctx context.Context, id int) error {
+	if _, err := s.store.Get(ctx, id); err != nil {
+		return err
+	}
+
+	return s.store.Remove(ctx, id)
+}
+
+// DeadLetters returns the latest events which could not be delivered
+func (s *WebhookService) DeadLetters(ctx context.Context, limit int) ([]webhooks.DeadLetter, error) {
+	return s.deadLetters.List(ctx, limit)
+}
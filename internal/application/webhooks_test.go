@@ -0,0 +1,38 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRegisterWebhook(t *testing.T) {
+	service := application.NewWebhookService(inmemory.NewWebhooksStore(), inmemory.NewDeadLettersStore())
+
+	t.Run("it registers a webhook with a secret", func(t *testing.T) {
+		webhook, err := service.Register(ctx, application.RegisterWebhook{Url: "https://ci.example.com/hook", Events: []string{"reservation.*", "subject.created"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 64, len(webhook.Secret))
+		list, err := service.List(ctx)
+		assert.NoError(t, err)
+		assert.SliceContains(t, list, webhook)
+	})
+
+	t.Run("it rejects invalid urls and unknown events", func(t *testing.T) {
+		_, err := service.Register(ctx, application.RegisterWebhook{Url: "ci.example.com/hook"})
+		assert.Error(t, err)
+		_, err = service.Register(ctx, application.RegisterWebhook{Url: "ftp://ci.example.com/hook"})
+		assert.Error(t, err)
+		_, err = service.Register(ctx, application.RegisterWebhook{Url: "https://ci.example.com/hook", Events: []string{"device.*"}})
+		assert.Error(t, err)
+		_, err = service.Register(ctx, application.RegisterWebhook{Url: "https://ci.example.com/hook", Events: []string{"reservation.updated"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("it returns an error on removing an unknown webhook", func(t *testing.T) {
+		assert.Error(t, service.Remove(ctx, 1234))
+	})
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	workspacesPort "github.com/SneedusSnake/Reservations/internal/ports/workspaces"
+)
+
+// WorkspaceService manages the workspaces and the chats bound to them
+type WorkspaceService struct {
+	store workspacesPort.WorkspacesRepository
+}
+
+func NewWorkspaceService(store workspacesPort.WorkspacesRepository) *WorkspaceService {
+	return &WorkspaceService{store: store}
+}
+
+// Create adds a workspace, names are single words so that commands can take them after a subject name
+func (s *WorkspaceService) Create(ctx context.Context, name string) (workspaces.Workspace, error) {
+	if name == "" || len(strings.Fields(name)) != 1 {
+		return workspaces.Workspace{}, i18n.Errorf("workspace.invalid_name")
+	}
+	if _, err := s.store.GetByName(ctx, name); err == nil {
+		return workspaces.Workspace{}, i18n.Errorf("workspace.exists", name)
+	}
+	id, err := s.store.NextIdentity(ctx)
+	if err != nil {
+		return workspaces.Workspace{}, err
+	}
+	workspace := workspaces.Workspace{Id: id, Name: name}
+
+	return workspace, s.store.Add(ctx, workspace)
+}
+
+func (s *WorkspaceService) Get(ctx context.Context, id int) (workspaces.Workspace, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *WorkspaceService) GetByName(ctx context.Context, name string) (workspaces.Workspace, error) {
+	return s.store.GetByName(ctx, name)
+}
+
+func (s *WorkspaceService) List(ctx context.Context) ([]workspaces.Workspace, error) {
+	return s.store.List(ctx)
+}
+
+func (s *WorkspaceService) Bind(ctx context.Context, chat string, workspaceId int) error {
+	if _, err := s.store.Get(ctx, workspaceId); err != nil {
+		return err
+	}
+
+	return s.store.Bind(ctx, chat, workspaceId)
+}
+
+// ForChat returns the id of the workspace of the chat, chats which were never bound use the default workspace
+func (s *WorkspaceService) ForChat(ctx context.Context, chat string) (int, error) {
+	return s.store.ForChat(ctx, chat)
+}
+
+// AddAdmin lets the user bind chats to the workspace, share its subjects and name its other admins
+func (s *WorkspaceService) AddAdmin(ctx context.Context, workspaceId int, userId int) error {
+	if _, err := s.store.Get(ctx, workspaceId); err != nil {
+		return err
+	}
+
+	return s.store.AddAdmin(ctx, workspaceId, userId)
+}
+
+func (s *WorkspaceService) RemoveAdmin(ctx context.Context, workspaceId int, userId int) error {
+	return s.store.RemoveAdmin(ctx, workspaceId, userId)
+}
+
+func (s *WorkspaceService) IsAdmin(ctx context.Context, workspaceId int, userId int) (bool, error) {
+	return s.store.IsAdmin(ctx, workspaceId, userId)
+}
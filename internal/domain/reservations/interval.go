@@ -0,0 +1,31 @@
+package reservations
+
+import "time"
+
+// Interval is a half-open period of time [Start, End): it includes its start but not its end,
+// so a reservation ending at 13:00 does not overlap with the one starting at 13:00.
+// An interval which does not end after its start is empty and overlaps nothing
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func NewInterval(start time.Time, end time.Time) Interval {
+	return Interval{Start: start, End: end}
+}
+
+func (i Interval) IsEmpty() bool {
+	return !i.Start.Before(i.End)
+}
+
+func (i Interval) Overlaps(other Interval) bool {
+	if i.IsEmpty() || other.IsEmpty() {
+		return false
+	}
+
+	return i.Start.Before(other.End) && other.Start.Before(i.End)
+}
+
+func (i Interval) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
@@ -0,0 +1,76 @@
+package reservations_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+)
+
+func TestInterval(t *testing.T) {
+	at := func(clock string) time.Time {
+		parsed, err := time.Parse(time.DateTime, "2025-09-20 "+clock+":00")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	period := reservations.NewInterval(at("12:00"), at("13:00"))
+
+	overlaps := []struct {
+		name     string
+		other    reservations.Interval
+		expected bool
+	}{
+		{"same interval", reservations.NewInterval(at("12:00"), at("13:00")), true},
+		{"interval containing the period", reservations.NewInterval(at("11:00"), at("14:00")), true},
+		{"interval contained by the period", reservations.NewInterval(at("12:10"), at("12:50")), true},
+		{"interval overlapping the start", reservations.NewInterval(at("11:00"), at("12:01")), true},
+		{"interval overlapping the end", reservations.NewInterval(at("12:59"), at("14:00")), true},
+		{"interval ending at the start", reservations.NewInterval(at("11:00"), at("12:00")), false},
+		{"interval starting at the end", reservations.NewInterval(at("13:00"), at("14:00")), false},
+		{"interval before the period", reservations.NewInterval(at("10:00"), at("11:00")), false},
+		{"interval after the period", reservations.NewInterval(at("14:00"), at("15:00")), false},
+		{"zero-length interval inside the period", reservations.NewInterval(at("12:30"), at("12:30")), false},
+		{"zero-length interval at the start", reservations.NewInterval(at("12:00"), at("12:00")), false},
+		{"reversed interval", reservations.NewInterval(at("12:50"), at("12:10")), false},
+	}
+
+	for _, c := range overlaps {
+		t.Run("it checks overlapping with "+c.name, func(t *testing.T) {
+			if period.Overlaps(c.other) != c.expected {
+				t.Errorf("Expected overlapping of %v and %v to be %v", period, c.other, c.expected)
+			}
+			if c.other.Overlaps(period) != c.expected {
+				t.Errorf("Expected overlapping of %v and %v to be %v", c.other, period, c.expected)
+			}
+		})
+	}
+
+	t.Run("it contains its start but not its end", func(t *testing.T) {
+		if !period.Contains(at("12:00")) {
+			t.Errorf("Expected %v to contain its start", period)
+		}
+		if !period.Contains(at("12:59")) {
+			t.Errorf("Expected %v to contain 12:59", period)
+		}
+		if period.Contains(at("13:00")) {
+			t.Errorf("Expected %v not to contain its end", period)
+		}
+		if period.Contains(at("11:59")) {
+			t.Errorf("Expected %v not to contain 11:59", period)
+		}
+	})
+
+	t.Run("it is empty given it does not end after its start", func(t *testing.T) {
+		if period.IsEmpty() {
+			t.Errorf("Expected %v not to be empty", period)
+		}
+		if !reservations.NewInterval(at("12:00"), at("12:00")).IsEmpty() {
+			t.Error("Expected zero-length interval to be empty")
+		}
+		if !reservations.NewInterval(at("13:00"), at("12:00")).IsEmpty() {
+			t.Error("Expected reversed interval to be empty")
+		}
+	})
+}
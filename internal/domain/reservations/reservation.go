@@ -1,6 +1,7 @@
 package reservations
 
 import (
+	"slices"
 	"time"
 )
 
@@ -12,6 +13,10 @@ type Reservation struct {
 	End       time.Time
 }
 
+func (r Reservation) Interval() Interval {
+	return NewInterval(r.Start, r.End)
+}
+
 type Reservations []Reservation
 
 func (r Reservations) ForSubject(subjectId int) Reservations {
@@ -26,6 +31,18 @@ func (r Reservations) ForSubject(subjectId int) Reservations {
 	return filtered
 }
 
+func (r Reservations) ForSubjects(subjectIds ...int) Reservations {
+	var filtered Reservations
+
+	for _, reservation := range r {
+		if slices.Contains(subjectIds, reservation.SubjectId) {
+			filtered = append(filtered, reservation)
+		}
+	}
+
+	return filtered
+}
+
 func (r Reservations) ForUser(userId int) Reservations {
 	var filtered Reservations
 
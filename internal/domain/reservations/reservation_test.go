@@ -29,4 +29,24 @@ func TestReservations(t *testing.T) {
 			t.Errorf("Expected %v, got %v", reservations[3], result[1])
 		}
 	})
+	t.Run("it returns reservations filtered by several subject ids", func(t *testing.T) {
+		reservations := reservations.Reservations{
+			reservations.Reservation{1, 1, 1, time.Now(), time.Now()},
+			reservations.Reservation{2, 2, 2, time.Now(), time.Now()},
+			reservations.Reservation{3, 3, 3, time.Now(), time.Now()},
+		}
+
+		result := reservations.ForSubjects(1, 3)
+
+		if len(result) != 2 {
+			t.Errorf("expected to get 2 reservations, got %d", len(result))
+		}
+
+		if result[0] != reservations[0] {
+			t.Errorf("Expected %v, got %v", reservations[0], result[0])
+		}
+		if result[1] != reservations[2] {
+			t.Errorf("Expected %v, got %v", reservations[2], result[1])
+		}
+	})
 }
@@ -7,6 +7,9 @@ import (
 type Subject struct {
 		Id int
 		Name string
+		ParentId int
+		// WorkspaceId owns the subject, other workspaces see it once it is shared with them
+		WorkspaceId int
 }
 type Subjects []Subject
 
@@ -17,3 +20,11 @@ func (subjects Subjects) Names() string {
 	}
 	return strings.Join(subjectNames, "\n")
 }
+
+func (subjects Subjects) Ids() []int {
+	ids := []int{}
+	for _, subject := range subjects {
+		ids = append(ids, subject.Id)
+	}
+	return ids
+}
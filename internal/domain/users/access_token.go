@@ -0,0 +1,42 @@
+package users
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	ScopeReservationsRead = "reservations:read"
+	ScopeReservationsWrite = "reservations:write"
+	ScopeSubjectsAdmin = "subjects:admin"
+)
+
+var Scopes = []string{ScopeReservationsRead, ScopeReservationsWrite, ScopeSubjectsAdmin}
+
+// AccessToken lets scripts act on behalf of a user, usually a service account, within its scopes.
+// Only the hash of the token is stored
+type AccessToken struct {
+	Id int
+	UserId int
+	// WorkspaceId is the workspace whose subjects the token works with
+	WorkspaceId int
+	Name string
+	Hash string
+	Scopes []string
+	// ExpiresAt is zero for tokens which do not expire
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (t AccessToken) Expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+// Allows reports whether the token grants the scope, reservations:write implies reservations:read
+func (t AccessToken) Allows(scope string) bool {
+	if scope == ScopeReservationsRead && slices.Contains(t.Scopes, ScopeReservationsWrite) {
+		return true
+	}
+
+	return slices.Contains(t.Scopes, scope)
+}
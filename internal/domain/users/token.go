@@ -0,0 +1,24 @@
+package users
+
+import "time"
+
+const (
+	TokenSession = "session"
+	TokenPasswordReset = "password_reset"
+	// TokenAccountLink lets a user created by a chat adapter claim an email account
+	TokenAccountLink = "account_link"
+	// TokenMerge lets another user take over the reservations and identities of the user who requested it
+	TokenMerge = "merge"
+)
+
+// Token grants access on behalf of a user until it expires, only the hash of the token is stored
+type Token struct {
+	Hash string
+	Kind string
+	UserId int
+	ExpiresAt time.Time
+}
+
+func (t Token) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
@@ -1,15 +1,43 @@
 package users
 
+import (
+	"context"
+	"time"
+)
+
 type User struct {
 	Id int
 	Name string
 	Email string
 	Password string
+	// TimeZone is an IANA name such as Europe/Berlin, empty for users who did not pick one
+	TimeZone string
+	// Language is the code of the language of the replies, empty for users who did not pick one
+	Language string
+}
+
+// Location returns the time zone of the user, fallback is used for users without a valid one
+func (u User) Location(fallback *time.Location) *time.Location {
+	if u.TimeZone == "" {
+		return fallback
+	}
+	loc, err := time.LoadLocation(u.TimeZone)
+	if err != nil {
+		return fallback
+	}
+
+	return loc
+}
+
+// ChatUser links the account of a chat platform, such as Slack or Discord, to a user
+type ChatUser struct {
+	ExternalId string
+	User
 }
 
 type UsersStore interface {
-	NextIdentity() (int, error)
-	Add(u User) error
-	Get(id int) (User, error)
-	Remove(id int) error
+	NextIdentity(ctx context.Context) (int, error)
+	Add(ctx context.Context, u User) error
+	Get(ctx context.Context, id int) (User, error)
+	Remove(ctx context.Context, id int) error
 }
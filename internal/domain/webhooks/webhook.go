@@ -0,0 +1,40 @@
+package webhooks
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+type Webhook struct {
+	Id int
+	Url string
+	// Secret signs the payloads delivered to the webhook
+	Secret string
+	// Events the webhook is subscribed to, "reservation.*" matches every reservation event, empty matches everything
+	Events []string
+}
+
+func (w Webhook) Accepts(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(w.Events, func(filter string) bool {
+		if category, found := strings.CutSuffix(filter, ".*"); found {
+			return strings.HasPrefix(event, category + ".")
+		}
+		return filter == event || filter == "*"
+	})
+}
+
+// DeadLetter is an event that could not be delivered to a webhook after all the attempts
+type DeadLetter struct {
+	Id int
+	WebhookId int
+	Event string
+	Payload string
+	Attempts int
+	Error string
+	FailedAt time.Time
+}
@@ -0,0 +1,20 @@
+package webhooks_test
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/webhooks"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestWebhookAccepts(t *testing.T) {
+	all := webhooks.Webhook{}
+	reservations := webhooks.Webhook{Events: []string{"reservation.*", "subject.created"}}
+
+	assert.True(t, all.Accepts("reservation.created"))
+	assert.True(t, reservations.Accepts("reservation.created"))
+	assert.True(t, reservations.Accepts("reservation.removed"))
+	assert.True(t, reservations.Accepts("subject.created"))
+	assert.False(t, reservations.Accepts("subject.tags_added"))
+	assert.False(t, reservations.Accepts("reservations.created"))
+}
@@ -0,0 +1,10 @@
+package workspaces
+
+// Default is the workspace of the chats which are not bound to one, it holds the subjects created before workspaces existed
+const Default = 1
+
+// Workspace isolates the subjects and reservations of the chats bound to it
+type Workspace struct {
+	Id int
+	Name string
+}
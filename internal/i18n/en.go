@@ -0,0 +1,257 @@
+package i18n
+
+var english = Catalog{
+	"error.internal": {Other: "An error occured"},
+	"error.admins_only": {Other: "This command is available to admins only"},
+	"error.chat_admins_only": {Other: "This command is available to chat admins only"},
+	"error.workspace_admins_only": {Other: "This command is available to workspace admins only"},
+	"error.private_only": {Other: "Send %s to the bot in a private chat"},
+	"error.name_required": {Other: "Name is required"},
+
+	"usage.add_subject": {Other: "Invalid format for add subject command. Expected: %s"},
+	"usage.add_tags": {Other: "Invalid format for add tags command. Expected: %s"},
+	"usage.tags": {Other: "Invalid format for list tags command. Expected: %s"},
+	"usage.add_alias": {Other: "Invalid format for add alias command. Expected: %s"},
+	"usage.aliases": {Other: "Invalid format for list aliases command. Expected: %s"},
+	"usage.add_component": {Other: "Invalid format for add component command. Expected: %s"},
+	"usage.components": {Other: "Invalid format for list components command. Expected: %s"},
+	"usage.calendar": {Other: "Invalid format for calendar command. Expected: %s"},
+	"usage.reserve": {Other: "Invalid format for reserve command. Expected: %s. %s"},
+	"usage.reserve_any": {Other: "Invalid format for reserve command. Expected: %s. %s"},
+	"usage.remove": {Other: "Invalid format for remove reservation command. Expected: %s"},
+	"usage.webhook_add": {Other: "Invalid format for add webhook command. Expected: %s"},
+	"usage.webhook_remove": {Other: "Invalid format for remove webhook command. Expected: %s"},
+	"usage.service_account": {Other: "Invalid format for service account command. Expected: %s"},
+	"usage.token_revoke": {Other: "Invalid format for revoke token command. Expected: %s"},
+	"usage.merge": {Other: "Invalid format for merge command. Expected: %s"},
+	"usage.workspace_create": {Other: "Invalid format for create workspace command. Expected: %s"},
+	"usage.workspace_bind": {Other: "Invalid format for bind workspace command. Expected: %s"},
+	"usage.workspace_admin": {Other: "Invalid format for workspace admin command. Expected: %s"},
+	"usage.workspace_admin_remove": {Other: "Invalid format for remove workspace admin command. Expected: %s"},
+	"usage.share": {Other: "Invalid format for share command. Expected: %s"},
+	"usage.unshare": {Other: "Invalid format for unshare command. Expected: %s"},
+	"usage.topic_tag": {Other: "Invalid format for topic tag command. Expected: %s"},
+	"usage.topic_subjects": {Other: "Invalid format for topic subjects command. Expected: %s"},
+	"usage.timezone": {Other: "Invalid format for timezone command. Expected: %s, for example /timezone Europe/Berlin"},
+	"usage.language": {Other: "Invalid format for language command. Expected: %s, one of: %s"},
+	"usage.chat_language": {Other: "Invalid format for chat language command. Expected: %s, one of: %s"},
+
+	"syntax.add_subject": {Other: "/add_subject <name>"},
+	"syntax.add_tags": {Other: "/add_tags <subject_name> <tag1> [tag2] [tag3]..."},
+	"syntax.list": {Other: "/list"},
+	"syntax.tags": {Other: "/tags <subject_name>"},
+	"syntax.add_alias": {Other: "/add_alias <subject_name> <alias>"},
+	"syntax.aliases": {Other: "/aliases <subject_name>"},
+	"syntax.add_component": {Other: "/add_component <parent_name> <component_name>"},
+	"syntax.components": {Other: "/components <subject_name>"},
+	"syntax.calendar": {Other: "/calendar <subject_name>"},
+	"syntax.my_calendar": {Other: "/my_calendar"},
+	"syntax.import_confirm": {Other: "/import_confirm"},
+	"syntax.link_account": {Other: "/link_account"},
+	"syntax.merge_code": {Other: "/merge_code"},
+	"syntax.merge": {Other: "/merge <code>"},
+	"syntax.service_account": {Other: "/service_account <name> <scope,...> [days] [workspace]"},
+	"syntax.tokens": {Other: "/tokens"},
+	"syntax.token_revoke": {Other: "/token_revoke <id>"},
+	"syntax.webhook_add": {Other: "/webhook_add <url> [event,...]"},
+	"syntax.webhook_remove": {Other: "/webhook_remove <id>"},
+	"syntax.webhooks": {Other: "/webhooks"},
+	"syntax.dead_letters": {Other: "/dead_letters"},
+	"syntax.workspace": {Other: "/workspace"},
+	"syntax.workspaces": {Other: "/workspaces"},
+	"syntax.workspace_create": {Other: "/workspace_create <name>"},
+	"syntax.workspace_bind": {Other: "/workspace_bind <name>"},
+	"syntax.workspace_admin": {Other: "/workspace_admin <telegram_id>"},
+	"syntax.workspace_admin_remove": {Other: "/workspace_admin_remove <telegram_id>"},
+	"syntax.share": {Other: "/share <subject_name> <workspace>"},
+	"syntax.unshare": {Other: "/unshare <subject_name> <workspace>"},
+	"syntax.topic": {Other: "/topic"},
+	"syntax.topic_tag": {Other: "/topic_tag <tag>"},
+	"syntax.topic_subjects": {Other: "/topic_subjects <subject_name>[,<subject_name>...]"},
+	"syntax.topic_unbind": {Other: "/topic_unbind"},
+	"syntax.timezone": {Other: "/timezone [<zone>|default]"},
+	"syntax.language": {Other: "/language [<language>|default]"},
+	"syntax.chat_language": {Other: "/chat_language <language>|default"},
+	"syntax.reserved": {Other: "/reserved [tag1] [tag2]..."},
+	"syntax.reserve": {Other: "/reserve <subject_name>[,<subject_name>...] <period>"},
+	"syntax.reserve_any": {Other: "/reserve <period>"},
+	"syntax.remove": {Other: "/remove <subject_name>"},
+	"syntax.help": {Other: "/help"},
+	"syntax.start": {Other: "/start"},
+
+	"command.add_subject": {Other: "Add a subject"},
+	"command.add_tags": {Other: "Tag a subject"},
+	"command.list": {Other: "List the subjects"},
+	"command.tags": {Other: "List the tags of a subject"},
+	"command.add_alias": {Other: "Give a subject another name"},
+	"command.aliases": {Other: "List the other names of a subject"},
+	"command.add_component": {Other: "Make a subject a component of another one"},
+	"command.components": {Other: "List the components of a subject"},
+	"command.calendar": {Other: "Get the calendar of a subject"},
+	"command.my_calendar": {Other: "Get the calendar of your reservations"},
+	"command.import_confirm": {Other: "Make the reservations of the uploaded calendar"},
+	"command.link_account": {Other: "Get a code to sign in on the web"},
+	"command.merge_code": {Other: "Get a code to merge this user into another account"},
+	"command.merge": {Other: "Merge another account into this one"},
+	"command.service_account": {Other: "Create a service account with an access token"},
+	"command.tokens": {Other: "List the access tokens"},
+	"command.token_revoke": {Other: "Revoke an access token"},
+	"command.webhook_add": {Other: "Add a webhook"},
+	"command.webhook_remove": {Other: "Remove a webhook"},
+	"command.webhooks": {Other: "List the webhooks"},
+	"command.dead_letters": {Other: "List the undelivered webhook events"},
+	"command.workspace": {Other: "Show the workspace of this chat"},
+	"command.workspaces": {Other: "List the workspaces"},
+	"command.workspace_create": {Other: "Create a workspace for this chat"},
+	"command.workspace_bind": {Other: "Move this chat to a workspace"},
+	"command.workspace_admin": {Other: "Let a user manage the workspace of this chat"},
+	"command.workspace_admin_remove": {Other: "Stop a user from managing the workspace of this chat"},
+	"command.share": {Other: "Share a subject with a workspace"},
+	"command.unshare": {Other: "Stop sharing a subject with a workspace"},
+	"command.topic": {Other: "Show what this topic is bound to"},
+	"command.topic_tag": {Other: "Bind this topic to the subjects with a tag"},
+	"command.topic_subjects": {Other: "Bind this topic to subjects"},
+	"command.topic_unbind": {Other: "Unbind this topic"},
+	"command.timezone": {Other: "Show or set your time zone"},
+	"command.language": {Other: "Show or set your language"},
+	"command.chat_language": {Other: "Set the language of this chat"},
+	"command.reserved": {Other: "List the active reservations"},
+	"command.reserve": {Other: "Reserve subjects"},
+	"command.remove": {Other: "Remove your reservation of a subject"},
+	"command.help": {Other: "List the commands"},
+	"command.start": {Other: "Start using the bot"},
+
+	"help.header": {Other: "Commands:"},
+	"help.start": {Other: "Hi %s! I keep track of who reserved what and until when. Reserve a subject with /reserve, see what is taken with /reserved."},
+	"help.line": {Other: "%s - %s"},
+
+	"subject.added": {Other: "Subject %s added"},
+	"subject.tags_added": {Other: "tags: %s added to %s"},
+	"subject.component_added": {Other: "%s added as a component of %s"},
+	"subject.no_components": {Other: "%s has no components"},
+	"subject.alias_added": {Other: "%s can now be called %s"},
+	"subject.alias_taken": {Other: "A subject is already called %s"},
+	"subject.no_aliases": {Other: "%s has no aliases"},
+	"subject.not_found": {Other: "Subject %s was not found"},
+	"subject.did_you_mean": {Other: "Subject %s was not found. Did you mean %s?"},
+	"subject.choose": {Other: "Several subjects are called %s, which one did you mean?"},
+	"subject.choice_expired": {Other: "This choice has expired, send the command again"},
+	"subject.choice_not_yours": {Other: "Only the sender of the command can choose"},
+	"subject.exists": {Other: "Subject with this name already exists"},
+	"subject.own_workspace": {Other: "Subject %s belongs to the workspace"},
+	"subject.component_itself": {Other: "Subject cannot be a component of itself"},
+	"subject.component_cycle": {Other: "Subject %s cannot be a component of its own component"},
+
+	"reservation.acquired": {Other: "Reservation for %s acquired by %s until %s"},
+	"reservation.already_reserved": {Other: "Already reserved by %s until %s"},
+	"reservation.removed": {Other: "Reservation for %s removed"},
+	"reservation.removed_by": {Other: "Reservation for %s by %s removed"},
+	"reservation.topic_full": {Other: "Every subject of this topic is already reserved"},
+	"reservation.no_subjects": {Other: "No subjects to reserve"},
+	"reservation.ends_before_start": {Other: "Reservation must end after it starts"},
+	"reservation.in_past": {Other: "Attempt to make a reservation in the past"},
+	"reservation.none_active": {Other: "No active reservations found"},
+	"reservation.same_subject": {Other: "Subjects with ids %d and %d are parts of the same subject and cannot be reserved together"},
+	"reservations.header": {Other: "Subject\tReserved Until\t\tUser"},
+
+	"calendar.subscribe": {Other: "Subscribe: %s"},
+	"calendar.no_reservations": {Other: "You have no reservations yet"},
+	"import.too_large": {Other: "The calendar file is too large"},
+	"import.unreadable": {Other: "Unable to read the calendar: %s"},
+	"import.nothing": {Other: "Nothing to reserve"},
+	"import.confirm": {Other: "Send /import_confirm to make the reservations"},
+	"import.not_pending": {Other: "Nothing to import, send an .ics file first"},
+	"import.no_events": {Other: "Calendar has no events"},
+	"import.overlap": {Other: "Overlaps another event of the calendar"},
+
+	"timezone.current": {Other: "Your time zone is %s"},
+	"timezone.set": {Other: "Your time zone is now %s"},
+	"timezone.unknown": {Other: "Unknown time zone %s, expected a name such as Europe/Berlin"},
+	"language.current": {Other: "Replies are in %s"},
+	"language.set": {Other: "Your replies are now in %s"},
+	"language.chat_set": {Other: "Replies in this chat are now in %s"},
+	"language.chat_reset": {Other: "Replies in this chat now follow the language of each sender"},
+	"language.unknown": {Other: "Unknown language %s"},
+
+	"time.now": {Other: "now"},
+	"time.in": {Other: "in %s"},
+	"time.ago": {Other: "%s ago"},
+	"time.days_hours": {Other: "%d d %d h"},
+	"time.days": {Other: "%d d"},
+	"time.hours_minutes": {Other: "%d h %d min"},
+	"time.hours": {Other: "%d h"},
+	"time.minutes": {Other: "%d min"},
+	"time.formats": {Other: "Accepted formats: 30 (minutes), 2h, 1h30m, for 2h, until 17:00, till end of day, tomorrow 9-11, friday 14:00-16:30, next monday 10:00 for 2h"},
+	"time.missing": {Other: "Reservation period is missing. %s"},
+	"time.unknown": {Other: "Could not understand %q. %s"},
+	"time.end_missing": {Other: "End of the reservation is missing in %q. %s"},
+	"time.ends_before_start": {Other: "Reservation must end after it starts. %s"},
+	"time.invalid_clock": {Other: "%s is not a valid time of day"},
+	"time.passed": {Other: "%s has already passed"},
+	"time.too_short": {Other: "Reservation must last at least a minute"},
+	"time.too_long": {Other: "Reservation cannot last longer than a year"},
+
+	"topic.not_bound": {Other: "This topic is not bound, commands here work with every subject of the chat"},
+	"topic.tag": {Other: "This topic works with subjects tagged %s"},
+	"topic.subjects": {Other: "This topic works with subjects:\n%s"},
+	"topic.bound_tag": {Other: "This topic now works with subjects tagged %s"},
+	"topic.bound_subjects": {Other: "This topic now works with %s"},
+	"topic.unbound": {Other: "This topic is no longer bound"},
+	"topic.forum_only": {Other: "Only forum topics can be bound"},
+	"topic.scope": {Other: "Topic is bound either to a tag or to subjects"},
+
+	"account.registered": {Other: "You are already registered as %s"},
+	"account.link_code": {
+		One: "Your link code is %s\nRegister with it as link_code within %d minute to sign in to the same user by email",
+		Other: "Your link code is %s\nRegister with it as link_code within %d minutes to sign in to the same user by email",
+	},
+	"account.merge_code": {
+		One: "Your merge code is %s\nSend /merge %s from your other telegram account, or use it on the web, within %d minute. This user is merged into that account",
+		Other: "Your merge code is %s\nSend /merge %s from your other telegram account, or use it on the web, within %d minutes. This user is merged into that account",
+	},
+	"account.merged": {Other: "Accounts merged, everything now belongs to %s"},
+	"account.invalid_credentials": {Other: "Invalid email or password"},
+	"account.invalid_token": {Other: "Invalid or expired token"},
+	"account.email_taken": {Other: "Email is already registered"},
+	"account.invalid_email": {Other: "Invalid email %s"},
+	"account.password_short": {Other: "Password must be at least %d characters long"},
+	"account.password_long": {Other: "Password must be at most 72 bytes long"},
+	"account.exists": {Other: "User %s already has an account"},
+	"account.merge_same": {Other: "The code has to be used from the other account"},
+	"account.merge_emails": {Other: "Both users have an email account, only one of them can be kept"},
+
+	"token.created": {Other: "Service account %s created with token #%d (%s, %s)\n%s\nSend it as a bearer token, it will not be shown again"},
+	"token.none": {Other: "No access tokens issued"},
+	"token.line": {Other: "#%d %s, user %d, workspace %s (%s, %s)"},
+	"token.revoked": {Other: "Token #%d revoked"},
+	"token.never_expires": {Other: "never expires"},
+	"token.expires": {Other: "expires %s"},
+	"token.missing_scope": {Other: "Token does not grant the required scope"},
+	"token.negative_lifetime": {Other: "Token lifetime must not be negative"},
+	"token.no_scopes": {Other: "At least one scope is required: %s"},
+	"token.unknown_scope": {Other: "Unknown scope %s, expected one of: %s"},
+
+	"webhook.added": {Other: "Webhook #%d added for %s\nSigning secret: %s\nPayloads are signed with HMAC-SHA256 in the X-Webhook-Signature header"},
+	"webhook.none": {Other: "No webhooks registered"},
+	"webhook.removed": {Other: "Webhook #%d removed"},
+	"webhook.all_events": {Other: "all events"},
+	"webhook.no_dead_letters": {Other: "No undelivered events"},
+	"webhook.dead_letter": {
+		One: "%s webhook #%d %s after %d attempt: %s",
+		Other: "%s webhook #%d %s after %d attempts: %s",
+	},
+	"webhook.invalid_url": {Other: "Webhook url must be an absolute http or https url, got %s"},
+	"webhook.unknown_event": {Other: "Unknown event %s"},
+
+	"workspace.current": {Other: "This chat works in workspace %s"},
+	"workspace.created": {Other: "Workspace %s created, this chat now works in it"},
+	"workspace.bound": {Other: "This chat now works in workspace %s"},
+	"workspace.shared": {Other: "%s is shared with workspace %s"},
+	"workspace.unshared": {Other: "%s is no longer shared with workspace %s"},
+	"workspace.admin_added": {Other: "%s now manages workspace %s"},
+	"workspace.admin_removed": {Other: "%s no longer manages workspace %s"},
+	"workspace.not_admin": {Other: "You do not manage workspace %s"},
+	"workspace.unknown_user": {Other: "User %d has not used the bot yet"},
+	"workspace.invalid_name": {Other: "Workspace name must be a single word"},
+	"workspace.exists": {Other: "Workspace %s already exists"},
+}
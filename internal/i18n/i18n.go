@@ -0,0 +1,222 @@
+// Package i18n keeps the catalogs of the bot replies and picks the plural forms of each language
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const Default = "en"
+
+// Message is a catalog entry. Messages without a count only have Other,
+// counted ones have the forms their language distinguishes, see Forms
+type Message struct {
+	One string
+	Few string
+	Many string
+	Other string
+}
+
+type Catalog map[string]Message
+
+type language struct {
+	name string
+	catalog Catalog
+	// plural picks the form for the count
+	plural func(n int) string
+}
+
+const (
+	FormOne = "one"
+	FormFew = "few"
+	FormMany = "many"
+	FormOther = "other"
+)
+
+var languages = map[string]language{
+	"en": {name: "English", catalog: english, plural: englishPlural},
+	"ru": {name: "Русский", catalog: russian, plural: slavicPlural},
+}
+
+func englishPlural(n int) string {
+	if n == 1 {
+		return FormOne
+	}
+
+	return FormOther
+}
+
+func slavicPlural(n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n % 10 == 1 && n % 100 != 11:
+		return FormOne
+	case n % 10 >= 2 && n % 10 <= 4 && (n % 100 < 12 || n % 100 > 14):
+		return FormFew
+	}
+
+	return FormMany
+}
+
+// Languages returns the codes of the shipped languages
+func Languages() []string {
+	var codes []string
+	for code := range languages {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	return codes
+}
+
+// Catalogs returns the catalog of every shipped language by its code
+func Catalogs() map[string]Catalog {
+	catalogs := make(map[string]Catalog)
+	for code, l := range languages {
+		catalogs[code] = l.catalog
+	}
+
+	return catalogs
+}
+
+// Forms returns the plural forms a counted message of the language has to provide
+func Forms(code string) []string {
+	var forms []string
+	for _, n := range []int{0, 1, 2, 5, 11, 21, 22, 25, 101} {
+		if form := languages[code].plural(n); !slices.Contains(forms, form) {
+			forms = append(forms, form)
+		}
+	}
+
+	return forms
+}
+
+// Match picks the shipped language of an IETF tag such as ru-RU, ok is false for the others
+func Match(tag string) (code string, ok bool) {
+	code, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	_, ok = languages[code]
+
+	return code, ok
+}
+
+// Name is the name of the language in that language
+func Name(code string) string {
+	return languages[code].name
+}
+
+// Printer renders the messages of one language, falling back to Default for the missing ones
+type Printer struct {
+	code string
+}
+
+// For returns the printer of the language of the tag, Default for unknown ones
+func For(tag string) Printer {
+	code, ok := Match(tag)
+	if !ok {
+		code = Default
+	}
+
+	return Printer{code: code}
+}
+
+func (p Printer) Language() string {
+	if p.code == "" {
+		return Default
+	}
+
+	return p.code
+}
+
+func (p Printer) Sprintf(key string, args ...any) string {
+	return fmt.Sprintf(p.message(key).Other, p.args(args)...)
+}
+
+// Plural renders the form of the message for the count n, n is not passed to the format by itself
+func (p Printer) Plural(key string, n int, args ...any) string {
+	message := p.message(key)
+	format := message.Other
+	switch languages[p.Language()].plural(n) {
+	case FormOne:
+		format = message.One
+	case FormFew:
+		format = message.Few
+	case FormMany:
+		format = message.Many
+	}
+	if format == "" {
+		format = message.Other
+	}
+
+	return fmt.Sprintf(format, p.args(args)...)
+}
+
+// Error renders translatable errors in the language of the printer and the others as they are
+func (p Printer) Error(err error) string {
+	var translatable *Error
+	if errors.As(err, &translatable) {
+		return p.Sprintf(translatable.Key, translatable.Args...)
+	}
+
+	return err.Error()
+}
+
+// args renders the Key arguments in the language of the printer
+func (p Printer) args(args []any) []any {
+	rendered := make([]any, len(args))
+	for i, arg := range args {
+		if key, ok := arg.(Key); ok {
+			arg = p.Sprintf(string(key))
+		}
+		rendered[i] = arg
+	}
+
+	return rendered
+}
+
+func (p Printer) message(key string) Message {
+	if message, ok := languages[p.Language()].catalog[key]; ok {
+		return message
+	}
+	if message, ok := english[key]; ok {
+		return message
+	}
+
+	return Message{Other: key}
+}
+
+// Key is an argument of a message taken from the catalog of the printer as well, such as the syntax of a command
+type Key string
+
+// Error is an error shown to users, its text comes from the catalog of the reader
+type Error struct {
+	Key string
+	Args []any
+}
+
+func Errorf(key string, args ...any) *Error {
+	return &Error{Key: key, Args: args}
+}
+
+func (e *Error) Error() string {
+	return For(Default).Sprintf(e.Key, e.Args...)
+}
+
+type printerKey struct{}
+
+func WithPrinter(ctx context.Context, p Printer) context.Context {
+	return context.WithValue(ctx, printerKey{}, p)
+}
+
+// FromContext returns the printer of the reader of the request, Default when none was set
+func FromContext(ctx context.Context) Printer {
+	if p, ok := ctx.Value(printerKey{}).(Printer); ok {
+		return p
+	}
+
+	return For(Default)
+}
@@ -0,0 +1,123 @@
+package i18n_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/alecthomas/assert/v2"
+)
+
+var verbPattern = regexp.MustCompile(`%[a-z]`)
+
+func forms(message i18n.Message) map[string]string {
+	all := map[string]string{
+		i18n.FormOne: message.One,
+		i18n.FormFew: message.Few,
+		i18n.FormMany: message.Many,
+		i18n.FormOther: message.Other,
+	}
+	for form, text := range all {
+		if text == "" {
+			delete(all, form)
+		}
+	}
+
+	return all
+}
+
+func counted(message i18n.Message) bool {
+	return message.One != "" || message.Few != "" || message.Many != ""
+}
+
+func TestCatalogs(t *testing.T) {
+	catalogs := i18n.Catalogs()
+	english := catalogs[i18n.Default]
+
+	t.Run("every key exists in every language", func(t *testing.T) {
+		for code, catalog := range catalogs {
+			for key := range english {
+				_, ok := catalog[key]
+				assert.True(t, ok, fmt.Sprintf("%s is missing in %s", key, code))
+			}
+			for key := range catalog {
+				_, ok := english[key]
+				assert.True(t, ok, fmt.Sprintf("%s of %s is not in the default language", key, code))
+			}
+		}
+	})
+
+	t.Run("counted messages have every plural form of the language", func(t *testing.T) {
+		for code, catalog := range catalogs {
+			for key, message := range catalog {
+				if !counted(english[key]) {
+					assert.False(t, counted(message), fmt.Sprintf("%s of %s is not counted in the default language", key, code))
+					assert.NotEqual(t, "", message.Other, fmt.Sprintf("%s of %s is empty", key, code))
+					continue
+				}
+				for _, form := range i18n.Forms(code) {
+					_, ok := forms(message)[form]
+					assert.True(t, ok, fmt.Sprintf("%s of %s has no %s form", key, code, form))
+				}
+			}
+		}
+	})
+
+	t.Run("described commands have a usage", func(t *testing.T) {
+		for key := range english {
+			if command, ok := strings.CutPrefix(key, "command."); ok {
+				_, ok = english["syntax." + command]
+				assert.True(t, ok, fmt.Sprintf("%s has no syntax", command))
+			}
+		}
+	})
+
+	t.Run("translations take the same arguments", func(t *testing.T) {
+		for code, catalog := range catalogs {
+			for key, message := range catalog {
+				expected := verbPattern.FindAllString(forms(english[key])[i18n.FormOther], -1)
+				for form, text := range forms(message) {
+					assert.Equal(t, expected, verbPattern.FindAllString(text, -1), fmt.Sprintf("%s of %s in %s form", key, code, form))
+				}
+			}
+		}
+	})
+}
+
+func TestPrinter(t *testing.T) {
+	t.Run("it picks the language of the tag", func(t *testing.T) {
+		assert.Equal(t, "ru", i18n.For("ru-RU").Language())
+		assert.Equal(t, "en", i18n.For("en-GB").Language())
+		assert.Equal(t, i18n.Default, i18n.For("de").Language())
+		assert.Equal(t, i18n.Default, i18n.For("").Language())
+	})
+
+	t.Run("it renders plural forms", func(t *testing.T) {
+		english := i18n.For("en")
+		assert.Contains(t, english.Plural("webhook.dead_letter", 1, "now", 1, "created", 1, "timeout"), "after 1 attempt:")
+		assert.Contains(t, english.Plural("webhook.dead_letter", 3, "now", 1, "created", 3, "timeout"), "after 3 attempts:")
+
+		russian := i18n.For("ru")
+		cases := map[int]string{1: "попытки", 21: "попытки", 2: "попыток", 11: "попыток", 14: "попыток", 25: "попыток", 104: "попыток"}
+		for n, word := range cases {
+			assert.Contains(t, russian.Plural("webhook.dead_letter", n, "now", 1, "created", n, "timeout"), fmt.Sprintf("после %d %s", n, word))
+		}
+		assert.Equal(t, []string{i18n.FormMany, i18n.FormOne, i18n.FormFew}, i18n.Forms("ru"))
+	})
+
+	t.Run("it translates catalog errors and keeps the others", func(t *testing.T) {
+		err := i18n.Errorf("usage.merge", i18n.Key("syntax.merge"))
+		assert.EqualError(t, err, "Invalid format for merge command. Expected: /merge <code>")
+		assert.Equal(t, "Неверный формат команды объединения. Ожидается: /merge <код>", i18n.For("ru").Error(fmt.Errorf("Wrapped: %w", err)))
+		assert.Equal(t, "Plain", i18n.For("ru").Error(fmt.Errorf("Plain")))
+	})
+
+	t.Run("it keeps the printer of the request", func(t *testing.T) {
+		ctx := context.Background()
+		assert.Equal(t, i18n.Default, i18n.FromContext(ctx).Language())
+		assert.Equal(t, "ru", i18n.FromContext(i18n.WithPrinter(ctx, i18n.For("ru"))).Language())
+	})
+}
@@ -0,0 +1,260 @@
+package i18n
+
+var russian = Catalog{
+	"error.internal": {Other: "Произошла ошибка"},
+	"error.admins_only": {Other: "Эта команда доступна только администраторам"},
+	"error.chat_admins_only": {Other: "Эта команда доступна только администраторам чата"},
+	"error.workspace_admins_only": {Other: "Эта команда доступна только администраторам пространства"},
+	"error.private_only": {Other: "Отправьте %s боту в личном чате"},
+	"error.name_required": {Other: "Укажите имя"},
+
+	"usage.add_subject": {Other: "Неверный формат команды добавления объекта. Ожидается: %s"},
+	"usage.add_tags": {Other: "Неверный формат команды добавления тегов. Ожидается: %s"},
+	"usage.tags": {Other: "Неверный формат команды списка тегов. Ожидается: %s"},
+	"usage.add_alias": {Other: "Неверный формат команды добавления псевдонима. Ожидается: %s"},
+	"usage.aliases": {Other: "Неверный формат команды списка псевдонимов. Ожидается: %s"},
+	"usage.add_component": {Other: "Неверный формат команды добавления компонента. Ожидается: %s"},
+	"usage.components": {Other: "Неверный формат команды списка компонентов. Ожидается: %s"},
+	"usage.calendar": {Other: "Неверный формат команды календаря. Ожидается: %s"},
+	"usage.reserve": {Other: "Неверный формат команды бронирования. Ожидается: %s. %s"},
+	"usage.reserve_any": {Other: "Неверный формат команды бронирования. Ожидается: %s. %s"},
+	"usage.remove": {Other: "Неверный формат команды снятия брони. Ожидается: %s"},
+	"usage.webhook_add": {Other: "Неверный формат команды добавления вебхука. Ожидается: %s"},
+	"usage.webhook_remove": {Other: "Неверный формат команды удаления вебхука. Ожидается: %s"},
+	"usage.service_account": {Other: "Неверный формат команды сервисного аккаунта. Ожидается: %s"},
+	"usage.token_revoke": {Other: "Неверный формат команды отзыва токена. Ожидается: %s"},
+	"usage.merge": {Other: "Неверный формат команды объединения. Ожидается: %s"},
+	"usage.workspace_create": {Other: "Неверный формат команды создания пространства. Ожидается: %s"},
+	"usage.workspace_bind": {Other: "Неверный формат команды привязки пространства. Ожидается: %s"},
+	"usage.workspace_admin": {Other: "Неверный формат команды администратора пространства. Ожидается: %s"},
+	"usage.workspace_admin_remove": {Other: "Неверный формат команды удаления администратора пространства. Ожидается: %s"},
+	"usage.share": {Other: "Неверный формат команды открытия доступа. Ожидается: %s"},
+	"usage.unshare": {Other: "Неверный формат команды закрытия доступа. Ожидается: %s"},
+	"usage.topic_tag": {Other: "Неверный формат команды привязки темы к тегу. Ожидается: %s"},
+	"usage.topic_subjects": {Other: "Неверный формат команды привязки темы к объектам. Ожидается: %s"},
+	"usage.timezone": {Other: "Неверный формат команды часового пояса. Ожидается: %s, например /timezone Europe/Moscow"},
+	"usage.language": {Other: "Неверный формат команды языка. Ожидается: %s, один из: %s"},
+	"usage.chat_language": {Other: "Неверный формат команды языка чата. Ожидается: %s, один из: %s"},
+
+	"syntax.add_subject": {Other: "/add_subject <название>"},
+	"syntax.add_tags": {Other: "/add_tags <объект> <тег1> [тег2] [тег3]..."},
+	"syntax.list": {Other: "/list"},
+	"syntax.tags": {Other: "/tags <объект>"},
+	"syntax.add_alias": {Other: "/add_alias <объект> <псевдоним>"},
+	"syntax.aliases": {Other: "/aliases <объект>"},
+	"syntax.add_component": {Other: "/add_component <родитель> <компонент>"},
+	"syntax.components": {Other: "/components <объект>"},
+	"syntax.calendar": {Other: "/calendar <объект>"},
+	"syntax.my_calendar": {Other: "/my_calendar"},
+	"syntax.import_confirm": {Other: "/import_confirm"},
+	"syntax.link_account": {Other: "/link_account"},
+	"syntax.merge_code": {Other: "/merge_code"},
+	"syntax.merge": {Other: "/merge <код>"},
+	"syntax.service_account": {Other: "/service_account <имя> <scope,...> [дней] [пространство]"},
+	"syntax.tokens": {Other: "/tokens"},
+	"syntax.token_revoke": {Other: "/token_revoke <id>"},
+	"syntax.webhook_add": {Other: "/webhook_add <url> [событие,...]"},
+	"syntax.webhook_remove": {Other: "/webhook_remove <id>"},
+	"syntax.webhooks": {Other: "/webhooks"},
+	"syntax.dead_letters": {Other: "/dead_letters"},
+	"syntax.workspace": {Other: "/workspace"},
+	"syntax.workspaces": {Other: "/workspaces"},
+	"syntax.workspace_create": {Other: "/workspace_create <название>"},
+	"syntax.workspace_bind": {Other: "/workspace_bind <название>"},
+	"syntax.workspace_admin": {Other: "/workspace_admin <telegram_id>"},
+	"syntax.workspace_admin_remove": {Other: "/workspace_admin_remove <telegram_id>"},
+	"syntax.share": {Other: "/share <объект> <пространство>"},
+	"syntax.unshare": {Other: "/unshare <объект> <пространство>"},
+	"syntax.topic": {Other: "/topic"},
+	"syntax.topic_tag": {Other: "/topic_tag <тег>"},
+	"syntax.topic_subjects": {Other: "/topic_subjects <объект>[,<объект>...]"},
+	"syntax.topic_unbind": {Other: "/topic_unbind"},
+	"syntax.timezone": {Other: "/timezone [<пояс>|default]"},
+	"syntax.language": {Other: "/language [<язык>|default]"},
+	"syntax.chat_language": {Other: "/chat_language <язык>|default"},
+	"syntax.reserved": {Other: "/reserved [тег1] [тег2]..."},
+	"syntax.reserve": {Other: "/reserve <объект>[,<объект>...] <период>"},
+	"syntax.reserve_any": {Other: "/reserve <период>"},
+	"syntax.remove": {Other: "/remove <объект>"},
+	"syntax.help": {Other: "/help"},
+	"syntax.start": {Other: "/start"},
+
+	"command.add_subject": {Other: "Добавить объект"},
+	"command.add_tags": {Other: "Добавить теги объекту"},
+	"command.list": {Other: "Список объектов"},
+	"command.tags": {Other: "Теги объекта"},
+	"command.add_alias": {Other: "Дать объекту другое имя"},
+	"command.aliases": {Other: "Другие имена объекта"},
+	"command.add_component": {Other: "Сделать объект компонентом другого"},
+	"command.components": {Other: "Компоненты объекта"},
+	"command.calendar": {Other: "Календарь объекта"},
+	"command.my_calendar": {Other: "Календарь ваших броней"},
+	"command.import_confirm": {Other: "Забронировать по загруженному календарю"},
+	"command.link_account": {Other: "Код для входа на сайте"},
+	"command.merge_code": {Other: "Код для объединения с другим аккаунтом"},
+	"command.merge": {Other: "Объединить другой аккаунт с этим"},
+	"command.service_account": {Other: "Создать сервисный аккаунт с токеном доступа"},
+	"command.tokens": {Other: "Список токенов доступа"},
+	"command.token_revoke": {Other: "Отозвать токен доступа"},
+	"command.webhook_add": {Other: "Добавить вебхук"},
+	"command.webhook_remove": {Other: "Удалить вебхук"},
+	"command.webhooks": {Other: "Список вебхуков"},
+	"command.dead_letters": {Other: "Недоставленные события вебхуков"},
+	"command.workspace": {Other: "Пространство этого чата"},
+	"command.workspaces": {Other: "Список пространств"},
+	"command.workspace_create": {Other: "Создать пространство для этого чата"},
+	"command.workspace_bind": {Other: "Перевести этот чат в пространство"},
+	"command.workspace_admin": {Other: "Дать пользователю управлять пространством этого чата"},
+	"command.workspace_admin_remove": {Other: "Забрать у пользователя управление пространством этого чата"},
+	"command.share": {Other: "Открыть доступ к объекту пространству"},
+	"command.unshare": {Other: "Закрыть доступ к объекту пространству"},
+	"command.topic": {Other: "К чему привязана эта тема"},
+	"command.topic_tag": {Other: "Привязать тему к объектам с тегом"},
+	"command.topic_subjects": {Other: "Привязать тему к объектам"},
+	"command.topic_unbind": {Other: "Отвязать эту тему"},
+	"command.timezone": {Other: "Показать или выбрать часовой пояс"},
+	"command.language": {Other: "Показать или выбрать язык"},
+	"command.chat_language": {Other: "Выбрать язык этого чата"},
+	"command.reserved": {Other: "Активные брони"},
+	"command.reserve": {Other: "Забронировать объекты"},
+	"command.remove": {Other: "Снять свою бронь объекта"},
+	"command.help": {Other: "Список команд"},
+	"command.start": {Other: "Начать работу с ботом"},
+
+	"help.header": {Other: "Команды:"},
+	"help.start": {Other: "Привет, %s! Я слежу за тем, кто и до какого времени что забронировал. Бронируйте объекты командой /reserve, смотрите занятые командой /reserved."},
+	"help.line": {Other: "%s — %s"},
+
+	"subject.added": {Other: "Объект %s добавлен"},
+	"subject.tags_added": {Other: "теги: %s добавлены к %s"},
+	"subject.component_added": {Other: "%s добавлен как компонент %s"},
+	"subject.no_components": {Other: "У %s нет компонентов"},
+	"subject.alias_added": {Other: "%s теперь можно называть %s"},
+	"subject.alias_taken": {Other: "Объект с именем %s уже есть"},
+	"subject.no_aliases": {Other: "У %s нет псевдонимов"},
+	"subject.not_found": {Other: "Объект %s не найден"},
+	"subject.did_you_mean": {Other: "Объект %s не найден. Возможно, вы имели в виду %s?"},
+	"subject.choose": {Other: "Имя %s есть у нескольких объектов, какой из них вы имели в виду?"},
+	"subject.choice_expired": {Other: "Выбор устарел, отправьте команду ещё раз"},
+	"subject.choice_not_yours": {Other: "Выбрать может только отправитель команды"},
+	"subject.exists": {Other: "Объект с таким названием уже существует"},
+	"subject.own_workspace": {Other: "Объект %s уже принадлежит этому пространству"},
+	"subject.component_itself": {Other: "Объект не может быть компонентом самого себя"},
+	"subject.component_cycle": {Other: "Объект %s не может быть компонентом своего компонента"},
+
+	"reservation.acquired": {Other: "%s забронирован пользователем %s до %s"},
+	"reservation.already_reserved": {Other: "Уже забронировано пользователем %s до %s"},
+	"reservation.removed": {Other: "Бронь %s снята"},
+	"reservation.removed_by": {Other: "Бронь %s пользователя %s снята"},
+	"reservation.topic_full": {Other: "Все объекты этой темы уже забронированы"},
+	"reservation.no_subjects": {Other: "Нечего бронировать"},
+	"reservation.ends_before_start": {Other: "Бронь должна заканчиваться после начала"},
+	"reservation.in_past": {Other: "Нельзя забронировать в прошлом"},
+	"reservation.none_active": {Other: "Активных броней не найдено"},
+	"reservation.same_subject": {Other: "Объекты с id %d и %d входят в один объект и не могут быть забронированы вместе"},
+	"reservations.header": {Other: "Объект\tЗабронирован до\t\tПользователь"},
+
+	"calendar.subscribe": {Other: "Подписаться: %s"},
+	"calendar.no_reservations": {Other: "У вас пока нет броней"},
+	"import.too_large": {Other: "Файл календаря слишком большой"},
+	"import.unreadable": {Other: "Не удалось прочитать календарь: %s"},
+	"import.nothing": {Other: "Нечего бронировать"},
+	"import.confirm": {Other: "Отправьте /import_confirm, чтобы забронировать"},
+	"import.not_pending": {Other: "Нечего импортировать, сначала отправьте файл .ics"},
+	"import.no_events": {Other: "В календаре нет событий"},
+	"import.overlap": {Other: "Пересекается с другим событием календаря"},
+
+	"timezone.current": {Other: "Ваш часовой пояс: %s"},
+	"timezone.set": {Other: "Ваш часовой пояс теперь %s"},
+	"timezone.unknown": {Other: "Неизвестный часовой пояс %s, ожидается название вроде Europe/Berlin"},
+	"language.current": {Other: "Ответы на языке: %s"},
+	"language.set": {Other: "Теперь ответы для вас на языке: %s"},
+	"language.chat_set": {Other: "Теперь ответы в этом чате на языке: %s"},
+	"language.chat_reset": {Other: "Теперь ответы в этом чате на языке отправителя"},
+	"language.unknown": {Other: "Неизвестный язык %s"},
+
+	"time.now": {Other: "сейчас"},
+	"time.in": {Other: "через %s"},
+	"time.ago": {Other: "%s назад"},
+	"time.days_hours": {Other: "%d дн %d ч"},
+	"time.days": {Other: "%d дн"},
+	"time.hours_minutes": {Other: "%d ч %d мин"},
+	"time.hours": {Other: "%d ч"},
+	"time.minutes": {Other: "%d мин"},
+	"time.formats": {Other: "Допустимые форматы: 30 (минут), 2h, 1h30m, for 2h, until 17:00, till end of day, tomorrow 9-11, friday 14:00-16:30, next monday 10:00 for 2h"},
+	"time.missing": {Other: "Не указан срок брони. %s"},
+	"time.unknown": {Other: "Не удалось разобрать %q. %s"},
+	"time.end_missing": {Other: "Не указан конец брони в %q. %s"},
+	"time.ends_before_start": {Other: "Бронь должна заканчиваться после начала. %s"},
+	"time.invalid_clock": {Other: "%s не является временем суток"},
+	"time.passed": {Other: "%s уже прошло"},
+	"time.too_short": {Other: "Бронь должна длиться хотя бы минуту"},
+	"time.too_long": {Other: "Бронь не может длиться дольше года"},
+
+	"topic.not_bound": {Other: "Эта тема не привязана, команды здесь работают со всеми объектами чата"},
+	"topic.tag": {Other: "Эта тема работает с объектами с тегом %s"},
+	"topic.subjects": {Other: "Эта тема работает с объектами:\n%s"},
+	"topic.bound_tag": {Other: "Теперь эта тема работает с объектами с тегом %s"},
+	"topic.bound_subjects": {Other: "Теперь эта тема работает с %s"},
+	"topic.unbound": {Other: "Эта тема больше не привязана"},
+	"topic.forum_only": {Other: "Привязать можно только темы форума"},
+	"topic.scope": {Other: "Тема привязывается либо к тегу, либо к объектам"},
+
+	"account.registered": {Other: "Вы уже зарегистрированы как %s"},
+	"account.link_code": {
+		One: "Ваш код привязки: %s\nЗарегистрируйтесь с ним как link_code в течение %d минуты, чтобы входить в того же пользователя по email",
+		Few: "Ваш код привязки: %s\nЗарегистрируйтесь с ним как link_code в течение %d минут, чтобы входить в того же пользователя по email",
+		Many: "Ваш код привязки: %s\nЗарегистрируйтесь с ним как link_code в течение %d минут, чтобы входить в того же пользователя по email",
+	},
+	"account.merge_code": {
+		One: "Ваш код объединения: %s\nОтправьте /merge %s из другого аккаунта telegram или используйте его на сайте в течение %d минуты. Этот пользователь будет объединён с тем аккаунтом",
+		Few: "Ваш код объединения: %s\nОтправьте /merge %s из другого аккаунта telegram или используйте его на сайте в течение %d минут. Этот пользователь будет объединён с тем аккаунтом",
+		Many: "Ваш код объединения: %s\nОтправьте /merge %s из другого аккаунта telegram или используйте его на сайте в течение %d минут. Этот пользователь будет объединён с тем аккаунтом",
+	},
+	"account.merged": {Other: "Аккаунты объединены, всё теперь принадлежит %s"},
+	"account.invalid_credentials": {Other: "Неверный email или пароль"},
+	"account.invalid_token": {Other: "Токен недействителен или истёк"},
+	"account.email_taken": {Other: "Этот email уже зарегистрирован"},
+	"account.invalid_email": {Other: "Некорректный email %s"},
+	"account.password_short": {Other: "Пароль должен быть не короче %d символов"},
+	"account.password_long": {Other: "Пароль должен быть не длиннее 72 байт"},
+	"account.exists": {Other: "У пользователя %s уже есть аккаунт"},
+	"account.merge_same": {Other: "Код нужно использовать из другого аккаунта"},
+	"account.merge_emails": {Other: "У обоих пользователей есть аккаунт с email, сохранить можно только один"},
+
+	"token.created": {Other: "Сервисный аккаунт %s создан с токеном #%d (%s, %s)\n%s\nПередавайте его как bearer-токен, больше он показан не будет"},
+	"token.none": {Other: "Токены доступа не выпущены"},
+	"token.line": {Other: "#%d %s, пользователь %d, пространство %s (%s, %s)"},
+	"token.revoked": {Other: "Токен #%d отозван"},
+	"token.never_expires": {Other: "бессрочный"},
+	"token.expires": {Other: "истекает %s"},
+	"token.missing_scope": {Other: "Токен не даёт нужного права"},
+	"token.negative_lifetime": {Other: "Срок действия токена не может быть отрицательным"},
+	"token.no_scopes": {Other: "Нужно хотя бы одно право: %s"},
+	"token.unknown_scope": {Other: "Неизвестное право %s, ожидается одно из: %s"},
+
+	"webhook.added": {Other: "Вебхук #%d добавлен для %s\nСекрет подписи: %s\nДанные подписываются HMAC-SHA256 в заголовке X-Webhook-Signature"},
+	"webhook.none": {Other: "Вебхуки не зарегистрированы"},
+	"webhook.removed": {Other: "Вебхук #%d удалён"},
+	"webhook.all_events": {Other: "все события"},
+	"webhook.no_dead_letters": {Other: "Недоставленных событий нет"},
+	"webhook.dead_letter": {
+		One: "%s вебхук #%d %s после %d попытки: %s",
+		Few: "%s вебхук #%d %s после %d попыток: %s",
+		Many: "%s вебхук #%d %s после %d попыток: %s",
+	},
+	"webhook.invalid_url": {Other: "Адрес вебхука должен быть абсолютным адресом http или https, получено %s"},
+	"webhook.unknown_event": {Other: "Неизвестное событие %s"},
+
+	"workspace.current": {Other: "Этот чат работает в пространстве %s"},
+	"workspace.created": {Other: "Пространство %s создано, этот чат теперь работает в нём"},
+	"workspace.bound": {Other: "Этот чат теперь работает в пространстве %s"},
+	"workspace.shared": {Other: "%s доступен пространству %s"},
+	"workspace.unshared": {Other: "%s больше не доступен пространству %s"},
+	"workspace.admin_added": {Other: "%s теперь управляет пространством %s"},
+	"workspace.admin_removed": {Other: "%s больше не управляет пространством %s"},
+	"workspace.not_admin": {Other: "Вы не управляете пространством %s"},
+	"workspace.unknown_user": {Other: "Пользователь %d ещё не писал боту"},
+	"workspace.invalid_name": {Other: "Название пространства должно быть одним словом"},
+	"workspace.exists": {Other: "Пространство %s уже существует"},
+}
@@ -0,0 +1,141 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	maxLineOctets = 75
+)
+
+// Calendar is an RFC 5545 VCALENDAR holding events only
+type Calendar struct {
+	ProductId string
+	Name string
+	Events []Event
+}
+
+type Event struct {
+	UID string
+	Stamp time.Time
+	Start time.Time
+	End time.Time
+	Summary string
+	Description string
+	Location string
+	// Status is one of StatusConfirmed, StatusTentative or StatusCancelled, empty omits the property
+	Status string
+	// RRule is a recurrence rule value, e.g. "FREQ=WEEKLY;BYDAY=MO;COUNT=4"
+	RRule string
+	// ExDates are the starts of the recurrence instances excluded from the series
+	ExDates []time.Time
+	// Sequence is the revision number of the event, clients replace events with a lower one
+	Sequence int
+}
+
+func (c Calendar) Render(w io.Writer) error {
+	var lines []string
+
+	lines = append(lines, "BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:" + escape(c.ProductId), "CALSCALE:GREGORIAN", "METHOD:PUBLISH")
+	if c.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:" + escape(c.Name))
+	}
+	for _, e := range c.Events {
+		lines = append(lines, e.lines()...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, fold(line)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c Calendar) String() string {
+	var sb strings.Builder
+	c.Render(&sb)
+
+	return sb.String()
+}
+
+func (e Event) lines() []string {
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + escape(e.UID),
+		"DTSTAMP:" + formatTime(e.Stamp),
+		"DTSTART:" + formatTime(e.Start),
+		"DTEND:" + formatTime(e.End),
+		"SUMMARY:" + escape(e.Summary),
+	}
+	if e.Description != "" {
+		lines = append(lines, "DESCRIPTION:" + escape(e.Description))
+	}
+	if e.Location != "" {
+		lines = append(lines, "LOCATION:" + escape(e.Location))
+	}
+	if e.Status != "" {
+		lines = append(lines, "STATUS:" + e.Status)
+	}
+	if e.Sequence > 0 {
+		lines = append(lines, fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	}
+	if e.RRule != "" {
+		lines = append(lines, "RRULE:" + e.RRule)
+	}
+	if len(e.ExDates) > 0 {
+		var dates []string
+		for _, d := range e.ExDates {
+			dates = append(dates, formatTime(d))
+		}
+		lines = append(lines, "EXDATE:" + strings.Join(dates, ","))
+	}
+
+	return append(lines, "END:VEVENT")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// escape escapes TEXT property values, see RFC 5545 section 3.3.11
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// fold splits the content line into lines of at most 75 octets without breaking UTF-8 sequences
+func fold(line string) string {
+	var sb strings.Builder
+	octets := 0
+
+	for _, r := range line {
+		size := len(string(r))
+		if octets + size > maxLineOctets {
+			sb.WriteString("\r\n ")
+			octets = 1
+		}
+		sb.WriteRune(r)
+		octets += size
+	}
+	sb.WriteString("\r\n")
+
+	return sb.String()
+}
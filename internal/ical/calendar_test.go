@@ -0,0 +1,105 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/ical"
+	readmodel "github.com/SneedusSnake/Reservations/internal/read_model"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRender(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+	start := time.Date(2026, 3, 2, 13, 0, 0, 0, moscow)
+	stamp := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	t.Run("it renders events in UTC with CRLF line endings", func(t *testing.T) {
+		calendar := ical.Calendar{
+			ProductId: "-//Reservations//EN",
+			Name: "Bench",
+			Events: []ical.Event{{
+				UID: "reservation-1@reservations",
+				Stamp: stamp,
+				Start: start,
+				End: start.Add(time.Hour),
+				Summary: "Bench, reserved by Alice; again",
+				Status: ical.StatusConfirmed,
+			}},
+		}
+
+		expected := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//Reservations//EN",
+			"CALSCALE:GREGORIAN",
+			"METHOD:PUBLISH",
+			"X-WR-CALNAME:Bench",
+			"BEGIN:VEVENT",
+			"UID:reservation-1@reservations",
+			"DTSTAMP:20260301T093000Z",
+			"DTSTART:20260302T100000Z",
+			"DTEND:20260302T110000Z",
+			`SUMMARY:Bench\, reserved by Alice\; again`,
+			"STATUS:CONFIRMED",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\r\n") + "\r\n"
+		assert.Equal(t, expected, calendar.String())
+	})
+
+	t.Run("it renders recurring series with exceptions and cancellations", func(t *testing.T) {
+		calendar := ical.Calendar{Events: []ical.Event{
+			{
+				UID: "series@reservations",
+				Stamp: stamp,
+				Start: start,
+				End: start.Add(time.Hour),
+				Summary: "Standup",
+				RRule: "FREQ=WEEKLY;BYDAY=MO;COUNT=4",
+				ExDates: []time.Time{start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)},
+			},
+			{
+				UID: "cancelled@reservations",
+				Stamp: stamp,
+				Start: start,
+				End: start.Add(time.Hour),
+				Summary: "Bench",
+				Status: ical.StatusCancelled,
+				Sequence: 1,
+			},
+		}}
+
+		rendered := calendar.String()
+		assert.Contains(t, rendered, "RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=4\r\n")
+		assert.Contains(t, rendered, "EXDATE:20260309T100000Z,20260316T100000Z\r\n")
+		assert.Contains(t, rendered, "STATUS:CANCELLED\r\nSEQUENCE:1\r\n")
+	})
+
+	t.Run("it folds long lines at 75 octets", func(t *testing.T) {
+		calendar := ical.Calendar{Events: []ical.Event{{
+			Summary: strings.Repeat("Стенд ", 20),
+		}}}
+
+		for _, line := range strings.Split(strings.TrimSuffix(calendar.String(), "\r\n"), "\r\n") {
+			assert.True(t, len(line) <= 75, "line %q is longer than 75 octets", line)
+		}
+		assert.Contains(t, calendar.String(), "\r\n Стенд")
+	})
+}
+
+func TestReservationsCalendar(t *testing.T) {
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	rs := []readmodel.Reservation{
+		{Id: 1, Subject: "Bench", User: "Alice", Start: start, End: start.Add(time.Hour)},
+		{Id: 2, Subject: "Bench", User: "Bob", Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Cancelled: true},
+	}
+
+	calendar := ical.ReservationsCalendar("Bench", rs, start)
+	assert.Equal(t, ical.StatusConfirmed, calendar.Events[0].Status)
+	assert.Equal(t, 0, calendar.Events[0].Sequence)
+	assert.Equal(t, ical.StatusCancelled, calendar.Events[1].Status)
+	assert.Equal(t, 1, calendar.Events[1].Sequence)
+}
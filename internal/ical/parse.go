@@ -0,0 +1,271 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	localDateTimeFormat = "20060102T150405"
+	dateFormat = "20060102"
+)
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+type property struct {
+	name string
+	params map[string]string
+	value string
+}
+
+// Parse reads the events of a VCALENDAR. Times without a zone are read in loc,
+// all times are returned in loc. Events without DTEND end after their DURATION,
+// all-day events last until the next day
+func Parse(r io.Reader, loc *time.Location) (Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+	if len(lines) == 0 {
+		return Calendar{}, fmt.Errorf("Empty calendar")
+	}
+
+	var calendar Calendar
+	var event *Event
+	var hasEnd bool
+	var duration time.Duration
+	var components []string
+
+	for i, line := range lines {
+		prop, err := parseProperty(line)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("Line %d: %w", i+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			if len(components) == 0 && component != "VCALENDAR" {
+				return Calendar{}, fmt.Errorf("Line %d: expected BEGIN:VCALENDAR", i+1)
+			}
+			if component == "VEVENT" && len(components) == 1 && components[0] == "VCALENDAR" {
+				event = &Event{}
+				hasEnd = false
+				duration = 0
+			}
+			components = append(components, component)
+			continue
+		case "END":
+			component := strings.ToUpper(prop.value)
+			if len(components) == 0 || components[len(components)-1] != component {
+				return Calendar{}, fmt.Errorf("Line %d: unexpected END:%s", i+1, prop.value)
+			}
+			components = components[:len(components)-1]
+			if component == "VEVENT" && event != nil {
+				if !hasEnd {
+					event.End = event.Start.Add(duration)
+				}
+				calendar.Events = append(calendar.Events, *event)
+				event = nil
+			}
+			continue
+		}
+
+		if len(components) == 0 {
+			return Calendar{}, fmt.Errorf("Line %d: property %s outside of VCALENDAR", i+1, prop.name)
+		}
+
+		switch components[len(components)-1] {
+		case "VCALENDAR":
+			switch prop.name {
+			case "PRODID":
+				calendar.ProductId = unescape(prop.value)
+			case "X-WR-CALNAME":
+				calendar.Name = unescape(prop.value)
+			}
+		case "VEVENT":
+			if event == nil {
+				continue
+			}
+			switch prop.name {
+			case "UID":
+				event.UID = unescape(prop.value)
+			case "DTSTAMP":
+				event.Stamp, _, err = parseTime(prop, loc)
+			case "DTSTART":
+				var allDay bool
+				event.Start, allDay, err = parseTime(prop, loc)
+				if allDay && !hasEnd && duration == 0 {
+					duration = 24 * time.Hour
+				}
+			case "DTEND":
+				event.End, _, err = parseTime(prop, loc)
+				hasEnd = true
+			case "DURATION":
+				duration, err = parseDuration(prop.value)
+			case "SUMMARY":
+				event.Summary = unescape(prop.value)
+			case "DESCRIPTION":
+				event.Description = unescape(prop.value)
+			case "LOCATION":
+				event.Location = unescape(prop.value)
+			case "STATUS":
+				event.Status = strings.ToUpper(prop.value)
+			case "RRULE":
+				event.RRule = prop.value
+			case "SEQUENCE":
+				event.Sequence, err = strconv.Atoi(prop.value)
+			case "EXDATE":
+				for _, value := range strings.Split(prop.value, ",") {
+					var exDate time.Time
+					exDate, _, err = parseTime(property{prop.name, prop.params, value}, loc)
+					if err != nil {
+						break
+					}
+					event.ExDates = append(event.ExDates, exDate)
+				}
+			}
+			if err != nil {
+				return Calendar{}, fmt.Errorf("Line %d: invalid %s: %w", i+1, prop.name, err)
+			}
+		}
+	}
+
+	if len(components) > 0 {
+		return Calendar{}, fmt.Errorf("Missing END:%s", components[len(components)-1])
+	}
+
+	return calendar, nil
+}
+
+// unfold joins folded content lines, see RFC 5545 section 3.1
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+func parseProperty(line string) (property, error) {
+	quoted := false
+	separators := []int{}
+	colon := -1
+
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		}
+		if quoted {
+			continue
+		}
+		if c == ';' {
+			separators = append(separators, i)
+		}
+		if c == ':' {
+			colon = i
+			break
+		}
+	}
+
+	if colon == -1 {
+		return property{}, fmt.Errorf("missing ':' in %q", line)
+	}
+
+	head := line[:colon]
+	prop := property{value: line[colon+1:], params: make(map[string]string)}
+	if len(separators) == 0 {
+		prop.name = strings.ToUpper(head)
+		return prop, nil
+	}
+
+	prop.name = strings.ToUpper(head[:separators[0]])
+	separators = append(separators, colon)
+	for i := 0; i < len(separators)-1; i++ {
+		param := line[separators[i]+1:separators[i+1]]
+		name, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+
+	return prop, nil
+}
+
+// parseTime reads DATE-TIME and DATE values and reports whether the value is a date
+func parseTime(prop property, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if strings.ToUpper(prop.params["VALUE"]) == "DATE" || len(value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeFormat, value)
+		return t.In(loc), false, err
+	}
+
+	zone := loc
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		zone, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %s", tzid)
+		}
+	}
+	t, err := time.ParseInLocation(localDateTimeFormat, value, zone)
+
+	return t.In(loc), false, err
+}
+
+// parseDuration reads DURATION values like PT1H30M or P1D, see RFC 5545 section 3.3.6
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("malformed duration %s", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, err
+		}
+		duration += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		duration = -duration
+	}
+
+	return duration, nil
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
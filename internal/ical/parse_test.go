@@ -0,0 +1,96 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/ical"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestParse(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+
+	t.Run("it reads back rendered calendars", func(t *testing.T) {
+		start := time.Date(2026, 3, 2, 13, 0, 0, 0, moscow)
+		calendar := ical.Calendar{
+			ProductId: ical.ProductId,
+			Name: "Bench",
+			Events: []ical.Event{{
+				UID: "reservation-1@reservations",
+				Stamp: start.Add(-time.Hour),
+				Start: start,
+				End: start.Add(time.Hour),
+				Summary: "Bench, reserved by Alice; again " + strings.Repeat("long ", 20),
+				Description: "first\nsecond",
+				Location: `Lab \ 2`,
+				Status: ical.StatusTentative,
+				Sequence: 2,
+				RRule: "FREQ=WEEKLY;COUNT=4",
+				ExDates: []time.Time{start.Add(7 * 24 * time.Hour)},
+			}},
+		}
+
+		parsed, err := ical.Parse(strings.NewReader(calendar.String()), moscow)
+
+		assert.NoError(t, err)
+		assert.Equal(t, calendar, parsed)
+	})
+
+	t.Run("it reads zoned, floating and all-day times and durations", func(t *testing.T) {
+		input := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"BEGIN:VTIMEZONE",
+			"TZID:Europe/Berlin",
+			"END:VTIMEZONE",
+			"BEGIN:VEVENT",
+			`DTSTART;TZID="Europe/Berlin":20260302T090000`,
+			"DURATION:PT1H30M",
+			"SUMMARY:Microscope",
+			"BEGIN:VALARM",
+			"SUMMARY:Reminder",
+			"END:VALARM",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"DTSTART:20260303T100000",
+			"DTEND:20260303T110000",
+			"SUMMARY:Floating",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"DTSTART;VALUE=DATE:20260304",
+			"SUMMARY:All day",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\n")
+
+		parsed, err := ical.Parse(strings.NewReader(input), moscow)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(parsed.Events))
+		assert.Equal(t, time.Date(2026, 3, 2, 11, 0, 0, 0, moscow), parsed.Events[0].Start)
+		assert.Equal(t, time.Date(2026, 3, 2, 12, 30, 0, 0, moscow), parsed.Events[0].End)
+		assert.Equal(t, "Microscope", parsed.Events[0].Summary)
+		assert.Equal(t, time.Date(2026, 3, 3, 10, 0, 0, 0, moscow), parsed.Events[1].Start)
+		assert.Equal(t, time.Date(2026, 3, 3, 11, 0, 0, 0, moscow), parsed.Events[1].End)
+		assert.Equal(t, time.Date(2026, 3, 4, 0, 0, 0, 0, moscow), parsed.Events[2].Start)
+		assert.Equal(t, time.Date(2026, 3, 5, 0, 0, 0, 0, moscow), parsed.Events[2].End)
+	})
+
+	t.Run("it rejects malformed calendars", func(t *testing.T) {
+		for _, input := range []string{
+			"",
+			"BEGIN:VEVENT\nEND:VEVENT",
+			"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:tomorrow\nEND:VEVENT\nEND:VCALENDAR",
+			"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;TZID=Mars/Olympus:20260303T100000\nEND:VEVENT\nEND:VCALENDAR",
+			"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDURATION:1 hour\nEND:VEVENT\nEND:VCALENDAR",
+			"BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR",
+			"BEGIN:VCALENDAR\nVERSION",
+		} {
+			_, err := ical.Parse(strings.NewReader(input), moscow)
+			assert.Error(t, err, input)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+
+	readmodel "github.com/SneedusSnake/Reservations/internal/read_model"
+)
+
+const ProductId = "-//SneedusSnake//Reservations//EN"
+
+// ReservationsCalendar renders every reservation as a single event, confirmed or cancelled once removed.
+// Reservations are not recurring, so RRULE and EXDATE are not produced here
+func ReservationsCalendar(name string, rs []readmodel.Reservation, stamp time.Time) Calendar {
+	calendar := Calendar{ProductId: ProductId, Name: name}
+
+	for _, r := range rs {
+		event := Event{
+			UID: fmt.Sprintf("reservation-%d@reservations", r.Id),
+			Stamp: stamp,
+			Start: r.Start,
+			End: r.End,
+			Summary: fmt.Sprintf("%s reserved by %s", r.Subject, r.User),
+			Location: r.Subject,
+			Status: StatusConfirmed,
+		}
+		// the cancellation is a revision of the confirmed event the clients already have
+		if r.Cancelled {
+			event.Status = StatusCancelled
+			event.Sequence = 1
+		}
+		calendar.Events = append(calendar.Events, event)
+	}
+
+	return calendar
+}
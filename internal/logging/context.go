@@ -0,0 +1,21 @@
+package logging
+
+import (
+	"context"
+	"log"
+)
+
+type loggerKey struct{}
+
+func WithLogger(ctx context.Context, logger *log.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger, falling back to the standard one
+func FromContext(ctx context.Context) *log.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*log.Logger); ok {
+		return logger
+	}
+
+	return log.Default()
+}
@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+type Event struct {
+	Type string
+	OccurredAt time.Time
+	// Data is the JSON serializable payload of the event
+	Data any
+}
+
+// EventPublisher notifies about changes made by the application, publishing must not fail the change itself
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// Publishers publishes every event to each of the publishers in order
+type Publishers []EventPublisher
+
+func (p Publishers) Publish(ctx context.Context, event Event) {
+	for _, publisher := range p {
+		publisher.Publish(ctx, event)
+	}
+}
@@ -0,0 +1,13 @@
+package ports
+
+import "context"
+
+type Mail struct {
+	To string
+	Subject string
+	Body string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
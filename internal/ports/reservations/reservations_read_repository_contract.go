@@ -1,10 +1,13 @@
 package reservations
 
 import (
+	"context"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
 	domain "github.com/SneedusSnake/Reservations/internal/domain/users"
 	"github.com/SneedusSnake/Reservations/internal/ports/users"
 	readmodel "github.com/SneedusSnake/Reservations/internal/read_model"
@@ -17,24 +20,27 @@ type ReservationsReadRepositoryContract struct {
 }
 
 func (r ReservationsReadRepositoryContract) Test (t *testing.T, reservationsStorage ReservationsRepository, usersStorage users.UsersRepository, subjectsStorage SubjectsRepository) {
+	ctx := context.Background()
 	store := r.NewRepository()
 	factory := builder(t, reservationsStorage)
 	now, err := time.Parse(time.DateTime, "2025-02-01 14:00:00")
 	assert.NoError(t, err)
 
 	subjects := reservations.Subjects{
-		reservations.Subject{Id: 1, Name: "Subject#1"},
-		reservations.Subject{Id: 2, Name: "Subject#2"},
-		reservations.Subject{Id: 3, Name: "Subject#3"},
+		reservations.Subject{Id: 1, Name: "Subject#1", WorkspaceId: workspaces.Default},
+		reservations.Subject{Id: 2, Name: "Subject#2", WorkspaceId: workspaces.Default},
+		reservations.Subject{Id: 3, Name: "Subject#3", WorkspaceId: workspaces.Default},
+		reservations.Subject{Id: 4, Name: "Subject#4", WorkspaceId: workspaces.Default},
+		reservations.Subject{Id: 5, Name: "Subject#5", WorkspaceId: workspaces.Default},
 	}
 
 	for _, s := range subjects {
-		err := subjectsStorage.Add(s)
+		err := subjectsStorage.Add(ctx, s)
 		assert.NoError(t, err)
 	}
 
-	subjectsStorage.AddTag(subjects[0].Id, "test")
-	subjectsStorage.AddTag(subjects[2].Id, "test")
+	subjectsStorage.AddTag(ctx, subjects[0].Id, "test")
+	subjectsStorage.AddTag(ctx, subjects[2].Id, "test")
 
 	users := []domain.User{
 		{Id: 1, Name: "Alice"},
@@ -42,25 +48,35 @@ func (r ReservationsReadRepositoryContract) Test (t *testing.T, reservationsStor
 	}
 
 	for _, u := range users {
-		err := usersStorage.Add(u)
+		err := usersStorage.Add(ctx, u)
 		assert.NoError(t, err)
 	}
 
 	cleanUp := factory.CleanUp
 
 	t.Run("It returns error when no reservation is found", func(t *testing.T) {
-		_, err := store.Get(12345)
+		_, err := store.Get(ctx, 12345)
 
 		assert.Error(t, err)
 	})
 
+	t.Run("It honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := store.Get(cancelled, 1)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.Active(cancelled, workspaces.Default, now)
+		assert.IsError(t, err, context.Canceled)
+	})
+
 	t.Run("It fetches reservation read model by Id", func(t *testing.T) {
 		cleanUp(t)
 		user := users[1]
 		subject := subjects[0]
 		expected := factory.UserId(user.Id).SubjectId(subject.Id).Persist()
 
-		actual, err := store.Get(1)
+		actual, err := store.Get(ctx, 1)
 		assert.NoError(t, err)
 
 		assert.Equal(t, subject.Name, actual.Subject)
@@ -78,17 +94,20 @@ func (r ReservationsReadRepositoryContract) Test (t *testing.T, reservationsStor
 				blueprint.Persist(),
 				blueprint.SubjectId(subjects[1].Id).Persist(),
 				blueprint.UserId(users[1].Id).SubjectId(subjects[2].Id).Persist(),
-				blueprint.UserId(users[1].Id).Persist(),
+				blueprint.UserId(users[1].Id).SubjectId(subjects[3].Id).Persist(),
 		}
-		blueprint.StartsAt(now.Add(-time.Hour)).EndsAt(now.Add(-time.Minute)).Persist()
+		// inactive ones are kept on separate subjects, since reservations of a subject never overlap
+		blueprint.SubjectId(subjects[3].Id).StartsAt(now.Add(-time.Hour)).EndsAt(now.Add(-time.Minute)).Persist()
+		blueprint.SubjectId(subjects[4].Id).StartsAt(now.Add(-time.Hour)).EndsAt(now).Persist()
+		blueprint.SubjectId(subjects[4].Id).StartsAt(now.Add(time.Minute)).EndsAt(now.Add(time.Hour)).Persist()
 
-		list, err := store.Active(now)
+		list, err := store.Active(ctx, workspaces.Default, now)
 		assert.NoError(t, err)
 		assert.Equal(t, len(expectedReservations), len(list))
 
 		for _, r := range expectedReservations {
-			u, _ := usersStorage.Get(r.UserId)
-			s, _ := subjectsStorage.Get(r.SubjectId)
+			u, _ := usersStorage.Get(ctx, r.UserId)
+			s, _ := subjectsStorage.Get(ctx, r.SubjectId)
 			assert.SliceContains(t, list, readmodel.Reservation{
 				Id: r.Id,
 				User: u.Name,
@@ -99,6 +118,50 @@ func (r ReservationsReadRepositoryContract) Test (t *testing.T, reservationsStor
 		}
 	})
 
+	t.Run("It fetches reservations of a subject and of a user ordered by start", func(t *testing.T) {
+		cleanUp(t)
+		blueprint := factory.UserId(users[0].Id).SubjectId(subjects[0].Id)
+		later := blueprint.StartsAt(now.Add(time.Hour)).EndsAt(now.Add(2 * time.Hour)).Persist()
+		earlier := blueprint.StartsAt(now.Add(-time.Hour)).EndsAt(now).Persist()
+		other := blueprint.UserId(users[1].Id).SubjectId(subjects[1].Id).StartsAt(now).EndsAt(now.Add(time.Hour)).Persist()
+
+		list, err := store.ForSubject(ctx, subjects[0].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{earlier.Id, later.Id}, ids(list))
+
+		list, err = store.ForUser(ctx, users[1].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{other.Id}, ids(list))
+		i := slices.IndexFunc(list, func(r readmodel.Reservation) bool { return r.Id == other.Id })
+		assert.Equal(t, subjects[1].Name, list[i].Subject)
+		assert.Equal(t, users[1].Name, list[i].User)
+	})
+
+	t.Run("It lists removed reservations as cancelled", func(t *testing.T) {
+		cleanUp(t)
+		blueprint := factory.UserId(users[1].Id).SubjectId(subjects[4].Id).StartsAt(now.Add(24 * time.Hour)).EndsAt(now.Add(25 * time.Hour))
+		removed := blueprint.Persist()
+		kept := blueprint.StartsAt(now.Add(26 * time.Hour)).EndsAt(now.Add(27 * time.Hour)).Persist()
+		assert.NoError(t, reservationsStorage.Remove(ctx, removed.Id))
+
+		list, err := store.ForSubject(ctx, subjects[4].Id)
+		assert.NoError(t, err)
+		assert.SliceContains(t, list, readmodel.Reservation{
+			Id: removed.Id,
+			Subject: subjects[4].Name,
+			User: users[1].Name,
+			Start: removed.Start,
+			End: removed.End,
+			Cancelled: true,
+		})
+		assert.Equal(t, []int{kept.Id}, ids(list))
+		list, err = store.ForUser(ctx, users[1].Id)
+		assert.NoError(t, err)
+		assert.True(t, slices.ContainsFunc(list, func(r readmodel.Reservation) bool { return r.Id == removed.Id && r.Cancelled }))
+		_, err = reservationsStorage.Get(ctx, removed.Id)
+		assert.Error(t, err)
+	})
+
 	t.Run("It fetches active reservations list filtered by tags", func(t *testing.T) {
 		cleanUp(t)
 		blueprint := factory.UserId(users[0].Id).StartsAt(now).EndsAt(now.Add(time.Hour))
@@ -106,10 +169,58 @@ func (r ReservationsReadRepositoryContract) Test (t *testing.T, reservationsStor
 		blueprint.SubjectId(subjects[1].Id).Persist()
 		blueprint.SubjectId(subjects[2].Id).Persist()
 
-		list, err := store.Active(now, "test")
+		list, err := store.Active(ctx, workspaces.Default, now, "test")
 		assert.NoError(t, err)
 		assert.Equal(t, 2, len(list))
 		assert.Equal(t, "Subject#1", list[0].Subject)
 		assert.Equal(t, "Subject#3", list[1].Subject)
 	})
+
+	t.Run("It fetches active reservations of the subjects visible in the workspace", func(t *testing.T) {
+		cleanUp(t)
+		hardware := reservations.Subject{Id: 6, Name: "Subject#6", WorkspaceId: 12}
+		assert.NoError(t, subjectsStorage.Add(ctx, hardware))
+		t.Cleanup(func() {
+			subjectsStorage.Remove(ctx, hardware.Id)
+		})
+		blueprint := factory.UserId(users[0].Id).StartsAt(now).EndsAt(now.Add(time.Hour))
+		blueprint.SubjectId(subjects[0].Id).Persist()
+		blueprint.SubjectId(hardware.Id).Persist()
+
+		list, err := store.Active(ctx, workspaces.Default, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Subject#1"}, names(list))
+		list, err = store.Active(ctx, hardware.WorkspaceId, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Subject#6"}, names(list))
+
+		assert.NoError(t, subjectsStorage.Share(ctx, subjects[0].Id, hardware.WorkspaceId))
+		t.Cleanup(func() {
+			subjectsStorage.Unshare(ctx, subjects[0].Id, hardware.WorkspaceId)
+		})
+		list, err = store.Active(ctx, hardware.WorkspaceId, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Subject#1", "Subject#6"}, names(list))
+	})
+}
+
+func names(list []readmodel.Reservation) []string {
+	var result []string
+	for _, r := range list {
+		result = append(result, r.Subject)
+	}
+
+	return result
+}
+
+// ids leaves out the cancelled reservations, which the clean ups of other tests leave behind
+func ids(list []readmodel.Reservation) []int {
+	var result []int
+	for _, r := range list {
+		if !r.Cancelled {
+			result = append(result, r.Id)
+		}
+	}
+
+	return result
 }
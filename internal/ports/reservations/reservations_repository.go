@@ -1,23 +1,31 @@
 package reservations
 
 import (
+	"context"
+	"time"
+
 	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
 	"github.com/SneedusSnake/Reservations/internal/read_model"
-	"time"
 )
 
 type ReservationsRepository interface {
-	NextIdentity() (int, error)
-	List() (reservations.Reservations, error)
-	Add(reservation reservations.Reservation) error
-	Get(id int) (reservations.Reservation, error)
-	Remove(id int) error
-	ForPeriod(from time.Time, to time.Time) (reservations.Reservations, error)
+	NextIdentity(ctx context.Context) (int, error)
+	// AdvanceIdentity makes NextIdentity hand out ids above id
+	AdvanceIdentity(ctx context.Context, id int) error
+	List(ctx context.Context) (reservations.Reservations, error)
+	Add(ctx context.Context, reservation reservations.Reservation) error
+	AddMany(ctx context.Context, rs reservations.Reservations) error
+	Get(ctx context.Context, id int) (reservations.Reservation, error)
+	// Remove keeps the reservation among the cancelled ones the read repository lists
+	Remove(ctx context.Context, id int) error
+	ForPeriod(ctx context.Context, from time.Time, to time.Time) (reservations.Reservations, error)
 }
 
 type ReservationsReadRepository interface {
-	Get(id int) (readmodel.Reservation, error)
-	Active(t time.Time, tags ...string) ([]readmodel.Reservation, error)
+	Get(ctx context.Context, id int) (readmodel.Reservation, error)
+	// Active returns the reservations of the subjects visible in the workspace
+	Active(ctx context.Context, workspaceId int, t time.Time, tags ...string) ([]readmodel.Reservation, error)
+	// ForSubject and ForUser list the removed reservations as well, marked cancelled
+	ForSubject(ctx context.Context, subjectId int) ([]readmodel.Reservation, error)
+	ForUser(ctx context.Context, userId int) ([]readmodel.Reservation, error)
 }
-
-
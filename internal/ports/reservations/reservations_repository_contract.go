@@ -1,6 +1,7 @@
 package reservations
 
 import (
+	"context"
 	"math/rand/v2"
 	"slices"
 	"testing"
@@ -16,9 +17,10 @@ type ReservationsRepositoryContract struct {
 }
 
 func (r ReservationsRepositoryContract) Test (t *testing.T) {
+	ctx := context.Background()
 	t.Run("it returns error when the reservation was not found", func (t *testing.T) {
 		store := r.NewRepository()
-		_, err := store.Get(1234567)
+		_, err := store.Get(ctx, 1234567)
 
 		if err == nil {
 			t.Error("expected to see error, got nil")
@@ -29,19 +31,58 @@ func (r ReservationsRepositoryContract) Test (t *testing.T) {
 		store := r.NewRepository()
 		reservation := builder(t, store).Persist()
 
-		foundReservation, err := store.Get(reservation.Id)
+		foundReservation, err := store.Get(ctx, reservation.Id)
 		assert.NoError(t, err)
 		assert.Equal(t, reservation, foundReservation)
 	})
 
+	t.Run("it adds several reservations at once", func (t *testing.T) {
+		store := r.NewRepository()
+		blueprint := builder(t, store)
+		expected := domain.Reservations{
+			blueprint.Make(),
+			blueprint.Make(),
+		}
+
+		err := store.AddMany(ctx, expected)
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			for _, reservation := range expected {
+				store.Remove(ctx, reservation.Id)
+			}
+		})
+
+		for _, reservation := range expected {
+			found, err := store.Get(ctx, reservation.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, reservation, found)
+		}
+	})
+
+	t.Run("it adds none of the reservations given one of them cannot be added", func (t *testing.T) {
+		store := r.NewRepository()
+		existing := builder(t, store).Persist()
+		fresh := builder(t, store).Make()
+
+		err := store.AddMany(ctx, domain.Reservations{fresh, existing})
+		if err == nil {
+			t.Cleanup(func() {
+				store.Remove(ctx, fresh.Id)
+			})
+		}
+
+		_, err = store.Get(ctx, fresh.Id)
+		assert.Error(t, err)
+	})
+
 	t.Run("it removes reservation from the store", func (t *testing.T) {
 		store := r.NewRepository()
 		reservation := builder(t, store).Persist()
 
-		err := store.Remove(reservation.Id)
+		err := store.Remove(ctx, reservation.Id)
 		assert.NoError(t, err)
 
-		_, err = store.Get(reservation.Id)
+		_, err = store.Get(ctx, reservation.Id)
 		assert.Error(t, err)
 	})
 
@@ -65,7 +106,7 @@ func (r ReservationsRepositoryContract) Test (t *testing.T) {
 		//future reservations relative to given period
 		blueprint.StartsAt(to.Add(time.Second)).EndsAt(to.Add(time.Hour)).Persist()
 
-		reservations, err := store.ForPeriod(from, to)
+		reservations, err := store.ForPeriod(ctx, from, to)
 		assert.NoError(t, err)
 		assert.Equal(t, len(reservations), len(expectedReservations))
 
@@ -74,6 +115,110 @@ func (r ReservationsRepositoryContract) Test (t *testing.T) {
 		}
 	})
 
+	t.Run("it keeps the instant of times in other zones", func (t *testing.T) {
+		store := r.NewRepository()
+		moscow := time.FixedZone("MSK", 3 * 60 * 60)
+		start := time.Date(2025, 9, 22, 12, 0, 0, 0, moscow)
+		reservation := builder(t, store).StartsAt(start).EndsAt(start.Add(time.Hour)).Persist()
+
+		found, err := store.Get(ctx, reservation.Id)
+		assert.NoError(t, err)
+		assert.True(t, found.Start.Equal(start), found.Start.String())
+		assert.True(t, found.End.Equal(start.Add(time.Hour)), found.End.String())
+
+		utc := time.Date(2025, 9, 22, 9, 30, 0, 0, time.UTC)
+		list, err := store.ForPeriod(ctx, utc, utc.Add(time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(list))
+		assert.Equal(t, reservation.Id, list[0].Id)
+	})
+
+	t.Run("it treats reservations and periods as half-open intervals", func (t *testing.T) {
+		store := r.NewRepository()
+		from, err := time.Parse(time.DateTime, "2025-09-21 12:00:00")
+		assert.NoError(t, err)
+		to := from.Add(time.Hour)
+		blueprint := builder(t, store)
+
+		containing := blueprint.StartsAt(from.Add(-time.Hour)).EndsAt(to.Add(time.Hour)).Persist()
+		contained := blueprint.StartsAt(from.Add(time.Minute*10)).EndsAt(to.Add(-time.Minute*10)).Persist()
+		endingAtStart := blueprint.StartsAt(from.Add(-time.Hour)).EndsAt(from).Persist()
+		startingAtEnd := blueprint.StartsAt(to).EndsAt(to.Add(time.Hour)).Persist()
+		blueprint.StartsAt(from.Add(time.Minute*30)).EndsAt(from.Add(time.Minute*30)).Persist()
+
+		t.Run("it includes reservations containing or contained by the period", func (t *testing.T) {
+			result, err := store.ForPeriod(ctx, from, to)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(result))
+			assert.SliceContains(t, result, containing)
+			assert.SliceContains(t, result, contained)
+		})
+
+		t.Run("it excludes adjacent reservations", func (t *testing.T) {
+			result, err := store.ForPeriod(ctx, from, to)
+			assert.NoError(t, err)
+			for _, r := range result {
+				assert.NotEqual(t, endingAtStart.Id, r.Id)
+				assert.NotEqual(t, startingAtEnd.Id, r.Id)
+			}
+
+			result, err = store.ForPeriod(ctx, to, to.Add(time.Minute))
+			assert.NoError(t, err)
+			assert.Equal(t, domain.Reservations{containing, startingAtEnd}, sorted(result))
+		})
+
+		t.Run("it finds nothing for zero-length or reversed periods", func (t *testing.T) {
+			result, err := store.ForPeriod(ctx, from.Add(time.Minute*30), from.Add(time.Minute*30))
+			assert.NoError(t, err)
+			assert.Equal(t, 0, len(result))
+
+			result, err = store.ForPeriod(ctx, to, from)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, len(result))
+		})
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		store := r.NewRepository()
+		blueprint := builder(t, store)
+		existing := blueprint.Persist()
+		fresh := blueprint.Make()
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := store.Get(cancelled, existing.Id)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.List(cancelled)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.ForPeriod(cancelled, existing.Start, existing.End)
+		assert.IsError(t, err, context.Canceled)
+		err = store.Add(cancelled, fresh)
+		assert.IsError(t, err, context.Canceled)
+		err = store.Remove(cancelled, existing.Id)
+		assert.IsError(t, err, context.Canceled)
+
+		_, err = store.Get(ctx, fresh.Id)
+		assert.Error(t, err)
+		_, err = store.Get(ctx, existing.Id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("it advances the identity", func(t *testing.T) {
+		store := r.NewRepository()
+		id, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.AdvanceIdentity(ctx, id + 100))
+		next, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, id + 101, next)
+
+		assert.NoError(t, store.AdvanceIdentity(ctx, id))
+		next, err = store.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, id + 102, next)
+	})
+
 	t.Run("it generates next ID", func(t *testing.T) {
 		store := r.NewRepository()
 		ch := make(chan int, 5)
@@ -81,7 +226,7 @@ func (r ReservationsRepositoryContract) Test (t *testing.T) {
 
 		for range 5 {
 			go (func (c chan int) {
-				id, _ := store.NextIdentity()
+				id, _ := store.NextIdentity(ctx)
 				c <- id
 			})(ch)
 		}
@@ -100,6 +245,12 @@ func (r ReservationsRepositoryContract) Test (t *testing.T) {
 	})
 }
 
+func sorted(rs domain.Reservations) domain.Reservations {
+	return slices.SortedFunc(slices.Values(rs), func(a domain.Reservation, b domain.Reservation) int {
+		return a.Id - b.Id
+	})
+}
+
 type reservationBuilder struct {
 	t testing.TB
 	store ReservationsRepository
@@ -107,7 +258,8 @@ type reservationBuilder struct {
 }
 
 func (builder reservationBuilder) Make() domain.Reservation {
-	id, err := builder.store.NextIdentity()
+	ctx := context.Background()
+	id, err := builder.store.NextIdentity(ctx)
 	assert.NoError(builder.t, err)
 	userId := builder.blueprint.UserId
 	subjectId := builder.blueprint.SubjectId
@@ -118,8 +270,9 @@ func (builder reservationBuilder) Make() domain.Reservation {
 		userId = rand.N(999999) + 1
 	}
 	
+	// a subject per reservation, so that defaults never overlap within a subject
 	if subjectId == 0 {
-		subjectId = rand.N(9999) + 1
+		subjectId = 10000 + id
 	}
 
 	if start.IsZero() {
@@ -140,11 +293,12 @@ func (builder reservationBuilder) Make() domain.Reservation {
 }
 
 func (builder reservationBuilder) Persist() domain.Reservation {
+	ctx := context.Background()
 	result := builder.Make()
-	err := builder.store.Add(result)
+	err := builder.store.Add(ctx, result)
 	assert.NoError(builder.t, err)
 	builder.t.Cleanup(func() {
-		builder.store.Remove(result.Id)
+		builder.store.Remove(ctx, result.Id)
 	})
 
 	return result
@@ -183,12 +337,13 @@ func (builder reservationBuilder) SubjectId(id int) reservationBuilder {
 }
 
 func (builder reservationBuilder) CleanUp(t testing.TB) {
+	ctx := context.Background()
 	t.Cleanup(func() {
-		rs, err := builder.store.List()
+		rs, err := builder.store.List(ctx)
 		assert.NoError(t, err)
 
 		for _, r := range rs {
-			builder.store.Remove(r.Id)
+			builder.store.Remove(ctx, r.Id)
 		}
 	})
 }
@@ -1,15 +1,35 @@
 package reservations
 
-import "github.com/SneedusSnake/Reservations/internal/domain/reservations"
+import (
+	"context"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+)
 
 type SubjectsRepository interface {
-	NextIdentity() (int, error)
-	Add(s reservations.Subject) error
-	Get(id int) (reservations.Subject, error)
-	GetByName(name string) (reservations.Subject, error)
-	List() (reservations.Subjects, error)
-	Remove(id int) error
-	AddTag(id int, tag string) error
-	GetTags(id int) ([]string, error)
-	GetByTags(tags []string) (reservations.Subjects, error)
+	NextIdentity(ctx context.Context) (int, error)
+	// AdvanceIdentity makes NextIdentity hand out ids above id
+	AdvanceIdentity(ctx context.Context, id int) error
+	Add(ctx context.Context, s reservations.Subject) error
+	Get(ctx context.Context, id int) (reservations.Subject, error)
+	// GetByName looks the name up among the subjects visible in the workspace
+	GetByName(ctx context.Context, workspaceId int, name string) (reservations.Subject, error)
+	// List returns the subjects of every workspace
+	List(ctx context.Context) (reservations.Subjects, error)
+	// InWorkspace returns the subjects owned by the workspace and the ones shared with it
+	InWorkspace(ctx context.Context, workspaceId int) (reservations.Subjects, error)
+	Share(ctx context.Context, id int, workspaceId int) error
+	Unshare(ctx context.Context, id int, workspaceId int) error
+	// SharedWith returns the ids of the workspaces the subject is shared with besides its own
+	SharedWith(ctx context.Context, id int) ([]int, error)
+	Remove(ctx context.Context, id int) error
+	AddTag(ctx context.Context, id int, tag string) error
+	GetTags(ctx context.Context, id int) ([]string, error)
+	AddAlias(ctx context.Context, id int, alias string) error
+	GetAliases(ctx context.Context, id int) ([]string, error)
+	// FindByName returns the subjects visible in the workspace named or aliased so, ignoring case
+	FindByName(ctx context.Context, workspaceId int, name string) (reservations.Subjects, error)
+	GetByTags(ctx context.Context, workspaceId int, tags []string) (reservations.Subjects, error)
+	SetParent(ctx context.Context, id int, parentId int) error
+	Children(ctx context.Context, id int) (reservations.Subjects, error)
 }
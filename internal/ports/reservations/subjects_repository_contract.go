@@ -1,10 +1,12 @@
 package reservations
 
 import (
+	"context"
 	"slices"
 	"testing"
 
 	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
 	"github.com/SneedusSnake/Reservations/internal/utils"
 	"github.com/alecthomas/assert/v2"
 )
@@ -15,23 +17,24 @@ type SubjectsRepositoryContract struct {
 }
 
 func (s SubjectsRepositoryContract) Test (t *testing.T) {
+	ctx := context.Background()
 	store := subjectsRepositoryHelper{SubjectsRepository: s.NewStore(), t: t}
 	cleanUp := store.CleanUp
 
 	t.Run("it returns error when the subject was not found", func(t *testing.T) {
-		_, err := store.Get(1234)
+		_, err := store.Get(ctx, 1234)
 
 		assert.Error(t, err)
 	})
 
 	t.Run("it adds a new subject to the store", func(t *testing.T) {
 		cleanUp(t)
-		subject := reservations.Subject{Id: 1, Name: "Test subject"}
+		subject := reservations.Subject{Id: 1, Name: "Test subject", WorkspaceId: workspaces.Default}
 
-		err := store.Add(subject)
+		err := store.Add(ctx, subject)
 		assert.NoError(t, err)
 
-		foundSubject, err := store.Get(subject.Id)
+		foundSubject, err := store.Get(ctx, subject.Id)
 		assert.NoError(t, err)
 
 		assert.Equal(t, subject, foundSubject)
@@ -41,11 +44,11 @@ func (s SubjectsRepositoryContract) Test (t *testing.T) {
 		cleanUp(t)
 		subjects := store.SubjectsExist("first", "second", "third")
 
-		s, err := store.GetByName("second")
+		s, err := store.GetByName(ctx, workspaces.Default, "second")
 		assert.NoError(t, err)
 		assert.Equal(t, subjects[1].Id, s.Id)
 
-		s, err = store.GetByName("does not exist")
+		s, err = store.GetByName(ctx, workspaces.Default, "does not exist")
 		assert.Error(t, err)
 	})
 
@@ -53,10 +56,10 @@ func (s SubjectsRepositoryContract) Test (t *testing.T) {
 		cleanUp(t)
 		subject := store.SubjectExists("Test Subject")
 
-		err := store.Remove(subject.Id)
+		err := store.Remove(ctx, subject.Id)
 		assert.NoError(t, err)
 
-		_, err = store.Get(subject.Id)
+		_, err = store.Get(ctx, subject.Id)
 		assert.Error(t, err)
 	})
 
@@ -64,11 +67,56 @@ func (s SubjectsRepositoryContract) Test (t *testing.T) {
 		cleanUp(t)
 		store.SubjectsExist("Subject 1", "Subject 2", "Subject 3")
 
-		subjects, err := store.List()
+		subjects, err := store.List(ctx)
 		assert.NoError(t, err)
 		assert.Equal(t, 3, len(subjects))
 	})
 
+	t.Run("it scopes subjects to workspaces and shares them", func(t *testing.T) {
+		cleanUp(t)
+		qaBench := store.SubjectInWorkspace("Bench", 10)
+		hardwareBench := store.SubjectInWorkspace("Bench", 11)
+		phone := store.SubjectInWorkspace("Phone", 11)
+		assert.NoError(t, store.AddTag(ctx, qaBench.Id, "lab"))
+		assert.NoError(t, store.AddTag(ctx, phone.Id, "lab"))
+
+		list, err := store.InWorkspace(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Subjects{qaBench}, list)
+		list, err = store.InWorkspace(ctx, 11)
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Subjects{hardwareBench, phone}, list)
+		found, err := store.GetByName(ctx, 11, "Bench")
+		assert.NoError(t, err)
+		assert.Equal(t, hardwareBench, found)
+		_, err = store.GetByName(ctx, 10, "Phone")
+		assert.Error(t, err)
+
+		assert.NoError(t, store.Share(ctx, phone.Id, 10))
+		assert.Error(t, store.Share(ctx, phone.Id, 10))
+		shares, err := store.SharedWith(ctx, phone.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{10}, shares)
+		list, err = store.InWorkspace(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Subjects{qaBench, phone}, list)
+		found, err = store.GetByName(ctx, 10, "Phone")
+		assert.NoError(t, err)
+		assert.Equal(t, phone, found)
+		list, err = store.GetByTags(ctx, 10, []string{"lab"})
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Subjects{qaBench, phone}, list)
+
+		assert.NoError(t, store.Unshare(ctx, phone.Id, 10))
+		assert.Error(t, store.Unshare(ctx, phone.Id, 10))
+		shares, err = store.SharedWith(ctx, phone.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(shares))
+		list, err = store.InWorkspace(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Subjects{qaBench}, list)
+	})
+
 	t.Run("it can add tags and filter by them", func(t *testing.T) {
 		cleanUp(t)
 		subjects := store.SubjectsExist(
@@ -80,17 +128,17 @@ func (s SubjectsRepositoryContract) Test (t *testing.T) {
 		expectedSpacious := subjects[1]
 		expectedSpaciousAndSoundProof := subjects[3]
 
-		store.AddTag(expectedSpacious.Id, "spacious")
-		store.AddTag(expectedSpaciousAndSoundProof.Id, "spacious")
-		store.AddTag(expectedSpaciousAndSoundProof.Id, "soundproof")
+		store.AddTag(ctx, expectedSpacious.Id, "spacious")
+		store.AddTag(ctx, expectedSpaciousAndSoundProof.Id, "spacious")
+		store.AddTag(ctx, expectedSpaciousAndSoundProof.Id, "soundproof")
 
-		spaciousRooms, err := store.GetByTags([]string{"spacious"})
+		spaciousRooms, err := store.GetByTags(ctx, workspaces.Default, []string{"spacious"})
 		assert.NoError(t, err)
 		assert.Equal(t, 2, len(spaciousRooms))
 		assert.Equal(t, expectedSpacious.Id, spaciousRooms[0].Id)
 		assert.Equal(t, expectedSpaciousAndSoundProof.Id, spaciousRooms[1].Id)
 
-		spaciousAndSoundProof, err := store.GetByTags([]string{"spacious", "soundproof"})
+		spaciousAndSoundProof, err := store.GetByTags(ctx, workspaces.Default, []string{"spacious", "soundproof"})
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(spaciousAndSoundProof))
 		assert.Equal(t, expectedSpaciousAndSoundProof.Id, spaciousAndSoundProof[0].Id)
@@ -100,10 +148,10 @@ func (s SubjectsRepositoryContract) Test (t *testing.T) {
 	t.Run("it cannot add same tag to the same subject twice", func(t *testing.T) {
 		cleanUp(t)
 		s := store.SubjectExists("Test Subject")
-		err := store.AddTag(s.Id, "Test")
+		err := store.AddTag(ctx, s.Id, "Test")
 		assert.NoError(t, err)
 
-		err = store.AddTag(s.Id, "Test")
+		err = store.AddTag(ctx, s.Id, "Test")
 		assert.Error(t, err)
 	})
 
@@ -112,10 +160,10 @@ func (s SubjectsRepositoryContract) Test (t *testing.T) {
 		subject := store.SubjectExists("Test")
 		expectedTags := []string{"tag 1", "tag 2", "tag 3"}
 		for _, tag := range expectedTags {
-			store.AddTag(subject.Id, tag)
+			store.AddTag(ctx, subject.Id, tag)
 		}
 
-		tags, err := store.GetTags(subject.Id)
+		tags, err := store.GetTags(ctx, subject.Id)
 		assert.NoError(t, err)
 
 		assert.SliceContains(t, tags, expectedTags[0])
@@ -123,6 +171,130 @@ func (s SubjectsRepositoryContract) Test (t *testing.T) {
 		assert.SliceContains(t, tags, expectedTags[2])
 	})
 
+	t.Run("it finds subjects by name ignoring case and by their aliases", func(t *testing.T) {
+		cleanUp(t)
+		subjects := store.SubjectsExist("Subject#1", "Subject#2", "Bench")
+		hidden := store.SubjectInWorkspace("bench", 10)
+		assert.NoError(t, store.AddAlias(ctx, subjects[1].Id, "second"))
+		assert.NoError(t, store.AddAlias(ctx, subjects[1].Id, "bench"))
+		assert.NoError(t, store.AddAlias(ctx, hidden.Id, "hidden"))
+		assert.Error(t, store.AddAlias(ctx, subjects[1].Id, "second"))
+
+		found, err := store.FindByName(ctx, workspaces.Default, "subject#1")
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Subjects{subjects[0]}, found)
+		found, err = store.FindByName(ctx, workspaces.Default, "SECOND")
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Subjects{subjects[1]}, found)
+		found, err = store.FindByName(ctx, workspaces.Default, "Bench")
+		assert.NoError(t, err)
+		assert.Equal(t, reservations.Subjects{subjects[1], subjects[2]}, found)
+		found, err = store.FindByName(ctx, workspaces.Default, "hidden")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(found))
+
+		aliases, err := store.GetAliases(ctx, subjects[1].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"bench", "second"}, aliases)
+	})
+
+	t.Run("it assigns a parent and lists its children", func(t *testing.T) {
+		cleanUp(t)
+		subjects := store.SubjectsExist("Test bench", "Phone", "Power meter", "Unrelated")
+		bench := subjects[0]
+
+		err := store.SetParent(ctx, subjects[1].Id, bench.Id)
+		assert.NoError(t, err)
+		err = store.SetParent(ctx, subjects[2].Id, bench.Id)
+		assert.NoError(t, err)
+
+		phone, err := store.Get(ctx, subjects[1].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, bench.Id, phone.ParentId)
+
+		children, err := store.Children(ctx, bench.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(children))
+		assert.SliceContains(t, children.Ids(), subjects[1].Id)
+		assert.SliceContains(t, children.Ids(), subjects[2].Id)
+
+		children, err = store.Children(ctx, subjects[3].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(children))
+	})
+
+	t.Run("it detaches a subject from its parent", func(t *testing.T) {
+		cleanUp(t)
+		subjects := store.SubjectsExist("Test bench", "Phone")
+
+		err := store.SetParent(ctx, subjects[1].Id, subjects[0].Id)
+		assert.NoError(t, err)
+		err = store.SetParent(ctx, subjects[1].Id, 0)
+		assert.NoError(t, err)
+
+		phone, err := store.Get(ctx, subjects[1].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, phone.ParentId)
+		children, err := store.Children(ctx, subjects[0].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(children))
+	})
+
+	t.Run("it returns error when assigning a parent to a missing subject", func(t *testing.T) {
+		cleanUp(t)
+		subject := store.SubjectExists("Test bench")
+
+		err := store.SetParent(ctx, 123456, subject.Id)
+		assert.Error(t, err)
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cleanUp(t)
+		subject := store.SubjectExists("Test")
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := store.Get(cancelled, subject.Id)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.GetByName(cancelled, workspaces.Default, subject.Name)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.List(cancelled)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.InWorkspace(cancelled, workspaces.Default)
+		assert.IsError(t, err, context.Canceled)
+		err = store.Share(cancelled, subject.Id, 10)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.Children(cancelled, subject.Id)
+		assert.IsError(t, err, context.Canceled)
+		err = store.AddTag(cancelled, subject.Id, "cancelled")
+		assert.IsError(t, err, context.Canceled)
+		err = store.AddAlias(cancelled, subject.Id, "cancelled")
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.FindByName(cancelled, workspaces.Default, subject.Name)
+		assert.IsError(t, err, context.Canceled)
+		err = store.Remove(cancelled, subject.Id)
+		assert.IsError(t, err, context.Canceled)
+
+		tags, err := store.GetTags(ctx, subject.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(tags))
+	})
+
+	t.Run("it advances the identity", func(t *testing.T) {
+		id, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.AdvanceIdentity(ctx, id + 100))
+		next, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, id + 101, next)
+
+		assert.NoError(t, store.AdvanceIdentity(ctx, id))
+		next, err = store.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, id + 102, next)
+	})
+
 	t.Run("it generates next ID", func(t *testing.T) {
 		cleanUp(t)
 		ch := make(chan int, 5)
@@ -130,7 +302,7 @@ func (s SubjectsRepositoryContract) Test (t *testing.T) {
 
 		for range 5 {
 			go (func (c chan int, t *testing.T) {
-				id, err := store.NextIdentity()
+				id, err := store.NextIdentity(ctx)
 				assert.NoError(t, err)
 				c <- id
 			})(ch, t)
@@ -156,10 +328,15 @@ type subjectsRepositoryHelper struct {
 }
 
 func (h *subjectsRepositoryHelper) SubjectExists(name string) reservations.Subject {
-	id, err := h.NextIdentity()
+	return h.SubjectInWorkspace(name, workspaces.Default)
+}
+
+func (h *subjectsRepositoryHelper) SubjectInWorkspace(name string, workspaceId int) reservations.Subject {
+	ctx := context.Background()
+	id, err := h.NextIdentity(ctx)
 	assert.NoError(h.t, err)
-	s := reservations.Subject{Id: id, Name: name}
-	err = h.Add(s)
+	s := reservations.Subject{Id: id, Name: name, WorkspaceId: workspaceId}
+	err = h.Add(ctx, s)
 	assert.NoError(h.t, err)
 
 	return s
@@ -176,12 +353,13 @@ func (h *subjectsRepositoryHelper) SubjectsExist(names ...string) reservations.S
 }
 
 func (h *subjectsRepositoryHelper) CleanUp(t testing.TB) {
+	ctx := context.Background()
 	t.Cleanup(func() {
-		subjects, err := h.List()
+		subjects, err := h.List(ctx)
 		assert.NoError(t, err)
 
 		for _, s := range subjects {
-			h.Remove(s.Id)
+			h.Remove(ctx, s.Id)
 		}
 	})
 }
@@ -0,0 +1,9 @@
+package reservations
+
+import "context"
+
+// UnitOfWork runs a function atomically while holding exclusive locks on the given subjects,
+// so that no other process can book them between a conflict check and the insert
+type UnitOfWork interface {
+	Execute(ctx context.Context, subjectIds []int, fn func(reservations ReservationsRepository) error) error
+}
@@ -0,0 +1,104 @@
+package reservations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	domain "github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+	workspacesPort "github.com/SneedusSnake/Reservations/internal/ports/workspaces"
+	"github.com/alecthomas/assert/v2"
+)
+
+// MergerStores are the stores the merger works on
+type MergerStores struct {
+	Merger users.UserMerger
+	Users users.UsersRepository
+	AccessTokens users.AccessTokensRepository
+	Reservations ReservationsRepository
+	Workspaces workspacesPort.WorkspacesRepository
+}
+
+type UserMergerContract struct {
+	NewStores func() MergerStores
+}
+
+func (c UserMergerContract) Test(t *testing.T) {
+	ctx := context.Background()
+	stores := c.NewStores()
+	start := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("it moves the records of the source to the target and removes the source", func(t *testing.T) {
+		sourceId, err := stores.Users.NextIdentity(ctx)
+		assert.NoError(t, err)
+		targetId, err := stores.Users.NextIdentity(ctx)
+		assert.NoError(t, err)
+		source := domain.User{Id: sourceId, Name: "alice_phone", Email: "merge@example.com", Password: "hash"}
+		target := domain.User{Id: targetId, Name: "Alice"}
+		assert.NoError(t, stores.Users.Add(ctx, source))
+		assert.NoError(t, stores.Users.Add(ctx, target))
+		t.Cleanup(func() {
+			stores.Users.Remove(ctx, source.Id)
+			stores.Users.Remove(ctx, target.Id)
+		})
+
+		reservationId, err := stores.Reservations.NextIdentity(ctx)
+		assert.NoError(t, err)
+		reservation := reservations.Reservation{Id: reservationId, SubjectId: 1, UserId: source.Id, Start: start, End: start.Add(time.Hour)}
+		assert.NoError(t, stores.Reservations.Add(ctx, reservation))
+		t.Cleanup(func() {
+			stores.Reservations.Remove(ctx, reservation.Id)
+		})
+		tokenId, err := stores.AccessTokens.NextIdentity(ctx)
+		assert.NoError(t, err)
+		token := domain.AccessToken{Id: tokenId, UserId: source.Id, Name: "laptop", Hash: "merge1", Scopes: []string{domain.ScopeReservationsRead}, CreatedAt: start}
+		assert.NoError(t, stores.AccessTokens.Add(ctx, token))
+		t.Cleanup(func() {
+			stores.AccessTokens.Remove(ctx, token.Id)
+		})
+
+		// both manage the default workspace, only the source manages the other one
+		other := workspaces.Default + 100
+		assert.NoError(t, stores.Workspaces.AddAdmin(ctx, workspaces.Default, source.Id))
+		assert.NoError(t, stores.Workspaces.AddAdmin(ctx, workspaces.Default, target.Id))
+		assert.NoError(t, stores.Workspaces.AddAdmin(ctx, other, source.Id))
+		t.Cleanup(func() {
+			stores.Workspaces.RemoveAdmin(ctx, workspaces.Default, target.Id)
+			stores.Workspaces.RemoveAdmin(ctx, other, target.Id)
+		})
+
+		target.Email = source.Email
+		target.Password = source.Password
+		assert.NoError(t, stores.Merger.Merge(ctx, source.Id, target))
+
+		_, err = stores.Users.Get(ctx, source.Id)
+		assert.Error(t, err)
+		merged, err := stores.Users.GetByEmail(ctx, source.Email)
+		assert.NoError(t, err)
+		assert.Equal(t, target, merged)
+		moved, err := stores.Reservations.Get(ctx, reservation.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, target.Id, moved.UserId)
+		movedToken, err := stores.AccessTokens.Get(ctx, token.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, target.Id, movedToken.UserId)
+		for _, workspaceId := range []int{workspaces.Default, other} {
+			admin, err := stores.Workspaces.IsAdmin(ctx, workspaceId, target.Id)
+			assert.NoError(t, err)
+			assert.True(t, admin)
+			admin, err = stores.Workspaces.IsAdmin(ctx, workspaceId, source.Id)
+			assert.NoError(t, err)
+			assert.False(t, admin)
+		}
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.IsError(t, stores.Merger.Merge(cancelled, 1, domain.User{Id: 2, Name: "Alice"}), context.Canceled)
+	})
+}
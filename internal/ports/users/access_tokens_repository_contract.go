@@ -0,0 +1,86 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/alecthomas/assert/v2"
+)
+
+type AccessTokensRepositoryContract struct {
+	NewStore func() AccessTokensRepository
+}
+
+func (c AccessTokensRepositoryContract) Test(t *testing.T) {
+	ctx := context.Background()
+	store := c.NewStore()
+	now := time.Now().UTC().Truncate(time.Second)
+	makeToken := func(hash string, expiresAt time.Time, scopes ...string) users.AccessToken {
+		id, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+
+		return users.AccessToken{Id: id, UserId: 1, WorkspaceId: 2, Name: "ci", Hash: hash, Scopes: scopes, ExpiresAt: expiresAt, CreatedAt: now}
+	}
+
+	t.Run("it returns error when access token was not found", func(t *testing.T) {
+		_, err := store.Get(ctx, 1234)
+		assert.Error(t, err)
+		_, err = store.GetByHash(ctx, "unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("it adds, lists and removes access tokens", func(t *testing.T) {
+		ci := makeToken("a1", now.Add(time.Hour), users.ScopeReservationsWrite, users.ScopeSubjectsAdmin)
+		forever := makeToken("a2", time.Time{}, users.ScopeReservationsRead)
+		assert.NoError(t, store.Add(ctx, ci))
+		assert.NoError(t, store.Add(ctx, forever))
+
+		found, err := store.Get(ctx, ci.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, ci, found)
+		found, err = store.GetByHash(ctx, forever.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, forever, found)
+
+		list, err := store.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []users.AccessToken{ci, forever}, list)
+
+		assert.NoError(t, store.Remove(ctx, ci.Id))
+		assert.NoError(t, store.Remove(ctx, forever.Id))
+		_, err = store.GetByHash(ctx, ci.Hash)
+		assert.Error(t, err)
+		list, err = store.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(list))
+	})
+
+	t.Run("it cannot add the same token twice", func(t *testing.T) {
+		token := makeToken("b1", time.Time{}, users.ScopeReservationsRead)
+		t.Cleanup(func() {
+			store.Remove(ctx, token.Id)
+		})
+		assert.NoError(t, store.Add(ctx, token))
+
+		assert.Error(t, store.Add(ctx, token))
+		duplicate := makeToken("b1", time.Time{}, users.ScopeReservationsRead)
+		assert.Error(t, store.Add(ctx, duplicate))
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := store.NextIdentity(cancelled)
+		assert.IsError(t, err, context.Canceled)
+		err = store.Add(cancelled, users.AccessToken{Id: 4321, Hash: "c1", CreatedAt: now})
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.GetByHash(cancelled, "c1")
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.List(cancelled)
+		assert.IsError(t, err, context.Canceled)
+		assert.IsError(t, store.Remove(cancelled, 4321), context.Canceled)
+	})
+}
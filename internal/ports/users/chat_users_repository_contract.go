@@ -0,0 +1,78 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/alecthomas/assert/v2"
+)
+
+type ChatUsersRepositoryContract struct {
+	NewUsersStore func() UsersRepository
+	// NewStore returns the store of the platform linking to the users of the users store
+	NewStore func(platform string, users UsersRepository) ChatUsersRepository
+}
+
+func (c ChatUsersRepositoryContract) Test(t *testing.T) {
+	ctx := context.Background()
+	usersStore := c.NewUsersStore()
+	slack, discord := c.NewStore("slack", usersStore), c.NewStore("discord", usersStore)
+	newUser := func(user users.User) users.User {
+		id, err := usersStore.NextIdentity(ctx)
+		assert.NoError(t, err)
+		user.Id = id
+		assert.NoError(t, usersStore.Add(ctx, user))
+
+		return user
+	}
+	alice := users.ChatUser{ExternalId: "U1111111", User: newUser(users.User{Name: "Alice", TimeZone: "Europe/Berlin", Language: "ru"})}
+	bob := users.ChatUser{ExternalId: "U2222222", User: newUser(users.User{Name: "Bob"})}
+
+	t.Run("it returns error when user was not found", func(t *testing.T) {
+		_, err := slack.Get(ctx, "U0000000")
+		assert.Error(t, err)
+	})
+
+	t.Run("it links external ids to users", func(t *testing.T) {
+		assert.NoError(t, slack.Add(ctx, bob))
+		assert.NoError(t, slack.Add(ctx, alice))
+
+		found, err := slack.Get(ctx, alice.ExternalId)
+		assert.NoError(t, err)
+		assert.Equal(t, alice, found)
+
+		list, err := slack.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []users.ChatUser{alice, bob}, list)
+	})
+
+	t.Run("it keeps the ids of every platform apart", func(t *testing.T) {
+		carol := users.ChatUser{ExternalId: alice.ExternalId, User: newUser(users.User{Name: "Carol"})}
+		assert.NoError(t, discord.Add(ctx, carol))
+
+		found, err := discord.Get(ctx, carol.ExternalId)
+		assert.NoError(t, err)
+		assert.Equal(t, carol, found)
+		found, err = slack.Get(ctx, alice.ExternalId)
+		assert.NoError(t, err)
+		assert.Equal(t, alice, found)
+		list, err := discord.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []users.ChatUser{carol}, list)
+	})
+
+	t.Run("it cannot link same id twice", func(t *testing.T) {
+		assert.Error(t, slack.Add(ctx, users.ChatUser{ExternalId: alice.ExternalId, User: newUser(users.User{Name: "Eve"})}))
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := slack.Get(cancelled, alice.ExternalId)
+		assert.IsError(t, err, context.Canceled)
+		_, err = slack.List(cancelled)
+		assert.IsError(t, err, context.Canceled)
+	})
+}
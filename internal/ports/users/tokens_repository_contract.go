@@ -0,0 +1,87 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/alecthomas/assert/v2"
+)
+
+type TokensRepositoryContract struct {
+	NewStore func() TokensRepository
+}
+
+func (c TokensRepositoryContract) Test(t *testing.T) {
+	ctx := context.Background()
+	store := c.NewStore()
+	expiresAt := time.Now().UTC().Truncate(time.Second).Add(time.Hour)
+
+	t.Run("it returns error when token was not found", func(t *testing.T) {
+		_, err := store.Get(ctx, "unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("it adds and removes tokens", func(t *testing.T) {
+		token := users.Token{Hash: "a1", Kind: users.TokenSession, UserId: 1, ExpiresAt: expiresAt}
+		assert.NoError(t, store.Add(ctx, token))
+
+		found, err := store.Get(ctx, token.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, token, found)
+
+		assert.NoError(t, store.Remove(ctx, token.Hash))
+		_, err = store.Get(ctx, token.Hash)
+		assert.Error(t, err)
+	})
+
+	t.Run("it cannot add the same token twice", func(t *testing.T) {
+		token := users.Token{Hash: "b1", Kind: users.TokenSession, UserId: 1, ExpiresAt: expiresAt}
+		t.Cleanup(func() {
+			store.Remove(ctx, token.Hash)
+		})
+		assert.NoError(t, store.Add(ctx, token))
+
+		assert.Error(t, store.Add(ctx, token))
+	})
+
+	t.Run("it removes the tokens of a kind issued to a user", func(t *testing.T) {
+		sessions := []users.Token{
+			{Hash: "c1", Kind: users.TokenSession, UserId: 2, ExpiresAt: expiresAt},
+			{Hash: "c2", Kind: users.TokenSession, UserId: 2, ExpiresAt: expiresAt},
+		}
+		reset := users.Token{Hash: "c3", Kind: users.TokenPasswordReset, UserId: 2, ExpiresAt: expiresAt}
+		other := users.Token{Hash: "c4", Kind: users.TokenSession, UserId: 3, ExpiresAt: expiresAt}
+		for _, token := range append(sessions, reset, other) {
+			assert.NoError(t, store.Add(ctx, token))
+		}
+		t.Cleanup(func() {
+			store.Remove(ctx, reset.Hash)
+			store.Remove(ctx, other.Hash)
+		})
+
+		assert.NoError(t, store.RemoveByUser(ctx, 2, users.TokenSession))
+
+		for _, session := range sessions {
+			_, err := store.Get(ctx, session.Hash)
+			assert.Error(t, err)
+		}
+		_, err := store.Get(ctx, reset.Hash)
+		assert.NoError(t, err)
+		_, err = store.Get(ctx, other.Hash)
+		assert.NoError(t, err)
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := store.Add(cancelled, users.Token{Hash: "d1", Kind: users.TokenSession, UserId: 1, ExpiresAt: expiresAt})
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.Get(cancelled, "d1")
+		assert.IsError(t, err, context.Canceled)
+		assert.IsError(t, store.Remove(cancelled, "d1"), context.Canceled)
+		assert.IsError(t, store.RemoveByUser(cancelled, 1, users.TokenSession), context.Canceled)
+	})
+}
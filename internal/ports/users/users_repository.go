@@ -1,10 +1,49 @@
 package users
 
-import "github.com/SneedusSnake/Reservations/internal/domain/users"
+import (
+	"context"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+)
 
 type UsersRepository interface {
-	NextIdentity() (int, error)
-	Add(u users.User) error
-	Get(id int) (users.User, error)
-	Remove(id int) error
+	NextIdentity(ctx context.Context) (int, error)
+	// AdvanceIdentity makes NextIdentity hand out ids above id
+	AdvanceIdentity(ctx context.Context, id int) error
+	Add(ctx context.Context, u users.User) error
+	Get(ctx context.Context, id int) (users.User, error)
+	GetByEmail(ctx context.Context, email string) (users.User, error)
+	Update(ctx context.Context, u users.User) error
+	List(ctx context.Context) ([]users.User, error)
+	Remove(ctx context.Context, id int) error
+}
+
+// ChatUsersRepository links the accounts of a chat platform to users, each platform gets a repository of its own
+type ChatUsersRepository interface {
+	Add(ctx context.Context, u users.ChatUser) error
+	Get(ctx context.Context, externalId string) (users.ChatUser, error)
+	List(ctx context.Context) ([]users.ChatUser, error)
+}
+
+type TokensRepository interface {
+	Add(ctx context.Context, t users.Token) error
+	Get(ctx context.Context, hash string) (users.Token, error)
+	Remove(ctx context.Context, hash string) error
+	// RemoveByUser removes the tokens of the given kind issued to the user
+	RemoveByUser(ctx context.Context, userId int, kind string) error
+}
+
+type AccessTokensRepository interface {
+	NextIdentity(ctx context.Context) (int, error)
+	Add(ctx context.Context, t users.AccessToken) error
+	Get(ctx context.Context, id int) (users.AccessToken, error)
+	GetByHash(ctx context.Context, hash string) (users.AccessToken, error)
+	List(ctx context.Context) ([]users.AccessToken, error)
+	Remove(ctx context.Context, id int) error
+}
+
+// UserMerger moves the reservations, chat identities and access tokens of the source user to the target
+// and removes the source, atomically. The target is saved as given, so the caller decides which account it keeps
+type UserMerger interface {
+	Merge(ctx context.Context, sourceId int, target users.User) error
 }
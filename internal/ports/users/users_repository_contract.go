@@ -1,6 +1,7 @@
 package users
 
 import (
+	"context"
 	"testing"
 
 	"github.com/SneedusSnake/Reservations/internal/domain/users"
@@ -14,10 +15,11 @@ type UsersRepositoryContract struct {
 var store UsersRepository
 
 func (s UsersRepositoryContract) Test(t *testing.T) {
+	ctx := context.Background()
 	store = s.NewStore()
 
 	t.Run("it returns error when user was not found", func(t *testing.T) {
-		_, err := store.Get(1234)
+		_, err := store.Get(ctx, 1234)
 		assert.Error(t, err)
 	})
 
@@ -25,35 +27,138 @@ func (s UsersRepositoryContract) Test(t *testing.T) {
 		user, err := makeUser("Adam")
 		assert.NoError(t, err)
 		t.Cleanup(func() {
-			store.Remove(user.Id)
+			store.Remove(ctx, user.Id)
 		})
 
-		err = store.Add(user)
+		err = store.Add(ctx, user)
 		assert.NoError(t, err)
 
-		foundUser, err := store.Get(user.Id)
+		foundUser, err := store.Get(ctx, user.Id)
 		assert.NoError(t, err)
 
 		assert.Equal(t, user, foundUser)
 	})
 
+	t.Run("it lists users", func(t *testing.T) {
+		alice, err := makeUser("Alice")
+		assert.NoError(t, err)
+		bob, err := makeUser("Bob")
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			store.Remove(ctx, alice.Id)
+			store.Remove(ctx, bob.Id)
+		})
+		assert.NoError(t, store.Add(ctx, alice))
+		assert.NoError(t, store.Add(ctx, bob))
+
+		list, err := store.List(ctx)
+		assert.NoError(t, err)
+		assert.SliceContains(t, list, alice)
+		assert.SliceContains(t, list, bob)
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		user, err := makeUser("Cain")
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			store.Remove(ctx, user.Id)
+		})
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err = store.Add(cancelled, user)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.Get(cancelled, user.Id)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.NextIdentity(cancelled)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.List(cancelled)
+		assert.IsError(t, err, context.Canceled)
+
+		_, err = store.Get(ctx, user.Id)
+		assert.Error(t, err)
+	})
+
+	t.Run("it finds users by email and updates them", func(t *testing.T) {
+		user, err := makeUser("Frank")
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			store.Remove(ctx, user.Id)
+		})
+		assert.NoError(t, store.Add(ctx, user))
+
+		_, err = store.GetByEmail(ctx, "frank@example.com")
+		assert.Error(t, err)
+
+		user.Name = "Frank Jr"
+		user.Email = "frank@example.com"
+		user.Password = "$2a$10$hash"
+		user.TimeZone = "Europe/Berlin"
+		user.Language = "ru"
+		assert.NoError(t, store.Update(ctx, user))
+
+		found, err := store.GetByEmail(ctx, "frank@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, user, found)
+		found, err = store.Get(ctx, user.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, user, found)
+	})
+
+	t.Run("it cannot give two users the same email", func(t *testing.T) {
+		grace, err := makeUser("Grace")
+		assert.NoError(t, err)
+		heidi, err := makeUser("Heidi")
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			store.Remove(ctx, grace.Id)
+			store.Remove(ctx, heidi.Id)
+		})
+		grace.Email = "grace@example.com"
+		assert.NoError(t, store.Add(ctx, grace))
+		assert.NoError(t, store.Add(ctx, heidi))
+
+		heidi.Email = "grace@example.com"
+		assert.Error(t, store.Update(ctx, heidi))
+	})
+
+	t.Run("it returns error when updating unknown user", func(t *testing.T) {
+		assert.Error(t, store.Update(ctx, users.User{Id: 4321, Name: "Nobody"}))
+	})
+
+	t.Run("it advances the identity", func(t *testing.T) {
+		id, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.AdvanceIdentity(ctx, id + 100))
+		next, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, id + 101, next)
+
+		assert.NoError(t, store.AdvanceIdentity(ctx, id))
+		next, err = store.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, id + 102, next)
+	})
+
 	t.Run("It cannot add user with same id twice", func(t *testing.T) {
 		user, err := makeUser("Eve")
 		assert.NoError(t, err)
 		t.Cleanup(func() {
-			store.Remove(user.Id)
+			store.Remove(ctx, user.Id)
 		})
 
-		err = store.Add(user)
+		err = store.Add(ctx, user)
 		assert.NoError(t, err)
 
-		err = store.Add(user)
+		err = store.Add(ctx, user)
 		assert.Error(t, err)
 	})
 }
 
 func makeUser(name string) (users.User, error) {
-	id, err := store.NextIdentity()
+	ctx := context.Background()
+	id, err := store.NextIdentity(ctx)
 	if err != nil {
 		return users.User{}, err
 	}
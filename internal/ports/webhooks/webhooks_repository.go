@@ -0,0 +1,22 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/webhooks"
+)
+
+type WebhooksRepository interface {
+	NextIdentity(ctx context.Context) (int, error)
+	Add(ctx context.Context, w webhooks.Webhook) error
+	Get(ctx context.Context, id int) (webhooks.Webhook, error)
+	List(ctx context.Context) ([]webhooks.Webhook, error)
+	Remove(ctx context.Context, id int) error
+}
+
+type DeadLettersRepository interface {
+	NextIdentity(ctx context.Context) (int, error)
+	Add(ctx context.Context, l webhooks.DeadLetter) error
+	// List returns the latest dead letters first
+	List(ctx context.Context, limit int) ([]webhooks.DeadLetter, error)
+}
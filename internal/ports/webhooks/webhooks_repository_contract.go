@@ -0,0 +1,119 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/webhooks"
+	"github.com/alecthomas/assert/v2"
+)
+
+type WebhooksRepositoryContract struct {
+	NewStore func() WebhooksRepository
+}
+
+func (c WebhooksRepositoryContract) Test(t *testing.T) {
+	ctx := context.Background()
+	store := c.NewStore()
+	makeWebhook := func(url string, events ...string) webhooks.Webhook {
+		id, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+
+		return webhooks.Webhook{Id: id, Url: url, Secret: "secret", Events: events}
+	}
+
+	t.Run("it returns error when webhook was not found", func(t *testing.T) {
+		_, err := store.Get(ctx, 1234)
+		assert.Error(t, err)
+	})
+
+	t.Run("it adds, lists and removes webhooks", func(t *testing.T) {
+		jenkins := makeWebhook("https://jenkins.example.com/hook", "reservation.*", "subject.created")
+		dashboard := makeWebhook("https://dashboard.example.com/hook")
+		assert.NoError(t, store.Add(ctx, jenkins))
+		assert.NoError(t, store.Add(ctx, dashboard))
+
+		found, err := store.Get(ctx, jenkins.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, jenkins, found)
+
+		list, err := store.List(ctx)
+		assert.NoError(t, err)
+		assert.SliceContains(t, list, jenkins)
+		assert.SliceContains(t, list, dashboard)
+
+		assert.NoError(t, store.Remove(ctx, jenkins.Id))
+		assert.NoError(t, store.Remove(ctx, dashboard.Id))
+		_, err = store.Get(ctx, jenkins.Id)
+		assert.Error(t, err)
+		list, err = store.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(list))
+	})
+
+	t.Run("it cannot add webhook with same id twice", func(t *testing.T) {
+		webhook := makeWebhook("https://example.com")
+		t.Cleanup(func() {
+			store.Remove(ctx, webhook.Id)
+		})
+
+		assert.NoError(t, store.Add(ctx, webhook))
+		assert.Error(t, store.Add(ctx, webhook))
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := store.NextIdentity(cancelled)
+		assert.IsError(t, err, context.Canceled)
+		err = store.Add(cancelled, webhooks.Webhook{Id: 4321, Url: "https://example.com"})
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.List(cancelled)
+		assert.IsError(t, err, context.Canceled)
+	})
+}
+
+type DeadLettersRepositoryContract struct {
+	NewStore func() DeadLettersRepository
+}
+
+func (c DeadLettersRepositoryContract) Test(t *testing.T) {
+	ctx := context.Background()
+	store := c.NewStore()
+	failedAt := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("it lists the latest dead letters first", func(t *testing.T) {
+		var letters []webhooks.DeadLetter
+		for i := range 3 {
+			id, err := store.NextIdentity(ctx)
+			assert.NoError(t, err)
+			letter := webhooks.DeadLetter{
+				Id: id,
+				WebhookId: 1,
+				Event: "reservation.created",
+				Payload: `{"type":"reservation.created"}`,
+				Attempts: 5,
+				Error: "502 Bad Gateway",
+				FailedAt: failedAt.Add(time.Duration(i) * time.Minute),
+			}
+			assert.NoError(t, store.Add(ctx, letter))
+			letters = append(letters, letter)
+		}
+
+		list, err := store.List(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []webhooks.DeadLetter{letters[2], letters[1]}, list)
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := store.NextIdentity(cancelled)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.List(cancelled, 10)
+		assert.IsError(t, err, context.Canceled)
+	})
+}
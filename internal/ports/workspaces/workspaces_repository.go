@@ -0,0 +1,27 @@
+package workspaces
+
+import (
+	"context"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+)
+
+type WorkspacesRepository interface {
+	NextIdentity(ctx context.Context) (int, error)
+	// AdvanceIdentity makes NextIdentity hand out ids above id
+	AdvanceIdentity(ctx context.Context, id int) error
+	Add(ctx context.Context, w workspaces.Workspace) error
+	Get(ctx context.Context, id int) (workspaces.Workspace, error)
+	GetByName(ctx context.Context, name string) (workspaces.Workspace, error)
+	List(ctx context.Context) ([]workspaces.Workspace, error)
+	// Bind replaces the workspace of the chat, chats are keyed by their adapter, e.g. "telegram:-100123"
+	Bind(ctx context.Context, chat string, workspaceId int) error
+	// ForChat returns the id of the workspace bound to the chat, or the default one
+	ForChat(ctx context.Context, chat string) (int, error)
+	// Bindings returns the workspace ids of the bound chats
+	Bindings(ctx context.Context) (map[string]int, error)
+	// AddAdmin lets the user manage the workspace, adding an admin twice is not an error
+	AddAdmin(ctx context.Context, workspaceId int, userId int) error
+	RemoveAdmin(ctx context.Context, workspaceId int, userId int) error
+	IsAdmin(ctx context.Context, workspaceId int, userId int) (bool, error)
+}
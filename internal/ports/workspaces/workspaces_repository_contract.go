@@ -0,0 +1,128 @@
+package workspaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/alecthomas/assert/v2"
+)
+
+type WorkspacesRepositoryContract struct {
+	NewStore func() WorkspacesRepository
+}
+
+func (c WorkspacesRepositoryContract) Test(t *testing.T) {
+	ctx := context.Background()
+	store := c.NewStore()
+	makeWorkspace := func(name string) workspaces.Workspace {
+		id, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+		workspace := workspaces.Workspace{Id: id, Name: name}
+		assert.NoError(t, store.Add(ctx, workspace))
+
+		return workspace
+	}
+
+	t.Run("it has the default workspace", func(t *testing.T) {
+		workspace, err := store.Get(ctx, workspaces.Default)
+		assert.NoError(t, err)
+		assert.Equal(t, "default", workspace.Name)
+	})
+
+	t.Run("it adds workspaces and finds them by name", func(t *testing.T) {
+		qa := makeWorkspace("qa")
+		hardware := makeWorkspace("hardware")
+		assert.NotEqual(t, workspaces.Default, qa.Id)
+
+		found, err := store.Get(ctx, qa.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, qa, found)
+		found, err = store.GetByName(ctx, "hardware")
+		assert.NoError(t, err)
+		assert.Equal(t, hardware, found)
+		_, err = store.GetByName(ctx, "unknown")
+		assert.Error(t, err)
+
+		list, err := store.List(ctx)
+		assert.NoError(t, err)
+		assert.SliceContains(t, list, qa)
+		assert.SliceContains(t, list, hardware)
+	})
+
+	t.Run("it cannot add two workspaces with the same name", func(t *testing.T) {
+		makeWorkspace("lab")
+		id, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+
+		assert.Error(t, store.Add(ctx, workspaces.Workspace{Id: id, Name: "lab"}))
+	})
+
+	t.Run("it binds chats to workspaces", func(t *testing.T) {
+		first := makeWorkspace("first")
+		second := makeWorkspace("second")
+
+		id, err := store.ForChat(ctx, "telegram:-100")
+		assert.NoError(t, err)
+		assert.Equal(t, workspaces.Default, id)
+
+		assert.NoError(t, store.Bind(ctx, "telegram:-100", first.Id))
+		id, err = store.ForChat(ctx, "telegram:-100")
+		assert.NoError(t, err)
+		assert.Equal(t, first.Id, id)
+
+		assert.NoError(t, store.Bind(ctx, "telegram:-100", second.Id))
+		id, err = store.ForChat(ctx, "telegram:-100")
+		assert.NoError(t, err)
+		assert.Equal(t, second.Id, id)
+		bindings, err := store.Bindings(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, second.Id, bindings["telegram:-100"])
+	})
+
+	t.Run("it keeps the admins of each workspace", func(t *testing.T) {
+		ops := makeWorkspace("ops")
+
+		assert.NoError(t, store.AddAdmin(ctx, ops.Id, 7))
+		assert.NoError(t, store.AddAdmin(ctx, ops.Id, 7))
+		admin, err := store.IsAdmin(ctx, ops.Id, 7)
+		assert.NoError(t, err)
+		assert.True(t, admin)
+		admin, err = store.IsAdmin(ctx, workspaces.Default, 7)
+		assert.NoError(t, err)
+		assert.False(t, admin)
+
+		assert.NoError(t, store.RemoveAdmin(ctx, ops.Id, 7))
+		admin, err = store.IsAdmin(ctx, ops.Id, 7)
+		assert.NoError(t, err)
+		assert.False(t, admin)
+	})
+
+	t.Run("it advances the identity", func(t *testing.T) {
+		id, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.AdvanceIdentity(ctx, id + 100))
+		next, err := store.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, id + 101, next)
+
+		assert.NoError(t, store.AdvanceIdentity(ctx, id))
+		next, err = store.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, id + 102, next)
+	})
+
+	t.Run("it honours context cancellation", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := store.Get(cancelled, workspaces.Default)
+		assert.IsError(t, err, context.Canceled)
+		_, err = store.ForChat(cancelled, "telegram:-100")
+		assert.IsError(t, err, context.Canceled)
+		assert.IsError(t, store.Bind(cancelled, "telegram:-200", workspaces.Default), context.Canceled)
+		_, err = store.IsAdmin(cancelled, workspaces.Default, 7)
+		assert.IsError(t, err, context.Canceled)
+	})
+}
@@ -8,5 +8,7 @@ type Reservation struct {
 	User string
 	Start time.Time
 	End time.Time
+	// Cancelled marks the removed reservations, only the calendar feeds list them
+	Cancelled bool
 }
 
@@ -0,0 +1,49 @@
+// Package timefmt renders reservation times for readers in different time zones
+package timefmt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+)
+
+// Zoned is the absolute format for readers who may be in another zone than the time
+const Zoned = "2006-01-02 15:04 MST"
+
+// Relative tells how far t is from now, such as "in 25 min" or "2 h 5 min ago", rounded to minutes
+func Relative(p i18n.Printer, t, now time.Time) string {
+	d := t.Sub(now).Round(time.Minute)
+	if d == 0 {
+		return p.Sprintf("time.now")
+	}
+	if d < 0 {
+		return p.Sprintf("time.ago", span(p, -d))
+	}
+
+	return p.Sprintf("time.in", span(p, d))
+}
+
+// Group renders t for a chat with several readers: how far it is and the absolute time in the zone of the chat
+func Group(p i18n.Printer, t, now time.Time, zone *time.Location) string {
+	return fmt.Sprintf("%s (%s)", Relative(p, t, now), t.In(zone).Format(Zoned))
+}
+
+func span(p i18n.Printer, d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0 && hours > 0:
+		return p.Sprintf("time.days_hours", days, hours)
+	case days > 0:
+		return p.Sprintf("time.days", days)
+	case hours > 0 && minutes > 0:
+		return p.Sprintf("time.hours_minutes", hours, minutes)
+	case hours > 0:
+		return p.Sprintf("time.hours", hours)
+	}
+
+	return p.Sprintf("time.minutes", minutes)
+}
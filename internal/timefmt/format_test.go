@@ -0,0 +1,39 @@
+package timefmt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/timefmt"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRelative(t *testing.T) {
+	english := i18n.For("en")
+	now := time.Date(2026, 3, 4, 12, 30, 0, 0, time.UTC)
+	cases := map[time.Duration]string{
+		0: "now",
+		20 * time.Second: "now",
+		25 * time.Minute: "in 25 min",
+		25 * time.Minute + 40 * time.Second: "in 26 min",
+		2 * time.Hour: "in 2 h",
+		2 * time.Hour + 5 * time.Minute: "in 2 h 5 min",
+		26 * time.Hour + 30 * time.Minute: "in 1 d 2 h",
+		72 * time.Hour: "in 3 d",
+		-10 * time.Minute: "10 min ago",
+	}
+
+	for d, expected := range cases {
+		assert.Equal(t, expected, timefmt.Relative(english, now.Add(d), now), d.String())
+	}
+	assert.Equal(t, "через 2 ч 5 мин", timefmt.Relative(i18n.For("ru"), now.Add(125 * time.Minute), now))
+}
+
+func TestGroup(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	now := time.Date(2026, 3, 4, 12, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "in 25 min (2026-03-04 13:55 CET)", timefmt.Group(i18n.For("en"), now.Add(25 * time.Minute), now, berlin))
+}
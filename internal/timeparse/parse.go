@@ -0,0 +1,243 @@
+// Package timeparse reads the human written periods of reservations, such as "1h30m" or "tomorrow 9-11"
+package timeparse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+)
+
+// Formats lists the accepted inputs in the language of the reader, it is appended to every parse error
+const Formats = i18n.Key("time.formats")
+
+// maxDuration keeps the end of a reservation within reach of time.Time arithmetic
+const maxDuration = 366 * 24 * time.Hour
+
+type Period struct {
+	From time.Time
+	To time.Time
+}
+
+var (
+	minutesPattern = regexp.MustCompile(`^\d{1,6}$`)
+	durationPattern = regexp.MustCompile(`^(?:(\d{1,4})h(?:ours?|rs?)?)?(?:(\d{1,6})m(?:in(?:utes?|s)?)?)?$`)
+	clockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?$`)
+	dashPattern = regexp.MustCompile(`\s*[-–—]\s*`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// Parse resolves the input against now, days and clock times are read in the location of now.
+// A weekday means its nearest occurrence including today, "next" skips today.
+// Ranges which already started are reserved from now on.
+func Parse(input string, now time.Time) (Period, error) {
+	p := parser{input: input, now: now, tokens: strings.Fields(dashPattern.ReplaceAllString(strings.ToLower(input), "-"))}
+	period, err := p.parse()
+	if err != nil {
+		return Period{}, err
+	}
+	if !period.To.After(period.From) {
+		return Period{}, i18n.Errorf("time.ends_before_start", Formats)
+	}
+	if !period.To.After(now) {
+		return Period{}, i18n.Errorf("time.passed", period.To.Format(time.DateTime))
+	}
+	if period.From.Before(now) {
+		period.From = now
+	}
+	if period.To.Sub(period.From) > maxDuration {
+		return Period{}, i18n.Errorf("time.too_long")
+	}
+
+	return period, nil
+}
+
+type parser struct {
+	input string
+	now time.Time
+	tokens []string
+}
+
+func (p *parser) parse() (Period, error) {
+	if len(p.tokens) == 0 {
+		return Period{}, i18n.Errorf("time.missing", Formats)
+	}
+
+	day, dated, err := p.day()
+	if err != nil {
+		return Period{}, err
+	}
+
+	switch token := p.next(); {
+	case token == "until" || token == "till" || token == "til":
+		end, err := p.end(day)
+		if err != nil {
+			return Period{}, err
+		}
+		return Period{From: p.now, To: end}, p.done()
+	case token == "for" && !dated:
+		d, err := p.duration(p.next())
+		if err != nil {
+			return Period{}, err
+		}
+		return Period{From: p.now, To: p.now.Add(d)}, p.done()
+	case strings.Contains(token, "-"):
+		from, to, _ := strings.Cut(token, "-")
+		start, err := p.clock(day, from)
+		if err != nil {
+			return Period{}, err
+		}
+		end, err := p.clock(day, to)
+		if err != nil {
+			return Period{}, err
+		}
+		return Period{From: start, To: end}, p.done()
+	case !dated && (minutesPattern.MatchString(token) || durationPattern.MatchString(token)):
+		d, err := p.duration(token)
+		if err != nil {
+			return Period{}, err
+		}
+		return Period{From: p.now, To: p.now.Add(d)}, p.done()
+	case clockPattern.MatchString(token):
+		start, err := p.clock(day, token)
+		if err != nil {
+			return Period{}, err
+		}
+		switch p.next() {
+		case "for":
+			d, err := p.duration(p.next())
+			if err != nil {
+				return Period{}, err
+			}
+			return Period{From: start, To: start.Add(d)}, p.done()
+		case "until", "till", "til", "to":
+			end, err := p.end(day)
+			if err != nil {
+				return Period{}, err
+			}
+			return Period{From: start, To: end}, p.done()
+		}
+		return Period{}, i18n.Errorf("time.end_missing", p.input, Formats)
+	}
+
+	return Period{}, p.unknown()
+}
+
+// day reads an optional leading day, today is returned when there is none
+func (p *parser) day() (time.Time, bool, error) {
+	today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+
+	switch p.tokens[0] {
+	case "today":
+		p.tokens = p.tokens[1:]
+		return today, true, nil
+	case "tomorrow":
+		p.tokens = p.tokens[1:]
+		return today.AddDate(0, 0, 1), true, nil
+	case "next":
+		weekday, ok := weekdays[p.peek(1)]
+		if !ok {
+			return time.Time{}, false, p.unknown()
+		}
+		p.tokens = p.tokens[2:]
+		return today.AddDate(0, 0, 1 + (int(weekday) - int(today.Weekday()) + 6) % 7), true, nil
+	}
+	if weekday, ok := weekdays[p.tokens[0]]; ok {
+		p.tokens = p.tokens[1:]
+		return today.AddDate(0, 0, (int(weekday) - int(today.Weekday()) + 7) % 7), true, nil
+	}
+
+	return today, false, nil
+}
+
+func (p *parser) end(day time.Time) (time.Time, error) {
+	token := p.next()
+	if token == "eod" {
+		return day.AddDate(0, 0, 1), nil
+	}
+	if token == "end" {
+		if p.next() != "of" || p.next() != "day" {
+			return time.Time{}, p.unknown()
+		}
+		return day.AddDate(0, 0, 1), nil
+	}
+
+	return p.clock(day, token)
+}
+
+func (p *parser) clock(day time.Time, token string) (time.Time, error) {
+	match := clockPattern.FindStringSubmatch(token)
+	if match == nil {
+		return time.Time{}, p.unknown()
+	}
+	hours, _ := strconv.Atoi(match[1])
+	minutes := 0
+	if match[2] != "" {
+		minutes, _ = strconv.Atoi(match[2])
+	}
+	if hours > 23 || minutes > 59 {
+		return time.Time{}, i18n.Errorf("time.invalid_clock", token)
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, 0, 0, day.Location()), nil
+}
+
+func (p *parser) duration(token string) (time.Duration, error) {
+	var d time.Duration
+	if minutesPattern.MatchString(token) {
+		minutes, _ := strconv.Atoi(token)
+		d = time.Duration(minutes) * time.Minute
+	} else if match := durationPattern.FindStringSubmatch(token); match != nil && token != "" {
+		hours, _ := strconv.Atoi(match[1])
+		minutes, _ := strconv.Atoi(match[2])
+		d = time.Duration(hours) * time.Hour + time.Duration(minutes) * time.Minute
+	} else {
+		return 0, p.unknown()
+	}
+	if d <= 0 {
+		return 0, i18n.Errorf("time.too_short")
+	}
+
+	return d, nil
+}
+
+func (p *parser) next() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+	token := p.tokens[0]
+	p.tokens = p.tokens[1:]
+
+	return token
+}
+
+func (p *parser) peek(i int) string {
+	if i >= len(p.tokens) {
+		return ""
+	}
+
+	return p.tokens[i]
+}
+
+func (p *parser) done() error {
+	if len(p.tokens) > 0 {
+		return p.unknown()
+	}
+
+	return nil
+}
+
+func (p *parser) unknown() error {
+	return i18n.Errorf("time.unknown", p.input, Formats)
+}
@@ -0,0 +1,116 @@
+package timeparse_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/i18n"
+	"github.com/SneedusSnake/Reservations/internal/timeparse"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestParse(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+	// a wednesday
+	now := time.Date(2026, 3, 4, 12, 30, 15, 0, moscow)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, moscow)
+	}
+
+	t.Run("it parses periods", func(t *testing.T) {
+		cases := []struct {
+			input string
+			expected timeparse.Period
+		}{
+			{"30", timeparse.Period{From: now, To: now.Add(30 * time.Minute)}},
+			{"2h", timeparse.Period{From: now, To: now.Add(2 * time.Hour)}},
+			{"1h30m", timeparse.Period{From: now, To: now.Add(90 * time.Minute)}},
+			{"45min", timeparse.Period{From: now, To: now.Add(45 * time.Minute)}},
+			{"for 3hours", timeparse.Period{From: now, To: now.Add(3 * time.Hour)}},
+			{"until 17:00", timeparse.Period{From: now, To: at(4, 17, 0)}},
+			{"Till 17", timeparse.Period{From: now, To: at(4, 17, 0)}},
+			{"till end of day", timeparse.Period{From: now, To: at(5, 0, 0)}},
+			{"until eod", timeparse.Period{From: now, To: at(5, 0, 0)}},
+			{"tomorrow until 12:15", timeparse.Period{From: now, To: at(5, 12, 15)}},
+			{"tomorrow 9-11", timeparse.Period{From: at(5, 9, 0), To: at(5, 11, 0)}},
+			{"tomorrow 9:30 - 11", timeparse.Period{From: at(5, 9, 30), To: at(5, 11, 0)}},
+			{"14:00-16:30", timeparse.Period{From: at(4, 14, 0), To: at(4, 16, 30)}},
+			{"12-13", timeparse.Period{From: now, To: at(4, 13, 0)}},
+			{"15:00 for 1h", timeparse.Period{From: at(4, 15, 0), To: at(4, 16, 0)}},
+			{"today 15:00 until 18:00", timeparse.Period{From: at(4, 15, 0), To: at(4, 18, 0)}},
+			{"friday 10 to 12", timeparse.Period{From: at(6, 10, 0), To: at(6, 12, 0)}},
+			{"wednesday 14-15", timeparse.Period{From: at(4, 14, 0), To: at(4, 15, 0)}},
+			{"next wed 14-15", timeparse.Period{From: at(11, 14, 0), To: at(11, 15, 0)}},
+			{"next monday 10:00 for 2h", timeparse.Period{From: at(9, 10, 0), To: at(9, 12, 0)}},
+		}
+
+		for _, c := range cases {
+			period, err := timeparse.Parse(c.input, now)
+			assert.NoError(t, err, c.input)
+			assert.Equal(t, c.expected, period, c.input)
+		}
+	})
+
+	t.Run("it returns errors listing the accepted formats", func(t *testing.T) {
+		for _, input := range []string{"", "soon", "tomorrow", "next week 10-11", "10:00", "for", "2h 30m", "tomorrow 2h", "until end of week"} {
+			_, err := timeparse.Parse(input, now)
+			assert.Error(t, err, input)
+			assert.Contains(t, err.Error(), i18n.For(i18n.Default).Sprintf(string(timeparse.Formats)), input)
+			assert.Contains(t, i18n.For("ru").Error(err), i18n.For("ru").Sprintf(string(timeparse.Formats)), input)
+		}
+	})
+
+	t.Run("it rejects impossible periods", func(t *testing.T) {
+		cases := map[string]string{
+			"0": "Reservation must last at least a minute",
+			"until 25:00": "25:00 is not a valid time of day",
+			"9-11": "2026-03-04 11:00:00 has already passed",
+			"tomorrow 11-9": "Reservation must end after it starts",
+			"9000h": "Reservation cannot last longer than a year",
+		}
+
+		for input, message := range cases {
+			_, err := timeparse.Parse(input, now)
+			assert.Error(t, err, input)
+			assert.True(t, strings.HasPrefix(err.Error(), message), err.Error())
+		}
+	})
+
+	t.Run("it keeps wall clock times across daylight saving changes", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		assert.NoError(t, err)
+		// the clocks move forward the next night
+		now := time.Date(2026, 3, 28, 20, 0, 0, 0, berlin)
+
+		period, err := timeparse.Parse("tomorrow 9-11", now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 29, 9, 0, 0, 0, berlin), period.From)
+		assert.Equal(t, 2 * time.Hour, period.To.Sub(period.From))
+	})
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"30", "1h30m", "for 2h", "until 17:00", "till end of day", "tomorrow 9-11", "next monday 10:00 for 2h", "fri 9:30 – 12", "99999999999", "next"} {
+		f.Add(seed)
+	}
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(f, err)
+	now := time.Date(2026, 3, 4, 12, 30, 15, 0, moscow)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		period, err := timeparse.Parse(input, now)
+		if err != nil {
+			assert.NotEqual(t, "", err.Error())
+			return
+		}
+
+		assert.False(t, period.From.Before(now), input)
+		assert.True(t, period.To.After(period.From), input)
+		assert.True(t, period.To.Sub(period.From) <= 366 * 24 * time.Hour, input)
+		assert.Equal(t, moscow, period.From.Location(), input)
+		assert.Equal(t, moscow, period.To.Location(), input)
+	})
+}
@@ -0,0 +1,96 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+)
+
+const Version = 1
+
+// Bundle is a storage independent copy of all the application data
+type Bundle struct {
+	Version int `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Users []User `json:"users"`
+	TelegramUsers []TelegramUser `json:"telegram_users"`
+	// Workspaces leave out the default one, which every store has
+	Workspaces []Workspace `json:"workspaces,omitempty"`
+	ChatBindings []ChatBinding `json:"chat_bindings,omitempty"`
+	Subjects []Subject `json:"subjects"`
+	Reservations []Reservation `json:"reservations"`
+}
+
+type User struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Password string `json:"password,omitempty"`
+	TimeZone string `json:"time_zone,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+type TelegramUser struct {
+	TelegramId int64 `json:"telegram_id"`
+	UserId int `json:"user_id"`
+}
+
+type Workspace struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+}
+
+type ChatBinding struct {
+	Chat string `json:"chat"`
+	WorkspaceId int `json:"workspace_id"`
+}
+
+type Subject struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+	ParentId int `json:"parent_id,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+	// WorkspaceId is omitted for the default workspace
+	WorkspaceId int `json:"workspace_id,omitempty"`
+	SharedWith []int `json:"shared_with,omitempty"`
+}
+
+func (s Subject) workspace() int {
+	if s.WorkspaceId == 0 {
+		return workspaces.Default
+	}
+
+	return s.WorkspaceId
+}
+
+type Reservation struct {
+	Id int `json:"id"`
+	UserId int `json:"user_id"`
+	SubjectId int `json:"subject_id"`
+	Start time.Time `json:"start"`
+	End time.Time `json:"end"`
+}
+
+func (b Bundle) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(b)
+}
+
+func ReadJSON(r io.Reader) (Bundle, error) {
+	var b Bundle
+
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return Bundle{}, fmt.Errorf("Could not decode bundle: %w", err)
+	}
+	if b.Version != Version {
+		return Bundle{}, fmt.Errorf("Unsupported bundle version %d, expected %d", b.Version, Version)
+	}
+
+	return b, nil
+}
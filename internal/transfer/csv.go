@@ -0,0 +1,299 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var (
+	usersHeader = []string{"id", "name", "email", "password", "time_zone", "language"}
+	telegramUsersHeader = []string{"telegram_id", "user_id"}
+	workspacesHeader = []string{"id", "name"}
+	chatBindingsHeader = []string{"chat", "workspace_id"}
+	subjectsHeader = []string{"id", "name", "parent_id", "workspace_id"}
+	subjectTagsHeader = []string{"subject_id", "tag"}
+	subjectAliasesHeader = []string{"subject_id", "alias"}
+	subjectSharesHeader = []string{"subject_id", "workspace_id"}
+	reservationsHeader = []string{"id", "user_id", "subject_id", "start", "end"}
+)
+
+// WriteCSV writes a CSV file per entity into dir
+func (b Bundle) WriteCSV(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	users := [][]string{usersHeader}
+	for _, u := range b.Users {
+		users = append(users, []string{strconv.Itoa(u.Id), u.Name, u.Email, u.Password, u.TimeZone, u.Language})
+	}
+	telegramUsers := [][]string{telegramUsersHeader}
+	for _, u := range b.TelegramUsers {
+		telegramUsers = append(telegramUsers, []string{strconv.FormatInt(u.TelegramId, 10), strconv.Itoa(u.UserId)})
+	}
+	workspaces := [][]string{workspacesHeader}
+	for _, w := range b.Workspaces {
+		workspaces = append(workspaces, []string{strconv.Itoa(w.Id), w.Name})
+	}
+	bindings := [][]string{chatBindingsHeader}
+	for _, c := range b.ChatBindings {
+		bindings = append(bindings, []string{c.Chat, strconv.Itoa(c.WorkspaceId)})
+	}
+	subjects := [][]string{subjectsHeader}
+	tags := [][]string{subjectTagsHeader}
+	aliases := [][]string{subjectAliasesHeader}
+	shares := [][]string{subjectSharesHeader}
+	for _, s := range b.Subjects {
+		subjects = append(subjects, []string{strconv.Itoa(s.Id), s.Name, strconv.Itoa(s.ParentId), strconv.Itoa(s.WorkspaceId)})
+		for _, tag := range s.Tags {
+			tags = append(tags, []string{strconv.Itoa(s.Id), tag})
+		}
+		for _, alias := range s.Aliases {
+			aliases = append(aliases, []string{strconv.Itoa(s.Id), alias})
+		}
+		for _, workspaceId := range s.SharedWith {
+			shares = append(shares, []string{strconv.Itoa(s.Id), strconv.Itoa(workspaceId)})
+		}
+	}
+	reservations := [][]string{reservationsHeader}
+	for _, r := range b.Reservations {
+		reservations = append(reservations, []string{
+			strconv.Itoa(r.Id),
+			strconv.Itoa(r.UserId),
+			strconv.Itoa(r.SubjectId),
+			r.Start.Format(time.RFC3339Nano),
+			r.End.Format(time.RFC3339Nano),
+		})
+	}
+
+	files := map[string][][]string{
+		"users.csv": users,
+		"telegram_users.csv": telegramUsers,
+		"workspaces.csv": workspaces,
+		"chat_bindings.csv": bindings,
+		"subjects.csv": subjects,
+		"subject_tags.csv": tags,
+		"subject_aliases.csv": aliases,
+		"subject_shares.csv": shares,
+		"reservations.csv": reservations,
+	}
+	for name, records := range files {
+		if err := writeRecords(filepath.Join(dir, name), records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadCSV reads a bundle written by WriteCSV
+func ReadCSV(dir string) (Bundle, error) {
+	b := Bundle{Version: Version}
+
+	users, err := readRecords(filepath.Join(dir, "users.csv"), usersHeader)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, record := range users {
+		id, err := strconv.Atoi(record[0])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid user id %q: %w", record[0], err)
+		}
+		b.Users = append(b.Users, User{Id: id, Name: record[1], Email: record[2], Password: record[3], TimeZone: record[4], Language: record[5]})
+	}
+
+	telegramUsers, err := readRecords(filepath.Join(dir, "telegram_users.csv"), telegramUsersHeader)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, record := range telegramUsers {
+		tgId, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid telegram id %q: %w", record[0], err)
+		}
+		userId, err := strconv.Atoi(record[1])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid user id %q: %w", record[1], err)
+		}
+		b.TelegramUsers = append(b.TelegramUsers, TelegramUser{TelegramId: tgId, UserId: userId})
+	}
+
+	workspaces, err := readOptionalRecords(filepath.Join(dir, "workspaces.csv"), workspacesHeader)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, record := range workspaces {
+		id, err := strconv.Atoi(record[0])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid workspace id %q: %w", record[0], err)
+		}
+		b.Workspaces = append(b.Workspaces, Workspace{Id: id, Name: record[1]})
+	}
+
+	bindings, err := readOptionalRecords(filepath.Join(dir, "chat_bindings.csv"), chatBindingsHeader)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, record := range bindings {
+		id, err := strconv.Atoi(record[1])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid workspace id %q: %w", record[1], err)
+		}
+		b.ChatBindings = append(b.ChatBindings, ChatBinding{Chat: record[0], WorkspaceId: id})
+	}
+
+	subjects, err := readRecords(filepath.Join(dir, "subjects.csv"), subjectsHeader)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, record := range subjects {
+		// subjects of older bundles belong to the default workspace
+		if record[3] == "" {
+			record[3] = "0"
+		}
+		ids, err := atoi(record[0], record[2], record[3])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid subject %v: %w", record, err)
+		}
+		b.Subjects = append(b.Subjects, Subject{Id: ids[0], Name: record[1], ParentId: ids[1], WorkspaceId: ids[2]})
+	}
+
+	tags, err := readRecords(filepath.Join(dir, "subject_tags.csv"), subjectTagsHeader)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, record := range tags {
+		id, err := strconv.Atoi(record[0])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid subject id %q: %w", record[0], err)
+		}
+		index := slices.IndexFunc(b.Subjects, func(s Subject) bool { return s.Id == id })
+		if index == -1 {
+			return Bundle{}, fmt.Errorf("Tag %s refers to unknown subject %d", record[1], id)
+		}
+		b.Subjects[index].Tags = append(b.Subjects[index].Tags, record[1])
+	}
+
+	aliases, err := readOptionalRecords(filepath.Join(dir, "subject_aliases.csv"), subjectAliasesHeader)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, record := range aliases {
+		id, err := strconv.Atoi(record[0])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid subject id %q: %w", record[0], err)
+		}
+		index := slices.IndexFunc(b.Subjects, func(s Subject) bool { return s.Id == id })
+		if index == -1 {
+			return Bundle{}, fmt.Errorf("Alias %s refers to unknown subject %d", record[1], id)
+		}
+		b.Subjects[index].Aliases = append(b.Subjects[index].Aliases, record[1])
+	}
+
+	shares, err := readOptionalRecords(filepath.Join(dir, "subject_shares.csv"), subjectSharesHeader)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, record := range shares {
+		ids, err := atoi(record[0], record[1])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid share %v: %w", record, err)
+		}
+		index := slices.IndexFunc(b.Subjects, func(s Subject) bool { return s.Id == ids[0] })
+		if index == -1 {
+			return Bundle{}, fmt.Errorf("Share with workspace %d refers to unknown subject %d", ids[1], ids[0])
+		}
+		b.Subjects[index].SharedWith = append(b.Subjects[index].SharedWith, ids[1])
+	}
+
+	reservations, err := readRecords(filepath.Join(dir, "reservations.csv"), reservationsHeader)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, record := range reservations {
+		ids, err := atoi(record[0], record[1], record[2])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid reservation %v: %w", record, err)
+		}
+		start, err := time.Parse(time.RFC3339Nano, record[3])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid reservation %v: %w", record, err)
+		}
+		end, err := time.Parse(time.RFC3339Nano, record[4])
+		if err != nil {
+			return Bundle{}, fmt.Errorf("Invalid reservation %v: %w", record, err)
+		}
+		b.Reservations = append(b.Reservations, Reservation{Id: ids[0], UserId: ids[1], SubjectId: ids[2], Start: start, End: end})
+	}
+
+	return b, nil
+}
+
+func writeRecords(path string, records [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err = writer.WriteAll(records); err != nil {
+		return fmt.Errorf("Could not write %s: %w", path, err)
+	}
+
+	return file.Close()
+}
+
+// readRecords returns the records of the file with a field per column of the header.
+// Files of older bundles may lack the last columns, their fields are left empty
+func readRecords(path string, header []string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Could not read %s: %w", path, err)
+	}
+	if len(records) == 0 || len(records[0]) > len(header) || !slices.Equal(records[0], header[:len(records[0])]) {
+		return nil, fmt.Errorf("File %s must start with header %v", path, header)
+	}
+	for i, record := range records {
+		records[i] = append(record, make([]string, len(header) - len(record))...)
+	}
+
+	return records[1:], nil
+}
+
+// readOptionalRecords reads the files older bundles do not have, as if they had no records
+func readOptionalRecords(path string, header []string) ([][]string, error) {
+	records, err := readRecords(path, header)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	return records, err
+}
+
+func atoi(values ...string) ([]int, error) {
+	result := make([]int, len(values))
+	for i, value := range values {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = n
+	}
+
+	return result, nil
+}
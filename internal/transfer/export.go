@@ -0,0 +1,102 @@
+package transfer
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+	workspacesPort "github.com/SneedusSnake/Reservations/internal/ports/workspaces"
+)
+
+// Stores are the repositories data is exported from and imported into
+type Stores struct {
+	Users users.UsersRepository
+	TelegramUsers telegram.TelegramUsersRepository
+	Workspaces workspacesPort.WorkspacesRepository
+	Subjects reservations.SubjectsRepository
+	Reservations reservations.ReservationsRepository
+}
+
+func Export(ctx context.Context, stores Stores, now time.Time) (Bundle, error) {
+	b := Bundle{Version: Version, ExportedAt: now}
+
+	userList, err := stores.Users.List(ctx)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, u := range userList {
+		b.Users = append(b.Users, User{Id: u.Id, Name: u.Name, Email: u.Email, Password: u.Password, TimeZone: u.TimeZone, Language: u.Language})
+	}
+
+	telegramUsers, err := stores.TelegramUsers.List(ctx)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, u := range telegramUsers {
+		b.TelegramUsers = append(b.TelegramUsers, TelegramUser{TelegramId: u.TelegramId, UserId: u.Id})
+	}
+
+	workspaceList, err := stores.Workspaces.List(ctx)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, w := range workspaceList {
+		if w.Id != workspaces.Default {
+			b.Workspaces = append(b.Workspaces, Workspace{Id: w.Id, Name: w.Name})
+		}
+	}
+
+	bindings, err := stores.Workspaces.Bindings(ctx)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for chat, workspaceId := range bindings {
+		b.ChatBindings = append(b.ChatBindings, ChatBinding{Chat: chat, WorkspaceId: workspaceId})
+	}
+
+	subjects, err := stores.Subjects.List(ctx)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, s := range subjects {
+		tags, err := stores.Subjects.GetTags(ctx, s.Id)
+		if err != nil {
+			return Bundle{}, err
+		}
+		slices.Sort(tags)
+		aliases, err := stores.Subjects.GetAliases(ctx, s.Id)
+		if err != nil {
+			return Bundle{}, err
+		}
+		shares, err := stores.Subjects.SharedWith(ctx, s.Id)
+		if err != nil {
+			return Bundle{}, err
+		}
+		subject := Subject{Id: s.Id, Name: s.Name, ParentId: s.ParentId, Tags: tags, Aliases: aliases, SharedWith: shares}
+		if s.WorkspaceId != workspaces.Default {
+			subject.WorkspaceId = s.WorkspaceId
+		}
+		b.Subjects = append(b.Subjects, subject)
+	}
+
+	list, err := stores.Reservations.List(ctx)
+	if err != nil {
+		return Bundle{}, err
+	}
+	for _, r := range list {
+		b.Reservations = append(b.Reservations, Reservation{Id: r.Id, UserId: r.UserId, SubjectId: r.SubjectId, Start: r.Start, End: r.End})
+	}
+
+	slices.SortFunc(b.Users, func(a, b User) int { return a.Id - b.Id })
+	slices.SortFunc(b.Workspaces, func(a, b Workspace) int { return a.Id - b.Id })
+	slices.SortFunc(b.ChatBindings, func(a, b ChatBinding) int { return strings.Compare(a.Chat, b.Chat) })
+	slices.SortFunc(b.Subjects, func(a, b Subject) int { return a.Id - b.Id })
+	slices.SortFunc(b.Reservations, func(a, b Reservation) int { return a.Id - b.Id })
+
+	return b, nil
+}
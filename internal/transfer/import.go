@@ -0,0 +1,461 @@
+package transfer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+)
+
+// ConflictMode decides what happens to imported records whose id is taken by a different record
+type ConflictMode string
+
+const (
+	// ConflictFail aborts the import before anything is written
+	ConflictFail ConflictMode = "fail"
+	// ConflictSkip keeps the existing records and imports the rest
+	ConflictSkip ConflictMode = "skip"
+)
+
+func ParseConflictMode(s string) (ConflictMode, error) {
+	switch mode := ConflictMode(s); mode {
+	case ConflictFail, ConflictSkip:
+		return mode, nil
+	}
+
+	return "", fmt.Errorf("Unknown conflict mode %s, expected %s or %s", s, ConflictFail, ConflictSkip)
+}
+
+type ImportOptions struct {
+	DryRun bool
+	OnConflict ConflictMode
+}
+
+type Counts struct {
+	Created int
+	Unchanged int
+	Skipped int
+}
+
+type Conflict struct {
+	Entity string
+	Id string
+	// Reason is set unless the record differs from the existing one with the same id
+	Reason string
+}
+
+type Report struct {
+	DryRun bool
+	Users Counts
+	TelegramUsers Counts
+	Workspaces Counts
+	ChatBindings Counts
+	Subjects Counts
+	Reservations Counts
+	Conflicts []Conflict
+}
+
+func (r Report) String() string {
+	var sb strings.Builder
+	if r.DryRun {
+		sb.WriteString("Dry run, nothing was written\n")
+	}
+	for _, line := range []struct{ entity string; counts Counts }{
+		{"users", r.Users},
+		{"telegram users", r.TelegramUsers},
+		{"workspaces", r.Workspaces},
+		{"chat bindings", r.ChatBindings},
+		{"subjects", r.Subjects},
+		{"reservations", r.Reservations},
+	} {
+		fmt.Fprintf(&sb, "%s: %d created, %d unchanged, %d skipped\n", line.entity, line.counts.Created, line.counts.Unchanged, line.counts.Skipped)
+	}
+	for _, c := range r.Conflicts {
+		reason := c.Reason
+		if reason == "" {
+			reason = "differs from the existing one"
+		}
+		fmt.Fprintf(&sb, "Conflict: %s %s %s\n", c.Entity, c.Id, reason)
+	}
+
+	return sb.String()
+}
+
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("Import aborted: %d records conflict with existing ones", len(e.Conflicts))
+}
+
+type plan struct {
+	users []users.User
+	telegramUsers []telegram.TelegramUser
+	workspaces []workspaces.Workspace
+	bindings []ChatBinding
+	subjects []Subject
+	reservations reservations.Reservations
+}
+
+// Import writes the bundle records missing from the stores, records already present unchanged are left alone.
+// Every conflict is found before the first write. The writes are not atomic across the stores though:
+// when a store fails midway the records written before stay, importing the bundle again completes the import
+func Import(ctx context.Context, stores Stores, b Bundle, opts ImportOptions) (Report, error) {
+	report := Report{DryRun: opts.DryRun}
+
+	if err := validate(b); err != nil {
+		return report, err
+	}
+	p, err := planImport(ctx, stores, b, &report)
+	if err != nil {
+		return report, err
+	}
+	if len(report.Conflicts) > 0 && opts.OnConflict != ConflictSkip {
+		return report, ConflictError{Conflicts: report.Conflicts}
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	return report, apply(ctx, stores, p)
+}
+
+// validate checks that the bundle references only records it contains
+func validate(b Bundle) error {
+	userIds := make(map[int]bool)
+	for _, u := range b.Users {
+		userIds[u.Id] = true
+	}
+	subjectIds := make(map[int]bool)
+	for _, s := range b.Subjects {
+		subjectIds[s.Id] = true
+	}
+	workspaceIds := map[int]bool{workspaces.Default: true}
+	for _, w := range b.Workspaces {
+		workspaceIds[w.Id] = true
+	}
+
+	for _, u := range b.TelegramUsers {
+		if !userIds[u.UserId] {
+			return fmt.Errorf("Telegram user %d refers to unknown user %d", u.TelegramId, u.UserId)
+		}
+	}
+	for _, c := range b.ChatBindings {
+		if !workspaceIds[c.WorkspaceId] {
+			return fmt.Errorf("Chat %s is bound to unknown workspace %d", c.Chat, c.WorkspaceId)
+		}
+	}
+	for _, s := range b.Subjects {
+		if s.ParentId != 0 && !subjectIds[s.ParentId] {
+			return fmt.Errorf("Subject %d refers to unknown parent %d", s.Id, s.ParentId)
+		}
+		for _, workspaceId := range append([]int{s.workspace()}, s.SharedWith...) {
+			if !workspaceIds[workspaceId] {
+				return fmt.Errorf("Subject %d refers to unknown workspace %d", s.Id, workspaceId)
+			}
+		}
+	}
+	for _, r := range b.Reservations {
+		if !userIds[r.UserId] {
+			return fmt.Errorf("Reservation %d refers to unknown user %d", r.Id, r.UserId)
+		}
+		if !subjectIds[r.SubjectId] {
+			return fmt.Errorf("Reservation %d refers to unknown subject %d", r.Id, r.SubjectId)
+		}
+	}
+
+	return nil
+}
+
+// planImport skips the records conflicting with existing ones, and with them the records referring to their ids,
+// which would otherwise hang from the different records holding those ids
+func planImport(ctx context.Context, stores Stores, b Bundle, report *Report) (plan, error) {
+	var p plan
+	skippedUsers := make(map[int]bool)
+	skippedSubjects := make(map[int]bool)
+	skippedWorkspaces := make(map[int]bool)
+
+	for _, u := range b.Users {
+		imported := users.User{Id: u.Id, Name: u.Name, Email: u.Email, Password: u.Password, TimeZone: u.TimeZone, Language: u.Language}
+		existing, err := stores.Users.Get(ctx, u.Id)
+		switch {
+		case err != nil:
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return p, ctxErr
+			}
+			p.users = append(p.users, imported)
+			report.Users.Created++
+		case existing == imported:
+			report.Users.Unchanged++
+		default:
+			skippedUsers[u.Id] = true
+			report.Users.Skipped++
+			report.Conflicts = append(report.Conflicts, Conflict{Entity: "user", Id: fmt.Sprint(u.Id)})
+		}
+	}
+
+	for _, u := range b.TelegramUsers {
+		if skippedUsers[u.UserId] {
+			report.TelegramUsers.Skipped++
+			continue
+		}
+		existing, err := stores.TelegramUsers.Get(ctx, u.TelegramId)
+		switch {
+		case err != nil:
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return p, ctxErr
+			}
+			p.telegramUsers = append(p.telegramUsers, telegram.TelegramUser{TelegramId: u.TelegramId, User: users.User{Id: u.UserId}})
+			report.TelegramUsers.Created++
+		case existing.Id == u.UserId:
+			report.TelegramUsers.Unchanged++
+		default:
+			report.TelegramUsers.Skipped++
+			report.Conflicts = append(report.Conflicts, Conflict{Entity: "telegram user", Id: fmt.Sprint(u.TelegramId)})
+		}
+	}
+
+	for _, w := range b.Workspaces {
+		existing, err := stores.Workspaces.Get(ctx, w.Id)
+		switch {
+		case err != nil:
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return p, ctxErr
+			}
+			if _, err = stores.Workspaces.GetByName(ctx, w.Name); err == nil {
+				skippedWorkspaces[w.Id] = true
+				report.Workspaces.Skipped++
+				report.Conflicts = append(report.Conflicts, Conflict{Entity: "workspace", Id: w.Name})
+				continue
+			}
+			p.workspaces = append(p.workspaces, workspaces.Workspace{Id: w.Id, Name: w.Name})
+			report.Workspaces.Created++
+		case existing.Name == w.Name:
+			report.Workspaces.Unchanged++
+		default:
+			skippedWorkspaces[w.Id] = true
+			report.Workspaces.Skipped++
+			report.Conflicts = append(report.Conflicts, Conflict{Entity: "workspace", Id: fmt.Sprint(w.Id)})
+		}
+	}
+
+	bindings, err := stores.Workspaces.Bindings(ctx)
+	if err != nil {
+		return p, err
+	}
+	for _, c := range b.ChatBindings {
+		if skippedWorkspaces[c.WorkspaceId] {
+			report.ChatBindings.Skipped++
+			continue
+		}
+		existing, ok := bindings[c.Chat]
+		switch {
+		case !ok:
+			p.bindings = append(p.bindings, c)
+			report.ChatBindings.Created++
+		case existing == c.WorkspaceId:
+			report.ChatBindings.Unchanged++
+		default:
+			report.ChatBindings.Skipped++
+			report.Conflicts = append(report.Conflicts, Conflict{Entity: "chat binding", Id: c.Chat})
+		}
+	}
+
+	for _, s := range b.Subjects {
+		if skippedWorkspaces[s.workspace()] || slices.ContainsFunc(s.SharedWith, func(id int) bool { return skippedWorkspaces[id] }) {
+			skippedSubjects[s.Id] = true
+			report.Subjects.Skipped++
+			continue
+		}
+		existing, err := stores.Subjects.Get(ctx, s.Id)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return p, ctxErr
+			}
+			if _, err = stores.Subjects.GetByName(ctx, s.workspace(), s.Name); err == nil {
+				skippedSubjects[s.Id] = true
+				report.Subjects.Skipped++
+				report.Conflicts = append(report.Conflicts, Conflict{Entity: "subject", Id: s.Name})
+				continue
+			}
+			p.subjects = append(p.subjects, s)
+			report.Subjects.Created++
+			continue
+		}
+
+		tags, err := stores.Subjects.GetTags(ctx, s.Id)
+		if err != nil {
+			return p, err
+		}
+		aliases, err := stores.Subjects.GetAliases(ctx, s.Id)
+		if err != nil {
+			return p, err
+		}
+		shares, err := stores.Subjects.SharedWith(ctx, s.Id)
+		if err != nil {
+			return p, err
+		}
+		same := sameValues(tags, s.Tags) && sameValues(aliases, s.Aliases) && sameValues(shares, s.SharedWith)
+		if existing.Name == s.Name && existing.ParentId == s.ParentId && existing.WorkspaceId == s.workspace() && same {
+			report.Subjects.Unchanged++
+			continue
+		}
+		skippedSubjects[s.Id] = true
+		report.Subjects.Skipped++
+		report.Conflicts = append(report.Conflicts, Conflict{Entity: "subject", Id: fmt.Sprint(s.Id)})
+	}
+	// components of skipped subjects are skipped along, down to the last level
+	for skipped := true; skipped; {
+		skipped = false
+		p.subjects = slices.DeleteFunc(p.subjects, func(s Subject) bool {
+			if !skippedSubjects[s.ParentId] {
+				return false
+			}
+			skippedSubjects[s.Id] = true
+			report.Subjects.Created--
+			report.Subjects.Skipped++
+			skipped = true
+			return true
+		})
+	}
+
+	for _, r := range b.Reservations {
+		if skippedUsers[r.UserId] || skippedSubjects[r.SubjectId] {
+			report.Reservations.Skipped++
+			continue
+		}
+		imported := reservations.Reservation{Id: r.Id, UserId: r.UserId, SubjectId: r.SubjectId, Start: r.Start, End: r.End}
+		existing, err := stores.Reservations.Get(ctx, r.Id)
+		switch {
+		case err != nil:
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return p, ctxErr
+			}
+			p.reservations = append(p.reservations, imported)
+			report.Reservations.Created++
+		case existing.UserId == r.UserId && existing.SubjectId == r.SubjectId && existing.Start.Equal(r.Start) && existing.End.Equal(r.End):
+			report.Reservations.Unchanged++
+		default:
+			report.Reservations.Skipped++
+			report.Conflicts = append(report.Conflicts, Conflict{Entity: "reservation", Id: fmt.Sprint(r.Id)})
+		}
+	}
+	p.reservations, err = withoutOverlaps(ctx, stores, p.reservations, report)
+
+	return p, err
+}
+
+// withoutOverlaps skips the reservations overlapping existing reservations of their subject, or each other
+func withoutOverlaps(ctx context.Context, stores Stores, planned reservations.Reservations, report *Report) (reservations.Reservations, error) {
+	var result reservations.Reservations
+	for _, r := range planned {
+		existing, err := stores.Reservations.ForPeriod(ctx, r.Start, r.End)
+		if err != nil {
+			return nil, err
+		}
+		other, found := overlapping(existing, r)
+		if !found {
+			other, found = overlapping(result, r)
+		}
+		if !found {
+			result = append(result, r)
+			continue
+		}
+		report.Reservations.Created--
+		report.Reservations.Skipped++
+		report.Conflicts = append(report.Conflicts, Conflict{Entity: "reservation", Id: fmt.Sprint(r.Id), Reason: fmt.Sprintf("overlaps reservation %d", other.Id)})
+	}
+
+	return result, nil
+}
+
+func overlapping(rs reservations.Reservations, r reservations.Reservation) (reservations.Reservation, bool) {
+	for _, other := range rs {
+		if other.SubjectId == r.SubjectId && other.Id != r.Id && other.Interval().Overlaps(r.Interval()) {
+			return other, true
+		}
+	}
+
+	return reservations.Reservation{}, false
+}
+
+func apply(ctx context.Context, stores Stores, p plan) error {
+	for _, u := range p.users {
+		if err := stores.Users.Add(ctx, u); err != nil {
+			return fmt.Errorf("Could not import user %d: %w", u.Id, err)
+		}
+	}
+	for _, u := range p.telegramUsers {
+		if err := stores.TelegramUsers.Add(ctx, u); err != nil {
+			return fmt.Errorf("Could not import telegram user %d: %w", u.TelegramId, err)
+		}
+	}
+	for _, w := range p.workspaces {
+		if err := stores.Workspaces.Add(ctx, w); err != nil {
+			return fmt.Errorf("Could not import workspace %d: %w", w.Id, err)
+		}
+	}
+	for _, c := range p.bindings {
+		if err := stores.Workspaces.Bind(ctx, c.Chat, c.WorkspaceId); err != nil {
+			return fmt.Errorf("Could not import binding of chat %s: %w", c.Chat, err)
+		}
+	}
+	for _, s := range p.subjects {
+		if err := stores.Subjects.Add(ctx, reservations.Subject{Id: s.Id, Name: s.Name, ParentId: s.ParentId, WorkspaceId: s.workspace()}); err != nil {
+			return fmt.Errorf("Could not import subject %d: %w", s.Id, err)
+		}
+		for _, tag := range s.Tags {
+			if err := stores.Subjects.AddTag(ctx, s.Id, tag); err != nil {
+				return fmt.Errorf("Could not import tag %s of subject %d: %w", tag, s.Id, err)
+			}
+		}
+		for _, alias := range s.Aliases {
+			if err := stores.Subjects.AddAlias(ctx, s.Id, alias); err != nil {
+				return fmt.Errorf("Could not import alias %s of subject %d: %w", alias, s.Id, err)
+			}
+		}
+		for _, workspaceId := range s.SharedWith {
+			if err := stores.Subjects.Share(ctx, s.Id, workspaceId); err != nil {
+				return fmt.Errorf("Could not share subject %d with workspace %d: %w", s.Id, workspaceId, err)
+			}
+		}
+	}
+	if len(p.reservations) > 0 {
+		if err := stores.Reservations.AddMany(ctx, p.reservations); err != nil {
+			return fmt.Errorf("Could not import reservations: %w", err)
+		}
+	}
+
+	// imported ids must not be handed out again
+	if err := stores.Users.AdvanceIdentity(ctx, maxId(p.users, func(u users.User) int { return u.Id })); err != nil {
+		return err
+	}
+	if err := stores.Workspaces.AdvanceIdentity(ctx, maxId(p.workspaces, func(w workspaces.Workspace) int { return w.Id })); err != nil {
+		return err
+	}
+	if err := stores.Subjects.AdvanceIdentity(ctx, maxId(p.subjects, func(s Subject) int { return s.Id })); err != nil {
+		return err
+	}
+
+	return stores.Reservations.AdvanceIdentity(ctx, maxId(p.reservations, func(r reservations.Reservation) int { return r.Id }))
+}
+
+func maxId[T any](items []T, id func(T) int) int {
+	result := 0
+	for _, item := range items {
+		result = max(result, id(item))
+	}
+
+	return result
+}
+
+func sameValues[T cmp.Ordered](a []T, b []T) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
@@ -0,0 +1,242 @@
+package transfer_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/inmemory"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/internal/domain/workspaces"
+	"github.com/SneedusSnake/Reservations/internal/transfer"
+	"github.com/alecthomas/assert/v2"
+)
+
+var ctx = context.Background()
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newStores() transfer.Stores {
+	usersStore := inmemory.NewUsersStore()
+
+	return transfer.Stores{
+		Users: usersStore,
+		TelegramUsers: inmemory.NewTelegramUsersStore(usersStore),
+		Workspaces: inmemory.NewWorkspacesStore(),
+		Subjects: inmemory.NewSubjectsStore(),
+		Reservations: inmemory.NewReservationStore(),
+	}
+}
+
+func seededStores(t *testing.T) transfer.Stores {
+	stores := newStores()
+	assert.NoError(t, stores.Users.Add(ctx, users.User{Id: 1, Name: "Alice", Email: "alice@example.com", TimeZone: "Europe/Moscow", Language: "ru"}))
+	assert.NoError(t, stores.Users.Add(ctx, users.User{Id: 2, Name: "Bob"}))
+	assert.NoError(t, stores.TelegramUsers.Add(ctx, telegram.TelegramUser{TelegramId: 100, User: users.User{Id: 1}}))
+	assert.NoError(t, stores.Subjects.Add(ctx, reservations.Subject{Id: 1, Name: "Bench", WorkspaceId: workspaces.Default}))
+	assert.NoError(t, stores.Subjects.Add(ctx, reservations.Subject{Id: 2, Name: "Phone, \"test\"", ParentId: 1, WorkspaceId: workspaces.Default}))
+	assert.NoError(t, stores.Subjects.AddTag(ctx, 1, "lab"))
+	assert.NoError(t, stores.Subjects.AddTag(ctx, 1, "floor 2"))
+	assert.NoError(t, stores.Subjects.AddAlias(ctx, 1, "workbench"))
+	assert.NoError(t, stores.Workspaces.Add(ctx, workspaces.Workspace{Id: 2, Name: "qa"}))
+	assert.NoError(t, stores.Workspaces.Bind(ctx, "telegram:-100", 2))
+	assert.NoError(t, stores.Subjects.Add(ctx, reservations.Subject{Id: 3, Name: "Scope", WorkspaceId: 2}))
+	assert.NoError(t, stores.Subjects.Share(ctx, 1, 2))
+	assert.NoError(t, stores.Reservations.Add(ctx, reservations.Reservation{Id: 7, UserId: 2, SubjectId: 2, Start: now, End: now.Add(time.Hour)}))
+
+	return stores
+}
+
+func TestExportImport(t *testing.T) {
+	t.Run("it copies everything between stores through JSON", func(t *testing.T) {
+		exported, err := transfer.Export(ctx, seededStores(t), now)
+		assert.NoError(t, err)
+		var buf bytes.Buffer
+		assert.NoError(t, exported.WriteJSON(&buf))
+
+		decoded, err := transfer.ReadJSON(&buf)
+		assert.NoError(t, err)
+		target := newStores()
+		report, err := transfer.Import(ctx, target, decoded, transfer.ImportOptions{OnConflict: transfer.ConflictFail})
+		assert.NoError(t, err)
+		assert.Equal(t, transfer.Counts{Created: 2}, report.Users)
+		assert.Equal(t, transfer.Counts{Created: 1}, report.Workspaces)
+		assert.Equal(t, transfer.Counts{Created: 1}, report.ChatBindings)
+		assert.Equal(t, transfer.Counts{Created: 1}, report.Reservations)
+
+		reexported, err := transfer.Export(ctx, target, now)
+		assert.NoError(t, err)
+		assert.Equal(t, exported, reexported)
+
+		id, err := target.Reservations.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 8, id)
+		id, err = target.Workspaces.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+	})
+
+	t.Run("it copies everything between stores through CSV", func(t *testing.T) {
+		dir := t.TempDir()
+		exported, err := transfer.Export(ctx, seededStores(t), now)
+		assert.NoError(t, err)
+		assert.NoError(t, exported.WriteCSV(dir))
+
+		decoded, err := transfer.ReadCSV(dir)
+		assert.NoError(t, err)
+		target := newStores()
+		_, err = transfer.Import(ctx, target, decoded, transfer.ImportOptions{OnConflict: transfer.ConflictFail})
+		assert.NoError(t, err)
+
+		reexported, err := transfer.Export(ctx, target, now)
+		assert.NoError(t, err)
+		assert.Equal(t, exported, reexported)
+	})
+
+	t.Run("it reads CSV of older bundles lacking the last columns", func(t *testing.T) {
+		dir := t.TempDir()
+		exported, err := transfer.Export(ctx, seededStores(t), now)
+		assert.NoError(t, err)
+		assert.NoError(t, exported.WriteCSV(dir))
+		users := "id,name,email,password\n1,Alice,alice@example.com,\n2,Bob,,\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte(users), 0o644))
+		assert.NoError(t, os.Remove(filepath.Join(dir, "subject_shares.csv")))
+		assert.NoError(t, os.Remove(filepath.Join(dir, "subject_aliases.csv")))
+
+		decoded, err := transfer.ReadCSV(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, transfer.User{Id: 1, Name: "Alice", Email: "alice@example.com"}, decoded.Users[0])
+	})
+
+	t.Run("it rejects bundles of unknown version", func(t *testing.T) {
+		_, err := transfer.ReadJSON(bytes.NewBufferString(`{"version": 99}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("it writes nothing on dry run", func(t *testing.T) {
+		exported, err := transfer.Export(ctx, seededStores(t), now)
+		assert.NoError(t, err)
+		target := newStores()
+
+		report, err := transfer.Import(ctx, target, exported, transfer.ImportOptions{DryRun: true, OnConflict: transfer.ConflictFail})
+		assert.NoError(t, err)
+		assert.Equal(t, transfer.Counts{Created: 3}, report.Subjects)
+
+		list, err := target.Users.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(list))
+	})
+
+	t.Run("it leaves unchanged records alone", func(t *testing.T) {
+		source := seededStores(t)
+		exported, err := transfer.Export(ctx, source, now)
+		assert.NoError(t, err)
+
+		report, err := transfer.Import(ctx, source, exported, transfer.ImportOptions{OnConflict: transfer.ConflictFail})
+		assert.NoError(t, err)
+		assert.Equal(t, transfer.Counts{Unchanged: 2}, report.Users)
+		assert.Equal(t, transfer.Counts{Unchanged: 3}, report.Subjects)
+		assert.Equal(t, transfer.Counts{Unchanged: 1}, report.ChatBindings)
+		assert.Equal(t, 0, len(report.Conflicts))
+	})
+
+	t.Run("it resolves conflicts by the given mode", func(t *testing.T) {
+		exported, err := transfer.Export(ctx, seededStores(t), now)
+		assert.NoError(t, err)
+		target := newStores()
+		assert.NoError(t, target.Users.Add(ctx, users.User{Id: 2, Name: "Carol"}))
+
+		_, err = transfer.Import(ctx, target, exported, transfer.ImportOptions{OnConflict: transfer.ConflictFail})
+		var conflictErr transfer.ConflictError
+		assert.True(t, errors.As(err, &conflictErr))
+		assert.Equal(t, []transfer.Conflict{{Entity: "user", Id: "2"}}, conflictErr.Conflicts)
+		subjects, err := target.Subjects.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(subjects))
+
+		report, err := transfer.Import(ctx, target, exported, transfer.ImportOptions{OnConflict: transfer.ConflictSkip})
+		assert.NoError(t, err)
+		assert.Equal(t, transfer.Counts{Created: 1, Skipped: 1}, report.Users)
+		carol, err := target.Users.Get(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "Carol", carol.Name)
+		subjects, err = target.Subjects.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(subjects))
+	})
+
+	t.Run("it skips the records referring to skipped ones", func(t *testing.T) {
+		exported, err := transfer.Export(ctx, seededStores(t), now)
+		assert.NoError(t, err)
+		target := newStores()
+		assert.NoError(t, target.Users.Add(ctx, users.User{Id: 1, Name: "Carol"}))
+		assert.NoError(t, target.Subjects.Add(ctx, reservations.Subject{Id: 1, Name: "Projector", WorkspaceId: workspaces.Default}))
+
+		report, err := transfer.Import(ctx, target, exported, transfer.ImportOptions{OnConflict: transfer.ConflictSkip})
+		assert.NoError(t, err)
+		assert.Equal(t, transfer.Counts{Created: 1, Skipped: 1}, report.Users)
+		assert.Equal(t, transfer.Counts{Skipped: 1}, report.TelegramUsers)
+		assert.Equal(t, transfer.Counts{Created: 1, Skipped: 2}, report.Subjects)
+		assert.Equal(t, transfer.Counts{Skipped: 1}, report.Reservations)
+		_, err = target.TelegramUsers.Get(ctx, 100)
+		assert.Error(t, err)
+		subjects, err := target.Subjects.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(subjects))
+		list, err := target.Reservations.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(list))
+
+		id, err := target.Users.NextIdentity(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+	})
+
+	t.Run("it skips the records of skipped workspaces", func(t *testing.T) {
+		exported, err := transfer.Export(ctx, seededStores(t), now)
+		assert.NoError(t, err)
+		target := newStores()
+		assert.NoError(t, target.Workspaces.Add(ctx, workspaces.Workspace{Id: 2, Name: "hardware"}))
+
+		report, err := transfer.Import(ctx, target, exported, transfer.ImportOptions{OnConflict: transfer.ConflictSkip})
+		assert.NoError(t, err)
+		assert.Equal(t, transfer.Counts{Skipped: 1}, report.Workspaces)
+		assert.Equal(t, transfer.Counts{Skipped: 1}, report.ChatBindings)
+		assert.Equal(t, transfer.Counts{Skipped: 3}, report.Subjects)
+		assert.Equal(t, []transfer.Conflict{{Entity: "workspace", Id: "2"}}, report.Conflicts)
+		id, err := target.Workspaces.ForChat(ctx, "telegram:-100")
+		assert.NoError(t, err)
+		assert.Equal(t, workspaces.Default, id)
+	})
+	t.Run("it reports reservations overlapping existing ones or each other as conflicts", func(t *testing.T) {
+		exported, err := transfer.Export(ctx, seededStores(t), now)
+		assert.NoError(t, err)
+		exported.Reservations = append(exported.Reservations,
+			transfer.Reservation{Id: 8, UserId: 1, SubjectId: 2, Start: now.Add(30 * time.Minute), End: now.Add(2 * time.Hour)},
+			transfer.Reservation{Id: 9, UserId: 1, SubjectId: 1, Start: now, End: now.Add(time.Hour)},
+		)
+		target := newStores()
+		assert.NoError(t, target.Reservations.Add(ctx, reservations.Reservation{Id: 20, UserId: 5, SubjectId: 1, Start: now.Add(-time.Hour), End: now.Add(time.Minute)}))
+
+		_, err = transfer.Import(ctx, target, exported, transfer.ImportOptions{OnConflict: transfer.ConflictFail})
+		var conflictErr transfer.ConflictError
+		assert.True(t, errors.As(err, &conflictErr))
+		assert.Equal(t, []transfer.Conflict{
+			{Entity: "reservation", Id: "8", Reason: "overlaps reservation 7"},
+			{Entity: "reservation", Id: "9", Reason: "overlaps reservation 20"},
+		}, conflictErr.Conflicts)
+		list, err := target.Users.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(list))
+
+		report, err := transfer.Import(ctx, target, exported, transfer.ImportOptions{OnConflict: transfer.ConflictSkip})
+		assert.NoError(t, err)
+		assert.Equal(t, transfer.Counts{Created: 1, Skipped: 2}, report.Reservations)
+		assert.Contains(t, report.String(), "Conflict: reservation 8 overlaps reservation 7")
+	})
+}
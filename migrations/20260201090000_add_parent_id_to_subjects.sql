@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE subjects ADD COLUMN parent_id INTEGER NULL;
+CREATE INDEX subjects_parent_id_index ON subjects(parent_id);
+
+-- +goose Down
+DROP INDEX subjects_parent_id_index ON subjects;
+ALTER TABLE subjects DROP COLUMN parent_id;
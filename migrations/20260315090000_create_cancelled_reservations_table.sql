@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS cancelled_reservations(
+    id INTEGER PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    subject_id INTEGER NOT NULL,
+    start DATETIME NOT NULL,
+    end DATETIME NOT NULL
+);
+
+-- +goose Down
+DROP TABLE cancelled_reservations;
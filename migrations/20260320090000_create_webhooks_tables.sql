@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhooks(
+    id INTEGER PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events VARCHAR(1024) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS dead_letters(
+    id INTEGER PRIMARY KEY,
+    webhook_id INTEGER NOT NULL,
+    event_type VARCHAR(255) NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    failed_at DATETIME NOT NULL,
+    INDEX dead_letters_failed_at_index (failed_at)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_seq(
+    value INTEGER PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS dead_letter_seq(
+    value INTEGER PRIMARY KEY
+);
+
+INSERT INTO webhook_seq VALUES (0);
+
+INSERT INTO dead_letter_seq VALUES (0);
+
+-- +goose Down
+DROP TABLE dead_letter_seq;
+DROP TABLE webhook_seq;
+DROP TABLE dead_letters;
+DROP TABLE webhooks;
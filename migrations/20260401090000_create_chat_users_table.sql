@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS chat_users(
+    platform VARCHAR(32) NOT NULL,
+    external_id VARCHAR(255) NOT NULL,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (platform, external_id)
+);
+
+-- +goose Down
+DROP TABLE chat_users;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS access_tokens(
+    id INTEGER PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at DATETIME NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS access_token_seq(
+    value INTEGER PRIMARY KEY
+);
+
+INSERT INTO access_token_seq VALUES (0);
+
+-- +goose Down
+DROP TABLE access_token_seq;
+DROP TABLE access_tokens;
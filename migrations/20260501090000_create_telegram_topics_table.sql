@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS telegram_topics(
+    chat_id BIGINT NOT NULL,
+    thread_id INTEGER NOT NULL,
+    tag VARCHAR(255) NOT NULL DEFAULT '',
+    subject_ids VARCHAR(1024) NOT NULL DEFAULT '',
+    PRIMARY KEY(chat_id, thread_id)
+);
+
+-- +goose Down
+DROP TABLE telegram_topics;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN time_zone;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN language VARCHAR(16) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS telegram_chats(
+    chat_id BIGINT PRIMARY KEY,
+    language VARCHAR(16) NOT NULL DEFAULT ''
+);
+
+-- +goose Down
+DROP TABLE telegram_chats;
+ALTER TABLE users DROP COLUMN language;
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS subject_aliases(
+    subject_id INTEGER NOT NULL,
+    alias VARCHAR(255) NOT NULL,
+    PRIMARY KEY(subject_id, alias)
+);
+
+-- +goose Down
+DROP TABLE subject_aliases;
@@ -0,0 +1,48 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    password VARCHAR(255)
+);
+
+CREATE TABLE IF NOT EXISTS subjects (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    parent_id INTEGER NULL
+);
+
+CREATE INDEX subjects_parent_id_index ON subjects(parent_id);
+
+CREATE TABLE IF NOT EXISTS subject_tags (
+    subject_id INTEGER NOT NULL,
+    tag VARCHAR(255) NOT NULL,
+    PRIMARY KEY(subject_id, tag)
+);
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id INTEGER PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    subject_id INTEGER NOT NULL,
+    start TIMESTAMPTZ NOT NULL,
+    "end" TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS telegram_users (
+    telegram_id BIGINT PRIMARY KEY,
+    user_id INTEGER NOT NULL
+);
+
+CREATE SEQUENCE IF NOT EXISTS user_seq;
+CREATE SEQUENCE IF NOT EXISTS subject_seq;
+CREATE SEQUENCE IF NOT EXISTS reservation_seq;
+
+-- +goose Down
+DROP SEQUENCE reservation_seq;
+DROP SEQUENCE subject_seq;
+DROP SEQUENCE user_seq;
+DROP TABLE telegram_users;
+DROP TABLE reservations;
+DROP TABLE subject_tags;
+DROP TABLE subjects;
+DROP TABLE users;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- reservations of a subject must not overlap, intervals are half-open [start, end).
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE reservations ADD CONSTRAINT reservations_no_overlap
+    EXCLUDE USING gist (subject_id WITH =, tstzrange(start, "end", '[)') WITH &&);
+
+-- +goose Down
+ALTER TABLE reservations DROP CONSTRAINT reservations_no_overlap;
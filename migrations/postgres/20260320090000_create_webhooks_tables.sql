@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events VARCHAR(1024) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id INTEGER PRIMARY KEY,
+    webhook_id INTEGER NOT NULL,
+    event_type VARCHAR(255) NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    failed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX dead_letters_failed_at_index ON dead_letters(failed_at);
+
+CREATE SEQUENCE IF NOT EXISTS webhook_seq;
+CREATE SEQUENCE IF NOT EXISTS dead_letter_seq;
+
+-- +goose Down
+DROP SEQUENCE dead_letter_seq;
+DROP SEQUENCE webhook_seq;
+DROP TABLE dead_letters;
+DROP TABLE webhooks;
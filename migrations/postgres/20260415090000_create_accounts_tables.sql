@@ -0,0 +1,17 @@
+-- +goose Up
+UPDATE users SET email = NULL WHERE email = '';
+UPDATE users SET password = NULL WHERE password = '';
+CREATE UNIQUE INDEX users_email_index ON users(email);
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    hash VARCHAR(64) PRIMARY KEY,
+    kind VARCHAR(32) NOT NULL,
+    user_id INTEGER NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX user_tokens_user_id_index ON user_tokens(user_id, kind);
+
+-- +goose Down
+DROP TABLE user_tokens;
+DROP INDEX users_email_index;
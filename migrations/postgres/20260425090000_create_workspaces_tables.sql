@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS workspaces (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS workspace_chats (
+    chat VARCHAR(255) PRIMARY KEY,
+    workspace_id INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS workspace_admins (
+    workspace_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY(workspace_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS subject_workspaces (
+    subject_id INTEGER NOT NULL,
+    workspace_id INTEGER NOT NULL,
+    PRIMARY KEY(subject_id, workspace_id)
+);
+
+INSERT INTO workspaces VALUES (1, 'default');
+
+CREATE SEQUENCE IF NOT EXISTS workspace_seq START WITH 2;
+
+ALTER TABLE subjects ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE access_tokens ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE access_tokens DROP COLUMN workspace_id;
+ALTER TABLE subjects DROP COLUMN workspace_id;
+DROP SEQUENCE workspace_seq;
+DROP TABLE subject_workspaces;
+DROP TABLE workspace_admins;
+DROP TABLE workspace_chats;
+DROP TABLE workspaces;
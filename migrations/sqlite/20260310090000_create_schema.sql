@@ -0,0 +1,64 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    password VARCHAR(255)
+);
+
+CREATE TABLE IF NOT EXISTS subjects (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    parent_id INTEGER NULL
+);
+
+CREATE INDEX subjects_parent_id_index ON subjects(parent_id);
+
+CREATE TABLE IF NOT EXISTS subject_tags (
+    subject_id INTEGER NOT NULL,
+    tag VARCHAR(255) NOT NULL,
+    PRIMARY KEY(subject_id, tag)
+);
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id INTEGER PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    subject_id INTEGER NOT NULL,
+    start DATETIME NOT NULL,
+    "end" DATETIME NOT NULL
+);
+
+CREATE INDEX reservations_period_index ON reservations(subject_id, start, "end");
+
+CREATE TABLE IF NOT EXISTS telegram_users (
+    telegram_id INTEGER PRIMARY KEY,
+    user_id INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_seq (
+    value INTEGER PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS subject_seq (
+    value INTEGER PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS reservation_seq (
+    value INTEGER PRIMARY KEY
+);
+
+INSERT INTO user_seq VALUES (0);
+
+INSERT INTO subject_seq VALUES (0);
+
+INSERT INTO reservation_seq VALUES (0);
+
+-- +goose Down
+DROP TABLE reservation_seq;
+DROP TABLE subject_seq;
+DROP TABLE user_seq;
+DROP TABLE telegram_users;
+DROP TABLE reservations;
+DROP TABLE subject_tags;
+DROP TABLE subjects;
+DROP TABLE users;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- reservations of a subject must not overlap, intervals are half-open [start, end)
+-- +goose StatementBegin
+CREATE TRIGGER reservations_no_overlap BEFORE INSERT ON reservations
+WHEN NEW.start < NEW."end" AND EXISTS (
+    SELECT 1 FROM reservations
+    WHERE subject_id = NEW.subject_id AND start < NEW."end" AND "end" > NEW.start AND start < "end"
+)
+BEGIN
+    SELECT RAISE(ABORT, 'reservation overlaps an existing reservation of the subject');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER reservations_no_overlap;
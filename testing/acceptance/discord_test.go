@@ -0,0 +1,51 @@
+package acceptance
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/clock/cache"
+	"github.com/SneedusSnake/Reservations/testing/acceptance/drivers/discord"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	"github.com/SneedusSnake/Reservations/testing/containers/app"
+	"github.com/SneedusSnake/Reservations/testing/containers/mysql"
+	"github.com/SneedusSnake/Reservations/testing/containers/discord_api"
+	"github.com/alecthomas/assert/v2"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+)
+
+func TestDiscordSuite(t *testing.T) {
+	ctx := t.Context()
+	net, err := network.New(ctx)
+	assert.NoError(t, err)
+	mysqlConnection := ""
+	if os.Getenv("PERSISTENCE") == "mysql" {
+		sqlContainer, err := mysql.Start(ctx, net.Name, containers.Stdout("mysql"))
+		assert.NoError(t, err)
+		mysqlConnection, err = sqlContainer.ExternalConnectionString(ctx)
+		assert.NoError(t, err)
+	}
+
+	// the stub has to be up first as the app registers its commands on startup
+	apiContainer, err := discord_api.Start(ctx, net.Name, app.HTTP_URL, containers.Stdout("Discord test server"))
+	testcontainers.CleanupContainer(t, apiContainer)
+	assert.NoError(t, err)
+	appContainer, err := app.StartDiscord(ctx, net.Name, mysqlConnection, discord_api.PublicKey(), discord_api.API_URL, containers.Stdout("Application"))
+	testcontainers.CleanupContainer(t, appContainer)
+	assert.NoError(t, err)
+	host, err := apiContainer.Endpoint(ctx, "")
+	assert.NoError(t, err)
+
+	driver := discord.NewDriver(
+		http.DefaultClient,
+		"http://" + host,
+		cache.NewClock(app.CLOCK_CACHE_PATH),
+		appContainer,
+		t,
+	)
+
+	prepareTestFixtures(driver)
+	runSpecifications(t, driver, driver.CleanUp)
+}
@@ -0,0 +1,305 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/clock/cache"
+	"github.com/alecthomas/assert/v2"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const channelId = "200000000000000001"
+
+type Option struct {
+	Name string `json:"name"`
+	Value any `json:"value"`
+}
+
+type Command struct {
+	Name string `json:"name"`
+	Options []Option `json:"options"`
+	UserId string `json:"user_id"`
+	UserName string `json:"user_name"`
+	ChannelId string `json:"channel_id"`
+}
+
+type Click struct {
+	CustomId string `json:"custom_id"`
+	UserId string `json:"user_id"`
+	UserName string `json:"user_name"`
+	ChannelId string `json:"channel_id"`
+}
+
+type Message struct {
+	ChannelId string `json:"channel_id"`
+	Content string `json:"content"`
+	Components []Component `json:"components"`
+}
+
+type Component struct {
+	CustomId string `json:"custom_id"`
+	Components []Component `json:"components"`
+}
+
+type Reservation struct {
+	User string
+	Subject string
+	Time time.Time
+}
+
+type DiscordDriver struct {
+	client *http.Client
+	host string
+	clock cache.CacheClock
+	users map[string]string
+	// releaseButtons keeps the custom id of the Release button of the last reservation of a user for a subject
+	releaseButtons map[string]string
+	appContainer testcontainers.Container
+	responses []Message
+	t *testing.T
+}
+
+func NewDriver(client *http.Client, host string, clock cache.CacheClock, app testcontainers.Container, t *testing.T) *DiscordDriver {
+	return &DiscordDriver{
+		client: client,
+		host: host,
+		clock: clock,
+		users: make(map[string]string),
+		releaseButtons: make(map[string]string),
+		appContainer: app,
+		t: t,
+	}
+}
+
+func (d *DiscordDriver) AdminAddsSubject(subject string) {
+	d.sendCommand("Admin", "add_subject", Option{"name", subject})
+}
+
+func (d *DiscordDriver) AdminAddsTagsToSubject(subject string, tags ...string) {
+	d.sendCommand("Admin", "add_tags", Option{"subject", subject}, Option{"tags", strings.Join(tags, " ")})
+}
+
+func (d *DiscordDriver) AdminAddsComponentToSubject(subject string, component string) {
+	d.sendCommand("Admin", "add_component", Option{"parent", subject}, Option{"component", component})
+}
+
+func (d *DiscordDriver) UserRequestsSubjectsList() {
+	d.sendCommand("Alice", "list")
+}
+
+func (d *DiscordDriver) UserRequestsSubjectTags(subject string) {
+	d.sendCommand("Alice", "tags", Option{"subject", subject})
+}
+
+func (d *DiscordDriver) UserRequestsReservationForSubject(user string, subject string, minutes int) {
+	msg := d.sendCommand(user, "reserve", Option{"subjects", subject}, Option{"period", strconv.Itoa(minutes)})
+
+	for _, row := range msg.Components {
+		for _, button := range row.Components {
+			if strings.HasPrefix(button.CustomId, "release:") {
+				d.releaseButtons[user + " " + subject] = button.CustomId
+			}
+		}
+	}
+}
+
+func (d *DiscordDriver) UserRequestsReservationForSubjects(user string, subjects []string, minutes int) {
+	d.UserRequestsReservationForSubject(user, strings.Join(subjects, ","), minutes)
+}
+
+// UserRequestsReservationRemoval clicks the Release button of the reservation when there is one
+func (d *DiscordDriver) UserRequestsReservationRemoval(user string, subject string) {
+	customId, ok := d.releaseButtons[user + " " + subject]
+	if !ok {
+		d.sendCommand(user, "remove", Option{"subject", subject})
+		return
+	}
+	delete(d.releaseButtons, user + " " + subject)
+
+	d.click(user, customId)
+	assert.Contains(d.t, d.getLastBotResponse(), "removed")
+}
+
+func (d *DiscordDriver) UserRequestsReservationsList(tags ...string) {
+	var options []Option
+	if len(tags) > 0 {
+		options = append(options, Option{"tags", strings.Join(tags, " ")})
+	}
+	d.sendCommand("Alice", "reserved", options...)
+}
+
+func (d *DiscordDriver) UserSeesSubjects(subject ...string) {
+	msg := d.getLastBotResponse()
+
+	subjects := strings.Split(msg, "\n")
+	for _, s := range subject {
+		assert.SliceContains(d.t, subjects, s)
+	}
+}
+
+func (d *DiscordDriver) UserSeesSubjectTags(tags ...string) {
+	msg := d.getLastBotResponse()
+
+	recievedTags := strings.Split(msg, "\n")
+	for _, tag := range tags {
+		assert.SliceContains(d.t, recievedTags, tag)
+	}
+}
+
+func (d *DiscordDriver) UserSeesReservations(reservations ...string) {
+	msg := d.getLastBotResponse()
+
+	listReservations := d.reservationsFromList(msg)
+	assert.Equal(d.t, len(reservations), len(listReservations))
+	for _, r := range reservations {
+		assert.SliceContains(d.t, listReservations, d.reservationFromSpec(r))
+	}
+}
+
+func (d *DiscordDriver) UserDoesNotSeeReservations(subject string) {
+	msg := d.getLastBotResponse()
+
+	for _, r := range d.reservationsFromList(msg) {
+		assert.NotEqual(d.t, subject, r.Subject)
+	}
+}
+
+func (d *DiscordDriver) UserAcquiredReservationForSubject(user string, subject string, until string) {
+	msg := d.getLastBotResponse()
+
+	assert.Contains(d.t, msg, subject)
+	assert.Contains(d.t, msg, user)
+	assert.Contains(d.t, msg, until)
+}
+
+func (d *DiscordDriver) SubjectHasAlreadyBeenReservedBy(user string, until string) {
+	msg := d.getLastBotResponse()
+
+	assert.Contains(d.t, msg, "Already reserved by")
+	assert.Contains(d.t, msg, user)
+	assert.Contains(d.t, msg, until)
+}
+
+func (d *DiscordDriver) ClockSet(t string) {
+	now := time.Now()
+	parsed, err := time.Parse(time.TimeOnly, t + ":00")
+	if err != nil {
+		d.t.Fatal(err)
+	}
+	year, month, day := now.Date()
+	hour, minute, second := parsed.Clock()
+	result := time.Date(year, month, day, hour, minute, second, 0, time.Local)
+
+	d.clock.Set(result)
+	d.appContainer.CopyFileToContainer(d.t.Context(), d.clock.Path(), d.clock.Path(), 0o666)
+}
+
+func (d *DiscordDriver) CleanUp() {
+	d.responses = []Message{}
+	d.releaseButtons = make(map[string]string)
+}
+
+// sendCommand runs the slash command as the user, the reply of the app comes with the response
+func (d *DiscordDriver) sendCommand(user string, name string, options ...Option) Message {
+	var msg Message
+	d.post("/testing/sendCommand", Command{
+		Name: name,
+		Options: options,
+		UserId: d.getUserId(user),
+		UserName: user,
+		ChannelId: channelId,
+	}, &msg)
+	d.responses = append(d.responses, msg)
+
+	return msg
+}
+
+func (d *DiscordDriver) click(user string, customId string) {
+	var msg Message
+	d.post("/testing/clickButton", Click{
+		CustomId: customId,
+		UserId: d.getUserId(user),
+		UserName: user,
+		ChannelId: channelId,
+	}, &msg)
+	d.responses = append(d.responses, msg)
+}
+
+func (d *DiscordDriver) post(path string, request any, response any) {
+	encoded, err := json.Marshal(request)
+	assert.NoError(d.t, err)
+	r, err := d.client.Post(d.host + path, "application/json", bytes.NewBuffer(encoded))
+	assert.NoError(d.t, err)
+	defer r.Body.Close()
+	assert.Equal(d.t, http.StatusOK, r.StatusCode)
+	assert.NoError(d.t, json.NewDecoder(r.Body).Decode(response))
+}
+
+func (d *DiscordDriver) getLastBotResponse() string {
+	assert.NotEqual(d.t, 0, len(d.responses))
+	botMessage := d.responses[len(d.responses) - 1]
+	assert.Equal(d.t, channelId, botMessage.ChannelId)
+
+	return botMessage.Content
+}
+
+func (d *DiscordDriver) getUserId(name string) string {
+	id, ok := d.users[name]
+
+	if !ok {
+		id = fmt.Sprintf("%018d", 100000000000000000 + len(d.users) + 1)
+		d.users[name] = id
+	}
+
+	return id
+}
+
+func (d *DiscordDriver) reservationsFromList(list string) []Reservation {
+	var reservations []Reservation
+	lines := strings.Split(strings.Trim(list, "\n"), "\n")
+
+	for _, line := range lines[1:] {
+		reservations = append(reservations, d.reservationFromList(line))
+	}
+
+	return reservations
+}
+
+func (d *DiscordDriver) reservationFromList(r string) Reservation {
+	data := strings.Split(r, "\t")
+	assert.Equal(d.t, 4, len(data))
+	t, err := time.Parse(time.DateTime, data[1])
+	assert.NoError(d.t, err)
+
+	return Reservation{
+		Subject: data[0],
+		User: data[3],
+		Time: t,
+	}
+}
+
+func (d *DiscordDriver) reservationFromSpec(r string) Reservation {
+	data := strings.Split(r, " ")
+	assert.Equal(d.t, 3, len(data))
+	specTime := strings.Split(data[2], ":")
+	assert.Equal(d.t, 2, len(specTime))
+	hours, err := strconv.Atoi(specTime[0])
+	assert.NoError(d.t, err)
+	minutes, err := strconv.Atoi(specTime[1])
+	assert.NoError(d.t, err)
+	now := d.clock.Current()
+	t := time.Date(now.Year(), now.Month(), now.Day(), hours, minutes, now.Second(), now.Nanosecond(), now.Location())
+
+	return Reservation{
+		Subject: data[1],
+		User: data[0],
+		Time: t,
+	}
+}
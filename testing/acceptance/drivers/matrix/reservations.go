@@ -0,0 +1,253 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/clock/cache"
+	"github.com/alecthomas/assert/v2"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const roomId = "!lab:localhost"
+
+type UserMessage struct {
+	RoomId string `json:"room_id"`
+	Sender string `json:"sender"`
+	Body string `json:"body"`
+}
+
+type BotMessage struct {
+	RoomId string `json:"room_id"`
+	InReplyTo string `json:"in_reply_to"`
+	Body string `json:"body"`
+}
+
+type Reservation struct {
+	User string
+	Subject string
+	Time time.Time
+}
+
+type MatrixDriver struct {
+	client *http.Client
+	host string
+	clock cache.CacheClock
+	appContainer testcontainers.Container
+	responses []BotMessage
+	t *testing.T
+}
+
+func NewDriver(client *http.Client, host string, clock cache.CacheClock, app testcontainers.Container, t *testing.T) *MatrixDriver {
+	return &MatrixDriver{
+		client: client,
+		host: host,
+		clock: clock,
+		appContainer: app,
+		t: t,
+	}
+}
+
+func (d *MatrixDriver) AdminAddsSubject(subject string) {
+	d.sendMessage("Admin", "/add_subject " + subject)
+}
+
+func (d *MatrixDriver) AdminAddsTagsToSubject(subject string, tags ...string) {
+	d.sendMessage("Admin", fmt.Sprintf("/add_tags %s %s", subject, strings.Join(tags, " ")))
+}
+
+func (d *MatrixDriver) AdminAddsComponentToSubject(subject string, component string) {
+	d.sendMessage("Admin", fmt.Sprintf("/add_component %s %s", subject, component))
+}
+
+func (d *MatrixDriver) UserRequestsSubjectsList() {
+	d.sendMessage("Alice", "/list")
+}
+
+func (d *MatrixDriver) UserRequestsSubjectTags(subject string) {
+	d.sendMessage("Alice", "/tags " + subject)
+}
+
+func (d *MatrixDriver) UserRequestsReservationForSubject(user string, subject string, minutes int) {
+	d.sendMessage(user, fmt.Sprintf("/reserve %s %d", subject, minutes))
+}
+
+func (d *MatrixDriver) UserRequestsReservationForSubjects(user string, subjects []string, minutes int) {
+	d.UserRequestsReservationForSubject(user, strings.Join(subjects, ","), minutes)
+}
+
+// UserRequestsReservationRemoval uses the ! prefix Element users type instead of /
+func (d *MatrixDriver) UserRequestsReservationRemoval(user string, subject string) {
+	d.sendMessage(user, "!remove " + subject)
+}
+
+func (d *MatrixDriver) UserRequestsReservationsList(tags ...string) {
+	d.sendMessage("Alice", strings.TrimSpace("/reserved " + strings.Join(tags, " ")))
+}
+
+func (d *MatrixDriver) UserSeesSubjects(subject ...string) {
+	msg := d.getLastBotResponse()
+
+	subjects := strings.Split(msg, "\n")
+	for _, s := range subject {
+		assert.SliceContains(d.t, subjects, s)
+	}
+}
+
+func (d *MatrixDriver) UserSeesSubjectTags(tags ...string) {
+	msg := d.getLastBotResponse()
+
+	recievedTags := strings.Split(msg, "\n")
+	for _, tag := range tags {
+		assert.SliceContains(d.t, recievedTags, tag)
+	}
+}
+
+func (d *MatrixDriver) UserSeesReservations(reservations ...string) {
+	msg := d.getLastBotResponse()
+
+	listReservations := d.reservationsFromList(msg)
+	assert.Equal(d.t, len(reservations), len(listReservations))
+	for _, r := range reservations {
+		assert.SliceContains(d.t, listReservations, d.reservationFromSpec(r))
+	}
+}
+
+func (d *MatrixDriver) UserDoesNotSeeReservations(subject string) {
+	msg := d.getLastBotResponse()
+
+	for _, r := range d.reservationsFromList(msg) {
+		assert.NotEqual(d.t, subject, r.Subject)
+	}
+}
+
+func (d *MatrixDriver) UserAcquiredReservationForSubject(user string, subject string, until string) {
+	msg := d.getLastBotResponse()
+
+	assert.Contains(d.t, msg, subject)
+	assert.Contains(d.t, msg, user)
+	assert.Contains(d.t, msg, until)
+}
+
+func (d *MatrixDriver) SubjectHasAlreadyBeenReservedBy(user string, until string) {
+	msg := d.getLastBotResponse()
+
+	assert.Contains(d.t, msg, "Already reserved by")
+	assert.Contains(d.t, msg, user)
+	assert.Contains(d.t, msg, until)
+}
+
+func (d *MatrixDriver) ClockSet(t string) {
+	now := time.Now()
+	parsed, err := time.Parse(time.TimeOnly, t + ":00")
+	if err != nil {
+		d.t.Fatal(err)
+	}
+	year, month, day := now.Date()
+	hour, minute, second := parsed.Clock()
+	result := time.Date(year, month, day, hour, minute, second, 0, time.Local)
+
+	d.clock.Set(result)
+	d.appContainer.CopyFileToContainer(d.t.Context(), d.clock.Path(), d.clock.Path(), 0o666)
+}
+
+func (d *MatrixDriver) CleanUp() {
+	d.responses = []BotMessage{}
+}
+
+// sendMessage writes to the room as the user and waits for the bot to reply to the message
+func (d *MatrixDriver) sendMessage(user string, body string) {
+	encoded, err := json.Marshal(UserMessage{RoomId: roomId, Sender: fmt.Sprintf("@%s:localhost", user), Body: body})
+	assert.NoError(d.t, err)
+	r, err := d.client.Post(d.host + "/testing/sendMessage", "application/json", bytes.NewBuffer(encoded))
+	assert.NoError(d.t, err)
+	defer r.Body.Close()
+	assert.Equal(d.t, http.StatusOK, r.StatusCode)
+
+	var sent struct {
+		EventId string `json:"event_id"`
+	}
+	assert.NoError(d.t, json.NewDecoder(r.Body).Decode(&sent))
+	d.waitForBotResponse(sent.EventId)
+}
+
+func (d *MatrixDriver) waitForBotResponse(eventId string) {
+	deadline := time.Now().Add(20 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, message := range d.botMessages() {
+			if message.InReplyTo == eventId {
+				d.responses = append(d.responses, message)
+				return
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	d.t.Fatal("The bot did not respond")
+}
+
+func (d *MatrixDriver) botMessages() []BotMessage {
+	var messages []BotMessage
+	r, err := d.client.Get(d.host + "/testing/getBotMessages")
+	assert.NoError(d.t, err)
+	defer r.Body.Close()
+	assert.NoError(d.t, json.NewDecoder(r.Body).Decode(&messages))
+
+	return messages
+}
+
+func (d *MatrixDriver) getLastBotResponse() string {
+	assert.NotEqual(d.t, 0, len(d.responses))
+	botMessage := d.responses[len(d.responses) - 1]
+	assert.Equal(d.t, roomId, botMessage.RoomId)
+
+	return botMessage.Body
+}
+
+func (d *MatrixDriver) reservationsFromList(list string) []Reservation {
+	var reservations []Reservation
+	lines := strings.Split(strings.Trim(list, "\n"), "\n")
+
+	for _, line := range lines[1:] {
+		reservations = append(reservations, d.reservationFromList(line))
+	}
+
+	return reservations
+}
+
+func (d *MatrixDriver) reservationFromList(r string) Reservation {
+	data := strings.Split(r, "\t")
+	assert.Equal(d.t, 4, len(data))
+	t, err := time.Parse(time.DateTime, data[1])
+	assert.NoError(d.t, err)
+
+	return Reservation{
+		Subject: data[0],
+		User: data[3],
+		Time: t,
+	}
+}
+
+func (d *MatrixDriver) reservationFromSpec(r string) Reservation {
+	data := strings.Split(r, " ")
+	assert.Equal(d.t, 3, len(data))
+	specTime := strings.Split(data[2], ":")
+	assert.Equal(d.t, 2, len(specTime))
+	hours, err := strconv.Atoi(specTime[0])
+	assert.NoError(d.t, err)
+	minutes, err := strconv.Atoi(specTime[1])
+	assert.NoError(d.t, err)
+	now := d.clock.Current()
+	t := time.Date(now.Year(), now.Month(), now.Day(), hours, minutes, now.Second(), now.Nanosecond(), now.Location())
+
+	return Reservation{
+		Subject: data[1],
+		User: data[0],
+		Time: t,
+	}
+}
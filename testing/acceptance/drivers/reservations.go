@@ -3,11 +3,13 @@ package drivers
 type Reservations interface{
 	AdminAddsSubject(subject string)
 	AdminAddsTagsToSubject(subject string, tags ...string)
+	AdminAddsComponentToSubject(subject string, component string)
 	
 	UserRequestsSubjectsList()
 	UserRequestsSubjectTags(subject string)
 	UserRequestsReservationsList(tags ...string)
 	UserRequestsReservationForSubject(user string, subject string, minutes int)
+	UserRequestsReservationForSubjects(user string, subjects []string, minutes int)
 	UserRequestsReservationRemoval(user string, subject string)
 
 	UserSeesSubjects(subject ...string)
@@ -0,0 +1,328 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/clock/cache"
+	"github.com/alecthomas/assert/v2"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const channelId = "C0000001"
+
+type SlashCommand struct {
+	Command string `json:"command"`
+	Text string `json:"text"`
+	UserId string `json:"user_id"`
+	UserName string `json:"user_name"`
+	ChannelId string `json:"channel_id"`
+}
+
+type Click struct {
+	UserId string `json:"user_id"`
+	UserName string `json:"user_name"`
+	ChannelId string `json:"channel_id"`
+	ActionId string `json:"action_id"`
+	Value string `json:"value"`
+}
+
+type Message struct {
+	ChannelId string `json:"channel_id"`
+	Text string `json:"text"`
+	Blocks []Block `json:"blocks"`
+}
+
+type Block struct {
+	Elements []Element `json:"elements"`
+}
+
+type Element struct {
+	ActionId string `json:"action_id"`
+	Value string `json:"value"`
+}
+
+type Reservation struct {
+	User string
+	Subject string
+	Time time.Time
+}
+
+type SlackDriver struct {
+	client *http.Client
+	host string
+	clock cache.CacheClock
+	users map[string]string
+	// releaseButtons keeps the Release button of the last reservation of a user for a subject
+	releaseButtons map[string]Element
+	appContainer testcontainers.Container
+	responses []Message
+	t *testing.T
+}
+
+func NewDriver(client *http.Client, host string, clock cache.CacheClock, app testcontainers.Container, t *testing.T) *SlackDriver {
+	return &SlackDriver{
+		client: client,
+		host: host,
+		clock: clock,
+		users: make(map[string]string),
+		releaseButtons: make(map[string]Element),
+		appContainer: app,
+		t: t,
+	}
+}
+
+func (d *SlackDriver) AdminAddsSubject(subject string) {
+	d.sendCommand("Admin", "/add_subject", subject)
+}
+
+func (d *SlackDriver) AdminAddsTagsToSubject(subject string, tags ...string) {
+	d.sendCommand("Admin", "/add_tags", fmt.Sprintf("%s %s", subject, strings.Join(tags, " ")))
+}
+
+func (d *SlackDriver) AdminAddsComponentToSubject(subject string, component string) {
+	d.sendCommand("Admin", "/add_component", fmt.Sprintf("%s %s", subject, component))
+}
+
+func (d *SlackDriver) UserRequestsSubjectsList() {
+	d.sendCommand("Alice", "/list", "")
+}
+
+func (d *SlackDriver) UserRequestsSubjectTags(subject string) {
+	d.sendCommand("Alice", "/tags", subject)
+}
+
+func (d *SlackDriver) UserRequestsReservationForSubject(user string, subject string, minutes int) {
+	msg := d.sendCommand(user, "/reserve", subject + " " + strconv.Itoa(minutes))
+
+	for _, block := range msg.Blocks {
+		for _, element := range block.Elements {
+			if element.ActionId == "release" {
+				d.releaseButtons[user + " " + subject] = element
+			}
+		}
+	}
+}
+
+func (d *SlackDriver) UserRequestsReservationForSubjects(user string, subjects []string, minutes int) {
+	d.UserRequestsReservationForSubject(user, strings.Join(subjects, ","), minutes)
+}
+
+// UserRequestsReservationRemoval clicks the Release button of the reservation when there is one
+func (d *SlackDriver) UserRequestsReservationRemoval(user string, subject string) {
+	button, ok := d.releaseButtons[user + " " + subject]
+	if !ok {
+		d.sendCommand(user, "/remove", subject)
+		return
+	}
+	delete(d.releaseButtons, user + " " + subject)
+
+	seen := len(d.botMessages())
+	d.click(user, button)
+	d.waitForBotResponse(seen)
+	assert.Contains(d.t, d.getLastBotResponse(), "removed")
+}
+
+func (d *SlackDriver) UserRequestsReservationsList(tags ...string) {
+	d.sendCommand("Alice", "/reserved", strings.Join(tags, " "))
+}
+
+func (d *SlackDriver) UserSeesSubjects(subject ...string) {
+	msg := d.getLastBotResponse()
+
+	subjects := strings.Split(msg, "\n")
+	for _, s := range subject {
+		assert.SliceContains(d.t, subjects, s)
+	}
+}
+
+func (d *SlackDriver) UserSeesSubjectTags(tags ...string) {
+	msg := d.getLastBotResponse()
+
+	recievedTags := strings.Split(msg, "\n")
+	for _, tag := range tags {
+		assert.SliceContains(d.t, recievedTags, tag)
+	}
+}
+
+func (d *SlackDriver) UserSeesReservations(reservations ...string) {
+	msg := d.getLastBotResponse()
+
+	listReservations := d.reservationsFromList(msg)
+	assert.Equal(d.t, len(reservations), len(listReservations))
+	for _, r := range reservations {
+		assert.SliceContains(d.t, listReservations, d.reservationFromSpec(r))
+	}
+}
+
+func (d *SlackDriver) UserDoesNotSeeReservations(subject string) {
+	msg := d.getLastBotResponse()
+
+	for _, r := range d.reservationsFromList(msg) {
+		assert.NotEqual(d.t, subject, r.Subject)
+	}
+}
+
+func (d *SlackDriver) UserAcquiredReservationForSubject(user string, subject string, until string) {
+	msg := d.getLastBotResponse()
+
+	assert.Contains(d.t, msg, subject)
+	assert.Contains(d.t, msg, user)
+	assert.Contains(d.t, msg, until)
+}
+
+func (d *SlackDriver) SubjectHasAlreadyBeenReservedBy(user string, until string) {
+	msg := d.getLastBotResponse()
+
+	assert.Contains(d.t, msg, "Already reserved by")
+	assert.Contains(d.t, msg, user)
+	assert.Contains(d.t, msg, until)
+}
+
+func (d *SlackDriver) ClockSet(t string) {
+	now := time.Now()
+	parsed, err := time.Parse(time.TimeOnly, t + ":00")
+	if err != nil {
+		d.t.Fatal(err)
+	}
+	year, month, day := now.Date()
+	hour, minute, second := parsed.Clock()
+	result := time.Date(year, month, day, hour, minute, second, 0, time.Local)
+
+	d.clock.Set(result)
+	d.appContainer.CopyFileToContainer(d.t.Context(), d.clock.Path(), d.clock.Path(), 0o666)
+}
+
+func (d *SlackDriver) CleanUp() {
+	d.responses = []Message{}
+	d.releaseButtons = make(map[string]Element)
+}
+
+// sendCommand runs the slash command as the user, the reply of the app comes with the response
+func (d *SlackDriver) sendCommand(user string, command string, text string) Message {
+	var msg Message
+	d.post("/testing/sendSlashCommand", SlashCommand{
+		Command: command,
+		Text: text,
+		UserId: d.getUserId(user),
+		UserName: user,
+		ChannelId: channelId,
+	}, &msg)
+	d.responses = append(d.responses, msg)
+
+	return msg
+}
+
+func (d *SlackDriver) click(user string, button Element) {
+	d.post("/testing/clickButton", Click{
+		UserId: d.getUserId(user),
+		UserName: user,
+		ChannelId: channelId,
+		ActionId: button.ActionId,
+		Value: button.Value,
+	}, nil)
+}
+
+func (d *SlackDriver) post(path string, request any, response any) {
+	encoded, err := json.Marshal(request)
+	assert.NoError(d.t, err)
+	r, err := d.client.Post(d.host + path, "application/json", bytes.NewBuffer(encoded))
+	assert.NoError(d.t, err)
+	defer r.Body.Close()
+	assert.Equal(d.t, http.StatusOK, r.StatusCode)
+
+	if response != nil {
+		assert.NoError(d.t, json.NewDecoder(r.Body).Decode(response))
+	}
+}
+
+// waitForBotResponse waits for the app to post a reply to the response_url of an interaction
+func (d *SlackDriver) waitForBotResponse(seen int) {
+	deadline := time.Now().Add(20 * time.Second)
+	for time.Now().Before(deadline) {
+		if messages := d.botMessages(); len(messages) > seen {
+			d.responses = append(d.responses, messages[seen:]...)
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	d.t.Fatal("The app did not respond")
+}
+
+func (d *SlackDriver) botMessages() []Message {
+	var messages []Message
+	r, err := d.client.Get(d.host + "/testing/getBotMessages")
+	assert.NoError(d.t, err)
+	defer r.Body.Close()
+	assert.NoError(d.t, json.NewDecoder(r.Body).Decode(&messages))
+
+	return messages
+}
+
+func (d *SlackDriver) getLastBotResponse() string {
+	assert.NotEqual(d.t, 0, len(d.responses))
+	botMessage := d.responses[len(d.responses) - 1]
+	assert.Equal(d.t, channelId, botMessage.ChannelId)
+
+	return botMessage.Text
+}
+
+func (d *SlackDriver) getUserId(name string) string {
+	id, ok := d.users[name]
+
+	if !ok {
+		id = fmt.Sprintf("U%07d", len(d.users) + 1)
+		d.users[name] = id
+	}
+
+	return id
+}
+
+func (d *SlackDriver) reservationsFromList(list string) []Reservation {
+	var reservations []Reservation
+	lines := strings.Split(strings.Trim(list, "\n"), "\n")
+
+	for _, line := range lines[1:] {
+		reservations = append(reservations, d.reservationFromList(line))
+	}
+
+	return reservations
+}
+
+func (d *SlackDriver) reservationFromList(r string) Reservation {
+	data := strings.Split(r, "\t")
+	assert.Equal(d.t, 4, len(data))
+	t, err := time.Parse(time.DateTime, data[1])
+	assert.NoError(d.t, err)
+
+	return Reservation{
+		Subject: data[0],
+		User: data[3],
+		Time: t,
+	}
+}
+
+func (d *SlackDriver) reservationFromSpec(r string) Reservation {
+	data := strings.Split(r, " ")
+	assert.Equal(d.t, 3, len(data))
+	specTime := strings.Split(data[2], ":")
+	assert.Equal(d.t, 2, len(specTime))
+	hours, err := strconv.Atoi(specTime[0])
+	assert.NoError(d.t, err)
+	minutes, err := strconv.Atoi(specTime[1])
+	assert.NoError(d.t, err)
+	now := d.clock.Current()
+	t := time.Date(now.Year(), now.Month(), now.Day(), hours, minutes, now.Second(), now.Nanosecond(), now.Location())
+
+	return Reservation{
+		Subject: data[1],
+		User: data[0],
+		Time: t,
+	}
+}
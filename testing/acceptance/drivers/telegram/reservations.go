@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -37,6 +38,16 @@ type Reservation struct {
 	Time time.Time
 }
 
+type CommandsMenu struct {
+	Scope struct {
+		Type string `json:"type"`
+	} `json:"scope"`
+	LanguageCode string `json:"language_code"`
+	Commands []struct {
+		Command string `json:"command"`
+	} `json:"commands"`
+}
+
 type Response struct {
 	ChatId int `json:"chat_id"`
 	Text string `json:"text"`
@@ -86,6 +97,14 @@ func (d *TelegramDriver) AdminAddsTagsToSubject(subject string, tags ...string)
 	d.sendClientMessage(msg)
 }
 
+func (d *TelegramDriver) AdminAddsComponentToSubject(subject string, component string) {
+	msg := Message{
+		Text: fmt.Sprintf("/add_component %s %s", subject, component),
+	}
+
+	d.sendClientMessage(msg)
+}
+
 func (d *TelegramDriver) UserRequestsSubjectsList() {
 	msg := Message{
 		Id: d.messageId,
@@ -117,6 +136,10 @@ func (d *TelegramDriver) UserRequestsReservationForSubject(user string, subject
 	d.waitForBotResponse()
 }
 
+func (d *TelegramDriver) UserRequestsReservationForSubjects(user string, subjects []string, minutes int) {
+	d.UserRequestsReservationForSubject(user, strings.Join(subjects, ","), minutes)
+}
+
 func (d *TelegramDriver) UserRequestsReservationRemoval(user string, subject string) {
 	msg := Message{
 		Id: d.messageId,
@@ -142,6 +165,71 @@ func (d *TelegramDriver) UserRequestsReservationsList(tags ...string) {
 	d.waitForBotResponse()
 }
 
+func (d *TelegramDriver) UserRequestsHelp() {
+	msg := Message{
+		Id: d.messageId,
+		Text: "/help",
+	}
+
+	d.sendClientMessage(msg)
+	d.waitForBotResponse()
+}
+
+func (d *TelegramDriver) UserSeesCommands(commands ...string) {
+	msg := d.getLastBotResponse()
+
+	for _, command := range commands {
+		assert.Contains(d.t, msg, "\n/" + command)
+	}
+}
+
+func (d *TelegramDriver) UserDoesNotSeeCommands(commands ...string) {
+	msg := d.getLastBotResponse()
+
+	for _, command := range commands {
+		assert.NotContains(d.t, msg, "\n/" + command + " ")
+	}
+}
+
+// CommandMenuHas checks the commands registered for the default language of the scope, such as default or all_chat_administrators
+func (d *TelegramDriver) CommandMenuHas(scope string, commands ...string) {
+	registered := d.menuCommands(scope)
+
+	for _, command := range commands {
+		assert.SliceContains(d.t, registered, command)
+	}
+}
+
+func (d *TelegramDriver) CommandMenuDoesNotHave(scope string, commands ...string) {
+	registered := d.menuCommands(scope)
+
+	for _, command := range commands {
+		assert.False(d.t, slices.Contains(registered, command), command)
+	}
+}
+
+func (d *TelegramDriver) menuCommands(scope string) []string {
+	var menus []CommandsMenu
+	r, err := d.client.Get(fmt.Sprintf("%s/testing/getBotCommands", d.host))
+	assert.NoError(d.t, err)
+	body, err := io.ReadAll(r.Body)
+	assert.NoError(d.t, err)
+	assert.NoError(d.t, json.Unmarshal(body, &menus))
+
+	var commands []string
+	for _, menu := range menus {
+		if menu.Scope.Type != scope || menu.LanguageCode != "" {
+			continue
+		}
+		for _, command := range menu.Commands {
+			commands = append(commands, command.Command)
+		}
+	}
+	assert.NotEqual(d.t, 0, len(commands), scope)
+
+	return commands
+}
+
 func (d *TelegramDriver) UserSeesSubjects(subject ...string) {
 	msg := d.getLastBotResponse()
 
@@ -0,0 +1,50 @@
+package acceptance
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/clock/cache"
+	"github.com/SneedusSnake/Reservations/testing/acceptance/drivers/slack"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	"github.com/SneedusSnake/Reservations/testing/containers/app"
+	"github.com/SneedusSnake/Reservations/testing/containers/mysql"
+	"github.com/SneedusSnake/Reservations/testing/containers/slack_api"
+	"github.com/alecthomas/assert/v2"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+)
+
+func TestSlackSuite(t *testing.T) {
+	ctx := t.Context()
+	net, err := network.New(ctx)
+	assert.NoError(t, err)
+	mysqlConnection := ""
+	if os.Getenv("PERSISTENCE") == "mysql" {
+		sqlContainer, err := mysql.Start(ctx, net.Name, containers.Stdout("mysql"))
+		assert.NoError(t, err)
+		mysqlConnection, err = sqlContainer.ExternalConnectionString(ctx)
+		assert.NoError(t, err)
+	}
+
+	appContainer, err := app.StartSlack(ctx, net.Name, mysqlConnection, slack_api.SIGNING_SECRET, containers.Stdout("Application"))
+	testcontainers.CleanupContainer(t, appContainer)
+	assert.NoError(t, err)
+	apiContainer, err := slack_api.Start(ctx, net.Name, app.HTTP_URL, containers.Stdout("Slack test server"))
+	testcontainers.CleanupContainer(t, apiContainer)
+	assert.NoError(t, err)
+	host, err := apiContainer.Endpoint(ctx, "")
+	assert.NoError(t, err)
+
+	driver := slack.NewDriver(
+		http.DefaultClient,
+		"http://" + host,
+		cache.NewClock(app.CLOCK_CACHE_PATH),
+		appContainer,
+		t,
+	)
+
+	prepareTestFixtures(driver)
+	runSpecifications(t, driver, driver.CleanUp)
+}
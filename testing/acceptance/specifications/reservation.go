@@ -15,6 +15,35 @@ func ReserveSubjectSpecification(t testing.TB, driver drivers.Reservations) {
 	driver.SubjectHasAlreadyBeenReservedBy("Alice", "12:30")
 }
 
+func ReserveSubjectWithComponentsSpecification(t testing.TB, driver drivers.Reservations) {
+	driver.ClockSet("16:00")
+	driver.UserRequestsReservationForSubject("Alice", "Bench#1", 30)
+	driver.UserAcquiredReservationForSubject("Alice", "Bench#1", "16:30")
+
+	driver.UserRequestsReservationForSubject("Bob", "Phone#1", 10)
+	driver.SubjectHasAlreadyBeenReservedBy("Alice", "16:30")
+
+	driver.ClockSet("17:00")
+	driver.UserRequestsReservationForSubject("Bob", "Phone#1", 10)
+	driver.UserAcquiredReservationForSubject("Bob", "Phone#1", "17:10")
+
+	driver.UserRequestsReservationForSubject("Alice", "Bench#1", 30)
+	driver.SubjectHasAlreadyBeenReservedBy("Bob", "17:10")
+}
+
+func ReserveSeveralSubjectsSpecification(t testing.TB, driver drivers.Reservations) {
+	driver.ClockSet("18:00")
+	driver.UserRequestsReservationForSubjects("Alice", []string{"Subject#1", "Subject#2"}, 30)
+	driver.UserAcquiredReservationForSubject("Alice", "Subject#1", "18:30")
+	driver.UserAcquiredReservationForSubject("Alice", "Subject#2", "18:30")
+
+	driver.UserRequestsReservationForSubjects("Bob", []string{"Subject#2", "Subject#3"}, 10)
+	driver.SubjectHasAlreadyBeenReservedBy("Alice", "18:30")
+
+	driver.UserRequestsReservationForSubject("Bob", "Subject#3", 10)
+	driver.UserAcquiredReservationForSubject("Bob", "Subject#3", "18:10")
+}
+
 func RemoveReservationSpecification(t testing.TB, driver drivers.Reservations) {
 	driver.ClockSet("13:00")
 	driver.UserRequestsReservationForSubject("Alice", "Subject#2", 5)
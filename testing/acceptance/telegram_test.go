@@ -53,10 +53,21 @@ func TestSuite(t *testing.T) {
 	)
 
 	prepareTestFixtures(driver)
-	cleanUp := func () {
-		driver.CleanUp()
-	}
+	runSpecifications(t, driver, driver.CleanUp)
+
+	t.Run("User can see the commands of the bot", func(t *testing.T) {
+		driver.UserRequestsHelp()
+		driver.UserSeesCommands("help", "reserve", "reserved", "remove")
+		driver.UserDoesNotSeeCommands("tokens", "topic_tag")
+		driver.CommandMenuHas("default", "help", "reserve", "list")
+		driver.CommandMenuDoesNotHave("default", "tokens", "topic_tag")
+		driver.CommandMenuHas("all_chat_administrators", "topic_tag", "chat_language")
+		t.Cleanup(driver.CleanUp)
+	})
+}
 
+// runSpecifications runs every specification against the driver, cleaning it up after each one
+func runSpecifications(t *testing.T, driver drivers.Reservations, cleanUp func()) {
 	t.Run("User can see list of all existing subjects", func(t *testing.T) {
 		specifications.ListSpecification(t, driver)
 		t.Cleanup(cleanUp)
@@ -72,6 +83,16 @@ func TestSuite(t *testing.T) {
 		t.Cleanup(cleanUp)
 	})
 
+	t.Run("User can make a reservation for a subject with components", func(t *testing.T) {
+		specifications.ReserveSubjectWithComponentsSpecification(t, driver)
+		t.Cleanup(cleanUp)
+	})
+
+	t.Run("User can reserve several subjects at once", func(t *testing.T) {
+		specifications.ReserveSeveralSubjectsSpecification(t, driver)
+		t.Cleanup(cleanUp)
+	})
+
 	t.Run("User can remove reservations for a subject", func(t *testing.T) {
 		specifications.RemoveReservationSpecification(t, driver)
 		t.Cleanup(cleanUp)
@@ -113,7 +134,10 @@ func prepareTestFixtures(driver drivers.Reservations) {
 	driver.AdminAddsSubject("Subject#1")
 	driver.AdminAddsSubject("Subject#2")
 	driver.AdminAddsSubject("Subject#3")
+	driver.AdminAddsSubject("Bench#1")
+	driver.AdminAddsSubject("Phone#1")
 	time.Sleep(time.Millisecond*100)
+	driver.AdminAddsComponentToSubject("Bench#1", "Phone#1")
 	driver.AdminAddsTagsToSubject("Subject#1", "Subject#1", "This_is_a_first_subject", "test")
 	driver.AdminAddsTagsToSubject("Subject#2", "Subject#2", "This_is_a_second_subject", "test")
 	time.Sleep(time.Millisecond*500)
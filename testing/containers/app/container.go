@@ -2,15 +2,61 @@ package app
 
 import (
 	"context"
+	"maps"
 	"time"
 
 	"github.com/SneedusSnake/Reservations/testing/utils"
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
 )
 
 const CLOCK_CACHE_PATH = "/tmp/clock_go"
 
+// HTTP_URL is where the app serves HTTP requests inside the test network
+const HTTP_URL = "http://app:8090"
+
 func Start(ctx context.Context, network string, mysqlConnection string, logs ...testcontainers.LogConsumer) (testcontainers.Container, error) {
+	return start(ctx, network, mysqlConnection, nil, nil, logs...)
+}
+
+// StartSlack runs the app serving Slack only, verifying requests with the signing secret
+func StartSlack(ctx context.Context, network string, mysqlConnection string, signingSecret string, logs ...testcontainers.LogConsumer) (testcontainers.Container, error) {
+	env := map[string]string{
+		"TELEGRAM_API_TOKEN": "",
+		"HTTP_ADDR": ":8090",
+		"SLACK_SIGNING_SECRET": signingSecret,
+	}
+
+	return start(ctx, network, mysqlConnection, env, wait.ForListeningPort("8090/tcp"), logs...)
+}
+
+// StartDiscord runs the app serving Discord only, registering its commands with the Discord API at apiUrl
+func StartDiscord(ctx context.Context, network string, mysqlConnection string, publicKey string, apiUrl string, logs ...testcontainers.LogConsumer) (testcontainers.Container, error) {
+	env := map[string]string{
+		"TELEGRAM_API_TOKEN": "",
+		"HTTP_ADDR": ":8090",
+		"DISCORD_PUBLIC_KEY": publicKey,
+		"DISCORD_APPLICATION_ID": "1234567890",
+		"DISCORD_BOT_TOKEN": "1234567",
+		"DISCORD_API_URL": apiUrl,
+	}
+
+	return start(ctx, network, mysqlConnection, env, wait.ForListeningPort("8090/tcp"), logs...)
+}
+
+// StartMatrix runs the app serving Matrix only, once its bot listens to the rooms of the homeserver
+func StartMatrix(ctx context.Context, network string, mysqlConnection string, homeserverUrl string, accessToken string, userId string, logs ...testcontainers.LogConsumer) (testcontainers.Container, error) {
+	env := map[string]string{
+		"TELEGRAM_API_TOKEN": "",
+		"MATRIX_HOMESERVER_URL": homeserverUrl,
+		"MATRIX_ACCESS_TOKEN": accessToken,
+		"MATRIX_USER_ID": userId,
+	}
+
+	return start(ctx, network, mysqlConnection, env, wait.ForLog("Listening to matrix rooms"), logs...)
+}
+
+func start(ctx context.Context, network string, mysqlConnection string, env map[string]string, waitingFor wait.Strategy, logs ...testcontainers.LogConsumer) (testcontainers.Container, error) {
 	persistenceDriver := "memory"
 	if mysqlConnection != "" {
 		persistenceDriver = "mysql"
@@ -30,11 +76,18 @@ func Start(ctx context.Context, network string, mysqlConnection string, logs ...
 			"PERSISTENCE_DRIVER": persistenceDriver,
 			},
 		Networks: []string{network},
+		NetworkAliases: map[string][]string{network: {"app"}},
+		WaitingFor: waitingFor,
 		LogConsumerCfg: &testcontainers.LogConsumerConfig{
 			Opts: []testcontainers.LogProductionOption{testcontainers.WithLogProductionTimeout(10*time.Second)},
 			Consumers: logs,
 		},
 	}
+	maps.Copy(req.Env, env)
+	if env["HTTP_ADDR"] != "" {
+		req.ExposedPorts = []string{"8090"}
+	}
+
 	 return testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: req,
 		Started: true,
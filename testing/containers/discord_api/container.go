@@ -0,0 +1,51 @@
+package discord_api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/testing/utils"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// API_URL is the Discord REST API of the stub inside the test network
+const API_URL = "http://discord-api:8080/api/v10"
+
+const keySeed = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+
+// PublicKey is the application public key matching the key the stub signs interactions with
+func PublicKey() string {
+	seed, _ := hex.DecodeString(keySeed)
+
+	return hex.EncodeToString(ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey))
+}
+
+// Start runs a stub of Discord posting the interactions of the tests to the app reachable at appUrl
+func Start(ctx context.Context, network string, appUrl string, logs ...testcontainers.LogConsumer) (testcontainers.Container, error) {
+	req := testcontainers.ContainerRequest{
+		FromDockerfile: testcontainers.FromDockerfile{
+			Context: utils.TestsRootDir() + "/containers/discord_api/server",
+			Dockerfile: "Dockerfile",
+			PrintBuildLog: true,
+		},
+		Env: map[string]string{
+			"APP_URL": appUrl,
+			"KEY_SEED": keySeed,
+		},
+		Networks: []string{network},
+		NetworkAliases: map[string][]string{network: {"discord-api"}},
+		ExposedPorts: []string{"8080"},
+		WaitingFor: wait.ForHTTP("/").WithPort("8080"),
+		LogConsumerCfg: &testcontainers.LogConsumerConfig{
+			Opts: []testcontainers.LogProductionOption{testcontainers.WithLogProductionTimeout(10*time.Second)},
+			Consumers: logs,
+		},
+	}
+	 return testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started: true,
+	})
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type User struct {
+	Id string `json:"id"`
+	Username string `json:"username"`
+}
+
+type Option struct {
+	Name string `json:"name"`
+	Value any `json:"value"`
+}
+
+// Command is what the tests send on behalf of a user running a slash command
+type Command struct {
+	Name string `json:"name"`
+	Options []Option `json:"options"`
+	UserId string `json:"user_id"`
+	UserName string `json:"user_name"`
+	ChannelId string `json:"channel_id"`
+}
+
+// Click is what the tests send on behalf of a user clicking a button
+type Click struct {
+	CustomId string `json:"custom_id"`
+	UserId string `json:"user_id"`
+	UserName string `json:"user_name"`
+	ChannelId string `json:"channel_id"`
+}
+
+type Message struct {
+	ChannelId string `json:"channel_id"`
+	Content string `json:"content"`
+	Flags int `json:"flags,omitempty"`
+	Components json.RawMessage `json:"components,omitempty"`
+}
+
+type interactionResponse struct {
+	Type int `json:"type"`
+	Data *Message `json:"data"`
+}
+
+var appUrl string
+var privateKey ed25519.PrivateKey
+var botMessages []Message
+var commands = make(map[string]json.RawMessage)
+var interactions int
+var mu sync.Mutex
+
+func ping(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{}`)
+}
+
+func registerCommands(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil || !json.Valid(body) {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	log.Print("registered commands of application ", r.PathValue("application"))
+
+	mu.Lock()
+	commands[r.PathValue("application")] = body
+	mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func getCommands(w http.ResponseWriter, r *http.Request) {
+	mu.Lock()
+	defer mu.Unlock()
+	data, _ := json.Marshal(commands)
+
+	fmt.Fprint(w, string(data))
+}
+
+// sendCommand posts an application command interaction to the app and returns the app reply
+func sendCommand(w http.ResponseWriter, r *http.Request) {
+	var cmd Command
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	log.Print("recieved command: ", cmd)
+
+	options := make([]map[string]any, 0, len(cmd.Options))
+	for _, option := range cmd.Options {
+		optionType := 3
+		if _, ok := option.Value.(float64); ok {
+			optionType = 4
+		}
+		options = append(options, map[string]any{"name": option.Name, "type": optionType, "value": option.Value})
+	}
+	interact(w, 2, map[string]any{"name": cmd.Name, "type": 1, "options": options}, User{cmd.UserId, cmd.UserName}, cmd.ChannelId)
+}
+
+// clickButton posts a message component interaction to the app and returns the app reply
+func clickButton(w http.ResponseWriter, r *http.Request) {
+	var click Click
+	if err := json.NewDecoder(r.Body).Decode(&click); err != nil {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	log.Print("recieved click: ", click)
+
+	interact(w, 3, map[string]any{"custom_id": click.CustomId, "component_type": 2}, User{click.UserId, click.UserName}, click.ChannelId)
+}
+
+func interact(w http.ResponseWriter, interactionType int, data map[string]any, user User, channelId string) {
+	mu.Lock()
+	interactions++
+	id := strconv.Itoa(interactions)
+	mu.Unlock()
+
+	body, _ := json.Marshal(map[string]any{
+		"id": id,
+		"application_id": "1234567890",
+		"type": interactionType,
+		"token": "interaction-token-" + id,
+		"channel_id": channelId,
+		"guild_id": "1",
+		"data": data,
+		"member": map[string]any{"user": user},
+		"version": 1,
+	})
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := ed25519.Sign(privateKey, append([]byte(timestamp), body...))
+
+	request, _ := http.NewRequest(http.MethodPost, appUrl + "/discord/interactions", bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	request.Header.Set("X-Signature-Timestamp", timestamp)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil || response.StatusCode != http.StatusOK {
+		log.Print("app did not accept the interaction: ", err)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	defer response.Body.Close()
+
+	var reply interactionResponse
+	if err = json.NewDecoder(response.Body).Decode(&reply); err != nil || reply.Data == nil {
+		log.Print("app replied with no message: ", err)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	reply.Data.ChannelId = channelId
+	log.Print("recieved app message: ", reply.Data.Content)
+	mu.Lock()
+	botMessages = append(botMessages, *reply.Data)
+	mu.Unlock()
+
+	json.NewEncoder(w).Encode(reply.Data)
+}
+
+func getMessages(w http.ResponseWriter, r *http.Request) {
+	mu.Lock()
+	defer mu.Unlock()
+	data, err := json.Marshal(botMessages)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	fmt.Fprint(w, string(data))
+}
+
+func main() {
+	appUrl = os.Getenv("APP_URL")
+	seed, err := hex.DecodeString(os.Getenv("KEY_SEED"))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		log.Fatal("KEY_SEED must be 32 hex encoded bytes")
+	}
+	privateKey = ed25519.NewKeyFromSeed(seed)
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", ping)
+	handler.HandleFunc("PUT /api/v10/applications/{application}/commands", registerCommands)
+	handler.HandleFunc("GET /testing/getCommands", getCommands)
+	handler.HandleFunc("POST /testing/sendCommand", sendCommand)
+	handler.HandleFunc("POST /testing/clickButton", clickButton)
+	handler.HandleFunc("GET /testing/getBotMessages", getMessages)
+
+	s := &http.Server{
+		Addr:           ":8080",
+		Handler:        handler,
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+	}
+	log.Fatal(s.ListenAndServe())
+}
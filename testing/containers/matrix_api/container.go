@@ -0,0 +1,44 @@
+package matrix_api
+
+import (
+	"context"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/testing/utils"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// HOMESERVER_URL is where the fake homeserver is reachable inside the test network
+const HOMESERVER_URL = "http://matrix-api:8080"
+
+const ACCESS_TOKEN = "syt_cmVzZXJ2YXRpb25z_fakeToken"
+
+const BOT_USER_ID = "@reservations:localhost"
+
+// Start runs a fake homeserver holding the rooms the tests and the bot write in
+func Start(ctx context.Context, network string, logs ...testcontainers.LogConsumer) (testcontainers.Container, error) {
+	req := testcontainers.ContainerRequest{
+		FromDockerfile: testcontainers.FromDockerfile{
+			Context: utils.TestsRootDir() + "/containers/matrix_api/server",
+			Dockerfile: "Dockerfile",
+			PrintBuildLog: true,
+		},
+		Env: map[string]string{
+			"ACCESS_TOKEN": ACCESS_TOKEN,
+			"BOT_USER_ID": BOT_USER_ID,
+		},
+		Networks: []string{network},
+		NetworkAliases: map[string][]string{network: {"matrix-api"}},
+		ExposedPorts: []string{"8080"},
+		WaitingFor: wait.ForHTTP("/").WithPort("8080"),
+		LogConsumerCfg: &testcontainers.LogConsumerConfig{
+			Opts: []testcontainers.LogProductionOption{testcontainers.WithLogProductionTimeout(10*time.Second)},
+			Consumers: logs,
+		},
+	}
+	 return testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started: true,
+	})
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// UserMessage is what the tests send on behalf of a user writing in a room
+type UserMessage struct {
+	RoomId string `json:"room_id"`
+	Sender string `json:"sender"`
+	Body string `json:"body"`
+}
+
+type Event struct {
+	Type string `json:"type"`
+	EventId string `json:"event_id"`
+	Sender string `json:"sender"`
+	OriginServerTs int64 `json:"origin_server_ts"`
+	Content json.RawMessage `json:"content"`
+	roomId string
+}
+
+type BotMessage struct {
+	RoomId string `json:"room_id"`
+	EventId string `json:"event_id"`
+	InReplyTo string `json:"in_reply_to"`
+	MsgType string `json:"msgtype"`
+	Body string `json:"body"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+type messageContent struct {
+	MsgType string `json:"msgtype"`
+	Body string `json:"body"`
+	FormattedBody string `json:"formatted_body"`
+	RelatesTo struct {
+		InReplyTo struct {
+			EventId string `json:"event_id"`
+		} `json:"m.in_reply_to"`
+	} `json:"m.relates_to"`
+}
+
+var accessToken string
+var botUserId string
+var events []Event
+var botMessages []BotMessage
+// transactions maps the txn ids of the bot to the events they created, retries get the same event back
+var transactions = make(map[string]string)
+var mu sync.Mutex
+
+func ping(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{}`)
+}
+
+func authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer " + accessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"errcode":"M_UNKNOWN_TOKEN","error":"Unrecognised access token"}`)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// syncEvents long polls for the events after since, the batch tokens are positions in the event log
+func syncEvents(w http.ResponseWriter, r *http.Request) {
+	since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+	timeout, _ := strconv.Atoi(r.URL.Query().Get("timeout"))
+	deadline := time.Now().Add(time.Duration(timeout) * time.Millisecond)
+
+	for {
+		mu.Lock()
+		if len(events) > since || time.Now().After(deadline) || r.Context().Err() != nil {
+			break
+		}
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+	}
+	defer mu.Unlock()
+
+	join := make(map[string]map[string]map[string][]Event)
+	for _, event := range events[min(since, len(events)):] {
+		if _, ok := join[event.roomId]; !ok {
+			join[event.roomId] = map[string]map[string][]Event{"timeline": {"events": nil}}
+		}
+		join[event.roomId]["timeline"]["events"] = append(join[event.roomId]["timeline"]["events"], event)
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"next_batch": strconv.Itoa(len(events)),
+		"rooms": map[string]any{"join": join},
+	})
+}
+
+func send(w http.ResponseWriter, r *http.Request) {
+	var content messageContent
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil || json.Unmarshal(raw, &content) != nil {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errcode":"M_NOT_JSON","error":"Content not JSON"}`)
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if eventId, ok := transactions[r.PathValue("txn")]; ok {
+		fmt.Fprintf(w, `{"event_id":%q}`, eventId)
+		return
+	}
+	event := appendEvent(r.PathValue("room"), r.PathValue("type"), botUserId, raw)
+	transactions[r.PathValue("txn")] = event.EventId
+	log.Print("recieved bot message: ", content.Body)
+	botMessages = append(botMessages, BotMessage{
+		RoomId: r.PathValue("room"),
+		EventId: event.EventId,
+		InReplyTo: content.RelatesTo.InReplyTo.EventId,
+		MsgType: content.MsgType,
+		Body: content.Body,
+		FormattedBody: content.FormattedBody,
+	})
+
+	fmt.Fprintf(w, `{"event_id":%q}`, event.EventId)
+}
+
+func join(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, `{"room_id":%q}`, r.PathValue("room"))
+}
+
+// sendMessage posts a text message of the user to the room and returns its event id
+func sendMessage(w http.ResponseWriter, r *http.Request) {
+	var message UserMessage
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	log.Print("recieved user message: ", message)
+	content, _ := json.Marshal(map[string]string{"msgtype": "m.text", "body": message.Body})
+
+	mu.Lock()
+	event := appendEvent(message.RoomId, "m.room.message", message.Sender, content)
+	mu.Unlock()
+
+	fmt.Fprintf(w, `{"event_id":%q}`, event.EventId)
+}
+
+func getMessages(w http.ResponseWriter, r *http.Request) {
+	mu.Lock()
+	defer mu.Unlock()
+	data, err := json.Marshal(botMessages)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	fmt.Fprint(w, string(data))
+}
+
+func appendEvent(roomId string, eventType string, sender string, content json.RawMessage) Event {
+	event := Event{
+		Type: eventType,
+		EventId: fmt.Sprintf("$%d:localhost", len(events) + 1),
+		Sender: sender,
+		OriginServerTs: time.Now().UnixMilli(),
+		Content: content,
+		roomId: roomId,
+	}
+	events = append(events, event)
+
+	return event
+}
+
+func main() {
+	accessToken = os.Getenv("ACCESS_TOKEN")
+	botUserId = os.Getenv("BOT_USER_ID")
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", ping)
+	handler.HandleFunc("GET /_matrix/client/v3/sync", authorized(syncEvents))
+	handler.HandleFunc("PUT /_matrix/client/v3/rooms/{room}/send/{type}/{txn}", authorized(send))
+	handler.HandleFunc("POST /_matrix/client/v3/rooms/{room}/join", authorized(join))
+	handler.HandleFunc("POST /testing/sendMessage", sendMessage)
+	handler.HandleFunc("GET /testing/getBotMessages", getMessages)
+
+	s := &http.Server{
+		Addr:           ":8080",
+		Handler:        handler,
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   60 * time.Second,
+	}
+	log.Fatal(s.ListenAndServe())
+}
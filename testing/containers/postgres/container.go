@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/testing/utils"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+type PostgresContainer struct {
+	*postgres.PostgresContainer
+	connectionString string
+}
+
+func (container *PostgresContainer) migrate() error {
+	conn, err := container.Connection()
+	if err != nil {
+		return err
+	}
+
+	goose.SetDialect("postgres")
+	return goose.Up(conn, utils.TestsRootDir() + "/../migrations/postgres")
+}
+
+func (container *PostgresContainer) Connection() (*sql.DB, error) {
+	db, err := sql.Open("pgx", container.connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (container *PostgresContainer) ExternalConnectionString(ctx context.Context) (string, error) {
+	endpoint, err := container.ContainerIP(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("postgres://%s:%s@%s:5432/%s?sslmode=disable", "root", "root", endpoint, "app"), nil
+}
+
+func Start(ctx context.Context, network string, logs ...testcontainers.LogConsumer) (*PostgresContainer, error) {
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Networks: []string{network},
+		},
+	}
+	container, err := postgres.Run(
+		ctx,
+		"postgres:17-alpine",
+		postgres.WithDatabase("app"),
+		postgres.WithUsername("root"),
+		postgres.WithPassword("root"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second),
+		),
+		testcontainers.CustomizeRequest(req),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	connStr, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		return nil, err
+	}
+	sqlContainer := &PostgresContainer{
+		PostgresContainer: container,
+		connectionString: connStr,
+	}
+	err = sqlContainer.migrate()
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlContainer, nil
+}
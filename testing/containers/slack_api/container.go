@@ -0,0 +1,40 @@
+package slack_api
+
+import (
+	"context"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/testing/utils"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const SIGNING_SECRET = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// Start runs a fake Slack posting the commands and clicks of the tests to the app reachable at appUrl
+func Start(ctx context.Context, network string, appUrl string, logs ...testcontainers.LogConsumer) (testcontainers.Container, error) {
+	req := testcontainers.ContainerRequest{
+		FromDockerfile: testcontainers.FromDockerfile{
+			Context: utils.TestsRootDir() + "/containers/slack_api/server",
+			Dockerfile: "Dockerfile",
+			PrintBuildLog: true,
+		},
+		Env: map[string]string{
+			"APP_URL": appUrl,
+			"SELF_URL": "http://slack-api:8080",
+			"SIGNING_SECRET": SIGNING_SECRET,
+		},
+		Networks: []string{network},
+		NetworkAliases: map[string][]string{network: {"slack-api"}},
+		ExposedPorts: []string{"8080"},
+		WaitingFor: wait.ForHTTP("/").WithPort("8080"),
+		LogConsumerCfg: &testcontainers.LogConsumerConfig{
+			Opts: []testcontainers.LogProductionOption{testcontainers.WithLogProductionTimeout(10*time.Second)},
+			Consumers: logs,
+		},
+	}
+	 return testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started: true,
+	})
+}
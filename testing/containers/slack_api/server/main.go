@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SlashCommand is what the tests send on behalf of a user typing a command
+type SlashCommand struct {
+	Command string `json:"command"`
+	Text string `json:"text"`
+	UserId string `json:"user_id"`
+	UserName string `json:"user_name"`
+	ChannelId string `json:"channel_id"`
+}
+
+// Click is what the tests send on behalf of a user clicking a button
+type Click struct {
+	UserId string `json:"user_id"`
+	UserName string `json:"user_name"`
+	ChannelId string `json:"channel_id"`
+	ActionId string `json:"action_id"`
+	Value string `json:"value"`
+}
+
+type Message struct {
+	ChannelId string `json:"channel_id"`
+	ResponseType string `json:"response_type"`
+	Text string `json:"text"`
+	Blocks json.RawMessage `json:"blocks,omitempty"`
+}
+
+var appUrl string
+var selfUrl string
+var signingSecret string
+var botMessages []Message
+var mu sync.Mutex
+
+func ping(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"ok":true}`)
+}
+
+// sendSlashCommand posts the command to the app like Slack does and returns the app reply
+func sendSlashCommand(w http.ResponseWriter, r *http.Request) {
+	var cmd SlashCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	log.Print("recieved slash command: ", cmd)
+
+	form := url.Values{
+		"command": {cmd.Command},
+		"text": {cmd.Text},
+		"team_id": {"T0000001"},
+		"channel_id": {cmd.ChannelId},
+		"user_id": {cmd.UserId},
+		"user_name": {cmd.UserName},
+		"response_url": {responseUrl(cmd.ChannelId)},
+	}
+	body, status, err := post(appUrl + "/slack/commands", form)
+	if err != nil || status != http.StatusOK {
+		log.Print("app did not accept the command: ", status, err)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	var message Message
+	if err = json.Unmarshal(body, &message); err != nil {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	message.ChannelId = cmd.ChannelId
+	record(message)
+
+	json.NewEncoder(w).Encode(message)
+}
+
+// clickButton posts a block action to the app, its reply comes to the response_url
+func clickButton(w http.ResponseWriter, r *http.Request) {
+	var click Click
+	if err := json.NewDecoder(r.Body).Decode(&click); err != nil {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	log.Print("recieved click: ", click)
+
+	payload, _ := json.Marshal(map[string]any{
+		"type": "block_actions",
+		"user": map[string]string{"id": click.UserId, "username": click.UserName, "name": click.UserName},
+		"channel": map[string]string{"id": click.ChannelId},
+		"response_url": responseUrl(click.ChannelId),
+		"actions": []map[string]string{{"action_id": click.ActionId, "value": click.Value}},
+	})
+	_, status, err := post(appUrl + "/slack/interactions", url.Values{"payload": {string(payload)}})
+	if err != nil || status != http.StatusOK {
+		log.Print("app did not accept the click: ", status, err)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	fmt.Fprint(w, "OK")
+}
+
+func respond(w http.ResponseWriter, r *http.Request) {
+	var message Message
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	message.ChannelId = r.PathValue("channel")
+	record(message)
+
+	fmt.Fprint(w, "ok")
+}
+
+func getMessages(w http.ResponseWriter, r *http.Request) {
+	mu.Lock()
+	defer mu.Unlock()
+	data, err := json.Marshal(botMessages)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	fmt.Fprint(w, string(data))
+}
+
+func record(message Message) {
+	log.Print("recieved app message: ", message.Text)
+	mu.Lock()
+	defer mu.Unlock()
+	botMessages = append(botMessages, message)
+}
+
+// post signs the form the way Slack does
+func post(target string, form url.Values) ([]byte, int, error) {
+	body := form.Encode()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+
+	request, err := http.NewRequest(http.MethodPost, target, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	request.Header.Set("X-Slack-Signature", "v0=" + hex.EncodeToString(mac.Sum(nil)))
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+
+	return data, response.StatusCode, err
+}
+
+func responseUrl(channel string) string {
+	return fmt.Sprintf("%s/response/%s", selfUrl, url.PathEscape(channel))
+}
+
+func main() {
+	appUrl = os.Getenv("APP_URL")
+	selfUrl = os.Getenv("SELF_URL")
+	signingSecret = os.Getenv("SIGNING_SECRET")
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", ping)
+	handler.HandleFunc("POST /response/{channel}", respond)
+	handler.HandleFunc("POST /testing/sendSlashCommand", sendSlashCommand)
+	handler.HandleFunc("POST /testing/clickButton", clickButton)
+	handler.HandleFunc("GET /testing/getBotMessages", getMessages)
+
+	s := &http.Server{
+		Addr:           ":8080",
+		Handler:        handler,
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+	}
+	log.Fatal(s.ListenAndServe())
+}
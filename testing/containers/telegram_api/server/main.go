@@ -38,6 +38,18 @@ type Update struct {
 type Message struct {
 	ChatId int `json:"chat_id"`
 	Text string `json:"text"`
+	Document string `json:"document,omitempty"`
+}
+
+type BotCommand struct {
+	Command string `json:"command"`
+	Description string `json:"description"`
+}
+
+type CommandsMenu struct {
+	Scope json.RawMessage `json:"scope"`
+	LanguageCode string `json:"language_code"`
+	Commands []BotCommand `json:"commands"`
 }
 
 type getUpdatesResponse struct {
@@ -77,6 +89,29 @@ func sendBotMessage(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, "OK")
 }
 
+func sendBotDocument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+
+	var message Message
+	chatId, _ := strconv.Atoi(r.FormValue("chat_id"))
+	message.ChatId = chatId
+	message.Text = r.FormValue("caption")
+
+	_, header, err := r.FormFile("document")
+	if err != nil {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	message.Document = header.Filename
+
+	log.Print("recieved bot document: ", message)
+	botMessages = append(botMessages, message)
+	fmt.Fprint(w, "OK")
+}
+
 func sendMessage(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -103,6 +138,31 @@ func sendMessage(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, "OK")
 }
 
+func setMyCommands(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	menu := CommandsMenu{
+		Scope: json.RawMessage(r.FormValue("scope")),
+		LanguageCode: r.FormValue("language_code"),
+	}
+	if len(menu.Scope) == 0 {
+		menu.Scope = json.RawMessage(`{"type":"default"}`)
+	}
+	err := json.Unmarshal([]byte(r.FormValue("commands")), &menu.Commands)
+	if err != nil || !json.Valid(menu.Scope) {
+		log.Print(err, string(debug.Stack()))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.Print("recieved bot commands: ", menu)
+	commandMenus = append(commandMenus, menu)
+	fmt.Fprint(w, `{"ok":true,"result":true}`)
+}
+
 func getUpdates(w http.ResponseWriter, r *http.Request) {
 	var updates []Update
 	for i := lastReadId; i < len(messages); i++ {
@@ -134,7 +194,18 @@ func getMessages(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, string(data))
 }
 
+func getCommands(w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(commandMenus)
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	fmt.Fprint(w, string(data))
+}
+
 var botMessages []Message
+var commandMenus []CommandsMenu
 var messages []UpdateMessage
 var lastReadId int
 var token string
@@ -145,9 +216,12 @@ func main () {
 	handler.Handle("/", http.HandlerFunc(getMe))
 	handler.Handle(url("/getMe"), http.HandlerFunc(getMe))
 	handler.Handle(url("/sendMessage"), http.HandlerFunc(sendBotMessage))
+	handler.Handle(url("/sendDocument"), http.HandlerFunc(sendBotDocument))
 	handler.Handle(url("/getUpdates"), http.HandlerFunc(getUpdates))
+	handler.Handle(url("/setMyCommands"), http.HandlerFunc(setMyCommands))
 	handler.Handle("/testing/sendClientMessage", http.HandlerFunc(sendMessage))
 	handler.Handle("/testing/getBotMessages", http.HandlerFunc(getMessages))
+	handler.Handle("/testing/getBotCommands", http.HandlerFunc(getCommands))
 
 	s := &http.Server{
 		Addr:           ":8080",
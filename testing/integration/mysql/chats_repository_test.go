@@ -0,0 +1,29 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/mysql"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	mysqlContainer "github.com/SneedusSnake/Reservations/testing/containers/mysql"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestMysqlChatsRepository(t *testing.T) {
+	container, err := mysqlContainer.Start(context.Background(), "", containers.Stdout("Mysql"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	telegram.ChatsRepositoryContract{
+		NewStore: func() telegram.ChatsRepository {
+			return mysql.NewChatsRepository(connection)
+		},
+	}.Test(t)
+}
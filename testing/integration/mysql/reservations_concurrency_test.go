@@ -0,0 +1,93 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/clock/system"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/mysql"
+	"github.com/SneedusSnake/Reservations/internal/application"
+	"github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/domain/users"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	mysqlContainer "github.com/SneedusSnake/Reservations/testing/containers/mysql"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestMysqlConcurrentReservations(t *testing.T) {
+	ctx := context.Background()
+	container, err := mysqlContainer.Start(ctx, "", containers.Stdout("Mysql"))
+	assert.NoError(t, err)
+
+	// every service imitates a separate application instance with its own connection pool
+	var services []*application.ReservationService
+	var connection *sql.DB
+	for range 2 {
+		connection, err = container.Connection()
+		assert.NoError(t, err)
+		services = append(services, reservationService(connection))
+	}
+
+	subjectsRepository := mysql.NewSubjectsRepository(connection)
+	bench := reservations.Subject{Id: 1, Name: "Bench"}
+	phone := reservations.Subject{Id: 2, Name: "Phone", ParentId: bench.Id}
+	for _, s := range []reservations.Subject{bench, phone} {
+		assert.NoError(t, subjectsRepository.Add(ctx, s))
+	}
+	usersRepository := mysql.NewUsersRepository(connection)
+	for id := 1; id <= 5; id++ {
+		assert.NoError(t, usersRepository.Add(ctx, users.User{Id: id, Name: "User"}))
+	}
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			subjectId := bench.Id
+			if i%2 == 0 {
+				subjectId = phone.Id
+			}
+			from := start.Add(time.Duration(i%7) * 10 * time.Minute)
+			services[i%len(services)].Create(ctx, application.CreateReservation{
+				SubjectId: subjectId,
+				UserId: i%5 + 1,
+				From: from,
+				To: from.Add(30 * time.Minute),
+			})
+		}()
+	}
+	wg.Wait()
+
+	list, err := mysql.NewReservationsRepository(connection).List(ctx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, len(list))
+
+	for i, a := range list {
+		for _, b := range list[i+1:] {
+			if a.Interval().Overlaps(b.Interval()) {
+				t.Errorf("Reservations %v and %v overlap", a, b)
+			}
+		}
+	}
+}
+
+func reservationService(connection *sql.DB) *application.ReservationService {
+	reservationsRepository := mysql.NewReservationsRepository(connection)
+	usersRepository := mysql.NewUsersRepository(connection)
+	subjectsRepository := mysql.NewSubjectsRepository(connection)
+
+	return application.NewReservationService(
+		subjectsRepository,
+		reservationsRepository,
+		mysql.NewReservationsReadRepository(connection),
+		usersRepository,
+		mysql.NewUnitOfWork(connection),
+		system.SystemClock{},
+		nil,
+	)
+}
@@ -0,0 +1,29 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/mysql"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	mysqlContainer "github.com/SneedusSnake/Reservations/testing/containers/mysql"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestMysqlTokensRepository(t *testing.T) {
+	container, err := mysqlContainer.Start(context.Background(), "", containers.Stdout("Mysql"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	users.TokensRepositoryContract{
+		NewStore: func() users.TokensRepository {
+			return mysql.NewTokensRepository(connection)
+		},
+	}.Test(t)
+}
@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/mysql"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	mysqlContainer "github.com/SneedusSnake/Reservations/testing/containers/mysql"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestMysqlUserMerger(t *testing.T) {
+	container, err := mysqlContainer.Start(context.Background(), "", containers.Stdout("Mysql"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	reservations.UserMergerContract{
+		NewStores: func() reservations.MergerStores {
+			return reservations.MergerStores{
+				Merger: mysql.NewUserMerger(connection),
+				Users: mysql.NewUsersRepository(connection),
+				AccessTokens: mysql.NewAccessTokensRepository(connection),
+				Reservations: mysql.NewReservationsRepository(connection),
+				Workspaces: mysql.NewWorkspacesRepository(connection),
+			}
+		},
+	}.Test(t)
+}
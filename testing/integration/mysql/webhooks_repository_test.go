@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/mysql"
+	"github.com/SneedusSnake/Reservations/internal/ports/webhooks"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	mysqlContainer "github.com/SneedusSnake/Reservations/testing/containers/mysql"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestMysqlWebhooksRepository(t *testing.T) {
+	container, err := mysqlContainer.Start(context.Background(), "", containers.Stdout("Mysql"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	webhooks.WebhooksRepositoryContract{
+		NewStore: func() webhooks.WebhooksRepository {
+			return mysql.NewWebhooksRepository(connection)
+		},
+	}.Test(t)
+
+	webhooks.DeadLettersRepositoryContract{
+		NewStore: func() webhooks.DeadLettersRepository {
+			return mysql.NewDeadLettersRepository(connection)
+		},
+	}.Test(t)
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/postgres"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	postgresContainer "github.com/SneedusSnake/Reservations/testing/containers/postgres"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestPostgresAccessTokensRepository(t *testing.T) {
+	container, err := postgresContainer.Start(context.Background(), "", containers.Stdout("Postgres"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	users.AccessTokensRepositoryContract{
+		NewStore: func() users.AccessTokensRepository {
+			return postgres.NewAccessTokensRepository(connection)
+		},
+	}.Test(t)
+}
@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/postgres"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	postgresContainer "github.com/SneedusSnake/Reservations/testing/containers/postgres"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestPostgresChatUsersRepository(t *testing.T) {
+	container, err := postgresContainer.Start(context.Background(), "", containers.Stdout("Postgres"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	users.ChatUsersRepositoryContract{
+		NewUsersStore: func() users.UsersRepository {
+			return postgres.NewUsersRepository(connection)
+		},
+		NewStore: func(platform string, _ users.UsersRepository) users.ChatUsersRepository {
+			return postgres.NewChatUsersRepository(connection, platform)
+		},
+	}.Test(t)
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/postgres"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	postgresContainer "github.com/SneedusSnake/Reservations/testing/containers/postgres"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestPostgresReservationsReadRepository(t *testing.T) {
+	container, err := postgresContainer.Start(context.Background(), "", containers.Stdout("Postgres"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	contract := reservations.ReservationsReadRepositoryContract{
+		NewRepository: func() reservations.ReservationsReadRepository {
+			return postgres.NewReservationsReadRepository(connection)
+		},
+	}
+
+	contract.Test(
+		t,
+		postgres.NewReservationsRepository(connection),
+		postgres.NewUsersRepository(connection),
+		postgres.NewSubjectsRepository(connection),
+	)
+}
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/postgres"
+	domain "github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	postgresContainer "github.com/SneedusSnake/Reservations/testing/containers/postgres"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestPostgresReservationsRepository(t *testing.T) {
+	container, err := postgresContainer.Start(context.Background(), "", containers.Stdout("Postgres"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	contract := reservations.ReservationsRepositoryContract{
+		NewRepository: func() reservations.ReservationsRepository {
+			return postgres.NewReservationsRepository(connection)
+		},
+	}
+
+	contract.Test(t)
+
+	t.Run("it rejects overlapping reservations of the same subject", func(t *testing.T) {
+		ctx := context.Background()
+		repository := postgres.NewReservationsRepository(connection)
+		start := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		assert.NoError(t, repository.Add(ctx, domain.Reservation{Id: 900001, UserId: 1, SubjectId: 900, Start: start, End: start.Add(time.Hour)}))
+		assert.NoError(t, repository.Add(ctx, domain.Reservation{Id: 900002, UserId: 1, SubjectId: 900, Start: start.Add(time.Hour), End: start.Add(2 * time.Hour)}))
+		assert.NoError(t, repository.Add(ctx, domain.Reservation{Id: 900003, UserId: 1, SubjectId: 901, Start: start, End: start.Add(time.Hour)}))
+		assert.Error(t, repository.Add(ctx, domain.Reservation{Id: 900004, UserId: 1, SubjectId: 900, Start: start.Add(30 * time.Minute), End: start.Add(90 * time.Minute)}))
+	})
+}
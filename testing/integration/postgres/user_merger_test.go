@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/postgres"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	postgresContainer "github.com/SneedusSnake/Reservations/testing/containers/postgres"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestPostgresUserMerger(t *testing.T) {
+	container, err := postgresContainer.Start(context.Background(), "", containers.Stdout("Postgres"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	reservations.UserMergerContract{
+		NewStores: func() reservations.MergerStores {
+			return reservations.MergerStores{
+				Merger: postgres.NewUserMerger(connection),
+				Users: postgres.NewUsersRepository(connection),
+				AccessTokens: postgres.NewAccessTokensRepository(connection),
+				Reservations: postgres.NewReservationsRepository(connection),
+				Workspaces: postgres.NewWorkspacesRepository(connection),
+			}
+		},
+	}.Test(t)
+}
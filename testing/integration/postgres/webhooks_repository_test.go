@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/postgres"
+	"github.com/SneedusSnake/Reservations/internal/ports/webhooks"
+	"github.com/SneedusSnake/Reservations/testing/containers"
+	postgresContainer "github.com/SneedusSnake/Reservations/testing/containers/postgres"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestPostgresWebhooksRepository(t *testing.T) {
+	container, err := postgresContainer.Start(context.Background(), "", containers.Stdout("Postgres"))
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+	connection, err := container.Connection()
+	if  err != nil {
+		assert.NoError(t, err)
+	}
+
+	webhooks.WebhooksRepositoryContract{
+		NewStore: func() webhooks.WebhooksRepository {
+			return postgres.NewWebhooksRepository(connection)
+		},
+	}.Test(t)
+
+	webhooks.DeadLettersRepositoryContract{
+		NewStore: func() webhooks.DeadLettersRepository {
+			return postgres.NewDeadLettersRepository(connection)
+		},
+	}.Test(t)
+}
@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+)
+
+func TestSqliteAccessTokensRepository(t *testing.T) {
+	connection := database(t)
+
+	users.AccessTokensRepositoryContract{
+		NewStore: func() users.AccessTokensRepository {
+			return sqlite.NewAccessTokensRepository(connection)
+		},
+	}.Test(t)
+}
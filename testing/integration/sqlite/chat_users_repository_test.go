@@ -0,0 +1,21 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+)
+
+func TestSqliteChatUsersRepository(t *testing.T) {
+	connection := database(t)
+
+	users.ChatUsersRepositoryContract{
+		NewUsersStore: func() users.UsersRepository {
+			return sqlite.NewUsersRepository(connection)
+		},
+		NewStore: func(platform string, _ users.UsersRepository) users.ChatUsersRepository {
+			return sqlite.NewChatUsersRepository(connection, platform)
+		},
+	}.Test(t)
+}
@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+)
+
+func TestSqliteChatsRepository(t *testing.T) {
+	connection := database(t)
+
+	telegram.ChatsRepositoryContract{
+		NewStore: func() telegram.ChatsRepository {
+			return sqlite.NewChatsRepository(connection)
+		},
+	}.Test(t)
+}
@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/testing/utils"
+	"github.com/alecthomas/assert/v2"
+	"github.com/pressly/goose/v3"
+)
+
+// database opens a freshly migrated database file, no container is needed for SQLite
+func database(t *testing.T) *sql.DB {
+	path := t.TempDir() + "/reservations.db"
+	migrate(t, path)
+
+	connection, err := sqlite.Open(path)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		connection.Close()
+	})
+
+	return connection
+}
+
+func migrate(t *testing.T, path string) {
+	connection, err := sqlite.Open(path)
+	assert.NoError(t, err)
+	defer connection.Close()
+
+	assert.NoError(t, goose.SetDialect("sqlite3"))
+	assert.NoError(t, goose.Up(connection, utils.TestsRootDir() + "/../migrations/sqlite"))
+}
@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+)
+
+func TestSqliteReservationsReadRepository(t *testing.T) {
+	connection := database(t)
+
+	contract := reservations.ReservationsReadRepositoryContract{
+		NewRepository: func() reservations.ReservationsReadRepository {
+			return sqlite.NewReservationsReadRepository(connection)
+		},
+	}
+
+	contract.Test(
+		t,
+		sqlite.NewReservationsRepository(connection),
+		sqlite.NewUsersRepository(connection),
+		sqlite.NewSubjectsRepository(connection),
+	)
+}
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	domain "github.com/SneedusSnake/Reservations/internal/domain/reservations"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestSqliteReservationsRepository(t *testing.T) {
+	connection := database(t)
+
+	contract := reservations.ReservationsRepositoryContract{
+		NewRepository: func() reservations.ReservationsRepository {
+			return sqlite.NewReservationsRepository(connection)
+		},
+	}
+
+	contract.Test(t)
+
+	t.Run("it rejects overlapping reservations of the same subject", func(t *testing.T) {
+		ctx := context.Background()
+		repository := sqlite.NewReservationsRepository(connection)
+		start := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		assert.NoError(t, repository.Add(ctx, domain.Reservation{Id: 900001, UserId: 1, SubjectId: 900, Start: start, End: start.Add(time.Hour)}))
+		assert.NoError(t, repository.Add(ctx, domain.Reservation{Id: 900002, UserId: 1, SubjectId: 900, Start: start.Add(time.Hour), End: start.Add(2 * time.Hour)}))
+		assert.NoError(t, repository.Add(ctx, domain.Reservation{Id: 900003, UserId: 1, SubjectId: 901, Start: start, End: start.Add(time.Hour)}))
+		assert.Error(t, repository.Add(ctx, domain.Reservation{Id: 900004, UserId: 1, SubjectId: 900, Start: start.Add(30 * time.Minute), End: start.Add(90 * time.Minute)}))
+	})
+
+	t.Run("it keeps the instant of times in other zones", func(t *testing.T) {
+		ctx := context.Background()
+		repository := sqlite.NewReservationsRepository(connection)
+		moscow := time.FixedZone("MSK", 3 * 60 * 60)
+		start := time.Date(2030, 2, 1, 12, 0, 0, 0, moscow)
+
+		assert.NoError(t, repository.Add(ctx, domain.Reservation{Id: 900011, UserId: 1, SubjectId: 910, Start: start, End: start.Add(time.Hour)}))
+		stored, err := repository.Get(ctx, 900011)
+		assert.NoError(t, err)
+		assert.True(t, stored.Start.Equal(start), stored.Start.String())
+		assert.True(t, stored.End.Equal(start.Add(time.Hour)), stored.End.String())
+
+		utc := time.Date(2030, 2, 1, 9, 30, 0, 0, time.UTC)
+		assert.Error(t, repository.Add(ctx, domain.Reservation{Id: 900012, UserId: 1, SubjectId: 910, Start: utc, End: utc.Add(time.Hour)}))
+	})
+}
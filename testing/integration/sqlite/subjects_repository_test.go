@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+)
+
+func TestSqliteSubjectsRepository(t *testing.T) {
+	connection := database(t)
+
+	contract := reservations.SubjectsRepositoryContract{
+		NewStore: func() reservations.SubjectsRepository {
+			return sqlite.NewSubjectsRepository(connection)
+		},
+	}
+
+	contract.Test(t)
+}
@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+)
+
+func TestSqliteTokensRepository(t *testing.T) {
+	connection := database(t)
+
+	users.TokensRepositoryContract{
+		NewStore: func() users.TokensRepository {
+			return sqlite.NewTokensRepository(connection)
+		},
+	}.Test(t)
+}
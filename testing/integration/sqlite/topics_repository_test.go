@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/adapters/driving/telegram"
+)
+
+func TestSqliteTopicsRepository(t *testing.T) {
+	connection := database(t)
+
+	telegram.TopicsRepositoryContract{
+		NewStore: func() telegram.TopicsRepository {
+			return sqlite.NewTopicsRepository(connection)
+		},
+	}.Test(t)
+}
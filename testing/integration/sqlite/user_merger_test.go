@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/ports/reservations"
+)
+
+func TestSqliteUserMerger(t *testing.T) {
+	connection := database(t)
+
+	reservations.UserMergerContract{
+		NewStores: func() reservations.MergerStores {
+			return reservations.MergerStores{
+				Merger: sqlite.NewUserMerger(connection),
+				Users: sqlite.NewUsersRepository(connection),
+				AccessTokens: sqlite.NewAccessTokensRepository(connection),
+				Reservations: sqlite.NewReservationsRepository(connection),
+				Workspaces: sqlite.NewWorkspacesRepository(connection),
+			}
+		},
+	}.Test(t)
+}
@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/ports/users"
+)
+
+func TestSqliteUsersRepository(t *testing.T) {
+	connection := database(t)
+
+	contract := users.UsersRepositoryContract{
+		NewStore: func() users.UsersRepository {
+			return sqlite.NewUsersRepository(connection)
+		},
+	}
+
+	contract.Test(t)
+}
@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/ports/webhooks"
+)
+
+func TestSqliteWebhooksRepository(t *testing.T) {
+	connection := database(t)
+
+	webhooks.WebhooksRepositoryContract{
+		NewStore: func() webhooks.WebhooksRepository {
+			return sqlite.NewWebhooksRepository(connection)
+		},
+	}.Test(t)
+
+	webhooks.DeadLettersRepositoryContract{
+		NewStore: func() webhooks.DeadLettersRepository {
+			return sqlite.NewDeadLettersRepository(connection)
+		},
+	}.Test(t)
+}
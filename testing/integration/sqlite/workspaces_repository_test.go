@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/SneedusSnake/Reservations/internal/adapters/driven/persistence/sqlite"
+	"github.com/SneedusSnake/Reservations/internal/ports/workspaces"
+)
+
+func TestSqliteWorkspacesRepository(t *testing.T) {
+	connection := database(t)
+
+	workspaces.WorkspacesRepositoryContract{
+		NewStore: func() workspaces.WorkspacesRepository {
+			return sqlite.NewWorkspacesRepository(connection)
+		},
+	}.Test(t)
+}